@@ -0,0 +1,91 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+// pmm API protobuf files live in the separate github.com/percona/pmm module, so new RPCs can't be added
+// together with the pmm-managed changes that implement them. Until they are described there,
+// such features are served by JSON APIs registered below with utils/httpapi. Those APIs follow
+// grpc-gateway conventions (POST with JSON body, the same error responses, gRPC metadata from HTTP headers),
+// so clients don't need changes when they are replaced with gRPC APIs and grpc-gateway handlers.
+// Request and response types are declared next to the service methods and should be moved
+// to the protobuf files unchanged, as should be the paths.
+
+import (
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+// addJSONAPIHandlers adds JSON APIs for the features which are not described in pmm API protobuf files yet.
+// All of them should be kept there, under /v1/management/ prefix that requires admin role.
+func addJSONAPIHandlers(mux httpapi.Mux, deps *http1ServerDeps) {
+	httpapi.Handle(mux, "/v1/management/backup/Backups/Restore", deps.backupsService.Restore)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/PlanRestore", deps.backupsService.PlanRestore)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/PauseBackup", deps.backupsService.PauseBackup)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/ResumeBackup", deps.backupsService.ResumeBackup)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/CancelBackup", deps.backupsService.CancelBackup)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/PauseRestore", deps.backupsService.PauseRestore)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/ResumeRestore", deps.backupsService.ResumeRestore)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/CancelRestore", deps.backupsService.CancelRestore)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/GetRetentionPolicy", deps.backupsService.GetRetentionPolicy)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/ChangeRetentionPolicy", deps.backupsService.ChangeRetentionPolicy)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/ListArtifactsToDelete", deps.backupsService.ListArtifactsToDelete)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/GetBackupLimits", deps.backupsService.GetBackupLimits)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/ChangeBackupLimits", deps.backupsService.ChangeBackupLimits)
+	httpapi.Handle(mux, "/v1/management/backup/Backups/GetScheduledBackupStatus", deps.backupsService.GetScheduledBackupStatus)
+	httpapi.Handle(mux, "/v1/management/backup/Artifacts/Verify", deps.artifactsService.Verify)
+	httpapi.Handle(mux, "/v1/management/backup/Artifacts/ListStats", deps.artifactsService.ListStats)
+	httpapi.Handle(mux, "/v1/management/backup/Artifacts/ListReplicas", deps.artifactsService.ListReplicas)
+	httpapi.Handle(mux, "/v1/management/backup/Artifacts/Replicate", deps.artifactsService.Replicate)
+	httpapi.Handle(mux, "/v1/management/backup/Locations/GetLocationUsage", deps.locationsService.GetLocationUsage)
+	httpapi.Handle(mux, "/v1/management/backup/Locations/SetLocationQuota", deps.locationsService.SetLocationQuota)
+	httpapi.Handle(mux, "/v1/management/backup/Locations/ListReplicationRules", deps.locationsService.ListReplicationRules)
+	httpapi.Handle(mux, "/v1/management/backup/Locations/AddReplicationRule", deps.locationsService.AddReplicationRule)
+	httpapi.Handle(mux, "/v1/management/backup/Locations/RemoveReplicationRule", deps.locationsService.RemoveReplicationRule)
+	httpapi.Handle(mux, "/v1/management/backup/Jobs/Get", deps.jobsAPIService.GetJob)
+	httpapi.HandleStream(mux, "/v1/management/backup/Jobs/Watch", deps.jobsAPIService.WatchJob)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/AddSecurityChecksTask", deps.scheduledTasksService.AddSecurityChecksTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/AddQueryActionTask", deps.scheduledTasksService.AddQueryActionTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/AddPTSummaryTask", deps.scheduledTasksService.AddPTSummaryTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/AddArtifactVerificationTask", deps.scheduledTasksService.AddArtifactVerificationTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/Get", deps.scheduledTasksService.GetScheduledTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/Remove", deps.scheduledTasksService.RemoveScheduledTask)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/ChangeCatchUpPolicy", deps.scheduledTasksService.ChangeCatchUpPolicy)
+	httpapi.Handle(mux, "/v1/management/ScheduledTasks/ListRuns", deps.scheduledTasksService.ListRuns)
+	httpapi.Handle(mux, "/v1/management/ia/MaintenanceWindows/List", deps.maintenanceWindowsService.ListMaintenanceWindows)
+	httpapi.Handle(mux, "/v1/management/ia/MaintenanceWindows/Create", deps.maintenanceWindowsService.CreateMaintenanceWindow)
+	httpapi.Handle(mux, "/v1/management/ia/MaintenanceWindows/Change", deps.maintenanceWindowsService.ChangeMaintenanceWindow)
+	httpapi.Handle(mux, "/v1/management/ia/MaintenanceWindows/Remove", deps.maintenanceWindowsService.RemoveMaintenanceWindow)
+	httpapi.Handle(mux, "/v1/management/ia/AlertRoutes/List", deps.alertRoutesService.ListAlertRoutes)
+	httpapi.Handle(mux, "/v1/management/ia/AlertRoutes/Create", deps.alertRoutesService.CreateAlertRoute)
+	httpapi.Handle(mux, "/v1/management/ia/AlertRoutes/Change", deps.alertRoutesService.ChangeAlertRoute)
+	httpapi.Handle(mux, "/v1/management/ia/AlertRoutes/Remove", deps.alertRoutesService.RemoveAlertRoute)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/GetFilters", deps.rulesService.GetRuleFilters)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/ChangeFilters", deps.rulesService.ChangeRuleFilters)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/Preview", deps.rulesService.PreviewAlertRule)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/GetNotificationTemplate", deps.rulesService.GetRuleNotificationTemplate)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/ChangeNotificationTemplate", deps.rulesService.ChangeRuleNotificationTemplate)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/ExportBundle", deps.rulesService.ExportBundle)
+	httpapi.Handle(mux, "/v1/management/ia/Rules/ImportBundle", deps.rulesService.ImportBundle)
+	httpapi.Handle(mux, "/v1/management/ia/Alerts/Acknowledge", deps.alertsService.AcknowledgeAlerts)
+	httpapi.Handle(mux, "/v1/management/ia/Alerts/ListEvents", deps.alertsService.ListAlertEvents)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/List", deps.extendedChannelsService.ListChannels)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/Get", deps.extendedChannelsService.GetChannel)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/Add", deps.extendedChannelsService.AddChannel)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/Change", deps.extendedChannelsService.ChangeChannel)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/Test", deps.extendedChannelsService.TestChannel)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/TestConfig", deps.extendedChannelsService.TestChannelConfig)
+	httpapi.Handle(mux, "/v1/management/ia/ExtendedChannels/PreviewNotificationTemplate", deps.extendedChannelsService.PreviewNotificationTemplate)
+}
@@ -0,0 +1,56 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMux records registered paths.
+type recordingMux struct {
+	*http.ServeMux
+	paths []string
+}
+
+func (m *recordingMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.paths = append(m.paths, pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
+}
+
+func TestJSONAPIHandlers(t *testing.T) {
+	mux := &recordingMux{ServeMux: http.NewServeMux()}
+	addJSONAPIHandlers(mux, &http1ServerDeps{}) // http.ServeMux panics on duplicate paths
+	require.NotEmpty(t, mux.paths)
+
+	for _, path := range mux.paths {
+		// all JSON APIs require admin role, see rules in services/grafana/auth_server.go
+		assert.True(t, strings.HasPrefix(path, "/v1/management/"), "%s", path)
+		assert.False(t, strings.HasPrefix(path, "/v1/management/Actions/"), "%s", path)
+		assert.False(t, strings.HasPrefix(path, "/v1/management/Jobs"), "%s", path)
+
+		// handlers are not called for methods other than POST
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotImplemented, rec.Code, "%s", path)
+	}
+}
@@ -51,6 +51,8 @@ import (
 	"github.com/percona/pmm/api/serverpb"
 	"github.com/percona/pmm/utils/sqlmetrics"
 	"github.com/percona/pmm/version"
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
@@ -149,7 +151,9 @@ type gRPCServerDeps struct {
 	versionServiceClient *managementdbaas.VersionServiceClient
 	schedulerService     *scheduler.Service
 	backupService        *backup.Service
-	backupRemovalService *backup.RemovalService
+	backupsService       *managementbackup.BackupsService
+	artifactsService     *managementbackup.ArtifactsService
+	locationsService     *managementbackup.LocationsService
 	minioService         *minio.Service
 	versionCache         *versioncache.Service
 	supervisord          *supervisord.Service
@@ -212,9 +216,9 @@ func runGRPCServer(ctx context.Context, deps *gRPCServerDeps) {
 	iav1beta1.RegisterRulesServer(gRPCServer, deps.rulesService)
 	iav1beta1.RegisterAlertsServer(gRPCServer, deps.alertsService)
 
-	backupv1beta1.RegisterBackupsServer(gRPCServer, managementbackup.NewBackupsService(deps.db, deps.backupService, deps.schedulerService))
-	backupv1beta1.RegisterLocationsServer(gRPCServer, managementbackup.NewLocationsService(deps.db, deps.minioService))
-	backupv1beta1.RegisterArtifactsServer(gRPCServer, managementbackup.NewArtifactsService(deps.db, deps.backupRemovalService))
+	backupv1beta1.RegisterBackupsServer(gRPCServer, deps.backupsService)
+	backupv1beta1.RegisterLocationsServer(gRPCServer, deps.locationsService)
+	backupv1beta1.RegisterArtifactsServer(gRPCServer, deps.artifactsService)
 	backupv1beta1.RegisterRestoreHistoryServer(gRPCServer, managementbackup.NewRestoreHistoryService(deps.db))
 
 	dbaasv1beta1.RegisterKubernetesServer(gRPCServer, managementdbaas.NewKubernetesServer(deps.db, deps.dbaasClient, deps.grafanaClient, deps.versionServiceClient))
@@ -271,8 +275,19 @@ func runGRPCServer(ctx context.Context, deps *gRPCServerDeps) {
 }
 
 type http1ServerDeps struct {
-	logs       *supervisord.Logs
-	authServer *grafana.AuthServer
+	logs                  *supervisord.Logs
+	authServer            *grafana.AuthServer
+	backupsService        *managementbackup.BackupsService
+	artifactsService      *managementbackup.ArtifactsService
+	locationsService      *managementbackup.LocationsService
+	jobsAPIService        *managementbackup.JobsService
+	scheduledTasksService *management.ScheduledTasksService
+
+	maintenanceWindowsService *ia.MaintenanceWindowsService
+	alertRoutesService        *ia.AlertRoutesService
+	alertsService             *ia.AlertsService
+	rulesService              *ia.RulesService
+	extendedChannelsService   *ia.ExtendedChannelsService
 }
 
 // runHTTP1Server runs grpc-gateway and other HTTP 1.1 APIs (like auth_request and logs.zip)
@@ -353,6 +368,7 @@ func runHTTP1Server(ctx context.Context, deps *http1ServerDeps) {
 
 	mux := http.NewServeMux()
 	addLogsHandler(mux, deps.logs)
+	addJSONAPIHandlers(mux, deps)
 	mux.Handle("/auth_request", deps.authServer)
 	mux.Handle("/", proxyMux)
 
@@ -667,6 +683,10 @@ func main() {
 		l.Panicf("VictoriaMetrics VMAlert service problem: %+v", err)
 	}
 	prom.MustRegister(vmalert)
+	vmClient, err := promapi.NewClient(promapi.Config{Address: *victoriaMetricsURLF})
+	if err != nil {
+		l.Panicf("VictoriaMetrics client problem: %+v", err)
+	}
 
 	minioService := minio.New()
 
@@ -698,7 +718,19 @@ func main() {
 	grafanaClient := grafana.NewClient(*grafanaAddrF)
 	prom.MustRegister(grafanaClient)
 
-	jobsService := agents.NewJobsService(db, agentsRegistry, backupRetentionService)
+	backupMetricsService := backup.NewMetricsService(db, minioService)
+	prom.MustRegister(backupMetricsService)
+	backupReplicationService := backup.NewReplicationService(db, minioService)
+	backupUsageService := backup.NewUsageService(db, minioService, backupRemovalService)
+	prom.MustRegister(backupUsageService)
+	jobsService := agents.NewJobsService(
+		db,
+		agentsRegistry,
+		backupRetentionService,
+		backupMetricsService,
+		backupReplicationService,
+		backupUsageService,
+		backupRemovalService)
 	agentsStateUpdater := agents.NewStateUpdater(db, agentsRegistry, vmdb)
 	agentsHandler := agents.NewHandler(db, qanClient, vmdb, agentsRegistry, agentsStateUpdater, jobsService)
 
@@ -718,15 +750,26 @@ func main() {
 	}
 	// We should collect templates before rules service created, because it will regenerate rule files on startup.
 	templatesService.CollectTemplates(ctx)
-	rulesService := ia.NewRulesService(db, templatesService, vmalert, alertManager)
-	alertsService := ia.NewAlertsService(db, alertManager, templatesService)
+	rulesService := ia.NewRulesService(db, templatesService, vmalert, externalRules, alertManager, promv1.NewAPI(vmClient))
+	alertsService := ia.NewAlertsService(db, alertManager, templatesService, grafanaClient)
+	maintenanceWindowsService := ia.NewMaintenanceWindowsService(db, alertManager)
+	alertRoutesService := ia.NewAlertRoutesService(db, alertManager)
+	extendedChannelsService := ia.NewExtendedChannelsService(db, alertManager)
 
 	versionService := managementdbaas.NewVersionServiceClient(*versionServiceAPIURLF)
 
 	versioner := agents.NewVersionerService(agentsRegistry)
 	dbaasClient := dbaas.NewClient(*dbaasControllerAPIAddrF)
-	backupService := backup.NewService(db, jobsService, agentsRegistry, versioner)
-	schedulerService := scheduler.New(db, backupService)
+	pitrTimerangeService := backup.NewPITRTimerangeService(minioService)
+	backupService := backup.NewService(db, jobsService, agentsRegistry, versioner, pitrTimerangeService,
+		backupReplicationService, backupRemovalService, minioService)
+	backupVerificationService := backup.NewVerificationService(db, minioService, alertManager)
+	schedulerService := scheduler.New(db, backupService, actionsService, checksService, backupVerificationService)
+	backupsService := managementbackup.NewBackupsService(db, backupService, schedulerService, backupRetentionService)
+	scheduledTasksService := management.NewScheduledTasksService(db, schedulerService)
+	artifactsService := managementbackup.NewArtifactsService(db, backupRemovalService, backupVerificationService, backupReplicationService)
+	jobsAPIService := managementbackup.NewJobsService(db, jobsService)
+	locationsService := managementbackup.NewLocationsService(db, minioService, backupUsageService)
 	versionCache := versioncache.New(db, versioner)
 	emailer := alertmanager.NewEmailer(logrus.WithField("component", "alertmanager-emailer").Logger)
 
@@ -867,6 +910,16 @@ func main() {
 		defer wg.Done()
 		alertManager.Run(ctx)
 	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		alertManager.RunMaintenanceWindows(ctx)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		alertsService.RunAlertEvents(ctx)
+	}()
 
 	wg.Add(1)
 	go func() {
@@ -924,7 +977,9 @@ func main() {
 				versionServiceClient: versionService,
 				schedulerService:     schedulerService,
 				backupService:        backupService,
-				backupRemovalService: backupRemovalService,
+				backupsService:       backupsService,
+				artifactsService:     artifactsService,
+				locationsService:     locationsService,
 				minioService:         minioService,
 				versionCache:         versionCache,
 				supervisord:          supervisord,
@@ -936,8 +991,19 @@ func main() {
 	go func() {
 		defer wg.Done()
 		runHTTP1Server(ctx, &http1ServerDeps{
-			logs:       logs,
-			authServer: authServer,
+			logs:                  logs,
+			authServer:            authServer,
+			backupsService:        backupsService,
+			artifactsService:      artifactsService,
+			locationsService:      locationsService,
+			jobsAPIService:        jobsAPIService,
+			scheduledTasksService: scheduledTasksService,
+
+			maintenanceWindowsService: maintenanceWindowsService,
+			alertRoutesService:        alertRoutesService,
+			alertsService:             alertsService,
+			rulesService:              rulesService,
+			extendedChannelsService:   extendedChannelsService,
 		})
 	}()
 
@@ -0,0 +1,230 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+)
+
+// AlertEventsFilter represents filters for alert events.
+type AlertEventsFilter struct {
+	AlertID    string
+	RuleID     string
+	Types      []AlertEventType
+	Severities []Severity
+	Username   string
+	// Return only events created at or after From, if set.
+	From time.Time
+	// Return only events created before To, if set.
+	To time.Time
+}
+
+// where returns WHERE clause and its arguments for given filters.
+func (f AlertEventsFilter) where(q *reform.Querier) (string, []interface{}) {
+	var args []interface{}
+	var andConds []string
+	idx := 1
+
+	if f.AlertID != "" {
+		andConds = append(andConds, "alert_id = "+q.Placeholder(idx))
+		args = append(args, f.AlertID)
+		idx++
+	}
+
+	if f.RuleID != "" {
+		andConds = append(andConds, "rule_id = "+q.Placeholder(idx))
+		args = append(args, f.RuleID)
+		idx++
+	}
+
+	if len(f.Types) != 0 {
+		p := strings.Join(q.Placeholders(idx, len(f.Types)), ", ")
+		for _, t := range f.Types {
+			args = append(args, t)
+		}
+		idx += len(f.Types)
+		andConds = append(andConds, fmt.Sprintf("type IN (%s)", p))
+	}
+
+	if len(f.Severities) != 0 {
+		p := strings.Join(q.Placeholders(idx, len(f.Severities)), ", ")
+		for _, s := range f.Severities {
+			args = append(args, s)
+		}
+		idx += len(f.Severities)
+		andConds = append(andConds, fmt.Sprintf("severity IN (%s)", p))
+	}
+
+	if f.Username != "" {
+		andConds = append(andConds, "username = "+q.Placeholder(idx))
+		args = append(args, f.Username)
+		idx++
+	}
+
+	if !f.From.IsZero() {
+		andConds = append(andConds, "created_at >= "+q.Placeholder(idx))
+		args = append(args, f.From)
+		idx++
+	}
+
+	if !f.To.IsZero() {
+		andConds = append(andConds, "created_at < "+q.Placeholder(idx))
+		args = append(args, f.To)
+	}
+
+	if len(andConds) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(andConds, " AND ") + " ", args
+}
+
+// FindAlertEvents returns alert events satisfying filters, newest first.
+// If pageSize is zero, all events are returned.
+func FindAlertEvents(q *reform.Querier, filters AlertEventsFilter, pageIndex, pageSize int) ([]*AlertEvent, error) {
+	where, args := filters.where(q)
+	tail := where + "ORDER BY created_at DESC, id"
+	if pageSize > 0 {
+		tail += fmt.Sprintf(" LIMIT %s OFFSET %s", q.Placeholder(len(args)+1), q.Placeholder(len(args)+2))
+		args = append(args, pageSize, pageIndex*pageSize)
+	}
+
+	rows, err := q.SelectAllFrom(AlertEventTable, tail, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select alert events")
+	}
+
+	events := make([]*AlertEvent, len(rows))
+	for i, s := range rows {
+		events[i] = s.(*AlertEvent)
+	}
+
+	return events, nil
+}
+
+// CountAlertEvents returns number of alert events satisfying filters.
+func CountAlertEvents(q *reform.Querier, filters AlertEventsFilter) (int, error) {
+	where, args := filters.where(q)
+	count, err := q.Count(AlertEventTable, where, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count alert events")
+	}
+
+	return count, nil
+}
+
+// FindOpenAlertEvents returns the latest fired, silenced or unsilenced event of every alert which is not resolved yet.
+// Acknowledgements don't change alert state, so they are skipped.
+func FindOpenAlertEvents(q *reform.Querier) ([]*AlertEvent, error) {
+	tail := fmt.Sprintf(`WHERE id IN (
+		SELECT DISTINCT ON (alert_id) id FROM %[1]s WHERE type <> %[2]s ORDER BY alert_id, created_at DESC, id
+	) AND type <> %[3]s`, AlertEventTable.Name(), q.Placeholder(1), q.Placeholder(2))
+	rows, err := q.SelectAllFrom(AlertEventTable, tail, AcknowledgedAlertEvent, ResolvedAlertEvent)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select open alert events")
+	}
+
+	events := make([]*AlertEvent, len(rows))
+	for i, s := range rows {
+		events[i] = s.(*AlertEvent)
+	}
+
+	return events, nil
+}
+
+// RemoveOldAlertEvents removes events of alerts resolved before given time.
+// Events of alerts which are not resolved yet are kept regardless of their age,
+// so FindOpenAlertEvents still returns them. It returns number of removed events.
+func RemoveOldAlertEvents(q *reform.Querier, resolvedBefore time.Time) (int, error) {
+	query := fmt.Sprintf(`DELETE FROM %[1]s e WHERE e.created_at < %[2]s AND EXISTS (
+		SELECT 1 FROM %[1]s r WHERE r.alert_id = e.alert_id AND r.type = %[3]s AND r.created_at >= e.created_at AND r.created_at < %[2]s
+	)`, AlertEventTable.Name(), q.Placeholder(1), q.Placeholder(2))
+	res, err := q.Exec(query, resolvedBefore, ResolvedAlertEvent)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to remove old alert events")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return int(n), nil
+}
+
+// CreateAlertEventParams are params for creating alert event.
+type CreateAlertEventParams struct {
+	AlertID  string
+	RuleID   string
+	Type     AlertEventType
+	Summary  string
+	Severity *Severity
+	Labels   map[string]string
+	Username string
+	Comment  string
+}
+
+// Validate validates params.
+func (p *CreateAlertEventParams) Validate() error {
+	if p.AlertID == "" {
+		return status.Error(codes.InvalidArgument, "Alert ID can't be empty.")
+	}
+
+	switch p.Type {
+	case FiredAlertEvent, AcknowledgedAlertEvent, SilencedAlertEvent, UnsilencedAlertEvent, ResolvedAlertEvent:
+	default:
+		return status.Errorf(codes.InvalidArgument, "Unknown alert event type %q.", p.Type)
+	}
+
+	return nil
+}
+
+// CreateAlertEvent persists alert lifecycle event.
+func CreateAlertEvent(q *reform.Querier, params *CreateAlertEventParams) (*AlertEvent, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	row := &AlertEvent{
+		ID:       "/alert_event_id/" + uuid.New().String(),
+		AlertID:  params.AlertID,
+		RuleID:   params.RuleID,
+		Type:     params.Type,
+		Summary:  params.Summary,
+		Severity: params.Severity,
+		Username: params.Username,
+		Comment:  params.Comment,
+	}
+
+	if err := row.SetLabels(params.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := q.Insert(row); err != nil {
+		return nil, errors.Wrap(err, "failed to create alert event")
+	}
+
+	return row, nil
+}
@@ -0,0 +1,158 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/percona-platform/saas/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestAlertEvents(t *testing.T) {
+	now, origNowF := models.Now(), models.Now
+	models.Now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		models.Now = origNowF
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	q := tx.Querier
+	critical := models.Severity(common.Critical)
+	create := func(alertID string, eventType models.AlertEventType, username string) *models.AlertEvent {
+		e, err := models.CreateAlertEvent(q, &models.CreateAlertEventParams{
+			AlertID:  alertID,
+			RuleID:   "/rule_id/" + alertID,
+			Type:     eventType,
+			Summary:  "summary " + alertID,
+			Severity: &critical,
+			Labels:   map[string]string{"service_name": alertID},
+			Username: username,
+		})
+		require.NoError(t, err)
+		return e
+	}
+
+	start := models.Now()
+	fired1 := create("1", models.FiredAlertEvent, "")
+	create("1", models.AcknowledgedAlertEvent, "admin")
+	create("2", models.FiredAlertEvent, "")
+	silenced2 := create("2", models.SilencedAlertEvent, "admin")
+	create("2", models.AcknowledgedAlertEvent, "admin")
+	create("3", models.FiredAlertEvent, "")
+	resolved3 := create("3", models.ResolvedAlertEvent, "")
+
+	t.Run("Create", func(t *testing.T) {
+		labels, err := fired1.GetLabels()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"service_name": "1"}, labels)
+		assert.Equal(t, &critical, fired1.Severity)
+
+		_, err = models.CreateAlertEvent(q, &models.CreateAlertEventParams{AlertID: "1", Type: "unknown"})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Unknown alert event type "unknown".`), err)
+	})
+
+	t.Run("FindOpen", func(t *testing.T) {
+		events, err := models.FindOpenAlertEvents(q)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		byAlert := map[string]*models.AlertEvent{}
+		for _, e := range events {
+			byAlert[e.AlertID] = e
+		}
+		assert.Equal(t, fired1.ID, byAlert["1"].ID)
+		assert.Equal(t, silenced2.ID, byAlert["2"].ID)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		events, err := models.FindAlertEvents(q, models.AlertEventsFilter{}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 7)
+		assert.Equal(t, resolved3.ID, events[0].ID, "newest first")
+
+		events, err = models.FindAlertEvents(q, models.AlertEventsFilter{
+			Types:    []models.AlertEventType{models.AcknowledgedAlertEvent},
+			Username: "admin",
+		}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "2", events[0].AlertID)
+		assert.Equal(t, "1", events[1].AlertID)
+
+		events, err = models.FindAlertEvents(q, models.AlertEventsFilter{AlertID: "2"}, 1, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.FiredAlertEvent, events[0].Type)
+
+		events, err = models.FindAlertEvents(q, models.AlertEventsFilter{
+			From: start.Add(2 * time.Second),
+			To:   start.Add(4 * time.Second),
+		}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := models.CountAlertEvents(q, models.AlertEventsFilter{RuleID: "/rule_id/3"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		count, err = models.CountAlertEvents(q, models.AlertEventsFilter{Severities: []models.Severity{critical}})
+		require.NoError(t, err)
+		assert.Equal(t, 7, count)
+	})
+
+	t.Run("RemoveOld", func(t *testing.T) {
+		fired3 := create("3", models.FiredAlertEvent, "")
+
+		n, err := models.RemoveOldAlertEvents(q, resolved3.CreatedAt)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n, "resolved event itself is not old enough")
+
+		n, err = models.RemoveOldAlertEvents(q, fired3.CreatedAt)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n, "only the resolved lifecycle of alert 3 is removed")
+
+		events, err := models.FindAlertEvents(q, models.AlertEventsFilter{AlertID: "3"}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, fired3.ID, events[0].ID)
+
+		count, err := models.CountAlertEvents(q, models.AlertEventsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 6, count)
+	})
+}
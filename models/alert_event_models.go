@@ -0,0 +1,84 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"gopkg.in/reform.v1"
+)
+
+//go:generate reform
+
+// AlertEventType represents type of the alert lifecycle event.
+type AlertEventType string
+
+// Alert lifecycle event types.
+const (
+	FiredAlertEvent        = AlertEventType("fired")
+	AcknowledgedAlertEvent = AlertEventType("acknowledged")
+	SilencedAlertEvent     = AlertEventType("silenced")
+	UnsilencedAlertEvent   = AlertEventType("unsilenced")
+	ResolvedAlertEvent     = AlertEventType("resolved")
+)
+
+// AlertEvent represents Integrated Alerting alert lifecycle event.
+// Alerts are identified by Alertmanager fingerprint, so the same alert may fire again after it's resolved.
+//reform:ia_alert_events
+type AlertEvent struct {
+	ID       string         `reform:"id,pk"`
+	AlertID  string         `reform:"alert_id"`
+	RuleID   string         `reform:"rule_id"`
+	Type     AlertEventType `reform:"type"`
+	Summary  string         `reform:"summary"`
+	Severity *Severity      `reform:"severity"`
+	Labels   []byte         `reform:"labels"`
+	// Username is a login of the user who caused the event, empty for events detected by pmm-managed.
+	Username  string    `reform:"username"`
+	Comment   string    `reform:"comment"`
+	CreatedAt time.Time `reform:"created_at"`
+}
+
+// BeforeInsert implements reform.BeforeInserter interface.
+func (e *AlertEvent) BeforeInsert() error {
+	e.CreatedAt = Now()
+
+	return nil
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (e *AlertEvent) AfterFind() error {
+	e.CreatedAt = e.CreatedAt.UTC()
+
+	return nil
+}
+
+// GetLabels decodes alert labels.
+func (e *AlertEvent) GetLabels() (map[string]string, error) {
+	return getLabels(e.Labels)
+}
+
+// SetLabels encodes alert labels.
+func (e *AlertEvent) SetLabels(m map[string]string) error {
+	return setLabels(m, &e.Labels)
+}
+
+// check interfaces.
+var (
+	_ reform.BeforeInserter = (*AlertEvent)(nil)
+	_ reform.AfterFinder    = (*AlertEvent)(nil)
+)
@@ -0,0 +1,248 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+)
+
+// FindAlertRoutes returns all alert routes ordered by their positions.
+func FindAlertRoutes(q *reform.Querier) ([]*AlertRoute, error) {
+	rows, err := q.SelectAllFrom(AlertRouteTable, "ORDER BY position, created_at")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select alert routes")
+	}
+
+	routes := make([]*AlertRoute, len(rows))
+	for i, s := range rows {
+		routes[i] = s.(*AlertRoute)
+	}
+
+	return routes, nil
+}
+
+// FindAlertRouteByID finds AlertRoute by ID.
+func FindAlertRouteByID(q *reform.Querier, id string) (*AlertRoute, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "Empty alert route ID.")
+	}
+
+	route := &AlertRoute{ID: id}
+	switch err := q.Reload(route); err {
+	case nil:
+		return route, nil
+	case reform.ErrNoRows:
+		return nil, status.Errorf(codes.NotFound, "Alert route with ID %q not found.", id)
+	default:
+		return nil, errors.WithStack(err)
+	}
+}
+
+// AlertRouteParams are params for creating new or changing existing AlertRoute.
+type AlertRouteParams struct {
+	Name string
+	// ParentID is empty for top-level routes.
+	ParentID        string
+	Position        int
+	Disabled        bool
+	Filters         Filters
+	GroupBy         []string
+	GroupWait       time.Duration
+	GroupInterval   time.Duration
+	RepeatInterval  time.Duration
+	Continue        bool
+	EscalationSteps EscalationSteps
+}
+
+// AlertRouteMatchers returns Alertmanager match and match_re route matchers for given alert route filters.
+// Alertmanager routes can't match other filter types, so error is returned for them.
+func AlertRouteMatchers(filters Filters) (match, matchRE map[string]string, err error) {
+	for _, f := range filters {
+		if err = f.Validate(); err != nil {
+			return nil, nil, err
+		}
+
+		switch f.Type {
+		case Equal:
+			if match == nil {
+				match = make(map[string]string)
+			}
+			match[f.Key] = f.Val
+		case Regex:
+			if matchRE == nil {
+				matchRE = make(map[string]string)
+			}
+			matchRE[f.Key] = f.Val
+		default:
+			return nil, nil, status.Errorf(codes.InvalidArgument, "Alert route filter type %q is not supported.", f.Type)
+		}
+	}
+
+	return match, matchRE, nil
+}
+
+func (p *AlertRouteParams) validate(q *reform.Querier, id string) error {
+	if p.Name == "" {
+		return status.Error(codes.InvalidArgument, "Alert route name can't be empty.")
+	}
+
+	if _, _, err := AlertRouteMatchers(p.Filters); err != nil {
+		return err
+	}
+
+	// "..." groups by all labels, Alertmanager doesn't accept it with other labels
+	for _, l := range p.GroupBy {
+		if (l != "..." || len(p.GroupBy) != 1) && !labelNameRE.MatchString(l) {
+			return status.Errorf(codes.InvalidArgument, "Invalid alert route group by label %q.", l)
+		}
+	}
+
+	if p.GroupWait < 0 || p.GroupInterval < 0 || p.RepeatInterval < 0 {
+		return status.Error(codes.InvalidArgument, "Alert route intervals can't be negative.")
+	}
+
+	var channelIDs []string
+	for i, step := range p.EscalationSteps {
+		if len(step.ChannelIDs) == 0 {
+			return status.Errorf(codes.InvalidArgument, "Escalation step %d should have at least one channel.", i+1)
+		}
+		if step.Delay < 0 || (i > 0 && step.Delay <= p.EscalationSteps[i-1].Delay) {
+			return status.Error(codes.InvalidArgument, "Escalation steps delays should be non-negative and in increasing order.")
+		}
+		// zero delay is replaced with group_wait by Alertmanager, so shorter delay would break the order of steps
+		if step.Delay != 0 && step.Delay <= p.GroupWait {
+			return status.Errorf(codes.InvalidArgument, "Escalation step %d delay should be longer than group wait %s.", i+1, p.GroupWait)
+		}
+		channelIDs = append(channelIDs, step.ChannelIDs...)
+	}
+
+	channelIDs = deduplicateStrings(channelIDs)
+	channels, err := FindChannelsByIDs(q, channelIDs)
+	if err != nil {
+		return err
+	}
+	if len(channelIDs) != len(channels) {
+		missingChannelsIDs := findMissingChannels(channelIDs, channels)
+		return status.Errorf(codes.NotFound, "Failed to find all required channels: %v.", missingChannelsIDs)
+	}
+
+	// check that parent exists and the route isn't moved under itself
+	for parentID := p.ParentID; parentID != ""; {
+		if parentID == id {
+			return status.Error(codes.InvalidArgument, "Alert route can't be a descendant of itself.")
+		}
+
+		parent, err := FindAlertRouteByID(q, parentID)
+		if err != nil {
+			return err
+		}
+		parentID = pointer.GetString(parent.ParentID)
+	}
+
+	return nil
+}
+
+// CreateAlertRoute persists alert route.
+func CreateAlertRoute(q *reform.Querier, params *AlertRouteParams) (*AlertRoute, error) {
+	id := "/route_id/" + uuid.New().String()
+	if err := params.validate(q, id); err != nil {
+		return nil, err
+	}
+
+	route := &AlertRoute{ID: id}
+	params.apply(route)
+	if err := q.Insert(route); err != nil {
+		return nil, errors.Wrap(err, "failed to create alert route")
+	}
+
+	return route, nil
+}
+
+// ChangeAlertRoute updates existing alert route.
+func ChangeAlertRoute(q *reform.Querier, id string, params *AlertRouteParams) (*AlertRoute, error) {
+	route, err := FindAlertRouteByID(q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = params.validate(q, id); err != nil {
+		return nil, err
+	}
+
+	params.apply(route)
+	if err = q.Update(route); err != nil {
+		return nil, errors.Wrap(err, "failed to change alert route")
+	}
+
+	return route, nil
+}
+
+func (p *AlertRouteParams) apply(route *AlertRoute) {
+	route.Name = p.Name
+	route.ParentID = pointer.ToStringOrNil(p.ParentID)
+	route.Position = p.Position
+	route.Disabled = p.Disabled
+	route.Filters = p.Filters
+	route.GroupBy = p.GroupBy
+	route.GroupWait = p.GroupWait
+	route.GroupInterval = p.GroupInterval
+	route.RepeatInterval = p.RepeatInterval
+	route.Continue = p.Continue
+	route.EscalationSteps = p.EscalationSteps
+}
+
+// RemoveAlertRoute removes alert route with specified ID. Routes with child routes can't be removed.
+func RemoveAlertRoute(q *reform.Querier, id string) error {
+	route, err := FindAlertRouteByID(q, id)
+	if err != nil {
+		return err
+	}
+
+	switch _, err = q.SelectOneFrom(AlertRouteTable, "WHERE parent_id = $1", id); err {
+	case nil:
+		return status.Errorf(codes.FailedPrecondition, `You can't delete the "%s" alert route when it has child routes.`, route.Name)
+	case reform.ErrNoRows:
+	default:
+		return errors.WithStack(err)
+	}
+
+	if err = q.Delete(&AlertRoute{ID: id}); err != nil {
+		return errors.Wrap(err, "failed to delete alert route")
+	}
+
+	return nil
+}
+
+func channelInUseByAlertRoute(q *reform.Querier, id string) (bool, error) {
+	_, err := q.SelectOneFrom(AlertRouteTable,
+		"WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(escalation_steps) AS step WHERE step->'channel_ids' ? $1)", id)
+	switch err {
+	case nil:
+		return true, nil
+	case reform.ErrNoRows:
+		return false, nil
+	default:
+		return false, errors.WithStack(err)
+	}
+}
@@ -0,0 +1,138 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestAlertRoutes(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	q := tx.Querier
+	channel, err := models.CreateChannel(q, &models.CreateChannelParams{
+		Summary: "some summary",
+		SlackConfig: &models.SlackConfig{
+			Channel: "general",
+		},
+	})
+	require.NoError(t, err)
+
+	parent, err := models.CreateAlertRoute(q, &models.AlertRouteParams{
+		Name:    "production",
+		Filters: models.Filters{{Type: models.Equal, Key: "environment", Val: "prod"}},
+		GroupBy: []string{"alertname"},
+		EscalationSteps: models.EscalationSteps{
+			{ChannelIDs: []string{channel.ID}},
+			{ChannelIDs: []string{channel.ID}, Delay: 30 * time.Minute},
+		},
+	})
+	require.NoError(t, err)
+
+	child, err := models.CreateAlertRoute(q, &models.AlertRouteParams{
+		Name:     "critical",
+		ParentID: parent.ID,
+		Filters:  models.Filters{{Type: models.Regex, Key: "severity", Val: "critical|emergency"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("find", func(t *testing.T) {
+		routes, err := models.FindAlertRoutes(q)
+		require.NoError(t, err)
+		require.Len(t, routes, 2)
+		assert.Equal(t, parent.ID, routes[0].ID)
+		assert.Equal(t, []string{"alertname"}, []string(routes[0].GroupBy))
+		assert.Equal(t, parent.EscalationSteps, routes[0].EscalationSteps)
+		assert.Equal(t, child.ID, routes[1].ID)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := models.CreateAlertRoute(q, &models.AlertRouteParams{
+			Name: "invalid",
+			EscalationSteps: models.EscalationSteps{
+				{ChannelIDs: []string{channel.ID}, Delay: time.Hour},
+				{ChannelIDs: []string{channel.ID}, Delay: time.Minute},
+			},
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Escalation steps delays should be non-negative and in increasing order."), err)
+
+		_, err = models.CreateAlertRoute(q, &models.AlertRouteParams{
+			Name:      "invalid",
+			GroupWait: time.Hour,
+			EscalationSteps: models.EscalationSteps{
+				{ChannelIDs: []string{channel.ID}},
+				{ChannelIDs: []string{channel.ID}, Delay: time.Minute},
+			},
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Escalation step 2 delay should be longer than group wait 1h0m0s."), err)
+
+		_, err = models.CreateAlertRoute(q, &models.AlertRouteParams{
+			Name:    "invalid",
+			Filters: models.Filters{{Type: models.Equal, Key: "service-name", Val: "mysql"}},
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid filter key "service-name".`), err)
+
+		_, err = models.CreateAlertRoute(q, &models.AlertRouteParams{
+			Name:    "invalid",
+			Filters: models.Filters{{Type: models.NotEqual, Key: "service_name", Val: "mysql"}},
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Alert route filter type "!=" is not supported.`), err)
+
+		_, err = models.CreateAlertRoute(q, &models.AlertRouteParams{
+			Name:    "invalid",
+			GroupBy: []string{"alertname", "..."},
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid alert route group by label "...".`), err)
+
+		_, err = models.ChangeAlertRoute(q, parent.ID, &models.AlertRouteParams{
+			Name:     parent.Name,
+			ParentID: child.ID,
+		})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Alert route can't be a descendant of itself."), err)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := models.RemoveChannel(q, channel.ID)
+		tests.AssertGRPCError(t, status.New(codes.FailedPrecondition, `You can't delete the "some summary" channel when it's being used by an alert route.`), err)
+
+		err = models.RemoveAlertRoute(q, parent.ID)
+		tests.AssertGRPCError(t, status.New(codes.FailedPrecondition, `You can't delete the "production" alert route when it has child routes.`), err)
+
+		require.NoError(t, models.RemoveAlertRoute(q, child.ID))
+		require.NoError(t, models.RemoveAlertRoute(q, parent.ID))
+		require.NoError(t, models.RemoveChannel(q, channel.ID))
+	})
+}
@@ -0,0 +1,99 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+)
+
+//go:generate reform
+
+// EscalationStep represents notification channels notified when alert is firing for Delay
+// and wasn't acknowledged (silenced) during that time.
+//
+// Delay is Alertmanager group_wait of the step, so it's counted from the moment the alert group is created,
+// not from the start of each alert; alerts joining already notified group are sent with group_interval.
+// Zero delay means the route group_wait, other delays should be longer than it.
+type EscalationStep struct {
+	ChannelIDs []string      `json:"channel_ids"`
+	Delay      time.Duration `json:"delay"`
+}
+
+// EscalationSteps represents escalation steps of the alert route in order of increasing delay.
+type EscalationSteps []EscalationStep
+
+// Value implements database/sql/driver Valuer interface.
+func (s EscalationSteps) Value() (driver.Value, error) { return jsonValue(s) }
+
+// Scan implements database/sql Scanner interface.
+func (s *EscalationSteps) Scan(src interface{}) error { return jsonScan(s, src) }
+
+// AlertRoute represents a node of Integrated Alerting routing tree.
+// Alerts matching route filters are grouped by GroupBy labels and sent to escalation steps channels.
+// Routes with nil ParentID are top-level ones, siblings are matched in order of their positions.
+// Alert matching a child route is handled by it, so child routes without escalation steps use the parent's ones.
+//reform:ia_routes
+type AlertRoute struct {
+	ID              string          `reform:"id,pk"`
+	Name            string          `reform:"name"`
+	ParentID        *string         `reform:"parent_id"`
+	Position        int             `reform:"position"`
+	Disabled        bool            `reform:"disabled"`
+	Filters         Filters         `reform:"filters"`
+	GroupBy         pq.StringArray  `reform:"group_by"`
+	GroupWait       time.Duration   `reform:"group_wait"`
+	GroupInterval   time.Duration   `reform:"group_interval"`
+	RepeatInterval  time.Duration   `reform:"repeat_interval"`
+	Continue        bool            `reform:"continue"`
+	EscalationSteps EscalationSteps `reform:"escalation_steps"`
+	CreatedAt       time.Time       `reform:"created_at"`
+	UpdatedAt       time.Time       `reform:"updated_at"`
+}
+
+// BeforeInsert implements reform.BeforeInserter interface.
+func (r *AlertRoute) BeforeInsert() error {
+	now := Now()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate implements reform.BeforeUpdater interface.
+func (r *AlertRoute) BeforeUpdate() error {
+	r.UpdatedAt = Now()
+
+	return nil
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (r *AlertRoute) AfterFind() error {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+
+	return nil
+}
+
+// check interfaces.
+var (
+	_ reform.BeforeInserter = (*AlertRoute)(nil)
+	_ reform.BeforeUpdater  = (*AlertRoute)(nil)
+	_ reform.AfterFinder    = (*AlertRoute)(nil)
+)
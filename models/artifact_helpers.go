@@ -19,6 +19,7 @@ package models
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -266,9 +267,16 @@ func CreateArtifact(q *reform.Querier, params CreateArtifactParams) (*Artifact,
 
 // UpdateArtifactParams are params for changing existing artifact.
 type UpdateArtifactParams struct {
-	ServiceID  *string
-	Status     *BackupStatus
-	ScheduleID *string
+	ServiceID          *string
+	Status             *BackupStatus
+	ScheduleID         *string
+	VerificationStatus *VerificationStatus
+	VerifiedAt         *time.Time
+	FileChecksums      ArtifactFileChecksums
+	PMMAgentID         *string
+	Size               *int64
+	StartedAt          *time.Time
+	FinishedAt         *time.Time
 }
 
 // UpdateArtifact updates existing artifact.
@@ -286,6 +294,30 @@ func UpdateArtifact(q *reform.Querier, artifactID string, params UpdateArtifactP
 	if params.ScheduleID != nil {
 		row.ScheduleID = *params.ScheduleID
 	}
+	if params.VerificationStatus != nil {
+		if err := params.VerificationStatus.Validate(); err != nil {
+			return nil, err
+		}
+		row.VerificationStatus = *params.VerificationStatus
+	}
+	if params.VerifiedAt != nil {
+		row.VerifiedAt = params.VerifiedAt
+	}
+	if params.FileChecksums != nil {
+		row.FileChecksums = params.FileChecksums
+	}
+	if params.PMMAgentID != nil {
+		row.PMMAgentID = *params.PMMAgentID
+	}
+	if params.Size != nil {
+		row.Size = *params.Size
+	}
+	if params.StartedAt != nil {
+		row.StartedAt = params.StartedAt
+	}
+	if params.FinishedAt != nil {
+		row.FinishedAt = params.FinishedAt
+	}
 
 	if err := q.Update(row); err != nil {
 		return nil, errors.Wrap(err, "failed to update backup artifact")
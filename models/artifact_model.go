@@ -17,8 +17,10 @@
 package models
 
 import (
+	"database/sql/driver"
 	"time"
 
+	"github.com/AlekSi/pointer"
 	"gopkg.in/reform.v1"
 )
 
@@ -117,22 +119,68 @@ func (m BackupMode) Validate() error {
 	return nil
 }
 
+// VerificationStatus shows result of artifact integrity verification.
+type VerificationStatus string
+
+// VerificationStatus statuses.
+const (
+	NotVerifiedVerificationStatus VerificationStatus = ""
+	SuccessVerificationStatus     VerificationStatus = "success"
+	FailedVerificationStatus      VerificationStatus = "failed"
+)
+
+// Validate validates verification status.
+func (vs VerificationStatus) Validate() error {
+	switch vs {
+	case NotVerifiedVerificationStatus:
+	case SuccessVerificationStatus:
+	case FailedVerificationStatus:
+	default:
+		return NewInvalidArgumentError("invalid verification status '%s'", vs)
+	}
+
+	return nil
+}
+
+// VerificationStatusPointer returns a pointer of verification status.
+func VerificationStatusPointer(status VerificationStatus) *VerificationStatus {
+	return &status
+}
+
+// ArtifactFileChecksums maps artifact file names (relative to the artifact directory) to their checksums.
+// Checksums of snapshot artifacts are recorded by the first successful verification and never replaced;
+// checksums of PITR artifact files are recorded by the first successful verification after they appear.
+type ArtifactFileChecksums map[string]string
+
+// Value implements database/sql/driver Valuer interface.
+func (c ArtifactFileChecksums) Value() (driver.Value, error) { return jsonValue(c) }
+
+// Scan implements database/sql Scanner interface.
+func (c *ArtifactFileChecksums) Scan(src interface{}) error { return jsonScan(c, src) }
+
 // Artifact represents result of a backup.
 //reform:artifacts
 type Artifact struct {
-	ID         string       `reform:"id,pk"`
-	Name       string       `reform:"name"`
-	Vendor     string       `reform:"vendor"`
-	DBVersion  string       `reform:"db_version"`
-	LocationID string       `reform:"location_id"`
-	ServiceID  string       `reform:"service_id"`
-	DataModel  DataModel    `reform:"data_model"`
-	Mode       BackupMode   `reform:"mode"`
-	Status     BackupStatus `reform:"status"`
-	Type       ArtifactType `reform:"type"`
-	ScheduleID string       `reform:"schedule_id"`
-	CreatedAt  time.Time    `reform:"created_at"`
-	UpdatedAt  time.Time    `reform:"updated_at"`
+	ID                 string                `reform:"id,pk"`
+	Name               string                `reform:"name"`
+	Vendor             string                `reform:"vendor"`
+	DBVersion          string                `reform:"db_version"`
+	LocationID         string                `reform:"location_id"`
+	ServiceID          string                `reform:"service_id"`
+	DataModel          DataModel             `reform:"data_model"`
+	Mode               BackupMode            `reform:"mode"`
+	Status             BackupStatus          `reform:"status"`
+	Type               ArtifactType          `reform:"type"`
+	ScheduleID         string                `reform:"schedule_id"`
+	VerificationStatus VerificationStatus    `reform:"verification_status"`
+	VerifiedAt         *time.Time            `reform:"verified_at"`
+	FileChecksums      ArtifactFileChecksums `reform:"file_checksums"`
+	PMMAgentID         string                `reform:"pmm_agent_id"`
+	Size               int64                 `reform:"size"`
+	StartedAt          *time.Time            `reform:"started_at"`
+	FinishedAt         *time.Time            `reform:"finished_at"`
+	CreatedAt          time.Time             `reform:"created_at"`
+	UpdatedAt          time.Time             `reform:"updated_at"`
 }
 
 // BeforeInsert implements reform.BeforeInserter interface.
@@ -153,9 +201,26 @@ func (s *Artifact) BeforeUpdate() error {
 func (s *Artifact) AfterFind() error {
 	s.CreatedAt = s.CreatedAt.UTC()
 	s.UpdatedAt = s.UpdatedAt.UTC()
+	if s.VerifiedAt != nil {
+		s.VerifiedAt = pointer.ToTime(s.VerifiedAt.UTC())
+	}
+	if s.StartedAt != nil {
+		s.StartedAt = pointer.ToTime(s.StartedAt.UTC())
+	}
+	if s.FinishedAt != nil {
+		s.FinishedAt = pointer.ToTime(s.FinishedAt.UTC())
+	}
 	return nil
 }
 
+// Duration returns time spent on making the artifact, zero if it's not finished yet or start time is unknown.
+func (s *Artifact) Duration() time.Duration {
+	if s.StartedAt == nil || s.FinishedAt == nil {
+		return 0
+	}
+	return s.FinishedAt.Sub(*s.StartedAt)
+}
+
 // check interfaces.
 var (
 	_ reform.BeforeInserter = (*Artifact)(nil)
@@ -39,6 +39,13 @@ func (v *artifactTableType) Columns() []string {
 		"status",
 		"type",
 		"schedule_id",
+		"verification_status",
+		"verified_at",
+		"file_checksums",
+		"pmm_agent_id",
+		"size",
+		"started_at",
+		"finished_at",
 		"created_at",
 		"updated_at",
 	}
@@ -76,6 +83,13 @@ var ArtifactTable = &artifactTableType{
 			{Name: "Status", Type: "BackupStatus", Column: "status"},
 			{Name: "Type", Type: "ArtifactType", Column: "type"},
 			{Name: "ScheduleID", Type: "string", Column: "schedule_id"},
+			{Name: "VerificationStatus", Type: "VerificationStatus", Column: "verification_status"},
+			{Name: "VerifiedAt", Type: "*time.Time", Column: "verified_at"},
+			{Name: "FileChecksums", Type: "ArtifactFileChecksums", Column: "file_checksums"},
+			{Name: "PMMAgentID", Type: "string", Column: "pmm_agent_id"},
+			{Name: "Size", Type: "int64", Column: "size"},
+			{Name: "StartedAt", Type: "*time.Time", Column: "started_at"},
+			{Name: "FinishedAt", Type: "*time.Time", Column: "finished_at"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
 		},
@@ -86,7 +100,7 @@ var ArtifactTable = &artifactTableType{
 
 // String returns a string representation of this struct or record.
 func (s Artifact) String() string {
-	res := make([]string, 13)
+	res := make([]string, 20)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "Name: " + reform.Inspect(s.Name, true)
 	res[2] = "Vendor: " + reform.Inspect(s.Vendor, true)
@@ -98,8 +112,15 @@ func (s Artifact) String() string {
 	res[8] = "Status: " + reform.Inspect(s.Status, true)
 	res[9] = "Type: " + reform.Inspect(s.Type, true)
 	res[10] = "ScheduleID: " + reform.Inspect(s.ScheduleID, true)
-	res[11] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[12] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[11] = "VerificationStatus: " + reform.Inspect(s.VerificationStatus, true)
+	res[12] = "VerifiedAt: " + reform.Inspect(s.VerifiedAt, true)
+	res[13] = "FileChecksums: " + reform.Inspect(s.FileChecksums, true)
+	res[14] = "PMMAgentID: " + reform.Inspect(s.PMMAgentID, true)
+	res[15] = "Size: " + reform.Inspect(s.Size, true)
+	res[16] = "StartedAt: " + reform.Inspect(s.StartedAt, true)
+	res[17] = "FinishedAt: " + reform.Inspect(s.FinishedAt, true)
+	res[18] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[19] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -118,6 +139,13 @@ func (s *Artifact) Values() []interface{} {
 		s.Status,
 		s.Type,
 		s.ScheduleID,
+		s.VerificationStatus,
+		s.VerifiedAt,
+		s.FileChecksums,
+		s.PMMAgentID,
+		s.Size,
+		s.StartedAt,
+		s.FinishedAt,
 		s.CreatedAt,
 		s.UpdatedAt,
 	}
@@ -138,6 +166,13 @@ func (s *Artifact) Pointers() []interface{} {
 		&s.Status,
 		&s.Type,
 		&s.ScheduleID,
+		&s.VerificationStatus,
+		&s.VerifiedAt,
+		&s.FileChecksums,
+		&s.PMMAgentID,
+		&s.Size,
+		&s.StartedAt,
+		&s.FinishedAt,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	}
@@ -34,6 +34,9 @@ const (
 	PagerDuty = ChannelType("pagerduty")
 	Slack     = ChannelType("slack")
 	WebHook   = ChannelType("webhook")
+	OpsGenie  = ChannelType("opsgenie")
+	MSTeams   = ChannelType("msteams")
+	Telegram  = ChannelType("telegram")
 )
 
 // Channel represents Integrated Alerting Notification Channel configuration.
@@ -47,6 +50,11 @@ type Channel struct {
 	PagerDutyConfig *PagerDutyConfig `reform:"pagerduty_config"`
 	SlackConfig     *SlackConfig     `reform:"slack_config"`
 	WebHookConfig   *WebHookConfig   `reform:"webhook_config"`
+	OpsGenieConfig  *OpsGenieConfig  `reform:"opsgenie_config"`
+	MSTeamsConfig   *MSTeamsConfig   `reform:"msteams_config"`
+	TelegramConfig  *TelegramConfig  `reform:"telegram_config"`
+
+	NotificationTemplate *NotificationTemplate `reform:"notification_template"`
 
 	Disabled bool `reform:"disabled"`
 
@@ -80,8 +88,8 @@ func (c *Channel) AfterFind() error {
 
 // EmailConfig is email notification channel configuration.
 type EmailConfig struct {
-	SendResolved bool     `json:"send_resolved"`
-	To           []string `json:"to"`
+	SendResolved bool     `json:"send_resolved" yaml:"send_resolved"`
+	To           []string `json:"to" yaml:"to"`
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
@@ -92,9 +100,9 @@ func (c *EmailConfig) Scan(src interface{}) error { return jsonScan(c, src) }
 
 // PagerDutyConfig represents PagerDuty channel configuration.
 type PagerDutyConfig struct {
-	SendResolved bool   `json:"send_resolved"`
-	RoutingKey   string `json:"routing_key,omitempty"`
-	ServiceKey   string `json:"service_key,omitempty"`
+	SendResolved bool   `json:"send_resolved" yaml:"send_resolved"`
+	RoutingKey   string `json:"routing_key,omitempty" yaml:"routing_key,omitempty"`
+	ServiceKey   string `json:"service_key,omitempty" yaml:"service_key,omitempty"`
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
@@ -105,8 +113,8 @@ func (c *PagerDutyConfig) Scan(src interface{}) error { return jsonScan(c, src)
 
 // SlackConfig is slack notification channel configuration.
 type SlackConfig struct {
-	SendResolved bool   `json:"send_resolved"`
-	Channel      string `json:"channel"`
+	SendResolved bool   `json:"send_resolved" yaml:"send_resolved"`
+	Channel      string `json:"channel" yaml:"channel"`
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
@@ -117,10 +125,10 @@ func (c *SlackConfig) Scan(src interface{}) error { return jsonScan(c, src) }
 
 // WebHookConfig is webhook notification channel configuration.
 type WebHookConfig struct {
-	SendResolved bool        ` json:"send_resolved"`
-	URL          string      ` json:"url"`
-	HTTPConfig   *HTTPConfig ` json:"http_config,omitempty"`
-	MaxAlerts    int32       ` json:"max_alerts"`
+	SendResolved bool        ` json:"send_resolved" yaml:"send_resolved"`
+	URL          string      ` json:"url" yaml:"url"`
+	HTTPConfig   *HTTPConfig ` json:"http_config,omitempty" yaml:"http_config,omitempty"`
+	MaxAlerts    int32       ` json:"max_alerts" yaml:"max_alerts"`
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
@@ -129,32 +137,86 @@ func (c WebHookConfig) Value() (driver.Value, error) { return jsonValue(c) }
 // Scan implements database/sql.Scanner interface. Should be defined on the pointer.
 func (c *WebHookConfig) Scan(src interface{}) error { return jsonScan(c, src) }
 
+// OpsGenieConfig is Opsgenie notification channel configuration.
+type OpsGenieConfig struct {
+	SendResolved bool     `json:"send_resolved" yaml:"send_resolved"`
+	APIKey       string   `json:"api_key" yaml:"api_key"`
+	APIURL       string   `json:"api_url,omitempty" yaml:"api_url,omitempty"`
+	Priority     string   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags         []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (c OpsGenieConfig) Value() (driver.Value, error) { return jsonValue(c) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (c *OpsGenieConfig) Scan(src interface{}) error { return jsonScan(c, src) }
+
+// MSTeamsConfig is Microsoft Teams notification channel configuration.
+type MSTeamsConfig struct {
+	SendResolved bool   `json:"send_resolved" yaml:"send_resolved"`
+	WebhookURL   string `json:"webhook_url" yaml:"webhook_url"`
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (c MSTeamsConfig) Value() (driver.Value, error) { return jsonValue(c) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (c *MSTeamsConfig) Scan(src interface{}) error { return jsonScan(c, src) }
+
+// TelegramConfig is Telegram notification channel configuration.
+type TelegramConfig struct {
+	SendResolved bool   `json:"send_resolved" yaml:"send_resolved"`
+	BotToken     string `json:"bot_token" yaml:"bot_token"`
+	ChatID       int64  `json:"chat_id" yaml:"chat_id"`
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (c TelegramConfig) Value() (driver.Value, error) { return jsonValue(c) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (c *TelegramConfig) Scan(src interface{}) error { return jsonScan(c, src) }
+
+// NotificationTemplate overrides default notification text with Alertmanager Go templates.
+// Title is executed over the whole notification (.Status, .Alerts, .CommonLabels, etc.);
+// text is executed for each alert of notification over its .Labels and .Annotations.
+type NotificationTemplate struct {
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	Text  string `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (t NotificationTemplate) Value() (driver.Value, error) { return jsonValue(t) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (t *NotificationTemplate) Scan(src interface{}) error { return jsonScan(t, src) }
+
 // HTTPConfig is HTTP connection configuration.
 type HTTPConfig struct {
-	BasicAuth       *HTTPBasicAuth `json:"basic_auth,omitempty"`
-	BearerToken     string         `json:"bearer_token,omitempty"`
-	BearerTokenFile string         `json:"bearer_token_file,omitempty"`
-	TLSConfig       *TLSConfig     `json:"tls_config,omitempty"`
-	ProxyURL        string         `json:"proxy_url,omitempty"`
+	BasicAuth       *HTTPBasicAuth `json:"basic_auth,omitempty" yaml:"basic_auth,omitempty"`
+	BearerToken     string         `json:"bearer_token,omitempty" yaml:"bearer_token,omitempty"`
+	BearerTokenFile string         `json:"bearer_token_file,omitempty" yaml:"bearer_token_file,omitempty"`
+	TLSConfig       *TLSConfig     `json:"tls_config,omitempty" yaml:"tls_config,omitempty"`
+	ProxyURL        string         `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
 }
 
 // HTTPBasicAuth is HTTP basic authentication configuration.
 type HTTPBasicAuth struct {
-	Username     string `json:"username,omitempty"`
-	Password     string `json:"password,omitempty"`
-	PasswordFile string `json:"password_file,omitempty"`
+	Username     string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password     string `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordFile string `json:"password_file,omitempty" yaml:"password_file,omitempty"`
 }
 
 // TLSConfig is TLS configuration.
 type TLSConfig struct {
-	CAFile             string `json:"ca_file,omitempty"`
-	CertFile           string `json:"cert_file,omitempty"`
-	KeyFile            string `json:"key_file,omitempty"`
-	ServerName         string `json:"server_name,omitempty"`
-	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
-	CAFileContent      string `json:"ca_file_content,omitempty"`
-	CertFileContent    string `json:"cert_file_content,omitempty"`
-	KeyFileContent     string `json:"key_file_content,omitempty"`
+	CAFile             string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+	CAFileContent      string `json:"ca_file_content,omitempty" yaml:"ca_file_content,omitempty"`
+	CertFileContent    string `json:"cert_file_content,omitempty" yaml:"cert_file_content,omitempty"`
+	KeyFileContent     string `json:"key_file_content,omitempty" yaml:"key_file_content,omitempty"`
 }
 
 // check interfaces.
@@ -18,10 +18,15 @@ package models
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gopkg.in/reform.v1"
@@ -191,6 +196,103 @@ func FindChannelsByIDs(q *reform.Querier, ids []string) ([]*Channel, error) {
 	return res, nil
 }
 
+func checkOpsGenieConfig(c *OpsGenieConfig) error {
+	if c == nil {
+		return status.Error(codes.InvalidArgument, "Opsgenie config is empty.")
+	}
+
+	if c.APIKey == "" {
+		return status.Error(codes.InvalidArgument, "Opsgenie API key field is empty.")
+	}
+
+	switch c.Priority {
+	case "", "P1", "P2", "P3", "P4", "P5":
+	default:
+		return status.Errorf(codes.InvalidArgument, "Invalid Opsgenie priority %q, should be one of P1-P5.", c.Priority)
+	}
+
+	return nil
+}
+
+func checkMSTeamsConfig(c *MSTeamsConfig) error {
+	if c == nil {
+		return status.Error(codes.InvalidArgument, "MS Teams config is empty.")
+	}
+
+	if c.WebhookURL == "" {
+		return status.Error(codes.InvalidArgument, "MS Teams webhook url field is empty.")
+	}
+
+	return nil
+}
+
+func checkTelegramConfig(c *TelegramConfig) error {
+	if c == nil {
+		return status.Error(codes.InvalidArgument, "Telegram config is empty.")
+	}
+
+	if c.BotToken == "" {
+		return status.Error(codes.InvalidArgument, "Telegram bot token field is empty.")
+	}
+
+	if c.ChatID == 0 {
+		return status.Error(codes.InvalidArgument, "Telegram chat ID field is empty.")
+	}
+
+	return nil
+}
+
+// checkNotificationTemplate checks that notification template can be executed by Alertmanager.
+func checkNotificationTemplate(t *NotificationTemplate) error {
+	if t == nil {
+		return nil
+	}
+
+	tmpl, err := template.FromGlobs()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmpl.ExternalURL = new(url.URL)
+
+	now := time.Now()
+	data := tmpl.Data("test", model.LabelSet{"rule_id": "/rule_id/test"}, &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: "/rule_id/test",
+				"rule_id":            "/rule_id/test",
+				"severity":           "notice",
+			},
+			Annotations: model.LabelSet{
+				"summary":     "Test alert summary.",
+				"description": "Test alert description.",
+				"rule":        "Test rule",
+			},
+			StartsAt: now,
+			EndsAt:   now.Add(time.Minute),
+		},
+	})
+
+	if _, err = tmpl.ExecuteTextString(t.Title, data); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid notification title template: %s.", err)
+	}
+
+	// text is executed for each alert
+	if _, err = tmpl.ExecuteTextString("{{ range .Alerts }}"+t.Text+"{{ end }}", data); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid notification text template: %s.", err)
+	}
+
+	return nil
+}
+
+// checkChannelNotificationTemplate checks notification template of the channel with given type.
+func checkChannelNotificationTemplate(channelType ChannelType, t *NotificationTemplate) error {
+	if t != nil && channelType == WebHook {
+		return status.Error(codes.InvalidArgument, "Webhook channel doesn't support notification template.")
+	}
+
+	return checkNotificationTemplate(t)
+}
+
 // CreateChannelParams are params for creating new channel.
 type CreateChannelParams struct {
 	Summary string
@@ -199,6 +301,11 @@ type CreateChannelParams struct {
 	PagerDutyConfig *PagerDutyConfig
 	SlackConfig     *SlackConfig
 	WebHookConfig   *WebHookConfig
+	OpsGenieConfig  *OpsGenieConfig
+	MSTeamsConfig   *MSTeamsConfig
+	TelegramConfig  *TelegramConfig
+
+	NotificationTemplate *NotificationTemplate
 
 	Disabled bool
 }
@@ -211,12 +318,26 @@ func CreateChannel(q *reform.Querier, params *CreateChannelParams) (*Channel, er
 		return nil, err
 	}
 
+	row, err := NewChannel(params)
+	if err != nil {
+		return nil, err
+	}
+	row.ID = id
+
+	if err := q.Insert(row); err != nil {
+		return nil, errors.Wrap(err, "failed to create notifications channel")
+	}
+
+	return row, nil
+}
+
+// NewChannel validates given params and returns notification channel without ID, it isn't persisted.
+func NewChannel(params *CreateChannelParams) (*Channel, error) {
 	if params.Summary == "" {
 		return nil, status.Error(codes.InvalidArgument, "Channel summary can't be empty.")
 	}
 
 	row := &Channel{
-		ID:       id,
 		Summary:  params.Summary,
 		Disabled: params.Disabled,
 	}
@@ -263,13 +384,47 @@ func CreateChannel(q *reform.Querier, params *CreateChannelParams) (*Channel, er
 		row.WebHookConfig = params.WebHookConfig
 	}
 
+	if params.OpsGenieConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkOpsGenieConfig(params.OpsGenieConfig); err != nil {
+			return nil, err
+		}
+		row.Type = OpsGenie
+		row.OpsGenieConfig = params.OpsGenieConfig
+	}
+
+	if params.MSTeamsConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkMSTeamsConfig(params.MSTeamsConfig); err != nil {
+			return nil, err
+		}
+		row.Type = MSTeams
+		row.MSTeamsConfig = params.MSTeamsConfig
+	}
+
+	if params.TelegramConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkTelegramConfig(params.TelegramConfig); err != nil {
+			return nil, err
+		}
+		row.Type = Telegram
+		row.TelegramConfig = params.TelegramConfig
+	}
+
 	if row.Type == "" {
 		return nil, status.Error(codes.InvalidArgument, "Missing channel configuration.")
 	}
 
-	if err := q.Insert(row); err != nil {
-		return nil, errors.Wrap(err, "failed to create notifications channel")
+	if err := checkChannelNotificationTemplate(row.Type, params.NotificationTemplate); err != nil {
+		return nil, err
 	}
+	row.NotificationTemplate = params.NotificationTemplate
 
 	return row, nil
 }
@@ -282,6 +437,11 @@ type ChangeChannelParams struct {
 	PagerDutyConfig *PagerDutyConfig
 	SlackConfig     *SlackConfig
 	WebHookConfig   *WebHookConfig
+	OpsGenieConfig  *OpsGenieConfig
+	MSTeamsConfig   *MSTeamsConfig
+	TelegramConfig  *TelegramConfig
+
+	NotificationTemplate *NotificationTemplate
 
 	Disabled bool
 }
@@ -299,6 +459,9 @@ func ChangeChannel(q *reform.Querier, channelID string, params *ChangeChannelPar
 	row.PagerDutyConfig = nil
 	row.SlackConfig = nil
 	row.WebHookConfig = nil
+	row.OpsGenieConfig = nil
+	row.MSTeamsConfig = nil
+	row.TelegramConfig = nil
 
 	if params.Summary != "" {
 		row.Summary = params.Summary
@@ -346,6 +509,44 @@ func ChangeChannel(q *reform.Querier, channelID string, params *ChangeChannelPar
 		row.WebHookConfig = params.WebHookConfig
 	}
 
+	if params.OpsGenieConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkOpsGenieConfig(params.OpsGenieConfig); err != nil {
+			return nil, err
+		}
+		row.Type = OpsGenie
+		row.OpsGenieConfig = params.OpsGenieConfig
+	}
+
+	if params.MSTeamsConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkMSTeamsConfig(params.MSTeamsConfig); err != nil {
+			return nil, err
+		}
+		row.Type = MSTeams
+		row.MSTeamsConfig = params.MSTeamsConfig
+	}
+
+	if params.TelegramConfig != nil {
+		if row.Type != "" {
+			return nil, invalidConfigurationError
+		}
+		if err := checkTelegramConfig(params.TelegramConfig); err != nil {
+			return nil, err
+		}
+		row.Type = Telegram
+		row.TelegramConfig = params.TelegramConfig
+	}
+
+	if err = checkChannelNotificationTemplate(row.Type, params.NotificationTemplate); err != nil {
+		return nil, err
+	}
+	row.NotificationTemplate = params.NotificationTemplate
+
 	row.Disabled = params.Disabled
 
 	if err = q.Update(row); err != nil {
@@ -371,6 +572,15 @@ func RemoveChannel(q *reform.Querier, id string) error {
 		return status.Errorf(codes.FailedPrecondition, `You can't delete the "%s" channel when it's being used by a rule.`, channel.Summary)
 	}
 
+	inUse, err = channelInUseByAlertRoute(q, id)
+	if err != nil {
+		return err
+	}
+
+	if inUse {
+		return status.Errorf(codes.FailedPrecondition, `You can't delete the "%s" channel when it's being used by an alert route.`, channel.Summary)
+	}
+
 	if err = q.Delete(&Channel{ID: id}); err != nil {
 		return errors.Wrap(err, "failed to delete notification channel")
 	}
@@ -289,6 +289,134 @@ func TestChannelValidation(t *testing.T) {
 			},
 			errorMsg: "",
 		},
+		{
+			name: "normal opsgenie config",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				OpsGenieConfig: &models.OpsGenieConfig{
+					APIKey:   "some key",
+					Priority: "P1",
+				},
+			},
+			errorMsg: "",
+		},
+		{
+			name: "invalid opsgenie priority",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				OpsGenieConfig: &models.OpsGenieConfig{
+					APIKey:   "some key",
+					Priority: "urgent",
+				},
+			},
+			errorMsg: `rpc error: code = InvalidArgument desc = Invalid Opsgenie priority "urgent", should be one of P1-P5.`,
+		},
+		{
+			name: "normal msteams config",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				MSTeamsConfig: &models.MSTeamsConfig{
+					WebhookURL: "https://example.com/webhook",
+				},
+			},
+			errorMsg: "",
+		},
+		{
+			name: "missing msteams webhook url",
+			channel: models.CreateChannelParams{
+				Summary:       "some summary",
+				MSTeamsConfig: &models.MSTeamsConfig{},
+			},
+			errorMsg: "rpc error: code = InvalidArgument desc = MS Teams webhook url field is empty.",
+		},
+		{
+			name: "normal telegram config",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				TelegramConfig: &models.TelegramConfig{
+					BotToken: "some token",
+					ChatID:   42,
+				},
+			},
+			errorMsg: "",
+		},
+		{
+			name: "missing telegram chat id",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				TelegramConfig: &models.TelegramConfig{
+					BotToken: "some token",
+				},
+			},
+			errorMsg: "rpc error: code = InvalidArgument desc = Telegram chat ID field is empty.",
+		},
+		{
+			name: "normal notification template",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				SlackConfig: &models.SlackConfig{
+					Channel: "channel",
+				},
+				NotificationTemplate: &models.NotificationTemplate{
+					Title: "{{ .CommonLabels.severity }}",
+					Text:  "Runbook: https://example.com/{{ .Labels.alertname }}\n{{ .Annotations.summary }}",
+				},
+			},
+			errorMsg: "",
+		},
+		{
+			name: "invalid notification title template",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				SlackConfig: &models.SlackConfig{
+					Channel: "channel",
+				},
+				NotificationTemplate: &models.NotificationTemplate{
+					Title: "{{ .Status ",
+				},
+			},
+			errorMsg: "rpc error: code = InvalidArgument desc = Invalid notification title template: template: :1: unclosed action.",
+		},
+		{
+			name: "invalid notification text template",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				SlackConfig: &models.SlackConfig{
+					Channel: "channel",
+				},
+				NotificationTemplate: &models.NotificationTemplate{
+					Text: "{{ .Foo }}",
+				},
+			},
+			errorMsg: `rpc error: code = InvalidArgument desc = Invalid notification text template: template: :1:22: executing "" at <.Foo>: can't evaluate field Foo in type template.Alert.`,
+		},
+		{
+			name: "webhook notification template",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				WebHookConfig: &models.WebHookConfig{
+					URL: "test.test",
+				},
+				NotificationTemplate: &models.NotificationTemplate{
+					Text: "{{ .Annotations.summary }}",
+				},
+			},
+			errorMsg: "rpc error: code = InvalidArgument desc = Webhook channel doesn't support notification template.",
+		},
+		{
+			name: "telegram and slack configs",
+			channel: models.CreateChannelParams{
+				Summary: "some summary",
+				SlackConfig: &models.SlackConfig{
+					Channel: "channel",
+				},
+				TelegramConfig: &models.TelegramConfig{
+					BotToken: "some token",
+					ChatID:   42,
+				},
+			},
+			errorMsg: "rpc error: code = InvalidArgument desc = Channel should contain only one type of channel configuration.",
+		},
 		{
 			name: "normal webhook config",
 			channel: models.CreateChannelParams{
@@ -35,6 +35,10 @@ func (v *channelTableType) Columns() []string {
 		"pagerduty_config",
 		"slack_config",
 		"webhook_config",
+		"opsgenie_config",
+		"msteams_config",
+		"telegram_config",
+		"notification_template",
 		"disabled",
 		"created_at",
 		"updated_at",
@@ -69,6 +73,10 @@ var ChannelTable = &channelTableType{
 			{Name: "PagerDutyConfig", Type: "*PagerDutyConfig", Column: "pagerduty_config"},
 			{Name: "SlackConfig", Type: "*SlackConfig", Column: "slack_config"},
 			{Name: "WebHookConfig", Type: "*WebHookConfig", Column: "webhook_config"},
+			{Name: "OpsGenieConfig", Type: "*OpsGenieConfig", Column: "opsgenie_config"},
+			{Name: "MSTeamsConfig", Type: "*MSTeamsConfig", Column: "msteams_config"},
+			{Name: "TelegramConfig", Type: "*TelegramConfig", Column: "telegram_config"},
+			{Name: "NotificationTemplate", Type: "*NotificationTemplate", Column: "notification_template"},
 			{Name: "Disabled", Type: "bool", Column: "disabled"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
@@ -80,7 +88,7 @@ var ChannelTable = &channelTableType{
 
 // String returns a string representation of this struct or record.
 func (s Channel) String() string {
-	res := make([]string, 10)
+	res := make([]string, 14)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "Summary: " + reform.Inspect(s.Summary, true)
 	res[2] = "Type: " + reform.Inspect(s.Type, true)
@@ -88,9 +96,13 @@ func (s Channel) String() string {
 	res[4] = "PagerDutyConfig: " + reform.Inspect(s.PagerDutyConfig, true)
 	res[5] = "SlackConfig: " + reform.Inspect(s.SlackConfig, true)
 	res[6] = "WebHookConfig: " + reform.Inspect(s.WebHookConfig, true)
-	res[7] = "Disabled: " + reform.Inspect(s.Disabled, true)
-	res[8] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[9] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[7] = "OpsGenieConfig: " + reform.Inspect(s.OpsGenieConfig, true)
+	res[8] = "MSTeamsConfig: " + reform.Inspect(s.MSTeamsConfig, true)
+	res[9] = "TelegramConfig: " + reform.Inspect(s.TelegramConfig, true)
+	res[10] = "NotificationTemplate: " + reform.Inspect(s.NotificationTemplate, true)
+	res[11] = "Disabled: " + reform.Inspect(s.Disabled, true)
+	res[12] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[13] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -105,6 +117,10 @@ func (s *Channel) Values() []interface{} {
 		s.PagerDutyConfig,
 		s.SlackConfig,
 		s.WebHookConfig,
+		s.OpsGenieConfig,
+		s.MSTeamsConfig,
+		s.TelegramConfig,
+		s.NotificationTemplate,
 		s.Disabled,
 		s.CreatedAt,
 		s.UpdatedAt,
@@ -122,6 +138,10 @@ func (s *Channel) Pointers() []interface{} {
 		&s.PagerDutyConfig,
 		&s.SlackConfig,
 		&s.WebHookConfig,
+		&s.OpsGenieConfig,
+		&s.MSTeamsConfig,
+		&s.TelegramConfig,
+		&s.NotificationTemplate,
 		&s.Disabled,
 		&s.CreatedAt,
 		&s.UpdatedAt,
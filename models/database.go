@@ -704,6 +704,186 @@ var databaseSchema = [][]string{
 			ADD COLUMN pmm_server_name VARCHAR NOT NULL,
 			ALTER COLUMN organization_id SET NOT NULL`,
 	},
+	61: {
+		`ALTER TABLE restore_history
+			ADD COLUMN pitr_timestamp TIMESTAMP`,
+	},
+	62: {
+		`ALTER TABLE artifacts
+			ADD COLUMN verification_status VARCHAR NOT NULL DEFAULT '',
+			ADD COLUMN verified_at TIMESTAMP,
+			ADD COLUMN file_checksums JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE artifacts
+			ALTER COLUMN verification_status DROP DEFAULT,
+			ALTER COLUMN file_checksums DROP DEFAULT`,
+	},
+	63: {
+		`ALTER TABLE artifacts
+			ADD COLUMN pmm_agent_id VARCHAR NOT NULL DEFAULT '',
+			ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN started_at TIMESTAMP,
+			ADD COLUMN finished_at TIMESTAMP`,
+		`ALTER TABLE artifacts
+			ALTER COLUMN pmm_agent_id DROP DEFAULT,
+			ALTER COLUMN size DROP DEFAULT`,
+	},
+	64: {
+		`CREATE TABLE replication_rules (
+			id VARCHAR NOT NULL,
+			source_location_id VARCHAR NOT NULL,
+			target_location_id VARCHAR NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (id),
+			FOREIGN KEY (source_location_id) REFERENCES backup_locations (id) ON DELETE CASCADE,
+			FOREIGN KEY (target_location_id) REFERENCES backup_locations (id) ON DELETE CASCADE,
+			UNIQUE (source_location_id, target_location_id)
+		)`,
+		`CREATE TABLE artifact_replicas (
+			id VARCHAR NOT NULL,
+			artifact_id VARCHAR NOT NULL,
+			location_id VARCHAR NOT NULL,
+			status VARCHAR NOT NULL CHECK (status <> ''),
+			error VARCHAR NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (id),
+			FOREIGN KEY (artifact_id) REFERENCES artifacts (id) ON DELETE CASCADE,
+			FOREIGN KEY (location_id) REFERENCES backup_locations (id) ON DELETE CASCADE,
+			UNIQUE (artifact_id, location_id)
+		)`,
+	},
+	65: {
+		`ALTER TABLE backup_locations
+			ADD COLUMN quota_bytes BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN quota_action VARCHAR NOT NULL DEFAULT '',
+			ADD COLUMN usage_bytes BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN usage_updated_at TIMESTAMP`,
+		`ALTER TABLE backup_locations
+			ALTER COLUMN quota_bytes DROP DEFAULT,
+			ALTER COLUMN quota_action DROP DEFAULT,
+			ALTER COLUMN usage_bytes DROP DEFAULT`,
+	},
+	66: {
+		`ALTER TABLE scheduled_tasks ADD COLUMN catch_up_policy VARCHAR NOT NULL DEFAULT 'skip'`,
+		`ALTER TABLE scheduled_tasks ALTER COLUMN catch_up_policy DROP DEFAULT`,
+		`CREATE TABLE scheduled_task_runs (
+			id VARCHAR NOT NULL,
+			scheduled_task_id VARCHAR NOT NULL,
+			status VARCHAR NOT NULL CHECK (status <> ''),
+			error VARCHAR NOT NULL,
+			artifact_id VARCHAR NOT NULL,
+			retries INTEGER NOT NULL,
+			catch_up BOOLEAN NOT NULL,
+			started_at TIMESTAMP NOT NULL,
+			finished_at TIMESTAMP,
+
+			PRIMARY KEY (id),
+			FOREIGN KEY (scheduled_task_id) REFERENCES scheduled_tasks (id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX scheduled_task_runs_scheduled_task_id_started_at_idx ON scheduled_task_runs (scheduled_task_id, started_at)`,
+	},
+	67: {
+		`ALTER TABLE scheduled_tasks ADD COLUMN deferred_reason VARCHAR NOT NULL DEFAULT ''`,
+		`ALTER TABLE scheduled_tasks ALTER COLUMN deferred_reason DROP DEFAULT`,
+	},
+	68: {
+		`ALTER TABLE jobs ADD COLUMN state VARCHAR NOT NULL DEFAULT 'running'`,
+		`UPDATE jobs SET state = 'done' WHERE done`,
+		`ALTER TABLE jobs ALTER COLUMN state DROP DEFAULT`,
+		`CREATE INDEX jobs_pmm_agent_id_state_idx ON jobs (pmm_agent_id, state)`,
+	},
+	69: {
+		`ALTER TABLE jobs ADD COLUMN progress JSONB`,
+	},
+	70: {
+		`CREATE TABLE maintenance_windows (
+			id VARCHAR NOT NULL,
+			name VARCHAR NOT NULL CHECK (name <> ''),
+			description VARCHAR NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			duration BIGINT NOT NULL,
+			cron_expression VARCHAR NOT NULL,
+			scope JSONB NOT NULL,
+			disabled BOOLEAN NOT NULL,
+			silence_id VARCHAR NOT NULL,
+			silenced_until TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (id)
+		)`,
+	},
+	71: {
+		`CREATE TABLE ia_routes (
+			id VARCHAR NOT NULL,
+			name VARCHAR NOT NULL CHECK (name <> ''),
+			parent_id VARCHAR REFERENCES ia_routes (id),
+			position INTEGER NOT NULL,
+			disabled BOOLEAN NOT NULL,
+			filters JSONB,
+			group_by VARCHAR[],
+			group_wait BIGINT NOT NULL,
+			group_interval BIGINT NOT NULL,
+			repeat_interval BIGINT NOT NULL,
+			continue BOOLEAN NOT NULL,
+			escalation_steps JSONB,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (id)
+		)`,
+	},
+	72: {
+		`ALTER TABLE ia_channels
+			ADD COLUMN opsgenie_config JSONB,
+			ADD COLUMN msteams_config JSONB,
+			ADD COLUMN telegram_config JSONB`,
+	},
+	73: {
+		`CREATE TABLE ia_alert_events (
+			id VARCHAR NOT NULL,
+			alert_id VARCHAR NOT NULL CHECK (alert_id <> ''),
+			rule_id VARCHAR NOT NULL,
+			type VARCHAR NOT NULL CHECK (type <> ''),
+			summary VARCHAR NOT NULL,
+			severity VARCHAR,
+			labels JSONB,
+			username VARCHAR NOT NULL,
+			comment VARCHAR NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (id)
+		)`,
+		`CREATE INDEX ia_alert_events_alert_id_created_at_idx ON ia_alert_events (alert_id, created_at)`,
+		`CREATE INDEX ia_alert_events_created_at_idx ON ia_alert_events (created_at)`,
+	},
+	74: {
+		`ALTER TABLE ia_channels ADD COLUMN notification_template JSONB`,
+		`ALTER TABLE ia_rules ADD COLUMN notification_template JSONB`,
+	},
+	75: {
+		`ALTER TABLE ia_templates
+			ADD COLUMN recording_rules JSONB NOT NULL DEFAULT '[]',
+			ADD COLUMN conditions JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE ia_templates
+			ALTER COLUMN recording_rules DROP DEFAULT,
+			ALTER COLUMN conditions DROP DEFAULT`,
+		`ALTER TABLE ia_rules
+			ADD COLUMN recording_rules JSONB NOT NULL DEFAULT '[]',
+			ADD COLUMN conditions JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE ia_rules
+			ALTER COLUMN recording_rules DROP DEFAULT,
+			ALTER COLUMN conditions DROP DEFAULT`,
+	},
+	76: {
+		`ALTER TABLE jobs
+			ADD COLUMN stop_requested BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN retry_at TIMESTAMP`,
+		`ALTER TABLE jobs ALTER COLUMN stop_requested DROP DEFAULT`,
+	},
 }
 
 // ^^^ Avoid default values in schema definition. ^^^
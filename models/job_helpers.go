@@ -32,6 +32,9 @@ const (
 	defaultLimit = 50
 )
 
+// ErrJobDone is returned when state of the job which is already done is changed.
+var ErrJobDone = errors.New("job is already done")
+
 // FindJobByID finds Job by ID.
 func FindJobByID(q *reform.Querier, id string) (*Job, error) {
 	if id == "" {
@@ -52,7 +55,12 @@ func FindJobByID(q *reform.Querier, id string) (*Job, error) {
 // JobsFilter represents filter for jobs.
 type JobsFilter struct {
 	ArtifactID string
+	RestoreID  string
+	PMMAgentID string
 	Types      []JobType
+	States     []JobState
+	// StopRequested selects jobs which should be stopped on pmm-agent when it connects again.
+	StopRequested bool
 }
 
 // FindJobs returns logs satisfying filters.
@@ -69,11 +77,37 @@ func FindJobs(q *reform.Querier, filters JobsFilter) ([]*Job, error) {
 		andConds = append(andConds, fmt.Sprintf("type IN (%s)", p))
 	}
 
+	if len(filters.States) != 0 {
+		p := strings.Join(q.Placeholders(idx, len(filters.States)), ", ")
+		for _, state := range filters.States {
+			args = append(args, state)
+		}
+		idx += len(filters.States)
+		andConds = append(andConds, fmt.Sprintf("state IN (%s)", p))
+	}
+
+	if filters.PMMAgentID != "" {
+		andConds = append(andConds, "pmm_agent_id = "+q.Placeholder(idx))
+		args = append(args, filters.PMMAgentID)
+		idx++
+	}
+
+	if filters.StopRequested {
+		andConds = append(andConds, "stop_requested")
+	}
+
 	crossJoin := false
 	if filters.ArtifactID != "" {
 		crossJoin = true
 		andConds = append(andConds, "value ->> 'artifact_id' = "+q.Placeholder(idx))
 		args = append(args, filters.ArtifactID)
+		idx++
+	}
+
+	if filters.RestoreID != "" {
+		crossJoin = true
+		andConds = append(andConds, "value ->> 'restore_id' = "+q.Placeholder(idx))
+		args = append(args, filters.RestoreID)
 	}
 
 	var tail strings.Builder
@@ -135,6 +169,7 @@ func CreateJob(q *reform.Querier, params CreateJobParams) (*Job, error) {
 		Timeout:    params.Timeout,
 		Interval:   params.Interval,
 		Retries:    params.Retries,
+		State:      QueuedJobState,
 	}
 	if err := q.Insert(result); err != nil {
 		return nil, errors.WithStack(err)
@@ -142,6 +177,45 @@ func CreateJob(q *reform.Querier, params CreateJobParams) (*Job, error) {
 	return result, nil
 }
 
+// UpdateJobState changes state of the job unless it's already done, ErrJobDone is returned in that case.
+// It doesn't overwrite concurrent changes of other job fields, e.g. by job result.
+func UpdateJobState(q *reform.Querier, id string, state JobState) error {
+	res, err := q.Exec("UPDATE jobs SET state = $1, done = $2, updated_at = $3 WHERE id = $4 AND state <> $5",
+		state, state == DoneJobState, Now(), id, DoneJobState)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if n == 0 {
+		return ErrJobDone
+	}
+
+	return nil
+}
+
+// UpdateJobProgress stores the latest progress of the job unless it's already done, so progress reported
+// after the job result doesn't overwrite the final one. Other job fields are left intact.
+func UpdateJobProgress(q *reform.Querier, id string, progress *JobProgress) error {
+	_, err := q.Exec("UPDATE jobs SET progress = $1, updated_at = $2 WHERE id = $3 AND state <> $4",
+		progress, Now(), id, DoneJobState)
+	return errors.WithStack(err)
+}
+
+// ClearJobStopRequest marks the job as stopped on pmm-agent. Other job fields are left intact.
+func ClearJobStopRequest(q *reform.Querier, id string) error {
+	_, err := q.Exec("UPDATE jobs SET stop_requested = false, updated_at = $1 WHERE id = $2", Now(), id)
+	return errors.WithStack(err)
+}
+
+// UnfinishedJobStates returns states of jobs which aren't done yet.
+func UnfinishedJobStates() []JobState {
+	return []JobState{QueuedJobState, DispatchedJobState, RunningJobState, RetryingJobState}
+}
+
 // CleanupOldJobs deletes jobs results older than a specified date.
 func CleanupOldJobs(q *reform.Querier, olderThan time.Time) error {
 	_, err := q.DeleteFrom(JobTable, " WHERE updated_at <= $1", olderThan)
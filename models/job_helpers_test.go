@@ -63,6 +63,8 @@ func TestJobs(t *testing.T) {
 		assert.Equal(t, createJobParams.Timeout, job.Timeout)
 		assert.Equal(t, createJobParams.Interval, job.Interval)
 		assert.Equal(t, createJobParams.Retries, job.Retries)
+		assert.Equal(t, models.QueuedJobState, job.State)
+		assert.False(t, job.Done)
 		require.NotNil(t, job.Data.MongoDBBackup)
 		assert.Equal(t, createJobParams.Data.MongoDBBackup.ServiceID, job.Data.MongoDBBackup.ServiceID)
 		assert.Equal(t, createJobParams.Data.MongoDBBackup.ArtifactID, job.Data.MongoDBBackup.ArtifactID)
@@ -110,6 +112,19 @@ func TestJobs(t *testing.T) {
 				},
 				Expect: []string{jobs[0].ID},
 			},
+			{
+				Filters: models.JobsFilter{
+					PMMAgentID: "agentid",
+					States:     []models.JobState{models.QueuedJobState},
+				},
+				Expect: []string{jobs[0].ID, jobs[1].ID, jobs[2].ID},
+			},
+			{
+				Filters: models.JobsFilter{
+					PMMAgentID: "otheragentid",
+				},
+				Expect: []string{},
+			},
 		}
 
 		for _, tc := range testCases {
@@ -126,6 +141,102 @@ func TestJobs(t *testing.T) {
 	})
 }
 
+func TestJobState(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	job, err := models.CreateJob(tx.Querier, models.CreateJobParams{
+		PMMAgentID: "pmmagent",
+		Type:       models.MySQLBackupJob,
+		Data:       &models.JobData{},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, models.UpdateJobState(tx.Querier, job.ID, models.DispatchedJobState))
+	job, err = models.FindJobByID(tx.Querier, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DispatchedJobState, job.State)
+	assert.False(t, job.Done)
+	assert.Nil(t, job.Progress)
+
+	progress := &models.JobProgress{
+		Percentage:   50,
+		LastLogLines: []string{"line"},
+		UpdatedAt:    time.Now().UTC().Round(time.Second),
+	}
+	require.NoError(t, models.UpdateJobProgress(tx.Querier, job.ID, progress))
+	job, err = models.FindJobByID(tx.Querier, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, progress, job.Progress)
+	assert.Equal(t, models.DispatchedJobState, job.State)
+
+	job.SetState(models.DoneJobState)
+	require.NoError(t, tx.Update(job))
+	assert.True(t, job.Done)
+
+	// done job isn't changed
+	assert.Equal(t, models.ErrJobDone, models.UpdateJobState(tx.Querier, job.ID, models.RunningJobState))
+	require.NoError(t, models.UpdateJobProgress(tx.Querier, job.ID, &models.JobProgress{Percentage: 60}))
+	job, err = models.FindJobByID(tx.Querier, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DoneJobState, job.State)
+	assert.True(t, job.Done)
+	assert.Equal(t, progress, job.Progress)
+
+	jobs, err := models.FindJobs(tx.Querier, models.JobsFilter{
+		PMMAgentID: "pmmagent",
+		States:     models.UnfinishedJobStates(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
+func TestJobStopRequest(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	var jobs []*models.Job
+	for i := 0; i < 2; i++ {
+		job, err := models.CreateJob(tx.Querier, models.CreateJobParams{
+			PMMAgentID: "pmmagent",
+			Type:       models.MySQLBackupJob,
+			Data:       &models.JobData{},
+		})
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+
+	jobs[0].SetState(models.DoneJobState)
+	jobs[0].StopRequested = true
+	require.NoError(t, tx.Update(jobs[0]))
+
+	found, err := models.FindJobs(tx.Querier, models.JobsFilter{PMMAgentID: "pmmagent", StopRequested: true})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, jobs[0].ID, found[0].ID)
+
+	require.NoError(t, models.ClearJobStopRequest(tx.Querier, jobs[0].ID))
+	found, err = models.FindJobs(tx.Querier, models.JobsFilter{PMMAgentID: "pmmagent", StopRequested: true})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	job, err := models.FindJobByID(tx.Querier, jobs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DoneJobState, job.State)
+}
+
 func TestJobLogs(t *testing.T) {
 	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
 	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
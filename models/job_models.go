@@ -62,6 +62,21 @@ func (r JobResult) Value() (driver.Value, error) { return jsonValue(r) }
 // Scan implements database/sql.Scanner interface. Should be defined on the pointer.
 func (r *JobResult) Scan(src interface{}) error { return jsonScan(r, src) }
 
+// JobProgress holds the latest progress of the job reported by pmm-agent.
+// Percentage and Phase are estimated from the job log, see agents.jobPhases.
+type JobProgress struct {
+	Percentage   uint32    `json:"percentage,omitempty"`
+	Phase        string    `json:"phase,omitempty"`
+	LastLogLines []string  `json:"last_log_lines,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (p JobProgress) Value() (driver.Value, error) { return jsonValue(p) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (p *JobProgress) Scan(src interface{}) error { return jsonScan(p, src) }
+
 // MySQLBackupJobData stores MySQL job specific result data.
 type MySQLBackupJobData struct {
 	ServiceID  string `json:"service_id"`
@@ -101,6 +116,23 @@ func (c JobData) Value() (driver.Value, error) { return jsonValue(c) }
 // Scan implements database/sql.Scanner interface. Should be defined on the pointer.
 func (c *JobData) Scan(src interface{}) error { return jsonScan(c, src) }
 
+// JobState represents state of the job in its lifecycle.
+type JobState string
+
+// Job states.
+const (
+	// QueuedJobState is a state of the job which is created, but not sent to pmm-agent yet.
+	QueuedJobState = JobState("queued")
+	// DispatchedJobState is a state of the job which is sent to pmm-agent, but not acknowledged by it yet.
+	DispatchedJobState = JobState("dispatched")
+	// RunningJobState is a state of the job which is started by pmm-agent.
+	RunningJobState = JobState("running")
+	// RetryingJobState is a state of the failed job which waits for the next attempt.
+	RetryingJobState = JobState("retrying")
+	// DoneJobState is a state of the finished, failed or cancelled job.
+	DoneJobState = JobState("done")
+)
+
 // Job describes a job result which is storing in persistent storage.
 //reform:jobs
 type Job struct {
@@ -112,10 +144,16 @@ type Job struct {
 	Retries    uint32        `reform:"retries"`
 	Interval   time.Duration `reform:"interval"`
 	Done       bool          `reform:"done"`
+	State      JobState      `reform:"state"`
 	Error      string        `reform:"error"`
 	Result     *JobResult    `reform:"result"`
-	CreatedAt  time.Time     `reform:"created_at"`
-	UpdatedAt  time.Time     `reform:"updated_at"`
+	Progress   *JobProgress  `reform:"progress"`
+	// StopRequested is set for the done job which should be stopped on pmm-agent when it connects again.
+	StopRequested bool `reform:"stop_requested"`
+	// RetryAt is the time of the next attempt of the retrying job.
+	RetryAt   *time.Time `reform:"retry_at"`
+	CreatedAt time.Time  `reform:"created_at"`
+	UpdatedAt time.Time  `reform:"updated_at"`
 }
 
 // BeforeInsert implements reform.BeforeInserter interface.
@@ -142,7 +180,14 @@ func (r *Job) AfterFind() error {
 	return nil
 }
 
+// SetState changes state of the job, Done flag is kept in sync with it.
+func (r *Job) SetState(state JobState) {
+	r.State = state
+	r.Done = state == DoneJobState
+}
+
 // JobLog stores chunk of logs from job.
+//
 //reform:job_logs
 type JobLog struct {
 	JobID     string `reform:"job_id"`
@@ -36,8 +36,12 @@ func (v *jobTableType) Columns() []string {
 		"retries",
 		"interval",
 		"done",
+		"state",
 		"error",
 		"result",
+		"progress",
+		"stop_requested",
+		"retry_at",
 		"created_at",
 		"updated_at",
 	}
@@ -72,8 +76,12 @@ var JobTable = &jobTableType{
 			{Name: "Retries", Type: "uint32", Column: "retries"},
 			{Name: "Interval", Type: "time.Duration", Column: "interval"},
 			{Name: "Done", Type: "bool", Column: "done"},
+			{Name: "State", Type: "JobState", Column: "state"},
 			{Name: "Error", Type: "string", Column: "error"},
 			{Name: "Result", Type: "*JobResult", Column: "result"},
+			{Name: "Progress", Type: "*JobProgress", Column: "progress"},
+			{Name: "StopRequested", Type: "bool", Column: "stop_requested"},
+			{Name: "RetryAt", Type: "*time.Time", Column: "retry_at"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
 		},
@@ -84,7 +92,7 @@ var JobTable = &jobTableType{
 
 // String returns a string representation of this struct or record.
 func (s Job) String() string {
-	res := make([]string, 12)
+	res := make([]string, 16)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "PMMAgentID: " + reform.Inspect(s.PMMAgentID, true)
 	res[2] = "Type: " + reform.Inspect(s.Type, true)
@@ -93,10 +101,14 @@ func (s Job) String() string {
 	res[5] = "Retries: " + reform.Inspect(s.Retries, true)
 	res[6] = "Interval: " + reform.Inspect(s.Interval, true)
 	res[7] = "Done: " + reform.Inspect(s.Done, true)
-	res[8] = "Error: " + reform.Inspect(s.Error, true)
-	res[9] = "Result: " + reform.Inspect(s.Result, true)
-	res[10] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[11] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[8] = "State: " + reform.Inspect(s.State, true)
+	res[9] = "Error: " + reform.Inspect(s.Error, true)
+	res[10] = "Result: " + reform.Inspect(s.Result, true)
+	res[11] = "Progress: " + reform.Inspect(s.Progress, true)
+	res[12] = "StopRequested: " + reform.Inspect(s.StopRequested, true)
+	res[13] = "RetryAt: " + reform.Inspect(s.RetryAt, true)
+	res[14] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[15] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -112,8 +124,12 @@ func (s *Job) Values() []interface{} {
 		s.Retries,
 		s.Interval,
 		s.Done,
+		s.State,
 		s.Error,
 		s.Result,
+		s.Progress,
+		s.StopRequested,
+		s.RetryAt,
 		s.CreatedAt,
 		s.UpdatedAt,
 	}
@@ -131,8 +147,12 @@ func (s *Job) Pointers() []interface{} {
 		&s.Retries,
 		&s.Interval,
 		&s.Done,
+		&s.State,
 		&s.Error,
 		&s.Result,
+		&s.Progress,
+		&s.StopRequested,
+		&s.RetryAt,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/AlekSi/pointer"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
@@ -352,6 +353,56 @@ func ChangeBackupLocation(q *reform.Querier, locationID string, params ChangeBac
 	return row, nil
 }
 
+// SetBackupLocationQuota sets quota of the backup location, zero quotaBytes removes the quota.
+func SetBackupLocationQuota(q *reform.Querier, locationID string, quotaBytes int64, action QuotaAction) (*BackupLocation, error) {
+	if quotaBytes < 0 {
+		return nil, NewInvalidArgumentError("quota shouldn't be negative")
+	}
+
+	if quotaBytes == 0 {
+		action = ""
+	} else if err := action.Validate(); err != nil {
+		return nil, err
+	}
+
+	row, err := FindBackupLocationByID(q, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quotaBytes != 0 && row.PMMClientConfig != nil {
+		return nil, NewInvalidArgumentError("usage of %s locations can't be computed, so quota can't be set", PMMClientBackupLocationType)
+	}
+
+	row.QuotaBytes = quotaBytes
+	row.QuotaAction = action
+	if err := q.Update(row); err != nil {
+		return nil, errors.Wrap(err, "failed to update backup location quota")
+	}
+
+	return row, nil
+}
+
+// UpdateBackupLocationUsage stores space taken in the backup location.
+func UpdateBackupLocationUsage(q *reform.Querier, locationID string, usageBytes int64) (*BackupLocation, error) {
+	row, err := FindBackupLocationByID(q, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if usageBytes < 0 {
+		usageBytes = 0
+	}
+
+	row.UsageBytes = usageBytes
+	row.UsageUpdatedAt = pointer.ToTime(Now())
+	if err := q.Update(row); err != nil {
+		return nil, errors.Wrap(err, "failed to update backup location usage")
+	}
+
+	return row, nil
+}
+
 // RemoveBackupLocation removes BackupLocation by ID.
 func RemoveBackupLocation(q *reform.Querier, id string, mode RemoveMode) error {
 	if _, err := FindBackupLocationByID(q, id); err != nil {
@@ -380,11 +431,20 @@ func RemoveBackupLocation(q *reform.Querier, id string, mode RemoveMode) error {
 		return err
 	}
 
+	replicas, err := FindArtifactReplicas(q, ArtifactReplicaFilters{LocationID: id})
+	if err != nil {
+		return err
+	}
+
 	if mode == RemoveRestrict {
 		if len(artifacts) != 0 {
 			return status.Errorf(codes.FailedPrecondition, "backup location with ID %q has artifacts.", id)
 		}
 
+		if len(replicas) != 0 {
+			return status.Errorf(codes.FailedPrecondition, "backup location with ID %q has artifact replicas.", id)
+		}
+
 		if len(restoreItems) != 0 {
 			return status.Errorf(codes.FailedPrecondition, "backup location with ID %q has restore history items.", id)
 		}
@@ -412,6 +472,7 @@ func RemoveBackupLocation(q *reform.Querier, id string, mode RemoveMode) error {
 		}
 	}
 
+	// Replicas and replication rules of the location are removed by foreign key constraints.
 	if err := q.Delete(&BackupLocation{ID: id}); err != nil {
 		return errors.Wrap(err, "failed to delete BackupLocation")
 	}
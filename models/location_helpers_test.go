@@ -238,6 +238,63 @@ func TestBackupLocations(t *testing.T) {
 		assert.Equal(t, updatedLoc, findLoc)
 	})
 
+	t.Run("quota and usage", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+
+		q := tx.Querier
+
+		location, err := models.CreateBackupLocation(q, models.CreateBackupLocationParams{
+			Name: "some name",
+			BackupLocationConfig: models.BackupLocationConfig{
+				PMMServerConfig: &models.PMMServerLocationConfig{
+					Path: "/tmp",
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, location.UsageUpdatedAt)
+		assert.False(t, location.QuotaExceeded())
+
+		_, err = models.SetBackupLocationQuota(q, location.ID, 100, "")
+		assert.EqualError(t, err, `invalid argument: invalid quota action ""`)
+		_, err = models.SetBackupLocationQuota(q, location.ID, -1, models.BlockQuotaAction)
+		assert.EqualError(t, err, "invalid argument: quota shouldn't be negative")
+
+		location, err = models.SetBackupLocationQuota(q, location.ID, 100, models.BlockQuotaAction)
+		require.NoError(t, err)
+		assert.Equal(t, models.BlockQuotaAction, location.QuotaAction)
+
+		location, err = models.UpdateBackupLocationUsage(q, location.ID, 100)
+		require.NoError(t, err)
+		assert.NotNil(t, location.UsageUpdatedAt)
+		assert.True(t, location.QuotaExceeded())
+
+		location, err = models.UpdateBackupLocationUsage(q, location.ID, -10)
+		require.NoError(t, err)
+		assert.Zero(t, location.UsageBytes)
+		assert.False(t, location.QuotaExceeded())
+
+		location, err = models.SetBackupLocationQuota(q, location.ID, 0, models.RetentionQuotaAction)
+		require.NoError(t, err)
+		assert.Empty(t, location.QuotaAction)
+
+		clientLocation, err := models.CreateBackupLocation(q, models.CreateBackupLocationParams{
+			Name: "client location",
+			BackupLocationConfig: models.BackupLocationConfig{
+				PMMClientConfig: &models.PMMClientLocationConfig{
+					Path: "/tmp",
+				},
+			},
+		})
+		require.NoError(t, err)
+		_, err = models.SetBackupLocationQuota(q, clientLocation.ID, 100, models.BlockQuotaAction)
+		assert.EqualError(t, err, "invalid argument: usage of pmm-client locations can't be computed, so quota can't be set")
+	})
+
 	t.Run("remove restrict", func(t *testing.T) {
 		tx, err := db.Begin()
 		require.NoError(t, err)
@@ -20,6 +20,7 @@ import (
 	"database/sql/driver"
 	"time"
 
+	"github.com/AlekSi/pointer"
 	"gopkg.in/reform.v1"
 )
 
@@ -35,6 +36,29 @@ const (
 	PMMClientBackupLocationType BackupLocationType = "pmm-client"
 )
 
+// QuotaAction defines what happens when backup location quota is exceeded.
+type QuotaAction string
+
+// QuotaAction types.
+const (
+	// BlockQuotaAction rejects new backups to the location.
+	BlockQuotaAction QuotaAction = "block"
+	// RetentionQuotaAction removes the oldest artifacts from the location.
+	RetentionQuotaAction QuotaAction = "retention"
+)
+
+// Validate validates quota action.
+func (a QuotaAction) Validate() error {
+	switch a {
+	case BlockQuotaAction:
+	case RetentionQuotaAction:
+	default:
+		return NewInvalidArgumentError("invalid quota action %q", a)
+	}
+
+	return nil
+}
+
 // BackupLocation represents destination for backup.
 //reform:backup_locations
 type BackupLocation struct {
@@ -45,6 +69,12 @@ type BackupLocation struct {
 	S3Config        *S3LocationConfig        `reform:"s3_config"`
 	PMMServerConfig *PMMServerLocationConfig `reform:"pmm_server_config"`
 	PMMClientConfig *PMMClientLocationConfig `reform:"pmm_client_config"`
+	// QuotaBytes is the maximum space artifacts can take in the location, zero means no limit.
+	QuotaBytes  int64       `reform:"quota_bytes"`
+	QuotaAction QuotaAction `reform:"quota_action"`
+	// UsageBytes is the space taken in the location when it was computed last time.
+	UsageBytes     int64      `reform:"usage_bytes"`
+	UsageUpdatedAt *time.Time `reform:"usage_updated_at"`
 
 	CreatedAt time.Time `reform:"created_at"`
 	UpdatedAt time.Time `reform:"updated_at"`
@@ -68,9 +98,17 @@ func (s *BackupLocation) BeforeUpdate() error {
 func (s *BackupLocation) AfterFind() error {
 	s.CreatedAt = s.CreatedAt.UTC()
 	s.UpdatedAt = s.UpdatedAt.UTC()
+	if s.UsageUpdatedAt != nil {
+		s.UsageUpdatedAt = pointer.ToTime(s.UsageUpdatedAt.UTC())
+	}
 	return nil
 }
 
+// QuotaExceeded returns true if location has a quota and its usage reached it.
+func (s *BackupLocation) QuotaExceeded() bool {
+	return s.QuotaBytes > 0 && s.UsageBytes >= s.QuotaBytes
+}
+
 // S3LocationConfig contains required properties for accessing S3 Bucket.
 type S3LocationConfig struct {
 	Endpoint     string `json:"endpoint"`
@@ -35,6 +35,10 @@ func (v *backupLocationTableType) Columns() []string {
 		"s3_config",
 		"pmm_server_config",
 		"pmm_client_config",
+		"quota_bytes",
+		"quota_action",
+		"usage_bytes",
+		"usage_updated_at",
 		"created_at",
 		"updated_at",
 	}
@@ -68,6 +72,10 @@ var BackupLocationTable = &backupLocationTableType{
 			{Name: "S3Config", Type: "*S3LocationConfig", Column: "s3_config"},
 			{Name: "PMMServerConfig", Type: "*PMMServerLocationConfig", Column: "pmm_server_config"},
 			{Name: "PMMClientConfig", Type: "*PMMClientLocationConfig", Column: "pmm_client_config"},
+			{Name: "QuotaBytes", Type: "int64", Column: "quota_bytes"},
+			{Name: "QuotaAction", Type: "QuotaAction", Column: "quota_action"},
+			{Name: "UsageBytes", Type: "int64", Column: "usage_bytes"},
+			{Name: "UsageUpdatedAt", Type: "*time.Time", Column: "usage_updated_at"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
 		},
@@ -78,7 +86,7 @@ var BackupLocationTable = &backupLocationTableType{
 
 // String returns a string representation of this struct or record.
 func (s BackupLocation) String() string {
-	res := make([]string, 9)
+	res := make([]string, 13)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "Name: " + reform.Inspect(s.Name, true)
 	res[2] = "Description: " + reform.Inspect(s.Description, true)
@@ -86,8 +94,12 @@ func (s BackupLocation) String() string {
 	res[4] = "S3Config: " + reform.Inspect(s.S3Config, true)
 	res[5] = "PMMServerConfig: " + reform.Inspect(s.PMMServerConfig, true)
 	res[6] = "PMMClientConfig: " + reform.Inspect(s.PMMClientConfig, true)
-	res[7] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[8] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[7] = "QuotaBytes: " + reform.Inspect(s.QuotaBytes, true)
+	res[8] = "QuotaAction: " + reform.Inspect(s.QuotaAction, true)
+	res[9] = "UsageBytes: " + reform.Inspect(s.UsageBytes, true)
+	res[10] = "UsageUpdatedAt: " + reform.Inspect(s.UsageUpdatedAt, true)
+	res[11] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[12] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -102,6 +114,10 @@ func (s *BackupLocation) Values() []interface{} {
 		s.S3Config,
 		s.PMMServerConfig,
 		s.PMMClientConfig,
+		s.QuotaBytes,
+		s.QuotaAction,
+		s.UsageBytes,
+		s.UsageUpdatedAt,
 		s.CreatedAt,
 		s.UpdatedAt,
 	}
@@ -118,6 +134,10 @@ func (s *BackupLocation) Pointers() []interface{} {
 		&s.S3Config,
 		&s.PMMServerConfig,
 		&s.PMMClientConfig,
+		&s.QuotaBytes,
+		&s.QuotaAction,
+		&s.UsageBytes,
+		&s.UsageUpdatedAt,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	}
@@ -0,0 +1,210 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+)
+
+// FindMaintenanceWindows returns all maintenance windows.
+func FindMaintenanceWindows(q *reform.Querier) ([]*MaintenanceWindow, error) {
+	rows, err := q.SelectAllFrom(MaintenanceWindowTable, "ORDER BY starts_at")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select maintenance windows")
+	}
+
+	windows := make([]*MaintenanceWindow, len(rows))
+	for i, s := range rows {
+		windows[i] = s.(*MaintenanceWindow)
+	}
+
+	return windows, nil
+}
+
+// FindMaintenanceWindowByID finds MaintenanceWindow by ID.
+func FindMaintenanceWindowByID(q *reform.Querier, id string) (*MaintenanceWindow, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "Empty maintenance window ID.")
+	}
+
+	window := &MaintenanceWindow{ID: id}
+	switch err := q.Reload(window); err {
+	case nil:
+		return window, nil
+	case reform.ErrNoRows:
+		return nil, status.Errorf(codes.NotFound, "Maintenance window with ID %q not found.", id)
+	default:
+		return nil, errors.WithStack(err)
+	}
+}
+
+// CreateMaintenanceWindowParams are params for creating new MaintenanceWindow.
+type CreateMaintenanceWindowParams struct {
+	Name           string
+	Description    string
+	StartsAt       time.Time
+	Duration       time.Duration
+	CronExpression string
+	Scope          MaintenanceWindowScope
+	Disabled       bool
+}
+
+// Validate validates params used for creating a maintenance window.
+func (p *CreateMaintenanceWindowParams) Validate() error {
+	if p.Name == "" {
+		return status.Error(codes.InvalidArgument, "Maintenance window name can't be empty.")
+	}
+
+	return validateMaintenanceWindow(p.StartsAt, p.Duration, p.CronExpression, p.Scope)
+}
+
+func validateMaintenanceWindow(startsAt time.Time, duration time.Duration, cronExpression string, scope MaintenanceWindowScope) error {
+	if startsAt.IsZero() {
+		return status.Error(codes.InvalidArgument, "Maintenance window start time can't be empty.")
+	}
+
+	if duration <= 0 {
+		return status.Error(codes.InvalidArgument, "Maintenance window duration should be positive.")
+	}
+
+	if cronExpression != "" {
+		if _, err := cron.ParseStandard(cronExpression); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid cron expression: %v", err)
+		}
+	}
+
+	// empty scope would silence all alerts
+	if scope.IsEmpty() {
+		return status.Error(codes.InvalidArgument, "Maintenance window scope can't be empty.")
+	}
+
+	return nil
+}
+
+// CreateMaintenanceWindow persists maintenance window.
+func CreateMaintenanceWindow(q *reform.Querier, params CreateMaintenanceWindowParams) (*MaintenanceWindow, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	window := &MaintenanceWindow{
+		ID:             "/maintenance_window_id/" + uuid.New().String(),
+		Name:           params.Name,
+		Description:    params.Description,
+		StartsAt:       params.StartsAt,
+		Duration:       params.Duration,
+		CronExpression: params.CronExpression,
+		Scope:          params.Scope,
+		Disabled:       params.Disabled,
+	}
+	if err := q.Insert(window); err != nil {
+		return nil, errors.Wrap(err, "failed to create maintenance window")
+	}
+
+	return window, nil
+}
+
+// ChangeMaintenanceWindowParams are params for updating existing maintenance window.
+type ChangeMaintenanceWindowParams struct {
+	Name           *string
+	Description    *string
+	StartsAt       *time.Time
+	Duration       *time.Duration
+	CronExpression *string
+	Scope          *MaintenanceWindowScope
+	Disabled       *bool
+}
+
+// ChangeMaintenanceWindow updates existing maintenance window.
+func ChangeMaintenanceWindow(q *reform.Querier, id string, params ChangeMaintenanceWindowParams) (*MaintenanceWindow, error) {
+	window, err := FindMaintenanceWindowByID(q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Name != nil {
+		if *params.Name == "" {
+			return nil, status.Error(codes.InvalidArgument, "Maintenance window name can't be empty.")
+		}
+		window.Name = *params.Name
+	}
+	if params.Description != nil {
+		window.Description = *params.Description
+	}
+	if params.StartsAt != nil {
+		window.StartsAt = *params.StartsAt
+	}
+	if params.Duration != nil {
+		window.Duration = *params.Duration
+	}
+	if params.CronExpression != nil {
+		window.CronExpression = *params.CronExpression
+	}
+	if params.Scope != nil {
+		window.Scope = *params.Scope
+	}
+	if params.Disabled != nil {
+		window.Disabled = *params.Disabled
+	}
+
+	if err = validateMaintenanceWindow(window.StartsAt, window.Duration, window.CronExpression, window.Scope); err != nil {
+		return nil, err
+	}
+
+	if err = q.Update(window); err != nil {
+		return nil, errors.Wrap(err, "failed to update maintenance window")
+	}
+
+	return window, nil
+}
+
+// SetMaintenanceWindowSilence stores Alertmanager silence created for the maintenance window.
+// Empty silenceID means that maintenance window isn't silenced.
+func SetMaintenanceWindowSilence(q *reform.Querier, id, silenceID string, silencedUntil *time.Time) error {
+	window, err := FindMaintenanceWindowByID(q, id)
+	if err != nil {
+		return err
+	}
+
+	window.SilenceID = silenceID
+	window.SilencedUntil = silencedUntil
+	if err = q.Update(window); err != nil {
+		return errors.Wrap(err, "failed to update maintenance window")
+	}
+
+	return nil
+}
+
+// RemoveMaintenanceWindow removes maintenance window with given ID.
+func RemoveMaintenanceWindow(q *reform.Querier, id string) error {
+	if _, err := FindMaintenanceWindowByID(q, id); err != nil {
+		return err
+	}
+
+	if err := q.Delete(&MaintenanceWindow{ID: id}); err != nil {
+		return errors.Wrap(err, "failed to delete maintenance window")
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	q := tx.Querier
+	params := models.CreateMaintenanceWindowParams{
+		Name:           "weekly",
+		StartsAt:       time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:       time.Hour,
+		CronExpression: "0 2 * * 6",
+		Scope: models.MaintenanceWindowScope{
+			Environments: []string{"prod"},
+		},
+	}
+
+	t.Run("create", func(t *testing.T) {
+		window, err := models.CreateMaintenanceWindow(q, params)
+		require.NoError(t, err)
+		assert.Equal(t, params.Name, window.Name)
+		assert.Equal(t, params.Scope, window.Scope)
+		assert.True(t, window.IsRecurring())
+
+		invalid := params
+		invalid.Scope = models.MaintenanceWindowScope{}
+		_, err = models.CreateMaintenanceWindow(q, invalid)
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Maintenance window scope can't be empty."), err)
+
+		invalid = params
+		invalid.CronExpression = "invalid"
+		_, err = models.CreateMaintenanceWindow(q, invalid)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("change and remove", func(t *testing.T) {
+		window, err := models.CreateMaintenanceWindow(q, params)
+		require.NoError(t, err)
+
+		window, err = models.ChangeMaintenanceWindow(q, window.ID, models.ChangeMaintenanceWindowParams{
+			CronExpression: pointer.ToString(""),
+			Disabled:       pointer.ToBool(true),
+		})
+		require.NoError(t, err)
+		assert.False(t, window.IsRecurring())
+		assert.True(t, window.Disabled)
+
+		silencedUntil := time.Now().UTC().Round(time.Second)
+		require.NoError(t, models.SetMaintenanceWindowSilence(q, window.ID, "silence", &silencedUntil))
+		window, err = models.FindMaintenanceWindowByID(q, window.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "silence", window.SilenceID)
+		assert.Equal(t, silencedUntil, *window.SilencedUntil)
+
+		require.NoError(t, models.RemoveMaintenanceWindow(q, window.ID))
+		_, err = models.FindMaintenanceWindowByID(q, window.ID)
+		tests.AssertGRPCError(t, status.Newf(codes.NotFound, "Maintenance window with ID %q not found.", window.ID), err)
+	})
+}
+
+func TestMaintenanceWindowActivePeriod(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) // Saturday
+
+	for _, tc := range []struct {
+		name   string
+		window models.MaintenanceWindow
+		now    time.Time
+		active bool
+		start  time.Time
+	}{
+		{
+			name:   "one-off before start",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour},
+			now:    startsAt.Add(-time.Minute),
+		},
+		{
+			name:   "one-off active",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour},
+			now:    startsAt,
+			active: true,
+			start:  startsAt,
+		},
+		{
+			name:   "one-off finished",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour},
+			now:    startsAt.Add(time.Hour),
+		},
+		{
+			name:   "disabled",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour, Disabled: true},
+			now:    startsAt,
+		},
+		{
+			name:   "recurring active",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour, CronExpression: "0 2 * * 6"},
+			now:    time.Date(2022, 1, 8, 2, 30, 0, 0, time.UTC),
+			active: true,
+			start:  time.Date(2022, 1, 8, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "recurring between occurrences",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour, CronExpression: "0 2 * * 6"},
+			now:    time.Date(2022, 1, 8, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "recurring overlapping occurrences",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: 2 * time.Hour, CronExpression: "0 * * * *"},
+			now:    time.Date(2022, 1, 8, 3, 30, 0, 0, time.UTC),
+			active: true,
+			start:  time.Date(2022, 1, 8, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "recurring matched in UTC",
+			window: models.MaintenanceWindow{StartsAt: startsAt, Duration: time.Hour, CronExpression: "0 2 * * 6"},
+			now:    time.Date(2022, 1, 8, 5, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60)),
+			active: true,
+			start:  time.Date(2022, 1, 8, 2, 0, 0, 0, time.UTC),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			start, end, active, err := tc.window.ActivePeriod(tc.now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.active, active)
+			if tc.active {
+				assert.Equal(t, tc.start, start)
+				assert.Equal(t, tc.start.Add(tc.window.Duration), end)
+			}
+		})
+	}
+}
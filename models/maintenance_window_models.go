@@ -0,0 +1,145 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/reform.v1"
+)
+
+//go:generate reform
+
+// MaintenanceWindowScope defines alerts silenced during maintenance window.
+// Alert should match all non-empty fields, and any value of each field.
+type MaintenanceWindowScope struct {
+	ServiceIDs   []string `json:"service_ids,omitempty"`
+	NodeIDs      []string `json:"node_ids,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	Clusters     []string `json:"clusters,omitempty"`
+}
+
+// IsEmpty returns true if scope doesn't limit silenced alerts.
+func (s MaintenanceWindowScope) IsEmpty() bool {
+	return len(s.ServiceIDs) == 0 && len(s.NodeIDs) == 0 && len(s.Environments) == 0 && len(s.Clusters) == 0
+}
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (s MaintenanceWindowScope) Value() (driver.Value, error) { return jsonValue(s) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (s *MaintenanceWindowScope) Scan(src interface{}) error { return jsonScan(s, src) }
+
+// MaintenanceWindow represents a period of time when alerts in the scope are silenced.
+// One-off window starts at StartsAt, recurring window starts by CronExpression, but not earlier than StartsAt.
+//reform:maintenance_windows
+type MaintenanceWindow struct {
+	ID             string                 `reform:"id,pk"`
+	Name           string                 `reform:"name"`
+	Description    string                 `reform:"description"`
+	StartsAt       time.Time              `reform:"starts_at"`
+	Duration       time.Duration          `reform:"duration"`
+	CronExpression string                 `reform:"cron_expression"`
+	Scope          MaintenanceWindowScope `reform:"scope"`
+	Disabled       bool                   `reform:"disabled"`
+	SilenceID      string                 `reform:"silence_id"`
+	SilencedUntil  *time.Time             `reform:"silenced_until"`
+	CreatedAt      time.Time              `reform:"created_at"`
+	UpdatedAt      time.Time              `reform:"updated_at"`
+}
+
+// BeforeInsert implements reform.BeforeInserter interface.
+func (w *MaintenanceWindow) BeforeInsert() error {
+	now := Now()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+	w.StartsAt = w.StartsAt.UTC()
+	return nil
+}
+
+// BeforeUpdate implements reform.BeforeUpdater interface.
+func (w *MaintenanceWindow) BeforeUpdate() error {
+	w.UpdatedAt = Now()
+	w.StartsAt = w.StartsAt.UTC()
+	return nil
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (w *MaintenanceWindow) AfterFind() error {
+	w.CreatedAt = w.CreatedAt.UTC()
+	w.UpdatedAt = w.UpdatedAt.UTC()
+	w.StartsAt = w.StartsAt.UTC()
+	if w.SilencedUntil != nil {
+		silencedUntil := w.SilencedUntil.UTC()
+		w.SilencedUntil = &silencedUntil
+	}
+	return nil
+}
+
+// IsRecurring returns true if maintenance window is repeated by cron expression.
+func (w *MaintenanceWindow) IsRecurring() bool {
+	return w.CronExpression != ""
+}
+
+// ActivePeriod returns start and end of the maintenance window occurrence that includes now.
+// The last argument is false if maintenance window isn't active at the moment.
+// Cron expression is matched in UTC as scheduled tasks are, regardless of the PMM Server time zone.
+func (w *MaintenanceWindow) ActivePeriod(now time.Time) (time.Time, time.Time, bool, error) {
+	now = now.UTC()
+	if w.Disabled || now.Before(w.StartsAt) {
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	start := w.StartsAt
+	if w.IsRecurring() {
+		schedule, err := cron.ParseStandard(w.CronExpression)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, errors.Wrap(err, "invalid cron expression")
+		}
+
+		from := now.Add(-w.Duration)
+		if from.Before(w.StartsAt) {
+			from = w.StartsAt
+		}
+
+		// Next returns time strictly after the given one, so step back to include occurrence starting right at from.
+		start = time.Time{}
+		for t := schedule.Next(from.Add(-time.Second)); !t.After(now); t = schedule.Next(t) {
+			start = t
+		}
+		if start.IsZero() {
+			return time.Time{}, time.Time{}, false, nil
+		}
+	}
+
+	end := start.Add(w.Duration)
+	if !now.Before(end) {
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	return start.UTC(), end.UTC(), true, nil
+}
+
+// check interfaces.
+var (
+	_ reform.BeforeInserter = (*MaintenanceWindow)(nil)
+	_ reform.BeforeUpdater  = (*MaintenanceWindow)(nil)
+	_ reform.AfterFinder    = (*MaintenanceWindow)(nil)
+)
@@ -0,0 +1,241 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/pointer"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+)
+
+// ReplicationRuleFilters represents filters for replication rules list.
+type ReplicationRuleFilters struct {
+	// Return only rules with specified source location.
+	SourceLocationID string
+}
+
+// FindReplicationRules returns replication rules list.
+func FindReplicationRules(q *reform.Querier, filters ReplicationRuleFilters) ([]*ReplicationRule, error) {
+	var tail string
+	var args []interface{}
+	if filters.SourceLocationID != "" {
+		tail = fmt.Sprintf("WHERE source_location_id = %s", q.Placeholder(1))
+		args = append(args, filters.SourceLocationID)
+	}
+
+	rows, err := q.SelectAllFrom(ReplicationRuleTable, tail+" ORDER BY created_at", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select replication rules")
+	}
+
+	rules := make([]*ReplicationRule, 0, len(rows))
+	for _, r := range rows {
+		rules = append(rules, r.(*ReplicationRule))
+	}
+
+	return rules, nil
+}
+
+// FindReplicationRuleByID returns replication rule by given ID if found, ErrNotFound if not.
+func FindReplicationRuleByID(q *reform.Querier, id string) (*ReplicationRule, error) {
+	if id == "" {
+		return nil, errors.New("provided replication rule id is empty")
+	}
+
+	rule := &ReplicationRule{ID: id}
+	switch err := q.Reload(rule); err {
+	case nil:
+		return rule, nil
+	case reform.ErrNoRows:
+		return nil, errors.Wrapf(ErrNotFound, "replication rule by id '%s'", id)
+	default:
+		return nil, errors.WithStack(err)
+	}
+}
+
+// CreateReplicationRuleParams are params for creating a new replication rule.
+type CreateReplicationRuleParams struct {
+	SourceLocationID string
+	TargetLocationID string
+}
+
+// CreateReplicationRule creates replication rule.
+func CreateReplicationRule(q *reform.Querier, params CreateReplicationRuleParams) (*ReplicationRule, error) {
+	if params.SourceLocationID == params.TargetLocationID {
+		return nil, NewInvalidArgumentError("source and target locations should be different")
+	}
+
+	for _, id := range []string{params.SourceLocationID, params.TargetLocationID} {
+		location, err := FindBackupLocationByID(q, id)
+		if err != nil {
+			return nil, err
+		}
+		if location.S3Config == nil {
+			return nil, NewInvalidArgumentError("only S3 locations can be replicated, location: %q", id)
+		}
+	}
+
+	rules, err := FindReplicationRules(q, ReplicationRuleFilters{SourceLocationID: params.SourceLocationID})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		if r.TargetLocationID == params.TargetLocationID {
+			return nil, status.Errorf(codes.AlreadyExists, "Replication rule from %q to %q already exists.",
+				params.SourceLocationID, params.TargetLocationID)
+		}
+	}
+
+	row := &ReplicationRule{
+		ID:               "/replication_rule_id/" + uuid.New().String(),
+		SourceLocationID: params.SourceLocationID,
+		TargetLocationID: params.TargetLocationID,
+	}
+	if err := q.Insert(row); err != nil {
+		return nil, errors.Wrap(err, "failed to insert replication rule")
+	}
+
+	return row, nil
+}
+
+// RemoveReplicationRule removes replication rule by ID. Existing replicas are kept.
+func RemoveReplicationRule(q *reform.Querier, id string) error {
+	if _, err := FindReplicationRuleByID(q, id); err != nil {
+		return err
+	}
+
+	if err := q.Delete(&ReplicationRule{ID: id}); err != nil {
+		return errors.Wrapf(err, "failed to delete replication rule by id '%s'", id)
+	}
+	return nil
+}
+
+// ArtifactReplicaFilters represents filters for artifact replicas list.
+type ArtifactReplicaFilters struct {
+	// Return only replicas of specified artifact.
+	ArtifactID string
+	// Return only replicas in specified location.
+	LocationID string
+	// Return only replicas with specified status.
+	Status ReplicaStatus
+}
+
+// FindArtifactReplicas returns artifact replicas list.
+func FindArtifactReplicas(q *reform.Querier, filters ArtifactReplicaFilters) ([]*ArtifactReplica, error) {
+	var conditions []string
+	var args []interface{}
+	idx := 1
+	if filters.ArtifactID != "" {
+		conditions = append(conditions, fmt.Sprintf("artifact_id = %s", q.Placeholder(idx)))
+		args = append(args, filters.ArtifactID)
+		idx++
+	}
+
+	if filters.LocationID != "" {
+		conditions = append(conditions, fmt.Sprintf("location_id = %s", q.Placeholder(idx)))
+		args = append(args, filters.LocationID)
+		idx++
+	}
+
+	if filters.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = %s", q.Placeholder(idx)))
+		args = append(args, filters.Status)
+	}
+
+	var whereClause string
+	if len(conditions) != 0 {
+		whereClause = fmt.Sprintf("WHERE %s", strings.Join(conditions, " AND "))
+	}
+	rows, err := q.SelectAllFrom(ArtifactReplicaTable, fmt.Sprintf("%s ORDER BY created_at", whereClause), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select artifact replicas")
+	}
+
+	replicas := make([]*ArtifactReplica, 0, len(rows))
+	for _, r := range rows {
+		replicas = append(replicas, r.(*ArtifactReplica))
+	}
+
+	return replicas, nil
+}
+
+// CreateArtifactReplica creates artifact replica entry in pending status,
+// existing replica of the artifact in the location is reset to pending status instead.
+func CreateArtifactReplica(q *reform.Querier, artifactID, locationID string) (*ArtifactReplica, error) {
+	replicas, err := FindArtifactReplicas(q, ArtifactReplicaFilters{ArtifactID: artifactID, LocationID: locationID})
+	if err != nil {
+		return nil, err
+	}
+	if len(replicas) != 0 {
+		return UpdateArtifactReplica(q, replicas[0].ID, UpdateArtifactReplicaParams{
+			Status: ReplicaStatusPointer(PendingReplicaStatus),
+			Error:  pointer.ToString(""),
+		})
+	}
+
+	row := &ArtifactReplica{
+		ID:         "/artifact_replica_id/" + uuid.New().String(),
+		ArtifactID: artifactID,
+		LocationID: locationID,
+		Status:     PendingReplicaStatus,
+	}
+	if err := q.Insert(row); err != nil {
+		return nil, errors.Wrap(err, "failed to insert artifact replica")
+	}
+
+	return row, nil
+}
+
+// UpdateArtifactReplicaParams are params for changing existing artifact replica.
+type UpdateArtifactReplicaParams struct {
+	Status *ReplicaStatus
+	Error  *string
+}
+
+// UpdateArtifactReplica updates existing artifact replica.
+func UpdateArtifactReplica(q *reform.Querier, id string, params UpdateArtifactReplicaParams) (*ArtifactReplica, error) {
+	row := &ArtifactReplica{ID: id}
+	switch err := q.Reload(row); err {
+	case nil:
+	case reform.ErrNoRows:
+		return nil, errors.Wrapf(ErrNotFound, "artifact replica by id '%s'", id)
+	default:
+		return nil, errors.WithStack(err)
+	}
+
+	if params.Status != nil {
+		if err := params.Status.Validate(); err != nil {
+			return nil, err
+		}
+		row.Status = *params.Status
+	}
+	if params.Error != nil {
+		row.Error = *params.Error
+	}
+
+	if err := q.Update(row); err != nil {
+		return nil, errors.Wrap(err, "failed to update artifact replica")
+	}
+
+	return row, nil
+}
@@ -0,0 +1,206 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestReplication(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	t.Cleanup(func() {
+		require.NoError(t, sqlDB.Close())
+	})
+
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+
+	nodeID, serviceID := "node_id_1", "service_id_1"
+	locationID1, locationID2, fsLocationID := "location_id_1", "location_id_2", "location_id_fs"
+
+	prepare := func(q *reform.Querier) *models.Artifact {
+		for _, str := range []reform.Struct{
+			&models.Node{
+				NodeID:   nodeID,
+				NodeType: models.GenericNodeType,
+				NodeName: "Node 1",
+			},
+			&models.Service{
+				ServiceID:   serviceID,
+				ServiceType: models.MySQLServiceType,
+				ServiceName: "Service 1",
+				NodeID:      nodeID,
+				Address:     pointer.ToString("127.0.0.1"),
+				Port:        pointer.ToUint16OrNil(777),
+			},
+			&models.BackupLocation{
+				ID:        locationID1,
+				Name:      "Location 1",
+				Type:      models.S3BackupLocationType,
+				S3Config:  &models.S3LocationConfig{},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			&models.BackupLocation{
+				ID:        locationID2,
+				Name:      "Location 2",
+				Type:      models.S3BackupLocationType,
+				S3Config:  &models.S3LocationConfig{},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			&models.BackupLocation{
+				ID:   fsLocationID,
+				Name: "Location 3",
+				Type: models.PMMClientBackupLocationType,
+				PMMClientConfig: &models.PMMClientLocationConfig{
+					Path: "/tmp",
+				},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		} {
+			require.NoError(t, q.Insert(str))
+		}
+
+		artifact, err := models.CreateArtifact(q, models.CreateArtifactParams{
+			Name:       "backup_name",
+			Vendor:     "MySQL",
+			LocationID: locationID1,
+			ServiceID:  serviceID,
+			DataModel:  models.PhysicalDataModel,
+			Status:     models.SuccessBackupStatus,
+			Mode:       models.Snapshot,
+		})
+		require.NoError(t, err)
+		return artifact
+	}
+
+	t.Run("rules", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, tx.Rollback())
+		})
+
+		q := tx.Querier
+		prepare(q)
+
+		rule, err := models.CreateReplicationRule(q, models.CreateReplicationRuleParams{
+			SourceLocationID: locationID1,
+			TargetLocationID: locationID2,
+		})
+		require.NoError(t, err)
+
+		_, err = models.CreateReplicationRule(q, models.CreateReplicationRuleParams{
+			SourceLocationID: locationID1,
+			TargetLocationID: locationID2,
+		})
+		tests.AssertGRPCError(t, status.New(codes.AlreadyExists,
+			`Replication rule from "location_id_1" to "location_id_2" already exists.`), err)
+
+		_, err = models.CreateReplicationRule(q, models.CreateReplicationRuleParams{
+			SourceLocationID: locationID1,
+			TargetLocationID: locationID1,
+		})
+		assert.EqualError(t, err, "invalid argument: source and target locations should be different")
+
+		_, err = models.CreateReplicationRule(q, models.CreateReplicationRuleParams{
+			SourceLocationID: locationID1,
+			TargetLocationID: fsLocationID,
+		})
+		assert.EqualError(t, err, `invalid argument: only S3 locations can be replicated, location: "location_id_fs"`)
+
+		rules, err := models.FindReplicationRules(q, models.ReplicationRuleFilters{SourceLocationID: locationID1})
+		require.NoError(t, err)
+		assert.Equal(t, []*models.ReplicationRule{rule}, rules)
+
+		rules, err = models.FindReplicationRules(q, models.ReplicationRuleFilters{SourceLocationID: locationID2})
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+
+		require.NoError(t, models.RemoveReplicationRule(q, rule.ID))
+		_, err = models.FindReplicationRuleByID(q, rule.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("replicas", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, tx.Rollback())
+		})
+
+		q := tx.Querier
+		artifact := prepare(q)
+
+		replica, err := models.CreateArtifactReplica(q, artifact.ID, locationID2)
+		require.NoError(t, err)
+		assert.Equal(t, models.PendingReplicaStatus, replica.Status)
+
+		replica, err = models.UpdateArtifactReplica(q, replica.ID, models.UpdateArtifactReplicaParams{
+			Status: models.ReplicaStatusPointer(models.ErrorReplicaStatus),
+			Error:  pointer.ToString("copy failed"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.ErrorReplicaStatus, replica.Status)
+		assert.Equal(t, "copy failed", replica.Error)
+
+		// Replicating again resets the existing replica.
+		again, err := models.CreateArtifactReplica(q, artifact.ID, locationID2)
+		require.NoError(t, err)
+		assert.Equal(t, replica.ID, again.ID)
+		assert.Equal(t, models.PendingReplicaStatus, again.Status)
+		assert.Empty(t, again.Error)
+
+		replicas, err := models.FindArtifactReplicas(q, models.ArtifactReplicaFilters{
+			ArtifactID: artifact.ID,
+			Status:     models.SuccessReplicaStatus,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, replicas)
+
+		_, err = models.UpdateArtifactReplica(q, replica.ID, models.UpdateArtifactReplicaParams{
+			Status: models.ReplicaStatusPointer(models.SuccessReplicaStatus),
+		})
+		require.NoError(t, err)
+
+		replicas, err = models.FindArtifactReplicas(q, models.ArtifactReplicaFilters{
+			ArtifactID: artifact.ID,
+			Status:     models.SuccessReplicaStatus,
+		})
+		require.NoError(t, err)
+		require.Len(t, replicas, 1)
+		assert.Equal(t, locationID2, replicas[0].LocationID)
+
+		err = models.RemoveBackupLocation(q, locationID2, models.RemoveRestrict)
+		tests.AssertGRPCError(t, status.New(codes.FailedPrecondition,
+			`backup location with ID "location_id_2" has artifact replicas.`), err)
+	})
+}
@@ -0,0 +1,129 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"gopkg.in/reform.v1"
+)
+
+//go:generate reform
+
+// ReplicationRule represents rule for copying successful artifacts from the source location to the target one.
+//reform:replication_rules
+type ReplicationRule struct {
+	ID               string    `reform:"id,pk"`
+	SourceLocationID string    `reform:"source_location_id"`
+	TargetLocationID string    `reform:"target_location_id"`
+	CreatedAt        time.Time `reform:"created_at"`
+	UpdatedAt        time.Time `reform:"updated_at"`
+}
+
+// BeforeInsert implements reform.BeforeInserter interface.
+func (r *ReplicationRule) BeforeInsert() error {
+	now := Now()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate implements reform.BeforeUpdater interface.
+func (r *ReplicationRule) BeforeUpdate() error {
+	r.UpdatedAt = Now()
+	return nil
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (r *ReplicationRule) AfterFind() error {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	return nil
+}
+
+// ReplicaStatus shows current status of artifact copy.
+type ReplicaStatus string
+
+// ReplicaStatus statuses.
+const (
+	PendingReplicaStatus    ReplicaStatus = "pending"
+	InProgressReplicaStatus ReplicaStatus = "in_progress"
+	SuccessReplicaStatus    ReplicaStatus = "success"
+	ErrorReplicaStatus      ReplicaStatus = "error"
+)
+
+// Validate validates replica status.
+func (rs ReplicaStatus) Validate() error {
+	switch rs {
+	case PendingReplicaStatus:
+	case InProgressReplicaStatus:
+	case SuccessReplicaStatus:
+	case ErrorReplicaStatus:
+	default:
+		return NewInvalidArgumentError("invalid replica status '%s'", rs)
+	}
+
+	return nil
+}
+
+// ReplicaStatusPointer returns a pointer of replica status.
+func ReplicaStatusPointer(status ReplicaStatus) *ReplicaStatus {
+	return &status
+}
+
+// ArtifactReplica represents a copy of artifact in the secondary location.
+//reform:artifact_replicas
+type ArtifactReplica struct {
+	ID         string        `reform:"id,pk"`
+	ArtifactID string        `reform:"artifact_id"`
+	LocationID string        `reform:"location_id"`
+	Status     ReplicaStatus `reform:"status"`
+	Error      string        `reform:"error"`
+	CreatedAt  time.Time     `reform:"created_at"`
+	UpdatedAt  time.Time     `reform:"updated_at"`
+}
+
+// BeforeInsert implements reform.BeforeInserter interface.
+func (r *ArtifactReplica) BeforeInsert() error {
+	now := Now()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	return nil
+}
+
+// BeforeUpdate implements reform.BeforeUpdater interface.
+func (r *ArtifactReplica) BeforeUpdate() error {
+	r.UpdatedAt = Now()
+	return nil
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (r *ArtifactReplica) AfterFind() error {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	return nil
+}
+
+// check interfaces.
+var (
+	_ reform.BeforeInserter = (*ReplicationRule)(nil)
+	_ reform.BeforeUpdater  = (*ReplicationRule)(nil)
+	_ reform.AfterFinder    = (*ReplicationRule)(nil)
+	_ reform.BeforeInserter = (*ArtifactReplica)(nil)
+	_ reform.BeforeUpdater  = (*ArtifactReplica)(nil)
+	_ reform.AfterFinder    = (*ArtifactReplica)(nil)
+)
@@ -19,6 +19,7 @@ package models
 import (
 	"time"
 
+	"github.com/AlekSi/pointer"
 	"gopkg.in/reform.v1"
 )
 
@@ -32,6 +33,8 @@ const (
 	InProgressRestoreStatus RestoreStatus = "in_progress"
 	SuccessRestoreStatus    RestoreStatus = "success"
 	ErrorRestoreStatus      RestoreStatus = "error"
+	// PausedRestoreStatus isn't in restores.proto yet.
+	PausedRestoreStatus RestoreStatus = "paused"
 )
 
 // Validate validates restore status.
@@ -40,6 +43,7 @@ func (rs RestoreStatus) Validate() error {
 	case InProgressRestoreStatus:
 	case SuccessRestoreStatus:
 	case ErrorRestoreStatus:
+	case PausedRestoreStatus:
 	default:
 		return NewInvalidArgumentError("invalid status %q", rs)
 	}
@@ -50,12 +54,13 @@ func (rs RestoreStatus) Validate() error {
 // RestoreHistoryItem represents a restore backup history.
 //reform:restore_history
 type RestoreHistoryItem struct {
-	ID         string        `reform:"id,pk"`
-	ArtifactID string        `reform:"artifact_id"`
-	ServiceID  string        `reform:"service_id"`
-	Status     RestoreStatus `reform:"status"`
-	StartedAt  time.Time     `reform:"started_at"`
-	FinishedAt *time.Time    `reform:"finished_at"`
+	ID            string        `reform:"id,pk"`
+	ArtifactID    string        `reform:"artifact_id"`
+	ServiceID     string        `reform:"service_id"`
+	PITRTimestamp *time.Time    `reform:"pitr_timestamp"`
+	Status        RestoreStatus `reform:"status"`
+	StartedAt     time.Time     `reform:"started_at"`
+	FinishedAt    *time.Time    `reform:"finished_at"`
 }
 
 // BeforeInsert implements reform.BeforeInserter interface.
@@ -67,6 +72,9 @@ func (s *RestoreHistoryItem) BeforeInsert() error {
 // AfterFind implements reform.AfterFinder interface.
 func (s *RestoreHistoryItem) AfterFind() error {
 	s.StartedAt = s.StartedAt.UTC()
+	if s.PITRTimestamp != nil {
+		s.PITRTimestamp = pointer.ToTime(s.PITRTimestamp.UTC())
+	}
 	return nil
 }
 
@@ -116,20 +116,29 @@ type CreateRuleParams struct {
 	CustomLabels      map[string]string
 	Labels            map[string]string
 	Annotations       map[string]string
+	RecordingRules    RecordingRules
+	Conditions        AlertConditions
 	Filters           Filters
 	ChannelIDs        []string
+
+	NotificationTemplate *NotificationTemplate
 }
 
-// CreateRule persists alert Rule.
-func CreateRule(q *reform.Querier, params *CreateRuleParams) (*Rule, error) {
-	id := "/rule_id/" + uuid.New().String()
-	var err error
-	if err = checkUniqueRuleID(q, id); err != nil {
+// NewRule returns alert Rule without ID and channels, it isn't persisted.
+func NewRule(params *CreateRuleParams) (*Rule, error) {
+	if err := checkNotificationTemplate(params.NotificationTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := checkRecordingRules(params.RecordingRules); err != nil {
+		return nil, err
+	}
+
+	if err := checkAlertConditions(params.Conditions, params.ParamsDefinitions); err != nil {
 		return nil, err
 	}
 
 	row := &Rule{
-		ID:                id,
 		Name:              params.Name,
 		TemplateName:      params.TemplateName,
 		Summary:           params.Summary,
@@ -141,9 +150,42 @@ func CreateRule(q *reform.Querier, params *CreateRuleParams) (*Rule, error) {
 		For:               params.For,
 		DefaultSeverity:   params.DefaultSeverity,
 		Severity:          params.Severity,
+		RecordingRules:    params.RecordingRules,
+		Conditions:        params.Conditions,
 		Filters:           params.Filters,
+
+		NotificationTemplate: params.NotificationTemplate,
+	}
+
+	if err := row.SetCustomLabels(params.CustomLabels); err != nil {
+		return nil, err
+	}
+
+	if err := row.SetLabels(params.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := row.SetAnnotations(params.Annotations); err != nil {
+		return nil, err
 	}
 
+	return row, nil
+}
+
+// CreateRule persists alert Rule.
+func CreateRule(q *reform.Querier, params *CreateRuleParams) (*Rule, error) {
+	id := "/rule_id/" + uuid.New().String()
+	var err error
+	if err = checkUniqueRuleID(q, id); err != nil {
+		return nil, err
+	}
+
+	row, err := NewRule(params)
+	if err != nil {
+		return nil, err
+	}
+	row.ID = id
+
 	if len(params.ChannelIDs) != 0 {
 		channelIDs := deduplicateStrings(params.ChannelIDs)
 		channels, err := FindChannelsByIDs(q, channelIDs)
@@ -159,18 +201,6 @@ func CreateRule(q *reform.Querier, params *CreateRuleParams) (*Rule, error) {
 		row.ChannelIDs = channelIDs
 	}
 
-	if err = row.SetCustomLabels(params.CustomLabels); err != nil {
-		return nil, err
-	}
-
-	if err = row.SetLabels(params.Labels); err != nil {
-		return nil, err
-	}
-
-	if err = row.SetAnnotations(params.Annotations); err != nil {
-		return nil, err
-	}
-
 	if err = q.Insert(row); err != nil {
 		return nil, errors.Wrap(err, "failed to create alert rule")
 	}
@@ -188,6 +218,8 @@ type ChangeRuleParams struct {
 	CustomLabels map[string]string
 	Filters      Filters
 	ChannelIDs   []string
+
+	NotificationTemplate *NotificationTemplate
 }
 
 // ChangeRule updates existing alerts Rule.
@@ -208,22 +240,76 @@ func ChangeRule(q *reform.Querier, ruleID string, params *ChangeRuleParams) (*Ru
 		return nil, status.Errorf(codes.NotFound, "Failed to find all required channels: %v.", missingChannelsIDs)
 	}
 
+	if err = ApplyChangeRuleParams(row, params); err != nil {
+		return nil, err
+	}
+
+	if err = q.Update(row); err != nil {
+		return nil, errors.Wrap(err, "failed to change alerts Rule")
+	}
+
+	return row, nil
+}
+
+// ApplyChangeRuleParams changes given alert Rule without persisting it; channels are not checked.
+func ApplyChangeRuleParams(row *Rule, params *ChangeRuleParams) error {
+	if err := checkNotificationTemplate(params.NotificationTemplate); err != nil {
+		return err
+	}
+
+	labels, err := json.Marshal(params.CustomLabels)
+	if err != nil {
+		return errors.Wrap(err, "failed to update alert rule")
+	}
+
 	row.Name = params.Name
 	row.Disabled = params.Disabled
 	row.For = params.For
 	row.Severity = params.Severity
 	row.Filters = params.Filters
 	row.ParamsValues = params.ParamsValues
+	row.NotificationTemplate = params.NotificationTemplate
+	row.CustomLabels = labels
+	row.ChannelIDs = params.ChannelIDs
 
-	labels, err := json.Marshal(params.CustomLabels)
+	return nil
+}
+
+// ChangeRuleFilters replaces filters of existing alerts Rule.
+func ChangeRuleFilters(q *reform.Querier, ruleID string, filters Filters) (*Rule, error) {
+	for _, f := range filters {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := FindRuleByID(q, ruleID)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to update alert rule")
+		return nil, err
 	}
-	row.CustomLabels = labels
-	row.ChannelIDs = params.ChannelIDs
 
+	row.Filters = filters
 	if err = q.Update(row); err != nil {
-		return nil, errors.Wrap(err, "failed to change alerts Rule")
+		return nil, errors.Wrap(err, "failed to change alerts Rule filters")
+	}
+
+	return row, nil
+}
+
+// ChangeRuleNotificationTemplate replaces notification template of existing alerts Rule, nil resets it to defaults.
+func ChangeRuleNotificationTemplate(q *reform.Querier, ruleID string, t *NotificationTemplate) (*Rule, error) {
+	if err := checkNotificationTemplate(t); err != nil {
+		return nil, err
+	}
+
+	row, err := FindRuleByID(q, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	row.NotificationTemplate = t
+	if err = q.Update(row); err != nil {
+		return nil, errors.Wrap(err, "failed to change alerts Rule notification template")
 	}
 
 	return row, nil
@@ -17,6 +17,7 @@
 package models_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -34,6 +35,65 @@ import (
 	"github.com/percona/pmm-managed/utils/tests"
 )
 
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{
+		"environment": "prod",
+		"service":     "mysql-1",
+		"port":        "3306",
+	}
+
+	for _, tt := range []struct {
+		filter models.Filter
+		match  bool
+	}{
+		{models.Filter{Type: models.Equal, Key: "environment", Val: "prod"}, true},
+		{models.Filter{Type: models.Equal, Key: "cluster", Val: ""}, false},
+		{models.Filter{Type: models.NotEqual, Key: "environment", Val: "prod"}, false},
+		{models.Filter{Type: models.NotEqual, Key: "cluster", Val: "main"}, true},
+		{models.Filter{Type: models.Regex, Key: "service", Val: "mysql-.*"}, true},
+		{models.Filter{Type: models.Regex, Key: "service", Val: "mysql"}, false},
+		{models.Filter{Type: models.NotRegex, Key: "service", Val: "mongo.*"}, true},
+		{models.Filter{Type: models.NotRegex, Key: "service", Val: "mysql-.*"}, false},
+		{models.Filter{Type: models.Greater, Key: "port", Val: "3000"}, true},
+		{models.Filter{Type: models.GreaterOrEqual, Key: "port", Val: "3306"}, true},
+		{models.Filter{Type: models.Less, Key: "port", Val: "3306"}, false},
+		{models.Filter{Type: models.LessOrEqual, Key: "port", Val: "3306.0"}, true},
+		{models.Filter{Type: models.Less, Key: "service", Val: "10"}, false},
+		{models.Filter{Type: models.Greater, Key: "cluster", Val: "0"}, false},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("%s%s%s", tt.filter.Key, tt.filter.Type, tt.filter.Val), func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, tt.filter.Validate())
+			match, err := tt.filter.Match(labels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, match)
+		})
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+
+		err := models.Filter{Type: models.NotEqual, Val: "prod"}.Validate()
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Filter key can't be empty."), err)
+
+		err = models.Filter{Type: models.Equal, Key: "service-name", Val: "mysql"}.Validate()
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid filter key "service-name".`), err)
+
+		err = models.Filter{Type: models.NotRegex, Key: "service", Val: ".***"}.Validate()
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, `Invalid filter regex "\.\*\*\*"`, err)
+
+		err = models.Filter{Type: models.Greater, Key: "port", Val: "many"}.Validate()
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid numeric filter value "many".`), err)
+
+		err = models.Filter{Type: "~", Key: "port", Val: "1"}.Validate()
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Unknown filter type "~".`), err)
+	})
+}
+
 func TestRules(t *testing.T) {
 	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
 	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
@@ -134,6 +194,33 @@ func TestRules(t *testing.T) {
 			assert.Empty(t, rule.ChannelIDs)
 		})
 
+		t.Run("notification template", func(t *testing.T) {
+			tx, err := db.Begin()
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, tx.Rollback())
+			}()
+
+			q := tx.Querier
+
+			template := createTemplate(t, q)
+			channel := createChannel(t, q)
+			rule, err := models.CreateRule(q, createCreateRuleParams(t, template, channel.ID, nonEmptyFilters))
+			require.NoError(t, err)
+
+			nt := &models.NotificationTemplate{Title: "{{ .CommonLabels.severity }}", Text: "{{ .Annotations.summary }}"}
+			updated, err := models.ChangeRuleNotificationTemplate(q, rule.ID, nt)
+			require.NoError(t, err)
+			assert.Equal(t, nt, updated.NotificationTemplate)
+
+			_, err = models.ChangeRuleNotificationTemplate(q, rule.ID, &models.NotificationTemplate{Title: "{{ .Foo"})
+			tests.AssertGRPCErrorRE(t, codes.InvalidArgument, `Invalid notification title template: .*`, err)
+
+			updated, err = models.ChangeRuleNotificationTemplate(q, rule.ID, nil)
+			require.NoError(t, err)
+			assert.Nil(t, updated.NotificationTemplate)
+		})
+
 		t.Run("unknown channel", func(t *testing.T) {
 			tx, err := db.Begin()
 			require.NoError(t, err)
@@ -201,6 +288,33 @@ func TestRules(t *testing.T) {
 			assert.ElementsMatch(t, params.ChannelIDs, updated.ChannelIDs)
 		})
 
+		t.Run("filters", func(t *testing.T) {
+			tx, err := db.Begin()
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, tx.Rollback())
+			}()
+
+			q := tx.Querier
+
+			template := createTemplate(t, q)
+			channel := createChannel(t, q)
+			rule, err := models.CreateRule(q, createCreateRuleParams(t, template, channel.ID, nonEmptyFilters))
+			require.NoError(t, err)
+
+			filters := models.Filters{
+				{Type: models.NotEqual, Key: "environment", Val: "dev"},
+				{Type: models.Greater, Key: "port", Val: "3000"},
+			}
+			updated, err := models.ChangeRuleFilters(q, rule.ID, filters)
+			require.NoError(t, err)
+			assert.Equal(t, filters, updated.Filters)
+			assert.Equal(t, rule.ChannelIDs, updated.ChannelIDs)
+
+			_, err = models.ChangeRuleFilters(q, rule.ID, models.Filters{{Type: models.Less, Key: "port", Val: "high"}})
+			tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid numeric filter value "high".`), err)
+		})
+
 		t.Run("unknown channel", func(t *testing.T) {
 			tx, err := db.Begin()
 			require.NoError(t, err)
@@ -19,8 +19,12 @@ package models
 import (
 	"database/sql/driver"
 	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/reform.v1"
 )
 
@@ -29,25 +33,28 @@ import (
 // Rule represents alert rule configuration.
 //reform:ia_rules
 type Rule struct {
-	ID                string                     `reform:"id,pk"`
-	Name              string                     `reform:"name"`
-	Summary           string                     `reform:"summary"`
-	TemplateName      string                     `reform:"template_name"`
-	Disabled          bool                       `reform:"disabled"`
-	ExprTemplate      string                     `reform:"expr_template"`
-	ParamsDefinitions AlertExprParamsDefinitions `reform:"params_definitions"`
-	ParamsValues      AlertExprParamsValues      `reform:"params_values"`
-	DefaultFor        time.Duration              `reform:"default_for"`
-	For               time.Duration              `reform:"for"`
-	DefaultSeverity   Severity                   `reform:"default_severity"`
-	Severity          Severity                   `reform:"severity"`
-	CustomLabels      []byte                     `reform:"custom_labels"`
-	Labels            []byte                     `reform:"labels"`
-	Annotations       []byte                     `reform:"annotations"`
-	Filters           Filters                    `reform:"filters"`
-	ChannelIDs        ChannelIDs                 `reform:"channel_ids"`
-	CreatedAt         time.Time                  `reform:"created_at"`
-	UpdatedAt         time.Time                  `reform:"updated_at"`
+	ID                   string                     `reform:"id,pk"`
+	Name                 string                     `reform:"name"`
+	Summary              string                     `reform:"summary"`
+	TemplateName         string                     `reform:"template_name"`
+	Disabled             bool                       `reform:"disabled"`
+	ExprTemplate         string                     `reform:"expr_template"`
+	ParamsDefinitions    AlertExprParamsDefinitions `reform:"params_definitions"`
+	ParamsValues         AlertExprParamsValues      `reform:"params_values"`
+	DefaultFor           time.Duration              `reform:"default_for"`
+	For                  time.Duration              `reform:"for"`
+	DefaultSeverity      Severity                   `reform:"default_severity"`
+	Severity             Severity                   `reform:"severity"`
+	CustomLabels         []byte                     `reform:"custom_labels"`
+	Labels               []byte                     `reform:"labels"`
+	Annotations          []byte                     `reform:"annotations"`
+	RecordingRules       RecordingRules             `reform:"recording_rules"`
+	Conditions           AlertConditions            `reform:"conditions"`
+	Filters              Filters                    `reform:"filters"`
+	ChannelIDs           ChannelIDs                 `reform:"channel_ids"`
+	NotificationTemplate *NotificationTemplate      `reform:"notification_template"`
+	CreatedAt            time.Time                  `reform:"created_at"`
+	UpdatedAt            time.Time                  `reform:"updated_at"`
 }
 
 // BeforeInsert implements reform.BeforeInserter interface.
@@ -108,10 +115,28 @@ type FilterType string
 
 // Available filter types.
 const (
-	Equal = FilterType("=")
-	Regex = FilterType("=~")
+	Equal    = FilterType("=")
+	NotEqual = FilterType("!=")
+	Regex    = FilterType("=~")
+	NotRegex = FilterType("!~")
+
+	// Numeric filter types compare label value parsed as a float.
+	Greater        = FilterType(">")
+	GreaterOrEqual = FilterType(">=")
+	Less           = FilterType("<")
+	LessOrEqual    = FilterType("<=")
 )
 
+// IsNumeric returns true for filter types comparing numeric label values.
+func (t FilterType) IsNumeric() bool {
+	switch t {
+	case Greater, GreaterOrEqual, Less, LessOrEqual:
+		return true
+	default:
+		return false
+	}
+}
+
 // Filters represents filters slice.
 type Filters []Filter
 
@@ -123,9 +148,79 @@ func (t *Filters) Scan(src interface{}) error { return jsonScan(t, src) }
 
 // Filter represents rule filter.
 type Filter struct {
-	Type FilterType `json:"type"`
-	Key  string     `json:"key"`
-	Val  string     `json:"value"`
+	Type FilterType `json:"type" yaml:"type"`
+	Key  string     `json:"key" yaml:"key"`
+	Val  string     `json:"value" yaml:"value"`
+}
+
+// Validate checks filter key, type and value.
+func (f Filter) Validate() error {
+	if f.Key == "" {
+		return status.Error(codes.InvalidArgument, "Filter key can't be empty.")
+	}
+	if !labelNameRE.MatchString(f.Key) {
+		return status.Errorf(codes.InvalidArgument, "Invalid filter key %q.", f.Key)
+	}
+
+	switch f.Type {
+	case Equal, NotEqual:
+	case Regex, NotRegex:
+		if _, err := regexp.Compile(f.Val); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid filter regex %q: %v.", f.Val, err)
+		}
+	case Greater, GreaterOrEqual, Less, LessOrEqual:
+		if _, err := strconv.ParseFloat(f.Val, 64); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid numeric filter value %q.", f.Val)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "Unknown filter type %q.", f.Type)
+	}
+
+	return nil
+}
+
+// Match returns true if given labels satisfy the filter.
+// Regular expressions are fully anchored like in Prometheus and Alertmanager.
+// Missing label satisfies negative filters only, and never satisfies numeric filters.
+func (f Filter) Match(labels map[string]string) (bool, error) {
+	value, ok := labels[f.Key]
+
+	switch f.Type {
+	case Equal:
+		return ok && value == f.Val, nil
+	case NotEqual:
+		return value != f.Val, nil
+	case Regex, NotRegex:
+		re, err := regexp.Compile("^(?:" + f.Val + ")$")
+		if err != nil {
+			return false, status.Errorf(codes.InvalidArgument, "Invalid filter regex %q: %v.", f.Val, err)
+		}
+		if f.Type == Regex {
+			return ok && re.MatchString(value), nil
+		}
+		return !re.MatchString(value), nil
+	case Greater, GreaterOrEqual, Less, LessOrEqual:
+		expected, err := strconv.ParseFloat(f.Val, 64)
+		if err != nil {
+			return false, status.Errorf(codes.InvalidArgument, "Invalid numeric filter value %q.", f.Val)
+		}
+		actual, err := strconv.ParseFloat(value, 64)
+		if !ok || err != nil {
+			return false, nil
+		}
+		switch f.Type {
+		case Greater:
+			return actual > expected, nil
+		case GreaterOrEqual:
+			return actual >= expected, nil
+		case Less:
+			return actual < expected, nil
+		default:
+			return actual <= expected, nil
+		}
+	default:
+		return false, status.Errorf(codes.InvalidArgument, "Unknown filter type %q.", f.Type)
+	}
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
@@ -166,11 +261,11 @@ func (p AlertExprParamsValues) AsStringMap() map[string]string {
 
 // AlertExprParamValue represents rule parameter value.
 type AlertExprParamValue struct {
-	Name        string    `json:"name"`
-	Type        ParamType `json:"type"`
-	BoolValue   bool      `json:"bool"`
-	FloatValue  float64   `json:"float"`
-	StringValue string    `json:"string"`
+	Name        string    `json:"name" yaml:"name"`
+	Type        ParamType `json:"type" yaml:"type"`
+	BoolValue   bool      `json:"bool" yaml:"bool"`
+	FloatValue  float64   `json:"float" yaml:"float"`
+	StringValue string    `json:"string" yaml:"string"`
 }
 
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
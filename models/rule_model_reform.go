@@ -43,8 +43,11 @@ func (v *ruleTableType) Columns() []string {
 		"custom_labels",
 		"labels",
 		"annotations",
+		"recording_rules",
+		"conditions",
 		"filters",
 		"channel_ids",
+		"notification_template",
 		"created_at",
 		"updated_at",
 	}
@@ -86,8 +89,11 @@ var RuleTable = &ruleTableType{
 			{Name: "CustomLabels", Type: "[]uint8", Column: "custom_labels"},
 			{Name: "Labels", Type: "[]uint8", Column: "labels"},
 			{Name: "Annotations", Type: "[]uint8", Column: "annotations"},
+			{Name: "RecordingRules", Type: "RecordingRules", Column: "recording_rules"},
+			{Name: "Conditions", Type: "AlertConditions", Column: "conditions"},
 			{Name: "Filters", Type: "Filters", Column: "filters"},
 			{Name: "ChannelIDs", Type: "ChannelIDs", Column: "channel_ids"},
+			{Name: "NotificationTemplate", Type: "*NotificationTemplate", Column: "notification_template"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
 		},
@@ -98,7 +104,7 @@ var RuleTable = &ruleTableType{
 
 // String returns a string representation of this struct or record.
 func (s Rule) String() string {
-	res := make([]string, 19)
+	res := make([]string, 22)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "Name: " + reform.Inspect(s.Name, true)
 	res[2] = "Summary: " + reform.Inspect(s.Summary, true)
@@ -114,10 +120,13 @@ func (s Rule) String() string {
 	res[12] = "CustomLabels: " + reform.Inspect(s.CustomLabels, true)
 	res[13] = "Labels: " + reform.Inspect(s.Labels, true)
 	res[14] = "Annotations: " + reform.Inspect(s.Annotations, true)
-	res[15] = "Filters: " + reform.Inspect(s.Filters, true)
-	res[16] = "ChannelIDs: " + reform.Inspect(s.ChannelIDs, true)
-	res[17] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[18] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[15] = "RecordingRules: " + reform.Inspect(s.RecordingRules, true)
+	res[16] = "Conditions: " + reform.Inspect(s.Conditions, true)
+	res[17] = "Filters: " + reform.Inspect(s.Filters, true)
+	res[18] = "ChannelIDs: " + reform.Inspect(s.ChannelIDs, true)
+	res[19] = "NotificationTemplate: " + reform.Inspect(s.NotificationTemplate, true)
+	res[20] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[21] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -140,8 +149,11 @@ func (s *Rule) Values() []interface{} {
 		s.CustomLabels,
 		s.Labels,
 		s.Annotations,
+		s.RecordingRules,
+		s.Conditions,
 		s.Filters,
 		s.ChannelIDs,
+		s.NotificationTemplate,
 		s.CreatedAt,
 		s.UpdatedAt,
 	}
@@ -166,8 +178,11 @@ func (s *Rule) Pointers() []interface{} {
 		&s.CustomLabels,
 		&s.Labels,
 		&s.Annotations,
+		&s.RecordingRules,
+		&s.Conditions,
 		&s.Filters,
 		&s.ChannelIDs,
+		&s.NotificationTemplate,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	}
@@ -30,11 +30,46 @@ type ScheduledTaskType string
 
 // Supported scheduled task types.
 const (
-	ScheduledMySQLBackupTask   = ScheduledTaskType("mysql_backup")
-	ScheduledMongoDBBackupTask = ScheduledTaskType("mongodb_backup")
+	ScheduledMySQLBackupTask          = ScheduledTaskType("mysql_backup")
+	ScheduledMongoDBBackupTask        = ScheduledTaskType("mongodb_backup")
+	ScheduledSecurityChecksTask       = ScheduledTaskType("security_checks")
+	ScheduledQueryActionTask          = ScheduledTaskType("query_action")
+	ScheduledPTSummaryTask            = ScheduledTaskType("pt_summary")
+	ScheduledArtifactVerificationTask = ScheduledTaskType("artifact_verification")
 )
 
+// CatchUpPolicy defines what to do with runs of the scheduled task missed during pmm-managed downtime.
+type CatchUpPolicy string
+
+// Supported catch-up policies.
+const (
+	// SkipCatchUpPolicy skips missed runs, task runs next time according to its schedule.
+	SkipCatchUpPolicy CatchUpPolicy = "skip"
+	// RunOnceCatchUpPolicy runs task once at startup if any runs were missed.
+	RunOnceCatchUpPolicy CatchUpPolicy = "run_once"
+	// RunAllCatchUpPolicy runs task at startup once for each missed run, up to MaxCatchUpRuns times.
+	// It can't be used for backups.
+	RunAllCatchUpPolicy CatchUpPolicy = "run_all"
+)
+
+// MaxCatchUpRuns limits the number of missed runs made at startup with RunAllCatchUpPolicy.
+const MaxCatchUpRuns = 10
+
+// Validate validates catch-up policy.
+func (p CatchUpPolicy) Validate() error {
+	switch p {
+	case SkipCatchUpPolicy:
+	case RunOnceCatchUpPolicy:
+	case RunAllCatchUpPolicy:
+	default:
+		return NewInvalidArgumentError("invalid catch-up policy '%s'", p)
+	}
+
+	return nil
+}
+
 // ScheduledTask describes a scheduled task.
+//
 //reform:scheduled_tasks
 type ScheduledTask struct {
 	ID             string             `reform:"id,pk"`
@@ -47,27 +82,67 @@ type ScheduledTask struct {
 	Data           *ScheduledTaskData `reform:"data"`
 	Running        bool               `reform:"running"`
 	Error          string             `reform:"error"`
-	CreatedAt      time.Time          `reform:"created_at"`
-	UpdatedAt      time.Time          `reform:"updated_at"`
+	CatchUpPolicy  CatchUpPolicy      `reform:"catch_up_policy"`
+	// DeferredReason is set while the run is queued because of blackout window or concurrency limits.
+	DeferredReason string    `reform:"deferred_reason"`
+	CreatedAt      time.Time `reform:"created_at"`
+	UpdatedAt      time.Time `reform:"updated_at"`
 }
 
 // ScheduledTaskData contains result data for different task types.
 type ScheduledTaskData struct {
-	MySQLBackupTask   *MySQLBackupTaskData `json:"mysql_backup,omitempty"`
-	MongoDBBackupTask *MongoBackupTaskData `json:"mongodb_backup,omitempty"`
+	MySQLBackupTask          *MySQLBackupTaskData          `json:"mysql_backup,omitempty"`
+	MongoDBBackupTask        *MongoBackupTaskData          `json:"mongodb_backup,omitempty"`
+	SecurityChecksTask       *SecurityChecksTaskData       `json:"security_checks,omitempty"`
+	QueryActionTask          *QueryActionTaskData          `json:"query_action,omitempty"`
+	PTSummaryTask            *PTSummaryTaskData            `json:"pt_summary,omitempty"`
+	ArtifactVerificationTask *ArtifactVerificationTaskData `json:"artifact_verification,omitempty"`
+}
+
+// RetentionPolicy contains time-based and grandfather-father-son (GFS) retention rules for scheduled backups.
+// Artifact is kept if it matches at least one of the rules or the count-based retention.
+// Zero count-based retention is not a rule: with non-empty policy, it doesn't keep any artifacts by count.
+type RetentionPolicy struct {
+	// Keep artifacts younger than MaxAge.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+	// Keep the latest artifact of each of the Daily most recent days having artifacts.
+	Daily uint32 `json:"daily,omitempty"`
+	// Keep the latest artifact of each of the Weekly most recent ISO weeks having artifacts.
+	Weekly uint32 `json:"weekly,omitempty"`
+	// Keep the latest artifact of each of the Monthly most recent months having artifacts.
+	Monthly uint32 `json:"monthly,omitempty"`
+}
+
+// IsEmpty returns true if retention policy doesn't have any rules.
+func (p *RetentionPolicy) IsEmpty() bool {
+	return p == nil || (p.MaxAge == 0 && p.Daily == 0 && p.Weekly == 0 && p.Monthly == 0)
+}
+
+// Validate validates retention policy.
+func (p *RetentionPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.MaxAge < 0 {
+		return NewInvalidArgumentError("retention max age can't be negative")
+	}
+
+	return nil
 }
 
 // CommonBackupTaskData contains common data for all backup tasks.
 type CommonBackupTaskData struct {
-	ServiceID     string        `json:"service_id"`
-	LocationID    string        `json:"location_id"`
-	Name          string        `json:"name"`
-	Description   string        `json:"description"`
-	Retention     uint32        `json:"retention"`
-	DataModel     DataModel     `json:"data_model"`
-	Mode          BackupMode    `json:"mode"`
-	Retries       uint32        `json:"retries"`
-	RetryInterval time.Duration `json:"retry_interval"`
+	ServiceID       string           `json:"service_id"`
+	LocationID      string           `json:"location_id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Retention       uint32           `json:"retention"`
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+	DataModel       DataModel        `json:"data_model"`
+	Mode            BackupMode       `json:"mode"`
+	Retries         uint32           `json:"retries"`
+	RetryInterval   time.Duration    `json:"retry_interval"`
 }
 
 // MySQLBackupTaskData contains data for mysql backup task.
@@ -80,6 +155,82 @@ type MongoBackupTaskData struct {
 	CommonBackupTaskData
 }
 
+// CommonTaskData contains common data for tasks which aren't backups.
+type CommonTaskData struct {
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Retries       uint32        `json:"retries"`
+	RetryInterval time.Duration `json:"retry_interval"`
+}
+
+// ActionTaskResult contains output of the action started by the latest task run.
+type ActionTaskResult struct {
+	ActionID   string    `json:"action_id,omitempty"`
+	Output     string    `json:"output,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// SecurityChecksTaskData contains data for security checks task.
+// All enabled checks are executed if CheckNames is empty.
+type SecurityChecksTaskData struct {
+	CommonTaskData
+	CheckNames []string `json:"check_names,omitempty"`
+}
+
+// QueryActionType represents type of the Query Action executed by the scheduled task.
+type QueryActionType string
+
+// Supported Query Action types.
+const (
+	MySQLQueryShowAction           = QueryActionType("mysql_query_show")
+	MySQLQuerySelectAction         = QueryActionType("mysql_query_select")
+	PostgreSQLQueryShowAction      = QueryActionType("postgresql_query_show")
+	PostgreSQLQuerySelectAction    = QueryActionType("postgresql_query_select")
+	MongoDBQueryBuildInfoAction    = QueryActionType("mongodb_query_buildinfo")
+	MongoDBQueryGetParameterAction = QueryActionType("mongodb_query_getparameter")
+)
+
+// Validate checks that Query Action type is supported and matches the query.
+func (t QueryActionType) Validate(query string) error {
+	switch t {
+	case MySQLQueryShowAction, MySQLQuerySelectAction, PostgreSQLQuerySelectAction:
+		if query == "" {
+			return NewInvalidArgumentError("query can't be empty for %s action", t)
+		}
+	case PostgreSQLQueryShowAction, MongoDBQueryBuildInfoAction, MongoDBQueryGetParameterAction:
+	case "":
+		return NewInvalidArgumentError("empty query action type")
+	default:
+		return NewInvalidArgumentError("invalid query action type '%s'", t)
+	}
+
+	return nil
+}
+
+// QueryActionTaskData contains data for Query Action task.
+type QueryActionTaskData struct {
+	CommonTaskData
+	ServiceID  string            `json:"service_id"`
+	ActionType QueryActionType   `json:"action_type"`
+	Query      string            `json:"query,omitempty"`
+	LastResult *ActionTaskResult `json:"last_result,omitempty"`
+}
+
+// PTSummaryTaskData contains data for pt-summary collection task.
+type PTSummaryTaskData struct {
+	CommonTaskData
+	NodeID     string            `json:"node_id"`
+	LastResult *ActionTaskResult `json:"last_result,omitempty"`
+}
+
+// ArtifactVerificationTaskData contains data for artifact verification task.
+type ArtifactVerificationTaskData struct {
+	CommonTaskData
+	ServiceID string `json:"service_id"`
+	// LocationID limits verified artifacts to the given location, any location is used if it's empty.
+	LocationID string `json:"location_id,omitempty"`
+}
+
 // Value implements database/sql/driver.Valuer interface. Should be defined on the value.
 func (c ScheduledTaskData) Value() (driver.Value, error) { return jsonValue(c) }
 
@@ -38,6 +38,8 @@ func (v *scheduledTaskTableType) Columns() []string {
 		"data",
 		"running",
 		"error",
+		"catch_up_policy",
+		"deferred_reason",
 		"created_at",
 		"updated_at",
 	}
@@ -74,6 +76,8 @@ var ScheduledTaskTable = &scheduledTaskTableType{
 			{Name: "Data", Type: "*ScheduledTaskData", Column: "data"},
 			{Name: "Running", Type: "bool", Column: "running"},
 			{Name: "Error", Type: "string", Column: "error"},
+			{Name: "CatchUpPolicy", Type: "CatchUpPolicy", Column: "catch_up_policy"},
+			{Name: "DeferredReason", Type: "string", Column: "deferred_reason"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
 		},
@@ -84,7 +88,7 @@ var ScheduledTaskTable = &scheduledTaskTableType{
 
 // String returns a string representation of this struct or record.
 func (s ScheduledTask) String() string {
-	res := make([]string, 12)
+	res := make([]string, 14)
 	res[0] = "ID: " + reform.Inspect(s.ID, true)
 	res[1] = "CronExpression: " + reform.Inspect(s.CronExpression, true)
 	res[2] = "Disabled: " + reform.Inspect(s.Disabled, true)
@@ -95,8 +99,10 @@ func (s ScheduledTask) String() string {
 	res[7] = "Data: " + reform.Inspect(s.Data, true)
 	res[8] = "Running: " + reform.Inspect(s.Running, true)
 	res[9] = "Error: " + reform.Inspect(s.Error, true)
-	res[10] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[11] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[10] = "CatchUpPolicy: " + reform.Inspect(s.CatchUpPolicy, true)
+	res[11] = "DeferredReason: " + reform.Inspect(s.DeferredReason, true)
+	res[12] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[13] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -114,6 +120,8 @@ func (s *ScheduledTask) Values() []interface{} {
 		s.Data,
 		s.Running,
 		s.Error,
+		s.CatchUpPolicy,
+		s.DeferredReason,
 		s.CreatedAt,
 		s.UpdatedAt,
 	}
@@ -133,6 +141,8 @@ func (s *ScheduledTask) Pointers() []interface{} {
 		&s.Data,
 		&s.Running,
 		&s.Error,
+		&s.CatchUpPolicy,
+		&s.DeferredReason,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	}
@@ -0,0 +1,124 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"github.com/AlekSi/pointer"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gopkg.in/reform.v1"
+)
+
+// FindScheduledTaskRunByID returns scheduled task run by given ID if found, ErrNotFound if not.
+func FindScheduledTaskRunByID(q *reform.Querier, id string) (*ScheduledTaskRun, error) {
+	if id == "" {
+		return nil, errors.New("provided scheduled task run id is empty")
+	}
+
+	run := &ScheduledTaskRun{ID: id}
+	switch err := q.Reload(run); err {
+	case nil:
+		return run, nil
+	case reform.ErrNoRows:
+		return nil, errors.Wrapf(ErrNotFound, "scheduled task run by id '%s'", id)
+	default:
+		return nil, errors.WithStack(err)
+	}
+}
+
+// FindScheduledTaskRunsOnPage returns a page with runs of the scheduled task, the latest runs go first.
+func FindScheduledTaskRunsOnPage(q *reform.Querier, scheduledTaskID string, pageIndex, pageSize int) ([]*ScheduledTaskRun, error) {
+	rows, err := q.SelectAllFrom(ScheduledTaskRunTable, "WHERE scheduled_task_id = $1 ORDER BY started_at DESC LIMIT $2 OFFSET $3",
+		scheduledTaskID, pageSize, pageIndex*pageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select scheduled task runs")
+	}
+
+	runs := make([]*ScheduledTaskRun, len(rows))
+	for i, r := range rows {
+		runs[i] = r.(*ScheduledTaskRun)
+	}
+
+	return runs, nil
+}
+
+// CountScheduledTaskRuns returns number of runs of the scheduled task.
+func CountScheduledTaskRuns(q *reform.Querier, scheduledTaskID string) (int, error) {
+	count, err := q.Count(ScheduledTaskRunTable, "WHERE scheduled_task_id = $1", scheduledTaskID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count scheduled task runs")
+	}
+
+	return count, nil
+}
+
+// CreateScheduledTaskRun stores start of the scheduled task run.
+func CreateScheduledTaskRun(q *reform.Querier, scheduledTaskID string, catchUp bool) (*ScheduledTaskRun, error) {
+	if _, err := FindScheduledTaskByID(q, scheduledTaskID); err != nil {
+		return nil, err
+	}
+
+	run := &ScheduledTaskRun{
+		ID:              "/scheduled_task_run_id/" + uuid.New().String(),
+		ScheduledTaskID: scheduledTaskID,
+		Status:          RunningScheduledTaskRunStatus,
+		CatchUp:         catchUp,
+		StartedAt:       Now(),
+	}
+	if err := q.Insert(run); err != nil {
+		return nil, errors.Wrap(err, "failed to insert scheduled task run")
+	}
+
+	return run, nil
+}
+
+// FinishScheduledTaskRunParams are params for storing outcome of the scheduled task run.
+type FinishScheduledTaskRunParams struct {
+	// Error is empty for successful runs.
+	Error      string
+	ArtifactID string
+	Retries    uint32
+}
+
+// FinishScheduledTaskRun stores outcome of the scheduled task run.
+func FinishScheduledTaskRun(q *reform.Querier, id string, params FinishScheduledTaskRunParams) (*ScheduledTaskRun, error) {
+	run, err := FindScheduledTaskRunByID(q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Status = SuccessScheduledTaskRunStatus
+	if params.Error != "" {
+		run.Status = ErrorScheduledTaskRunStatus
+	}
+	run.Error = params.Error
+	run.ArtifactID = params.ArtifactID
+	run.Retries = params.Retries
+	run.FinishedAt = pointer.ToTime(Now())
+	if err = q.Update(run); err != nil {
+		return nil, errors.Wrap(err, "failed to update scheduled task run")
+	}
+
+	return run, nil
+}
+
+// FailInterruptedScheduledTaskRuns sets error status for runs which weren't finished, e.g. because of pmm-managed restart.
+func FailInterruptedScheduledTaskRuns(q *reform.Querier) error {
+	_, err := q.Exec("UPDATE scheduled_task_runs SET status = $1, error = $2, finished_at = $3 WHERE status = $4",
+		ErrorScheduledTaskRunStatus, "interrupted", Now(), RunningScheduledTaskRunStatus)
+	return errors.Wrap(err, "failed to update interrupted scheduled task runs")
+}
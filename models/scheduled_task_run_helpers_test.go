@@ -0,0 +1,119 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+)
+
+func TestScheduledTaskRunHelpers(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, sqlDB.Close())
+	})
+
+	q := tx.Querier
+
+	task, err := models.CreateScheduledTask(q, models.CreateScheduledTaskParams{
+		CronExpression: "* * * * *",
+		Type:           models.ScheduledPTSummaryTask,
+		Data: &models.ScheduledTaskData{
+			PTSummaryTask: &models.PTSummaryTaskData{
+				CommonTaskData: models.CommonTaskData{Name: "task"},
+				NodeID:         "node-id",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.SkipCatchUpPolicy, task.CatchUpPolicy)
+
+	_, err = models.CreateScheduledTask(q, models.CreateScheduledTaskParams{
+		CronExpression: "* * * * *",
+		Type:           models.ScheduledPTSummaryTask,
+		CatchUpPolicy:  "invalid",
+	})
+	assert.EqualError(t, err, "invalid argument: invalid catch-up policy 'invalid'")
+
+	t.Run("finish", func(t *testing.T) {
+		run, err := models.CreateScheduledTaskRun(q, task.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunningScheduledTaskRunStatus, run.Status)
+		assert.Nil(t, run.FinishedAt)
+
+		run, err = models.FinishScheduledTaskRun(q, run.ID, models.FinishScheduledTaskRunParams{
+			Error:   "failed",
+			Retries: 2,
+		})
+		require.NoError(t, err)
+
+		run, err = models.FindScheduledTaskRunByID(q, run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ErrorScheduledTaskRunStatus, run.Status)
+		assert.Equal(t, "failed", run.Error)
+		assert.Equal(t, uint32(2), run.Retries)
+		assert.NotNil(t, run.FinishedAt)
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		run, err := models.CreateScheduledTaskRun(q, task.ID, true)
+		require.NoError(t, err)
+
+		require.NoError(t, models.FailInterruptedScheduledTaskRuns(q))
+
+		run, err = models.FindScheduledTaskRunByID(q, run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ErrorScheduledTaskRunStatus, run.Status)
+		assert.Equal(t, "interrupted", run.Error)
+		assert.True(t, run.CatchUp)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		total, err := models.CountScheduledTaskRuns(q, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+
+		runs, err := models.FindScheduledTaskRunsOnPage(q, task.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.True(t, runs[0].CatchUp)
+
+		runs, err = models.FindScheduledTaskRunsOnPage(q, task.ID, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.False(t, runs[0].CatchUp)
+	})
+
+	t.Run("removed with task", func(t *testing.T) {
+		require.NoError(t, models.RemoveScheduledTask(q, task.ID))
+
+		total, err := models.CountScheduledTaskRuns(q, task.ID)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+}
@@ -0,0 +1,80 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"gopkg.in/reform.v1"
+)
+
+//go:generate reform
+
+// ScheduledTaskRunStatus shows outcome of the scheduled task run.
+type ScheduledTaskRunStatus string
+
+// ScheduledTaskRunStatus statuses.
+const (
+	RunningScheduledTaskRunStatus ScheduledTaskRunStatus = "running"
+	SuccessScheduledTaskRunStatus ScheduledTaskRunStatus = "success"
+	ErrorScheduledTaskRunStatus   ScheduledTaskRunStatus = "error"
+)
+
+// Validate validates scheduled task run status.
+func (s ScheduledTaskRunStatus) Validate() error {
+	switch s {
+	case RunningScheduledTaskRunStatus:
+	case SuccessScheduledTaskRunStatus:
+	case ErrorScheduledTaskRunStatus:
+	default:
+		return NewInvalidArgumentError("invalid scheduled task run status '%s'", s)
+	}
+
+	return nil
+}
+
+// ScheduledTaskRun represents a single run of the scheduled task.
+//reform:scheduled_task_runs
+type ScheduledTaskRun struct {
+	ID              string                 `reform:"id,pk"`
+	ScheduledTaskID string                 `reform:"scheduled_task_id"`
+	Status          ScheduledTaskRunStatus `reform:"status"`
+	Error           string                 `reform:"error"`
+	// ArtifactID is set for runs of backup tasks.
+	ArtifactID string `reform:"artifact_id"`
+	// Retries is a number of retries made by the task during this run.
+	Retries uint32 `reform:"retries"`
+	// CatchUp is true for runs made at startup instead of runs missed during downtime.
+	CatchUp    bool       `reform:"catch_up"`
+	StartedAt  time.Time  `reform:"started_at"`
+	FinishedAt *time.Time `reform:"finished_at"`
+}
+
+// AfterFind implements reform.AfterFinder interface.
+func (r *ScheduledTaskRun) AfterFind() error {
+	r.StartedAt = r.StartedAt.UTC()
+	if r.FinishedAt != nil {
+		r.FinishedAt = pointer.ToTime(r.FinishedAt.UTC())
+	}
+	return nil
+}
+
+// check interfaces.
+var (
+	_ reform.AfterFinder = (*ScheduledTaskRun)(nil)
+)
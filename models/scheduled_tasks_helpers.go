@@ -129,6 +129,8 @@ type CreateScheduledTaskParams struct {
 	Type           ScheduledTaskType
 	Data           *ScheduledTaskData
 	Disabled       bool
+	// CatchUpPolicy defaults to SkipCatchUpPolicy.
+	CatchUpPolicy CatchUpPolicy
 }
 
 // Validate checks if required params are set and valid.
@@ -136,6 +138,10 @@ func (p CreateScheduledTaskParams) Validate() error {
 	switch p.Type {
 	case ScheduledMySQLBackupTask:
 	case ScheduledMongoDBBackupTask:
+	case ScheduledSecurityChecksTask:
+	case ScheduledQueryActionTask:
+	case ScheduledPTSummaryTask:
+	case ScheduledArtifactVerificationTask:
 	default:
 		return status.Errorf(codes.InvalidArgument, "Unknown type: %s", p.Type)
 	}
@@ -144,6 +150,10 @@ func (p CreateScheduledTaskParams) Validate() error {
 		return status.Errorf(codes.InvalidArgument, "Invalid cron expression: %v", err)
 	}
 
+	if p.CatchUpPolicy != "" {
+		return p.CatchUpPolicy.Validate()
+	}
+
 	return nil
 }
 
@@ -157,6 +167,11 @@ func CreateScheduledTask(q *reform.Querier, params CreateScheduledTaskParams) (*
 		return nil, err
 	}
 
+	catchUpPolicy := params.CatchUpPolicy
+	if catchUpPolicy == "" {
+		catchUpPolicy = SkipCatchUpPolicy
+	}
+
 	task := &ScheduledTask{
 		ID:             id,
 		CronExpression: params.CronExpression,
@@ -165,6 +180,7 @@ func CreateScheduledTask(q *reform.Querier, params CreateScheduledTaskParams) (*
 		NextRun:        params.NextRun,
 		Type:           params.Type,
 		Data:           params.Data,
+		CatchUpPolicy:  catchUpPolicy,
 	}
 	if err := q.Insert(task); err != nil {
 		return nil, errors.WithStack(err)
@@ -181,6 +197,8 @@ type ChangeScheduledTaskParams struct {
 	Error          *string
 	Data           *ScheduledTaskData
 	CronExpression *string
+	CatchUpPolicy  *CatchUpPolicy
+	DeferredReason *string
 }
 
 // Validate checks if params for scheduled tasks are valid.
@@ -191,6 +209,11 @@ func (p ChangeScheduledTaskParams) Validate() error {
 			return err
 		}
 	}
+
+	if p.CatchUpPolicy != nil {
+		return p.CatchUpPolicy.Validate()
+	}
+
 	return nil
 }
 
@@ -233,6 +256,14 @@ func ChangeScheduledTask(q *reform.Querier, id string, params ChangeScheduledTas
 		row.Error = *params.Error
 	}
 
+	if params.CatchUpPolicy != nil {
+		row.CatchUpPolicy = *params.CatchUpPolicy
+	}
+
+	if params.DeferredReason != nil {
+		row.DeferredReason = *params.DeferredReason
+	}
+
 	if err := q.Update(row); err != nil {
 		return nil, errors.Wrap(err, "failed to update scheduled task")
 	}
@@ -24,6 +24,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+const blackoutWindowTimeLayout = "15:04"
+
 // MetricsResolutions contains standard VictoriaMetrics metrics resolutions.
 type MetricsResolutions struct {
 	HR time.Duration `json:"hr"`
@@ -93,12 +95,83 @@ type Settings struct {
 
 	BackupManagement struct {
 		Enabled bool `json:"enabled"`
+		// Limits of snapshot backups running at the same time, zero means no limit.
+		// Scheduled backups are deferred when limits are reached, on-demand backups are rejected.
+		MaxConcurrentBackupsPerNode           uint32 `json:"max_concurrent_backups_per_node,omitempty"`
+		MaxConcurrentBackupsPerReplicationSet uint32 `json:"max_concurrent_backups_per_replication_set,omitempty"`
+		// Scheduled tasks are deferred during blackout windows, on-demand backups aren't restricted by them.
+		BlackoutWindows []BlackoutWindow `json:"blackout_windows,omitempty"`
 	} `json:"backup_management"`
 
 	// PMMServerID is generated on the first start of PMM server.
 	PMMServerID string `json:"pmmServerID"`
 }
 
+// BlackoutWindow is a recurring period of time during which scheduled tasks are deferred.
+type BlackoutWindow struct {
+	// StartTime is a start time of the window in UTC in 15:04 format.
+	StartTime string        `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	// Weekdays limits days the window starts on, window starts every day if it's empty.
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+}
+
+// Validate validates blackout window.
+func (w *BlackoutWindow) Validate() error {
+	if _, err := time.Parse(blackoutWindowTimeLayout, w.StartTime); err != nil {
+		return errors.Errorf("invalid blackout window start time %q, expected format is HH:MM", w.StartTime)
+	}
+
+	if w.Duration <= 0 || w.Duration > 24*time.Hour {
+		return errors.Errorf("blackout window duration should be positive and not longer than 24h, got %s", w.Duration)
+	}
+
+	for _, d := range w.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return errors.Errorf("invalid blackout window weekday %d", d)
+		}
+	}
+
+	return nil
+}
+
+// Contains returns true if the given time is inside the window.
+// Window started on the previous day is checked too, as window may span midnight.
+func (w *BlackoutWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(blackoutWindowTimeLayout, w.StartTime)
+	if err != nil {
+		return false
+	}
+
+	t = t.UTC()
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		if !w.startsOn(day.Weekday()) {
+			continue
+		}
+
+		from := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !t.Before(from) && t.Before(from.Add(w.Duration)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *BlackoutWindow) startsOn(weekday time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, d := range w.Weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
 // EmailAlertingSettings represents email settings for Integrated Alerting.
 type EmailAlertingSettings struct {
 	From       string `json:"from"`
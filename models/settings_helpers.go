@@ -115,6 +115,13 @@ type ChangeSettingsParams struct {
 	EnableBackupManagement bool
 	// Disable Backup Management features.
 	DisableBackupManagement bool
+
+	// Limits of scheduled backups running at the same time, zero removes the limit.
+	MaxConcurrentBackupsPerNode           *uint32
+	MaxConcurrentBackupsPerReplicationSet *uint32
+	// Blackout windows of scheduled tasks.
+	BlackoutWindows       []BlackoutWindow
+	RemoveBlackoutWindows bool
 }
 
 // SetPMMServerID should be run on start up to generate unique PMM Server ID.
@@ -279,6 +286,22 @@ func UpdateSettings(q reform.DBTX, params *ChangeSettingsParams) (*Settings, err
 		settings.BackupManagement.Enabled = true
 	}
 
+	if params.MaxConcurrentBackupsPerNode != nil {
+		settings.BackupManagement.MaxConcurrentBackupsPerNode = *params.MaxConcurrentBackupsPerNode
+	}
+
+	if params.MaxConcurrentBackupsPerReplicationSet != nil {
+		settings.BackupManagement.MaxConcurrentBackupsPerReplicationSet = *params.MaxConcurrentBackupsPerReplicationSet
+	}
+
+	if params.RemoveBlackoutWindows {
+		settings.BackupManagement.BlackoutWindows = nil
+	}
+
+	if len(params.BlackoutWindows) != 0 {
+		settings.BackupManagement.BlackoutWindows = params.BlackoutWindows
+	}
+
 	err = SaveSettings(q, settings)
 	if err != nil {
 		return nil, err
@@ -340,6 +363,14 @@ func ValidateSettings(params *ChangeSettingsParams) error {
 	if params.EnableBackupManagement && params.DisableBackupManagement {
 		return errors.New("both enable_backup_management and disable_backup_management are present")
 	}
+	if len(params.BlackoutWindows) != 0 && params.RemoveBlackoutWindows {
+		return errors.New("both blackout_windows and remove_blackout_windows are present")
+	}
+	for i := range params.BlackoutWindows {
+		if err := params.BlackoutWindows[i].Validate(); err != nil {
+			return err
+		}
+	}
 	// TODO: consider refactoring this and the validation for STT check intervals
 	checkCases := []struct {
 		dur       time.Duration
@@ -459,3 +459,40 @@ func TestSettings(t *testing.T) {
 		})
 	})
 }
+
+func TestBlackoutWindow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+
+		w := &models.BlackoutWindow{StartTime: "22:00", Duration: 4 * time.Hour, Weekdays: []time.Weekday{time.Friday}}
+		assert.NoError(t, w.Validate())
+
+		w = &models.BlackoutWindow{StartTime: "25:00", Duration: time.Hour}
+		assert.EqualError(t, w.Validate(), `invalid blackout window start time "25:00", expected format is HH:MM`)
+
+		w = &models.BlackoutWindow{StartTime: "22:00", Duration: 25 * time.Hour}
+		assert.EqualError(t, w.Validate(), "blackout window duration should be positive and not longer than 24h, got 25h0m0s")
+
+		w = &models.BlackoutWindow{StartTime: "22:00", Duration: time.Hour, Weekdays: []time.Weekday{7}}
+		assert.EqualError(t, w.Validate(), "invalid blackout window weekday 7")
+	})
+
+	t.Run("Contains", func(t *testing.T) {
+		t.Parallel()
+
+		// Friday night till Saturday morning.
+		w := &models.BlackoutWindow{StartTime: "22:00", Duration: 4 * time.Hour, Weekdays: []time.Weekday{time.Friday}}
+		friday := time.Date(2021, 6, 11, 0, 0, 0, 0, time.UTC)
+
+		assert.False(t, w.Contains(friday.Add(21*time.Hour+59*time.Minute)))
+		assert.True(t, w.Contains(friday.Add(22*time.Hour)))
+		assert.True(t, w.Contains(friday.Add(25*time.Hour)))
+		assert.False(t, w.Contains(friday.Add(26*time.Hour)))
+		assert.False(t, w.Contains(friday.AddDate(0, 0, 1).Add(22*time.Hour)))
+
+		w.Weekdays = nil
+		assert.True(t, w.Contains(friday.AddDate(0, 0, 1).Add(22*time.Hour)))
+	})
+}
@@ -17,6 +17,8 @@
 package models
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/AlekSi/pointer"
@@ -43,6 +45,52 @@ func checkUniqueTemplateName(q *reform.Querier, name string) error {
 	}
 }
 
+var recordNameRE = regexp.MustCompile("^[a-zA-Z_:][a-zA-Z0-9_:]*$")
+
+// checkRecordingRules checks that recording rules have valid names, labels and non-empty expressions.
+func checkRecordingRules(rules RecordingRules) error {
+	for _, r := range rules {
+		if !recordNameRE.MatchString(r.Record) {
+			return status.Errorf(codes.InvalidArgument, "Invalid recording rule name %q.", r.Record)
+		}
+		if r.Expr == "" {
+			return status.Errorf(codes.InvalidArgument, "Recording rule %q has empty expression.", r.Record)
+		}
+		for name := range r.Labels {
+			if !labelNameRE.MatchString(name) || strings.HasPrefix(name, "__") {
+				return status.Errorf(codes.InvalidArgument, "Invalid recording rule %q label name %q.", r.Record, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkAlertConditions checks that alert conditions have unique valid names, that don't clash
+// with parameters names, and non-empty expressions.
+func checkAlertConditions(conditions AlertConditions, params AlertExprParamsDefinitions) error {
+	names := make(map[string]struct{}, len(conditions)+len(params))
+	for _, p := range params {
+		names[p.Name] = struct{}{}
+	}
+
+	for _, c := range conditions {
+		if !labelNameRE.MatchString(c.Name) {
+			return status.Errorf(codes.InvalidArgument, "Invalid alert condition name %q.", c.Name)
+		}
+		if _, ok := names[c.Name]; ok {
+			return status.Errorf(codes.InvalidArgument, "Alert condition name %q is already used.", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		if c.Expr == "" {
+			return status.Errorf(codes.InvalidArgument, "Alert condition %q has empty expression.", c.Name)
+		}
+	}
+
+	return nil
+}
+
 // FindTemplates returns saved notification rule templates.
 func FindTemplates(q *reform.Querier) ([]Template, error) {
 	structs, err := q.SelectAllFrom(TemplateTable, "")
@@ -79,9 +127,11 @@ func FindTemplateByName(q *reform.Querier, name string) (*Template, error) {
 
 // CreateTemplateParams are params for creating new rule template.
 type CreateTemplateParams struct {
-	Template *alert.Template
-	Yaml     string
-	Source   Source
+	Template       *alert.Template
+	RecordingRules RecordingRules
+	Conditions     AlertConditions
+	Yaml           string
+	Source         Source
 }
 
 // CreateTemplate creates rule template.
@@ -100,16 +150,26 @@ func CreateTemplate(q *reform.Querier, params *CreateTemplateParams) (*Template,
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid rule template parameters: %v.", err)
 	}
 
+	if err = checkRecordingRules(params.RecordingRules); err != nil {
+		return nil, err
+	}
+
+	if err = checkAlertConditions(params.Conditions, p); err != nil {
+		return nil, err
+	}
+
 	row := &Template{
-		Name:     template.Name,
-		Version:  template.Version,
-		Summary:  template.Summary,
-		Expr:     template.Expr,
-		Params:   p,
-		For:      time.Duration(template.For),
-		Severity: Severity(template.Severity),
-		Source:   params.Source,
-		Yaml:     params.Yaml,
+		Name:           template.Name,
+		Version:        template.Version,
+		Summary:        template.Summary,
+		Expr:           template.Expr,
+		Params:         p,
+		For:            time.Duration(template.For),
+		Severity:       Severity(template.Severity),
+		RecordingRules: params.RecordingRules,
+		Conditions:     params.Conditions,
+		Source:         params.Source,
+		Yaml:           params.Yaml,
 	}
 
 	if err := row.SetLabels(template.Labels); err != nil {
@@ -129,9 +189,11 @@ func CreateTemplate(q *reform.Querier, params *CreateTemplateParams) (*Template,
 
 // ChangeTemplateParams is params for changing existing rule template.
 type ChangeTemplateParams struct {
-	Template *alert.Template
-	Name     string
-	Yaml     string
+	Template       *alert.Template
+	RecordingRules RecordingRules
+	Conditions     AlertConditions
+	Name           string
+	Yaml           string
 }
 
 // ChangeTemplate updates existing rule template.
@@ -155,6 +217,14 @@ func ChangeTemplate(q *reform.Querier, params *ChangeTemplateParams) (*Template,
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid rule template parameters: %v.", err)
 	}
 
+	if err = checkRecordingRules(params.RecordingRules); err != nil {
+		return nil, err
+	}
+
+	if err = checkAlertConditions(params.Conditions, p); err != nil {
+		return nil, err
+	}
+
 	row.Name = template.Name
 	row.Version = template.Version
 	row.Summary = template.Summary
@@ -162,6 +232,8 @@ func ChangeTemplate(q *reform.Querier, params *ChangeTemplateParams) (*Template,
 	row.Params = p
 	row.For = time.Duration(template.For)
 	row.Severity = Severity(template.Severity)
+	row.RecordingRules = params.RecordingRules
+	row.Conditions = params.Conditions
 	row.Yaml = params.Yaml
 
 	if err = row.SetLabels(template.Labels); err != nil {
@@ -28,11 +28,14 @@ import (
 	"github.com/percona/promconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/reform.v1"
 	"gopkg.in/reform.v1/dialects/postgresql"
 
 	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
 )
 
 func TestRuleTemplates(t *testing.T) {
@@ -202,6 +205,65 @@ func TestRuleTemplates(t *testing.T) {
 		assert.Empty(t, templates)
 	})
 
+	t.Run("create with recording rules and conditions", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+
+		q := tx.Querier
+
+		params := createTemplateParams(uuid.New().String())
+		params.RecordingRules = models.RecordingRules{{
+			Record: "pmm:node_cpu_usage:ratio",
+			Expr:   `1 - avg by (node_name) (rate(node_cpu_seconds_total{mode="idle"}[5m]))`,
+			Labels: map[string]string{"foo": "bar"},
+		}}
+		params.Conditions = models.AlertConditions{{
+			Name: "high_cpu",
+			Expr: "pmm:node_cpu_usage:ratio > 0.9",
+		}}
+
+		created, err := models.CreateTemplate(q, params)
+		require.NoError(t, err)
+
+		templates, err := models.FindTemplates(q)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, created.RecordingRules, templates[0].RecordingRules)
+		assert.Equal(t, params.Conditions, templates[0].Conditions)
+	})
+
+	t.Run("create err - invalid recording rule name", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+
+		params := createTemplateParams(uuid.New().String())
+		params.RecordingRules = models.RecordingRules{{Record: "pmm-node", Expr: "up"}}
+
+		_, err = models.CreateTemplate(tx.Querier, params)
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid recording rule name "pmm-node".`), err)
+	})
+
+	t.Run("create err - condition name is used by parameter", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+
+		params := createTemplateParams(uuid.New().String())
+		params.Template.Params[0].Name = "threshold"
+		params.Conditions = models.AlertConditions{{Name: "threshold", Expr: "up == 0"}}
+
+		_, err = models.CreateTemplate(tx.Querier, params)
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Alert condition name "threshold" is already used.`), err)
+	})
+
 	t.Run("list", func(t *testing.T) {
 		tx, err := db.Begin()
 		require.NoError(t, err)
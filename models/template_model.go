@@ -29,17 +29,19 @@ import (
 // Template represents Integrated Alerting rule template.
 //reform:ia_templates
 type Template struct {
-	Name        string                     `reform:"name,pk"`
-	Version     uint32                     `reform:"version"`
-	Summary     string                     `reform:"summary"`
-	Expr        string                     `reform:"expr"`
-	Params      AlertExprParamsDefinitions `reform:"params"`
-	For         time.Duration              `reform:"for"`
-	Severity    Severity                   `reform:"severity"`
-	Labels      []byte                     `reform:"labels"`
-	Annotations []byte                     `reform:"annotations"`
-	Source      Source                     `reform:"source"`
-	Yaml        string                     `reform:"yaml"`
+	Name           string                     `reform:"name,pk"`
+	Version        uint32                     `reform:"version"`
+	Summary        string                     `reform:"summary"`
+	Expr           string                     `reform:"expr"`
+	Params         AlertExprParamsDefinitions `reform:"params"`
+	For            time.Duration              `reform:"for"`
+	Severity       Severity                   `reform:"severity"`
+	Labels         []byte                     `reform:"labels"`
+	Annotations    []byte                     `reform:"annotations"`
+	RecordingRules RecordingRules             `reform:"recording_rules"`
+	Conditions     AlertConditions            `reform:"conditions"`
+	Source         Source                     `reform:"source"`
+	Yaml           string                     `reform:"yaml"`
 
 	CreatedAt time.Time `reform:"created_at"`
 	UpdatedAt time.Time `reform:"updated_at"`
@@ -109,31 +111,64 @@ func (p *AlertExprParamsDefinitions) Scan(src interface{}) error { return jsonSc
 
 // AlertExprParamDefinition represents query parameter definition.
 type AlertExprParamDefinition struct {
-	Name    string    `json:"name"`
-	Summary string    `json:"summary"`
-	Unit    string    `json:"unit"`
-	Type    ParamType `json:"type"`
+	Name    string    `json:"name" yaml:"name"`
+	Summary string    `json:"summary" yaml:"summary"`
+	Unit    string    `json:"unit" yaml:"unit"`
+	Type    ParamType `json:"type" yaml:"type"`
 
-	FloatParam *FloatParam `json:"float_param"`
+	FloatParam *FloatParam `json:"float_param" yaml:"float_param"`
 	// BoolParam   *BoolParam   `json:"bool_param"`
 	// StringParam *StringParam `json:"string_param"`
 }
 
 // BoolParam represents boolean template parameter.
 type BoolParam struct {
-	Default *bool `json:"default,omitempty"`
+	Default *bool `json:"default,omitempty" yaml:"default,omitempty"`
 }
 
 // FloatParam represents float template parameter.
 type FloatParam struct {
-	Default *float64 `json:"default,omitempty"`
-	Min     *float64 `json:"min,omitempty"`
-	Max     *float64 `json:"max,omitempty"`
+	Default *float64 `json:"default,omitempty" yaml:"default,omitempty"`
+	Min     *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty" yaml:"max,omitempty"`
 }
 
 // StringParam represents string template parameter.
 type StringParam struct {
-	Default *string `json:"default,omitempty"`
+	Default *string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// RecordingRules represents RecordingRule slice.
+type RecordingRules []RecordingRule
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (r RecordingRules) Value() (driver.Value, error) { return jsonValue(r) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (r *RecordingRules) Scan(src interface{}) error { return jsonScan(r, src) }
+
+// RecordingRule represents recording rule declared by template to support its alert expression.
+// Expression and labels may contain parameters placeholders.
+type RecordingRule struct {
+	Record string            `json:"record" yaml:"record"`
+	Expr   string            `json:"expr" yaml:"expr"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// AlertConditions represents AlertCondition slice.
+type AlertConditions []AlertCondition
+
+// Value implements database/sql/driver.Valuer interface. Should be defined on the value.
+func (c AlertConditions) Value() (driver.Value, error) { return jsonValue(c) }
+
+// Scan implements database/sql.Scanner interface. Should be defined on the pointer.
+func (c *AlertConditions) Scan(src interface{}) error { return jsonScan(c, src) }
+
+// AlertCondition represents named sub-expression of alert expression.
+// Alert expression refers to it by `[[ .name ]]` placeholder, the same way as to parameters.
+type AlertCondition struct {
+	Name string `json:"name" yaml:"name"`
+	Expr string `json:"expr" yaml:"expr"`
 }
 
 // Source represents template source.
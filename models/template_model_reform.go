@@ -37,6 +37,8 @@ func (v *templateTableType) Columns() []string {
 		"severity",
 		"labels",
 		"annotations",
+		"recording_rules",
+		"conditions",
 		"source",
 		"yaml",
 		"created_at",
@@ -74,6 +76,8 @@ var TemplateTable = &templateTableType{
 			{Name: "Severity", Type: "Severity", Column: "severity"},
 			{Name: "Labels", Type: "[]uint8", Column: "labels"},
 			{Name: "Annotations", Type: "[]uint8", Column: "annotations"},
+			{Name: "RecordingRules", Type: "RecordingRules", Column: "recording_rules"},
+			{Name: "Conditions", Type: "AlertConditions", Column: "conditions"},
 			{Name: "Source", Type: "Source", Column: "source"},
 			{Name: "Yaml", Type: "string", Column: "yaml"},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
@@ -86,7 +90,7 @@ var TemplateTable = &templateTableType{
 
 // String returns a string representation of this struct or record.
 func (s Template) String() string {
-	res := make([]string, 13)
+	res := make([]string, 15)
 	res[0] = "Name: " + reform.Inspect(s.Name, true)
 	res[1] = "Version: " + reform.Inspect(s.Version, true)
 	res[2] = "Summary: " + reform.Inspect(s.Summary, true)
@@ -96,10 +100,12 @@ func (s Template) String() string {
 	res[6] = "Severity: " + reform.Inspect(s.Severity, true)
 	res[7] = "Labels: " + reform.Inspect(s.Labels, true)
 	res[8] = "Annotations: " + reform.Inspect(s.Annotations, true)
-	res[9] = "Source: " + reform.Inspect(s.Source, true)
-	res[10] = "Yaml: " + reform.Inspect(s.Yaml, true)
-	res[11] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
-	res[12] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[9] = "RecordingRules: " + reform.Inspect(s.RecordingRules, true)
+	res[10] = "Conditions: " + reform.Inspect(s.Conditions, true)
+	res[11] = "Source: " + reform.Inspect(s.Source, true)
+	res[12] = "Yaml: " + reform.Inspect(s.Yaml, true)
+	res[13] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[14] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
 	return strings.Join(res, ", ")
 }
 
@@ -116,6 +122,8 @@ func (s *Template) Values() []interface{} {
 		s.Severity,
 		s.Labels,
 		s.Annotations,
+		s.RecordingRules,
+		s.Conditions,
 		s.Source,
 		s.Yaml,
 		s.CreatedAt,
@@ -136,6 +144,8 @@ func (s *Template) Pointers() []interface{} {
 		&s.Severity,
 		&s.Labels,
 		&s.Annotations,
+		&s.RecordingRules,
+		&s.Conditions,
 		&s.Source,
 		&s.Yaml,
 		&s.CreatedAt,
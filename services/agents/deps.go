@@ -44,9 +44,34 @@ type retentionService interface {
 	EnforceRetention(ctx context.Context, scheduleID string) error
 }
 
+// backupMetricsService is a subset of methods of backup.MetricsService used by this package.
+// We use it instead of real type to avoid dependency cycle.
+type backupMetricsService interface {
+	UpdateArtifactSize(ctx context.Context, artifactID string) error
+}
+
+// backupReplicationService is a subset of methods of backup.ReplicationService used by this package.
+// We use it instead of real type to avoid dependency cycle.
+type backupReplicationService interface {
+	ReplicateArtifact(ctx context.Context, artifactID string) error
+}
+
+// backupUsageService is a subset of methods of backup.UsageService used by this package.
+// We use it instead of real type to avoid dependency cycle.
+type backupUsageService interface {
+	UpdateArtifactLocationUsage(ctx context.Context, artifactID string) error
+}
+
+// backupRemovalService is a subset of methods of backup.RemovalService used by this package.
+// We use it instead of real type to avoid dependency cycle.
+type backupRemovalService interface {
+	DeleteArtifactFiles(ctx context.Context, artifactID string) error
+}
+
 // jobsService is a subset of methods of agents.JobsService used by this package.
 // We use it instead of real type to avoid dependency cycle.
 type jobsService interface {
 	handleJobResult(ctx context.Context, l *logrus.Entry, result *agentpb.JobResult)
 	handleJobProgress(ctx context.Context, progress *agentpb.JobProgress)
+	reconcileJobs(ctx context.Context, pmmAgentID string)
 }
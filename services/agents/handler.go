@@ -80,6 +80,9 @@ func (h *Handler) Run(stream agentpb.Agent_ConnectServer) error {
 
 	h.state.RequestStateUpdate(ctx, agent.id)
 
+	// restart or fail jobs interrupted by pmm-agent disconnect.
+	go h.jobsService.reconcileJobs(ctx, agent.id)
+
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 	for {
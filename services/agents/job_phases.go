@@ -0,0 +1,71 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package agents
+
+import (
+	"strings"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// jobPhase is a step of the job recognized by a line of its log.
+type jobPhase struct {
+	marker     string
+	name       string
+	percentage uint32
+}
+
+// jobPhases are known steps of jobs in the order they are made.
+// pmm-agent doesn't report percentage and phase of the job, so both are estimates only. Markers are lines of
+// xtrabackup and pbm output which may change between their versions, then the job just has no phase.
+// Phase names are ours and percentages are guesses of the work done before the step starts, not measurements.
+var jobPhases = map[models.JobType][]jobPhase{
+	models.MySQLBackupJob: {
+		{marker: "Connecting to MySQL server", name: "connecting", percentage: 5},
+		{marker: "Streaming ./", name: "copying InnoDB files", percentage: 10},
+		{marker: "Starting to backup non-InnoDB tables and files", name: "copying non-InnoDB files", percentage: 70},
+		{marker: "Finished backing up non-InnoDB tables and files", name: "copying redo log", percentage: 85},
+		{marker: "completed OK!", name: "uploading", percentage: 95},
+	},
+	models.MySQLRestoreBackupJob: {
+		{marker: "Starting InnoDB instance for recovery", name: "preparing", percentage: 40},
+		{marker: "Moving ./", name: "moving files", percentage: 70},
+	},
+	models.MongoDBBackupJob: {
+		{marker: "backup started", name: "dumping", percentage: 5},
+		{marker: "mongodump finished, waiting for the oplog", name: "copying oplog", percentage: 80},
+		{marker: "backup finished", name: "finishing", percentage: 95},
+	},
+	models.MongoDBRestoreBackupJob: {
+		{marker: "restore started", name: "restoring", percentage: 5},
+		{marker: "mongorestore finished", name: "replaying oplog", percentage: 70},
+		{marker: "restoring users and roles", name: "restoring users and roles", percentage: 90},
+	},
+}
+
+// updateJobPhase advances phase and percentage of the job progress by the log chunk, they never go back.
+func updateJobPhase(p *models.JobProgress, jobType models.JobType, data string) {
+	phases := jobPhases[jobType]
+	for _, line := range strings.Split(data, "\n") {
+		for _, phase := range phases {
+			if phase.percentage > p.Percentage && strings.Contains(line, phase.marker) {
+				p.Phase = phase.name
+				p.Percentage = phase.percentage
+			}
+		}
+	}
+}
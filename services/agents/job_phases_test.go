@@ -0,0 +1,45 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestUpdateJobPhase(t *testing.T) {
+	t.Parallel()
+
+	p := &models.JobProgress{}
+	updateJobPhase(p, models.MySQLBackupJob, "xtrabackup version 8.0.28\nConnecting to MySQL server host: localhost\n")
+	assert.Equal(t, &models.JobProgress{Phase: "connecting", Percentage: 5}, p)
+
+	updateJobPhase(p, models.MySQLBackupJob, "[01] Streaming ./ibdata1\n"+
+		"Starting to backup non-InnoDB tables and files\n[01] Streaming ./mysql.ibd\n")
+	assert.Equal(t, &models.JobProgress{Phase: "copying non-InnoDB files", Percentage: 70}, p)
+
+	// unknown lines and lines of previous steps don't change the phase
+	updateJobPhase(p, models.MySQLBackupJob, "[01] Streaming ./undo_001\nsomething else")
+	assert.Equal(t, &models.JobProgress{Phase: "copying non-InnoDB files", Percentage: 70}, p)
+
+	p = &models.JobProgress{}
+	updateJobPhase(p, models.MongoDBRestoreBackupJob, "2022-05-12T10:00:00Z I [restore/2022-05-12T10:00:00Z] restore started")
+	assert.Equal(t, &models.JobProgress{Phase: "restoring", Percentage: 5}, p)
+}
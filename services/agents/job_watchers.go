@@ -0,0 +1,79 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package agents
+
+import (
+	"sync"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// jobWatchers delivers job updates to subscribers of WatchJob.
+// Slow subscribers receive only the latest update of the job.
+type jobWatchers struct {
+	rw sync.RWMutex
+	m  map[string]map[chan *models.Job]struct{} // job ID -> subscribers
+}
+
+func newJobWatchers() *jobWatchers {
+	return &jobWatchers{
+		m: make(map[string]map[chan *models.Job]struct{}),
+	}
+}
+
+func (w *jobWatchers) subscribe(jobID string) chan *models.Job {
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	ch := make(chan *models.Job, 1)
+	if w.m[jobID] == nil {
+		w.m[jobID] = make(map[chan *models.Job]struct{})
+	}
+	w.m[jobID][ch] = struct{}{}
+	return ch
+}
+
+func (w *jobWatchers) unsubscribe(jobID string, ch chan *models.Job) {
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	delete(w.m[jobID], ch)
+	if len(w.m[jobID]) == 0 {
+		delete(w.m, jobID)
+	}
+}
+
+func (w *jobWatchers) watched(jobID string) bool {
+	w.rw.RLock()
+	defer w.rw.RUnlock()
+
+	return len(w.m[jobID]) != 0
+}
+
+func (w *jobWatchers) publish(job *models.Job) {
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	for ch := range w.m[job.ID] {
+		// drop previous update if subscriber didn't receive it yet
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- job
+	}
+}
@@ -0,0 +1,70 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestJobWatchers(t *testing.T) {
+	t.Parallel()
+
+	w := newJobWatchers()
+	assert.False(t, w.watched("job1"))
+
+	ch1 := w.subscribe("job1")
+	ch2 := w.subscribe("job1")
+	assert.True(t, w.watched("job1"))
+	assert.False(t, w.watched("job2"))
+
+	w.publish(&models.Job{ID: "job1", State: models.DispatchedJobState})
+	w.publish(&models.Job{ID: "job1", State: models.RunningJobState})
+	w.publish(&models.Job{ID: "job2", State: models.RunningJobState})
+
+	// only the latest update is kept
+	assert.Equal(t, models.RunningJobState, (<-ch1).State)
+	assert.Equal(t, models.RunningJobState, (<-ch2).State)
+	assert.Empty(t, ch1)
+
+	w.unsubscribe("job1", ch1)
+	w.publish(&models.Job{ID: "job1", State: models.DoneJobState})
+	assert.Empty(t, ch1)
+	assert.Equal(t, models.DoneJobState, (<-ch2).State)
+
+	w.unsubscribe("job1", ch2)
+	assert.False(t, w.watched("job1"))
+}
+
+func TestAppendLogLines(t *testing.T) {
+	t.Parallel()
+
+	lines := appendLogLines(nil, "first\nsecond\n")
+	assert.Equal(t, []string{"first", "second"}, lines)
+
+	lines = appendLogLines(lines, "")
+	assert.Equal(t, []string{"first", "second"}, lines)
+
+	for i := 0; i < maxProgressLogLines; i++ {
+		lines = appendLogLines(lines, "line")
+	}
+	assert.Len(t, lines, maxProgressLogLines)
+	assert.Equal(t, "line", lines[0])
+}
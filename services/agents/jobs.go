@@ -19,28 +19,39 @@ package agents
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/hashicorp/go-version"
 	"github.com/percona/pmm/api/agentpb"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/reform.v1"
 
 	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/logger"
 )
 
 var (
 	// ErrRetriesExhausted is returned when remaining retries are 0.
 	ErrRetriesExhausted = errors.New("retries exhausted")
+	// ErrJobRestarting is returned when job is already being restarted.
+	ErrJobRestarting = errors.New("job is already being restarted")
 
 	pmmAgentMinVersionForMySQLBackupAndRestore   = version.Must(version.NewVersion("2.23"))
 	pmmAgentMinVersionForMongoDBBackupAndRestore = version.Must(version.NewVersion("2.19"))
+	pmmAgentMinVersionForMongoDBPITRRestore      = version.Must(version.NewVersion("2.28"))
 )
 
 const (
 	maxRestartInterval = 8 * time.Hour
+
+	// maxProgressLogLines is a number of the last log lines stored in the job progress.
+	maxProgressLogLines = 20
 )
 
 // JobsService provides methods for managing jobs.
@@ -48,26 +59,104 @@ type JobsService struct {
 	r  *Registry
 	db *reform.DB
 
-	retentionService retentionService
-	l                *logrus.Entry
+	retentionService         retentionService
+	backupMetricsService     backupMetricsService
+	backupReplicationService backupReplicationService
+	backupUsageService       backupUsageService
+	backupRemovalService     backupRemovalService
+	l                        *logrus.Entry
+
+	restarting sync.Map // job ID -> struct{}, for jobs waiting for the next attempt in this process
+	watchers   *jobWatchers
 }
 
 // NewJobsService returns new jobs service.
-func NewJobsService(db *reform.DB, registry *Registry, retention retentionService) *JobsService {
+func NewJobsService(
+	db *reform.DB,
+	registry *Registry,
+	retention retentionService,
+	backupMetrics backupMetricsService,
+	backupReplication backupReplicationService,
+	backupUsage backupUsageService,
+	backupRemoval backupRemovalService,
+) *JobsService {
 	return &JobsService{
-		db:               db,
-		r:                registry,
-		retentionService: retention,
-		l:                logrus.WithField("component", "agents/jobsService"),
+		db:                       db,
+		r:                        registry,
+		retentionService:         retention,
+		backupMetricsService:     backupMetrics,
+		backupReplicationService: backupReplication,
+		backupUsageService:       backupUsage,
+		backupRemovalService:     backupRemoval,
+		l:                        logrus.WithField("component", "agents/jobsService"),
+		watchers:                 newJobWatchers(),
+	}
+}
+
+// WatchJob returns channel with updates of the job with given ID, starting with its current state.
+// Channel is closed when the job is done or ctx is canceled.
+func (s *JobsService) WatchJob(ctx context.Context, jobID string) (<-chan *models.Job, error) {
+	// subscribe before reading the job, so updates made in between aren't lost
+	updates := s.watchers.subscribe(jobID)
+
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		s.watchers.unsubscribe(jobID, updates)
+		return nil, err
+	}
+
+	res := make(chan *models.Job)
+	go func() {
+		defer close(res)
+		defer s.watchers.unsubscribe(jobID, updates)
+
+		for {
+			select {
+			case res <- job:
+			case <-ctx.Done():
+				return
+			}
+
+			if job.Done {
+				return
+			}
+
+			select {
+			case job = <-updates:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+// notifyWatchers sends the current state of the job to WatchJob subscribers.
+func (s *JobsService) notifyWatchers(jobID string) {
+	if !s.watchers.watched(jobID) {
+		return
+	}
+
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		s.l.Errorf("failed to find job %s: %s", jobID, err)
+		return
 	}
+
+	s.watchers.publish(job)
 }
 
+// RestartJob starts again failed backup job after its retry interval if it has retries left, artifact stays pending meanwhile.
+// Time of the next attempt is stored in the job, so the job is started at that time after pmm-managed restart too.
+// If pmm-agent isn't connected at that moment, job is left in retrying state and started after reconnect.
 func (s *JobsService) RestartJob(ctx context.Context, jobID string) error {
+	if _, loaded := s.restarting.LoadOrStore(jobID, struct{}{}); loaded {
+		return ErrJobRestarting
+	}
+	defer s.restarting.Delete(jobID)
+
 	var job *models.Job
-	var artifact *models.Artifact
-	var location *models.BackupLocation
-	var locationConfig *models.BackupLocationConfig
-	var dbConfig *models.DBConfig
 	errTx := s.db.InTransaction(func(tx *reform.TX) error {
 		var err error
 		job, err = models.FindJobByID(tx.Querier, jobID)
@@ -79,78 +168,321 @@ func (s *JobsService) RestartJob(ctx context.Context, jobID string) error {
 			return ErrRetriesExhausted
 		}
 
+		params, err := s.findBackupJobParams(tx.Querier, job)
+		if err != nil {
+			return err
+		}
+
+		// Artifact isn't failed while the job is retried.
+		if _, err = models.UpdateArtifact(tx.Querier, params.artifact.ID, models.UpdateArtifactParams{
+			Status: models.BackupStatusPointer(models.PendingBackupStatus),
+		}); err != nil {
+			return err
+		}
+
 		job.Retries--
-		if err = tx.Update(job); err != nil {
+		job.SetState(models.RetryingJobState)
+		job.RetryAt = pointer.ToTime(models.Now().Add(job.Interval))
+		// job is started from scratch, so progress of the previous attempt is dropped
+		job.Progress = nil
+		return tx.Update(job)
+	})
+	if errTx != nil {
+		return errTx
+	}
+	s.notifyWatchers(jobID)
+
+	s.l.Debugf("restarting job: %s, delay: %v", jobID, job.Interval)
+
+	return s.startRetryingJob(ctx, jobID)
+}
+
+// startRetryingJob waits until the time of the next attempt of the retrying job and starts it.
+// Job isn't started if it's done meanwhile, e.g. cancelled. Caller should mark the job in s.restarting.
+func (s *JobsService) startRetryingJob(ctx context.Context, jobID string) error {
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if job.RetryAt != nil {
+		select {
+		case <-time.After(time.Until(*job.RetryAt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Job may be cancelled while waiting for the retry interval, it's not started then.
+	if job, err = models.FindJobByID(s.db.Querier, jobID); err != nil {
+		return errors.WithStack(err)
+	}
+	if job.Done {
+		s.l.Debugf("job %s is done, it's not restarted", jobID)
+		return nil
+	}
+
+	params, err := s.findBackupJobParams(s.db.Querier, job)
+	if err == nil {
+		err = s.startBackupJob(job, params)
+	}
+	if err != nil {
+		if errors.Is(err, models.ErrJobDone) {
+			s.l.Debugf("job %s was cancelled while being restarted", jobID)
+			return nil
+		}
+
+		if !s.r.IsConnected(job.PMMAgentID) {
+			s.l.Infof("pmm-agent %s is not connected, job %s will be restarted after reconnect.", job.PMMAgentID, jobID)
+			return nil
+		}
+
+		job.Error = err.Error()
+		if handleErr := s.handleJobError(job); handleErr != nil {
+			s.l.Errorf("failed to handle error of job %s: %s", jobID, handleErr)
+		}
+		job.SetState(models.DoneJobState)
+		if updateErr := s.db.Update(job); updateErr != nil {
+			s.l.Errorf("failed to update job %s: %s", jobID, updateErr)
+		}
+		s.notifyWatchers(jobID)
+		return err
+	}
+
+	return nil
+}
+
+// restartJobAsync restarts failed job in the background.
+func (s *JobsService) restartJobAsync(jobID string) {
+	go func() {
+		restartCtx, cancel := context.WithTimeout(context.Background(), maxRestartInterval)
+		defer cancel()
+		restartErr := s.RestartJob(restartCtx, jobID)
+		if restartErr != nil && restartErr != ErrRetriesExhausted && restartErr != ErrJobRestarting {
+			s.l.Errorf("restart job %s: %v", jobID, restartErr)
+		}
+	}()
+}
+
+// startRetryingJobAsync starts retrying job at the time of its next attempt in the background,
+// unless the job is already waiting for it in RestartJob.
+func (s *JobsService) startRetryingJobAsync(jobID string) {
+	if _, loaded := s.restarting.LoadOrStore(jobID, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer s.restarting.Delete(jobID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), maxRestartInterval)
+		defer cancel()
+		if err := s.startRetryingJob(ctx, jobID); err != nil {
+			s.l.Errorf("restart job %s: %v", jobID, err)
+		}
+	}()
+}
+
+// reconcileJobs brings unfinished jobs of the connected pmm-agent in line with the pmm-agent state.
+// Jobs cancelled while pmm-agent was disconnected are stopped. Queued jobs are started, jobs waiting for restart
+// are started at the time of their next attempt. Jobs lost by pmm-agent (e.g. because it was restarted while being
+// disconnected) are failed and restarted if they have retries left.
+func (s *JobsService) reconcileJobs(ctx context.Context, pmmAgentID string) {
+	l := logger.Get(ctx)
+
+	s.stopCancelledJobs(l, pmmAgentID)
+
+	jobs, err := models.FindJobs(s.db.Querier, models.JobsFilter{
+		PMMAgentID: pmmAgentID,
+		States:     models.UnfinishedJobStates(),
+	})
+	if err != nil {
+		l.Errorf("Failed to find unfinished jobs: %+v.", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if err := s.reconcileJob(job); err != nil {
+			l.Errorf("Failed to reconcile job %s: %+v.", job.ID, err)
+		}
+	}
+}
+
+func (s *JobsService) reconcileJob(job *models.Job) error {
+	switch job.State {
+	case models.QueuedJobState:
+		return s.redispatchJob(job)
+
+	case models.RetryingJobState:
+		// Job is started at the time of its next attempt, it may be waiting for it already.
+		s.startRetryingJobAsync(job.ID)
+		return nil
+
+	case models.DispatchedJobState, models.RunningJobState:
+		agent, err := s.r.get(job.PMMAgentID)
+		if err != nil {
 			return err
 		}
 
-		switch job.Type {
-		case models.MySQLBackupJob:
-			artifact, err = models.FindArtifactByID(tx.Querier, job.Data.MySQLBackup.ArtifactID)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+		resp, err := agent.channel.SendAndWaitResponse(&agentpb.JobStatusRequest{JobId: job.ID})
+		if err != nil {
+			return err
+		}
 
-			location, err = models.FindBackupLocationByID(tx.Querier, artifact.LocationID)
-			if err != nil {
-				return errors.WithStack(err)
-			}
-			dbConfig, err = models.FindDBConfigForService(tx.Querier, job.Data.MySQLBackup.ServiceID)
-			if err != nil {
-				return errors.WithStack(err)
-			}
-		case models.MongoDBBackupJob:
-			artifact, err = models.FindArtifactByID(tx.Querier, job.Data.MongoDBBackup.ArtifactID)
-			if err != nil {
-				return errors.WithStack(err)
+		if resp.(*agentpb.JobStatusResponse).Alive {
+			if err = models.UpdateJobState(s.db.Querier, job.ID, models.RunningJobState); err != nil {
+				if errors.Is(err, models.ErrJobDone) {
+					// job was cancelled meanwhile, it's stopped by CancelJob
+					return nil
+				}
+				return err
 			}
+			s.notifyWatchers(job.ID)
+			return nil
+		}
 
-			location, err = models.FindBackupLocationByID(tx.Querier, artifact.LocationID)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+		job.Error = "job was interrupted by pmm-agent disconnect"
+		return s.failJob(job)
 
-			dbConfig, err = models.FindDBConfigForService(tx.Querier, job.Data.MongoDBBackup.ServiceID)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+	default:
+		return errors.Errorf("unexpected job state %q", job.State)
+	}
+}
 
-		case models.MySQLRestoreBackupJob, models.MongoDBRestoreBackupJob:
-			fallthrough
-		default:
-			return errors.Errorf("job type %v can't be restarted", job.Type)
+// redispatchJob starts again backup job which wasn't started on the pmm-agent, or fails the job if it can't be started.
+func (s *JobsService) redispatchJob(job *models.Job) error {
+	params, err := s.findBackupJobParams(s.db.Querier, job)
+	if err == nil {
+		err = s.startBackupJob(job, params)
+	}
+	if errors.Is(err, models.ErrJobDone) {
+		// job was cancelled meanwhile
+		return nil
+	}
+	if err != nil {
+		job.Error = err.Error()
+		return s.failJob(job)
+	}
+
+	return nil
+}
+
+// failJob marks job as done with an error, updates related artifact or restore, and restarts the job if possible.
+func (s *JobsService) failJob(job *models.Job) error {
+	if err := s.handleJobError(job); err != nil {
+		return err
+	}
+
+	job.SetState(models.DoneJobState)
+	if err := s.db.Update(job); err != nil {
+		return errors.WithStack(err)
+	}
+	s.notifyWatchers(job.ID)
+
+	s.restartJobAsync(job.ID)
+	return nil
+}
+
+// ResumeJob starts again backup job stopped by CancelJob.
+// pmm-agent can't continue the job, so it's started from scratch.
+func (s *JobsService) ResumeJob(jobID string) error {
+	var job *models.Job
+	var params *backupJobParams
+	errTx := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		job, err = models.FindJobByID(tx.Querier, jobID)
+		if err != nil {
+			return errors.WithStack(err)
 		}
 
-		return nil
+		if !job.Done {
+			return errors.Errorf("job %s is not stopped", jobID)
+		}
+
+		params, err = s.findBackupJobParams(tx.Querier, job)
+		if err != nil {
+			return err
+		}
+
+		job.SetState(models.QueuedJobState)
+		job.Error = ""
+		job.Progress = nil
+		return tx.Update(job)
 	})
 	if errTx != nil {
 		return errTx
 	}
 
-	if location != nil {
-		locationConfig = &models.BackupLocationConfig{
-			PMMServerConfig: location.PMMServerConfig,
-			PMMClientConfig: location.PMMClientConfig,
-			S3Config:        location.S3Config,
+	if err := s.startBackupJob(job, params); err != nil {
+		// Job isn't running, so keep it stopped to allow resuming it later.
+		job.SetState(models.DoneJobState)
+		if updateErr := s.db.Update(job); updateErr != nil {
+			s.l.Errorf("failed to update job %s: %s", jobID, updateErr)
 		}
+		s.notifyWatchers(jobID)
+		return err
 	}
 
-	s.l.Debugf("restarting job: %s, delay: %v", jobID, job.Interval)
+	return nil
+}
 
-	select {
-	case <-time.After(job.Interval):
-	case <-ctx.Done():
-		return ctx.Err()
+// backupJobParams contains data required for starting backup job again.
+type backupJobParams struct {
+	artifact       *models.Artifact
+	locationConfig *models.BackupLocationConfig
+	dbConfig       *models.DBConfig
+}
+
+// findBackupJobParams returns data required for starting backup job again, other job types can't be restarted.
+func (s *JobsService) findBackupJobParams(q *reform.Querier, job *models.Job) (*backupJobParams, error) {
+	var artifactID, serviceID string
+	switch job.Type {
+	case models.MySQLBackupJob:
+		artifactID, serviceID = job.Data.MySQLBackup.ArtifactID, job.Data.MySQLBackup.ServiceID
+	case models.MongoDBBackupJob:
+		artifactID, serviceID = job.Data.MongoDBBackup.ArtifactID, job.Data.MongoDBBackup.ServiceID
+	case models.MySQLRestoreBackupJob,
+		models.MongoDBRestoreBackupJob:
+		fallthrough
+	default:
+		return nil, errors.Errorf("job type %v can't be restarted", job.Type)
 	}
 
+	artifact, err := models.FindArtifactByID(q, artifactID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	location, err := models.FindBackupLocationByID(q, artifact.LocationID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	dbConfig, err := models.FindDBConfigForService(q, serviceID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &backupJobParams{
+		artifact: artifact,
+		locationConfig: &models.BackupLocationConfig{
+			PMMServerConfig: location.PMMServerConfig,
+			PMMClientConfig: location.PMMClientConfig,
+			S3Config:        location.S3Config,
+		},
+		dbConfig: dbConfig,
+	}, nil
+}
+
+func (s *JobsService) startBackupJob(job *models.Job, params *backupJobParams) error {
 	switch job.Type {
 	case models.MySQLBackupJob:
-		if err := s.StartMySQLBackupJob(job.ID, job.PMMAgentID, job.Timeout, artifact.Name, dbConfig, locationConfig); err != nil {
+		if err := s.StartMySQLBackupJob(job.ID, job.PMMAgentID, job.Timeout, params.artifact.Name, params.dbConfig,
+			params.locationConfig); err != nil {
 			return errors.WithStack(err)
 		}
 	case models.MongoDBBackupJob:
-		if err := s.StartMongoDBBackupJob(job.ID, job.PMMAgentID, job.Timeout, artifact.Name, dbConfig,
-			job.Data.MongoDBBackup.Mode, locationConfig); err != nil {
+		if err := s.StartMongoDBBackupJob(job.ID, job.PMMAgentID, job.Timeout, params.artifact.Name, params.dbConfig,
+			job.Data.MongoDBBackup.Mode, params.locationConfig); err != nil {
 			return errors.WithStack(err)
 		}
 	case models.MySQLRestoreBackupJob:
@@ -161,31 +493,44 @@ func (s *JobsService) RestartJob(ctx context.Context, jobID string) error {
 }
 
 func (s *JobsService) handleJobResult(ctx context.Context, l *logrus.Entry, result *agentpb.JobResult) {
-	var scheduleID string
+	var scheduleID, artifactID, restartJobID, cancelledArtifactID string
+	finishedAt := time.Now()
+	if result.Timestamp != nil {
+		finishedAt = result.Timestamp.AsTime()
+	}
+
 	if errTx := s.db.InTransaction(func(t *reform.TX) error {
 		job, err := models.FindJobByID(t.Querier, result.JobId)
 		if err != nil {
 			return err
 		}
 
+		// Result of the job cancelled by CancelJob is ignored, so it doesn't change state of artifact or restore.
+		// Partial files of the cancelled backup are removed once pmm-agent doesn't write them anymore.
+		if job.Done {
+			l.Debugf("Job %s is already done, result is ignored.", job.ID)
+			cancelledArtifactID, err = findCancelledArtifactID(t.Querier, job)
+			return err
+		}
+
 		switch result := result.Result.(type) {
 		case *agentpb.JobResult_Error_:
+			job.Error = result.Error.Message
 			if err := s.handleJobError(job); err != nil {
 				l.Errorf("failed to handle job error: %s", err)
 			}
-			job.Error = result.Error.Message
+			restartJobID = job.ID
 		case *agentpb.JobResult_MysqlBackup:
 			if job.Type != models.MySQLBackupJob {
 				return errors.Errorf("result type %s doesn't match job type %s", models.MySQLBackupJob, job.Type)
 			}
 
-			artifact, err := models.UpdateArtifact(t.Querier, job.Data.MySQLBackup.ArtifactID, models.UpdateArtifactParams{
-				Status: models.BackupStatusPointer(models.SuccessBackupStatus),
-			})
+			artifact, err := models.UpdateArtifact(t.Querier, job.Data.MySQLBackup.ArtifactID, backupResultParams(job, finishedAt))
 			if err != nil {
 				return err
 			}
 
+			artifactID = artifact.ID
 			if artifact.Type == models.ScheduledArtifactType {
 				scheduleID = artifact.ScheduleID
 			}
@@ -194,13 +539,12 @@ func (s *JobsService) handleJobResult(ctx context.Context, l *logrus.Entry, resu
 				return errors.Errorf("result type %s doesn't match job type %s", models.MongoDBBackupJob, job.Type)
 			}
 
-			artifact, err := models.UpdateArtifact(t.Querier, job.Data.MongoDBBackup.ArtifactID, models.UpdateArtifactParams{
-				Status: models.BackupStatusPointer(models.SuccessBackupStatus),
-			})
+			artifact, err := models.UpdateArtifact(t.Querier, job.Data.MongoDBBackup.ArtifactID, backupResultParams(job, finishedAt))
 			if err != nil {
 				return err
 			}
 
+			artifactID = artifact.ID
 			if artifact.Type == models.ScheduledArtifactType {
 				scheduleID = artifact.ScheduleID
 			}
@@ -236,11 +580,53 @@ func (s *JobsService) handleJobResult(ctx context.Context, l *logrus.Entry, resu
 		default:
 			return errors.Errorf("unexpected job result type: %T", result)
 		}
-		job.Done = true
+
+		if job.Error == "" {
+			if job.Progress == nil {
+				job.Progress = &models.JobProgress{}
+			}
+			job.Progress.Percentage = 100
+			job.Progress.UpdatedAt = finishedAt.UTC()
+		}
+		job.SetState(models.DoneJobState)
 		return t.Update(job)
 	}); errTx != nil {
 		l.Errorf("Failed to save job result: %+v", errTx)
 	}
+	s.notifyWatchers(result.JobId)
+
+	// Job is restarted after the transaction is committed, so the restart isn't overwritten by the result.
+	if restartJobID != "" {
+		s.restartJobAsync(restartJobID)
+	}
+
+	if artifactID != "" {
+		go func() {
+			if err := s.backupMetricsService.UpdateArtifactSize(context.Background(), artifactID); err != nil {
+				l.Errorf("failed to update artifact size: %v", err)
+			}
+		}()
+
+		go func() {
+			if err := s.backupReplicationService.ReplicateArtifact(context.Background(), artifactID); err != nil {
+				l.Errorf("failed to replicate artifact: %v", err)
+			}
+		}()
+
+		go func() {
+			if err := s.backupUsageService.UpdateArtifactLocationUsage(context.Background(), artifactID); err != nil {
+				l.Errorf("failed to update location usage: %v", err)
+			}
+		}()
+	}
+
+	if cancelledArtifactID != "" {
+		go func() {
+			if err := s.backupRemovalService.DeleteArtifactFiles(context.Background(), cancelledArtifactID); err != nil {
+				l.Errorf("failed to remove files of cancelled artifact: %v", err)
+			}
+		}()
+	}
 
 	if scheduleID != "" {
 		go func() {
@@ -251,6 +637,40 @@ func (s *JobsService) handleJobResult(ctx context.Context, l *logrus.Entry, resu
 	}
 }
 
+// findCancelledArtifactID returns ID of the artifact if the given done job is a cancelled snapshot backup, and empty string otherwise.
+// Cancelled backup has error status, while paused one keeps its files until it's resumed.
+func findCancelledArtifactID(q *reform.Querier, job *models.Job) (string, error) {
+	var artifactID string
+	switch job.Type {
+	case models.MySQLBackupJob:
+		artifactID = job.Data.MySQLBackup.ArtifactID
+	case models.MongoDBBackupJob:
+		artifactID = job.Data.MongoDBBackup.ArtifactID
+	default:
+		return "", nil
+	}
+
+	artifact, err := models.FindArtifactByID(q, artifactID)
+	if err != nil {
+		return "", err
+	}
+
+	if artifact.Mode != models.Snapshot || artifact.Status != models.ErrorBackupStatus {
+		return "", nil
+	}
+
+	return artifact.ID, nil
+}
+
+// backupResultParams returns artifact params for successfully finished backup job.
+func backupResultParams(job *models.Job, finishedAt time.Time) models.UpdateArtifactParams {
+	return models.UpdateArtifactParams{
+		Status:     models.BackupStatusPointer(models.SuccessBackupStatus),
+		PMMAgentID: pointer.ToString(job.PMMAgentID),
+		FinishedAt: pointer.ToTime(finishedAt.UTC()),
+	}
+}
+
 func (s *JobsService) handleJobError(job *models.Job) error {
 	var err error
 	switch job.Type {
@@ -280,19 +700,15 @@ func (s *JobsService) handleJobError(job *models.Job) error {
 		return errors.Errorf("unknown job type %s", job.Type)
 	}
 
-	go func() {
-		restartCtx, cancel := context.WithTimeout(context.Background(), maxRestartInterval)
-		defer cancel()
-		restartErr := s.RestartJob(restartCtx, job.ID)
-		if restartErr != nil && restartErr != ErrRetriesExhausted {
-			s.l.Errorf("restart job %s: %v", job.ID, restartErr)
-		}
-	}()
-
 	return err
 }
 
 func (s *JobsService) handleJobProgress(ctx context.Context, progress *agentpb.JobProgress) {
+	updatedAt := time.Now()
+	if progress.Timestamp != nil {
+		updatedAt = progress.Timestamp.AsTime()
+	}
+
 	switch result := progress.Result.(type) {
 	case *agentpb.JobProgress_Logs_:
 		_, err := models.CreateJobLog(s.db.Querier, models.CreateJobLogParams{
@@ -304,11 +720,125 @@ func (s *JobsService) handleJobProgress(ctx context.Context, progress *agentpb.J
 		if err != nil {
 			s.l.WithError(err).Errorf("failed to create log for job %s [chunk: %d]", progress.JobId, result.Logs.ChunkId)
 		}
+
+		s.updateJobProgress(progress.JobId, updatedAt, func(p *models.JobProgress, jobType models.JobType) {
+			p.LastLogLines = appendLogLines(p.LastLogLines, result.Logs.Data)
+			updateJobPhase(p, jobType, result.Logs.Data)
+		})
+	case *agentpb.JobProgress_MysqlBackup, *agentpb.JobProgress_MysqlRestoreBackup:
+		// pmm-agent API doesn't report percentage and phase of the job yet, they are estimated from logs.
+		s.updateJobProgress(progress.JobId, updatedAt, func(*models.JobProgress, models.JobType) {})
 	default:
 		s.l.Errorf("unexpected job progress type: %T", result)
 	}
 }
 
+// updateJobProgress applies given changes to the stored job progress and notifies job watchers.
+func (s *JobsService) updateJobProgress(jobID string, updatedAt time.Time, update func(p *models.JobProgress, jobType models.JobType)) {
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		s.l.Errorf("failed to find job %s: %s", jobID, err)
+		return
+	}
+
+	if job.Done {
+		return
+	}
+
+	if job.Progress == nil {
+		job.Progress = &models.JobProgress{}
+	}
+	update(job.Progress, job.Type)
+	job.Progress.UpdatedAt = updatedAt.UTC()
+
+	if err = models.UpdateJobProgress(s.db.Querier, jobID, job.Progress); err != nil {
+		s.l.Errorf("failed to update progress of job %s: %s", jobID, err)
+		return
+	}
+
+	s.watchers.publish(job)
+}
+
+// appendLogLines appends lines of the log chunk to the given lines keeping only maxProgressLogLines last ones.
+func appendLogLines(lines []string, data string) []string {
+	data = strings.TrimRight(data, "\n")
+	if data != "" {
+		lines = append(lines, strings.Split(data, "\n")...)
+	}
+
+	if len(lines) > maxProgressLogLines {
+		lines = lines[len(lines)-maxProgressLogLines:]
+	}
+	return lines
+}
+
+// sendStartJobRequest sends start job request to the pmm-agent and tracks state of the job.
+// Jobs which are already done, e.g. cancelled ones, aren't started, models.ErrJobDone is returned for them.
+func (s *JobsService) sendStartJobRequest(pmmAgentID string, req *agentpb.StartJobRequest) (*agentpb.StartJobResponse, error) {
+	agent, err := s.r.get(pmmAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = models.UpdateJobState(s.db.Querier, req.JobId, models.DispatchedJobState); err != nil {
+		return nil, err
+	}
+	s.notifyWatchers(req.JobId)
+
+	resp, err := agent.channel.SendAndWaitResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resp.(*agentpb.StartJobResponse)
+	state := models.RunningJobState
+	if res.Error != "" {
+		state = models.DoneJobState
+	}
+	if err = models.UpdateJobState(s.db.Querier, req.JobId, state); err != nil {
+		if errors.Is(err, models.ErrJobDone) && state == models.RunningJobState {
+			// Job was cancelled while pmm-agent was starting it, so stop request of CancelJob could come before the job.
+			if _, stopErr := agent.channel.SendAndWaitResponse(&agentpb.StopJobRequest{JobId: req.JobId}); stopErr != nil {
+				s.l.Warnf("Failed to stop cancelled job %s: %s.", req.JobId, stopErr)
+			}
+		}
+		return nil, err
+	}
+	s.notifyWatchers(req.JobId)
+
+	if state == models.RunningJobState {
+		if err = s.updateArtifactStartedAt(req.JobId); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// updateArtifactStartedAt sets start time of the backup artifact when pmm-agent starts its job.
+// Jobs may wait for admission or be restarted, so job creation time can't be used for that.
+func (s *JobsService) updateArtifactStartedAt(jobID string) error {
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		return err
+	}
+
+	var artifactID string
+	switch job.Type {
+	case models.MySQLBackupJob:
+		artifactID = job.Data.MySQLBackup.ArtifactID
+	case models.MongoDBBackupJob:
+		artifactID = job.Data.MongoDBBackup.ArtifactID
+	default:
+		return nil
+	}
+
+	_, err = models.UpdateArtifact(s.db.Querier, artifactID, models.UpdateArtifactParams{
+		StartedAt: pointer.ToTime(models.Now()),
+	})
+	return err
+}
+
 // StartMySQLBackupJob starts mysql backup job on the pmm-agent.
 func (s *JobsService) StartMySQLBackupJob(jobID, pmmAgentID string, timeout time.Duration, name string, dbConfig *models.DBConfig, locationConfig *models.BackupLocationConfig) error {
 	if err := PMMAgentSupported(s.r.db.Querier, pmmAgentID,
@@ -341,16 +871,11 @@ func (s *JobsService) StartMySQLBackupJob(jobID, pmmAgentID string, timeout time
 		},
 	}
 
-	agent, err := s.r.get(pmmAgentID)
+	resp, err := s.sendStartJobRequest(pmmAgentID, req)
 	if err != nil {
 		return err
 	}
-
-	resp, err := agent.channel.SendAndWaitResponse(req)
-	if err != nil {
-		return err
-	}
-	if e := resp.(*agentpb.StartJobResponse).Error; e != "" {
+	if e := resp.Error; e != "" {
 		return errors.Errorf("failed to start MySQL job: %s", e)
 	}
 
@@ -398,16 +923,11 @@ func (s *JobsService) StartMongoDBBackupJob(
 		},
 	}
 
-	agent, err := s.r.get(pmmAgentID)
+	resp, err := s.sendStartJobRequest(pmmAgentID, req)
 	if err != nil {
 		return err
 	}
-
-	resp, err := agent.channel.SendAndWaitResponse(req)
-	if err != nil {
-		return err
-	}
-	if e := resp.(*agentpb.StartJobResponse).Error; e != "" {
+	if e := resp.Error; e != "" {
 		return errors.Errorf("failed to start MongoDB job: %s", e)
 	}
 
@@ -446,16 +966,11 @@ func (s *JobsService) StartMySQLRestoreBackupJob(
 		},
 	}
 
-	agent, err := s.r.get(pmmAgentID)
-	if err != nil {
-		return err
-	}
-
-	resp, err := agent.channel.SendAndWaitResponse(req)
+	resp, err := s.sendStartJobRequest(pmmAgentID, req)
 	if err != nil {
 		return err
 	}
-	if e := resp.(*agentpb.StartJobResponse).Error; e != "" {
+	if e := resp.Error; e != "" {
 		return errors.Errorf("failed to start MySQL restore backup job: %s", e)
 	}
 
@@ -463,12 +978,14 @@ func (s *JobsService) StartMySQLRestoreBackupJob(
 }
 
 // StartMongoDBRestoreBackupJob starts mongo restore backup job on the pmm-agent.
+// Non-zero pitrTimestamp restores PITR artifact to the given point in time.
 func (s *JobsService) StartMongoDBRestoreBackupJob(
 	jobID string,
 	pmmAgentID string,
 	timeout time.Duration,
 	name string,
 	dbConfig *models.DBConfig,
+	pitrTimestamp time.Time,
 	locationConfig *models.BackupLocationConfig,
 ) error {
 	if err := PMMAgentSupported(s.r.db.Querier, pmmAgentID,
@@ -485,6 +1002,14 @@ func (s *JobsService) StartMongoDBRestoreBackupJob(
 		Socket:   dbConfig.Socket,
 	}
 
+	if !pitrTimestamp.IsZero() {
+		if err := PMMAgentSupported(s.r.db.Querier, pmmAgentID,
+			"mongodb pitr restore", pmmAgentMinVersionForMongoDBPITRRestore); err != nil {
+			return err
+		}
+		mongoDBReq.PitrTimestamp = timestamppb.New(pitrTimestamp)
+	}
+
 	switch {
 	case locationConfig.S3Config != nil:
 		mongoDBReq.LocationConfig = &agentpb.StartJobRequest_MongoDBRestoreBackup_S3Config{
@@ -502,16 +1027,11 @@ func (s *JobsService) StartMongoDBRestoreBackupJob(
 		},
 	}
 
-	agent, err := s.r.get(pmmAgentID)
+	resp, err := s.sendStartJobRequest(pmmAgentID, req)
 	if err != nil {
 		return err
 	}
-
-	resp, err := agent.channel.SendAndWaitResponse(req)
-	if err != nil {
-		return err
-	}
-	if e := resp.(*agentpb.StartJobResponse).Error; e != "" {
+	if e := resp.Error; e != "" {
 		return errors.Errorf("failed to start MonogDB restore backup job: %s", e)
 	}
 
@@ -540,6 +1060,69 @@ func (s *JobsService) StopJob(jobID string) error {
 	return err
 }
 
+// CancelJob marks job as done with given reason and stops it on the pmm-agent.
+// Results of cancelled job are ignored, and it's not restarted. If pmm-agent is not connected,
+// the job is stopped when it connects again.
+func (s *JobsService) CancelJob(jobID, reason string) error {
+	job, err := models.FindJobByID(s.db.Querier, jobID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if job.Done {
+		// Job already finished
+		return nil
+	}
+
+	// pmm-agent may still run the job, so it's stopped when pmm-agent connects again, see reconcileJobs.
+	agent, agentErr := s.r.get(job.PMMAgentID)
+	job.SetState(models.DoneJobState)
+	job.Error = reason
+	job.StopRequested = agentErr != nil
+	if err = s.db.Update(job); err != nil {
+		return errors.WithStack(err)
+	}
+	s.watchers.publish(job)
+
+	if agentErr != nil {
+		s.l.Warnf("Job %s is cancelled, but pmm-agent %s is not connected: %s.", jobID, job.PMMAgentID, agentErr)
+		return nil
+	}
+
+	_, err = agent.channel.SendAndWaitResponse(&agentpb.StopJobRequest{JobId: jobID})
+
+	return err
+}
+
+// stopCancelledJobs sends stop requests for jobs cancelled while the pmm-agent was disconnected.
+func (s *JobsService) stopCancelledJobs(l *logrus.Entry, pmmAgentID string) {
+	jobs, err := models.FindJobs(s.db.Querier, models.JobsFilter{
+		PMMAgentID:    pmmAgentID,
+		StopRequested: true,
+	})
+	if err != nil {
+		l.Errorf("Failed to find cancelled jobs: %+v.", err)
+		return
+	}
+
+	agent, err := s.r.get(pmmAgentID)
+	if err != nil {
+		l.Warnf("Failed to stop cancelled jobs: %s.", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if _, err = agent.channel.SendAndWaitResponse(&agentpb.StopJobRequest{JobId: job.ID}); err != nil {
+			l.Warnf("Failed to stop cancelled job %s: %s.", job.ID, err)
+			continue
+		}
+
+		if err = models.ClearJobStopRequest(s.db.Querier, job.ID); err != nil {
+			l.Errorf("Failed to update cancelled job %s: %+v.", job.ID, err)
+		}
+	}
+}
+
 func convertS3ConfigModel(config *models.S3LocationConfig) *agentpb.S3LocationConfig {
 	return &agentpb.S3LocationConfig{
 		Endpoint:     config.Endpoint,
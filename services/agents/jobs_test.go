@@ -0,0 +1,329 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package agents
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/percona/pmm/api/agentpb"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/agents/channel"
+	"github.com/percona/pmm-managed/utils/logger"
+	"github.com/percona/pmm-managed/utils/testdb"
+)
+
+// fakeAgentStream is a pmm-agent connection which replies to start and stop job requests and records them.
+type fakeAgentStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	messages chan *agentpb.ServerMessage
+
+	rw   sync.RWMutex
+	sent []*agentpb.ServerMessage
+}
+
+func newFakeAgentStream(ctx context.Context) *fakeAgentStream {
+	return &fakeAgentStream{
+		ctx:      logger.Set(ctx, "fake-agent"),
+		messages: make(chan *agentpb.ServerMessage, 10),
+	}
+}
+
+func (s *fakeAgentStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeAgentStream) Send(msg *agentpb.ServerMessage) error {
+	s.rw.Lock()
+	s.sent = append(s.sent, msg)
+	s.rw.Unlock()
+
+	s.messages <- msg
+	return nil
+}
+
+func (s *fakeAgentStream) Recv() (*agentpb.AgentMessage, error) {
+	select {
+	case msg := <-s.messages:
+		var payload agentpb.AgentResponsePayload
+		switch msg.Payload.(type) {
+		case *agentpb.ServerMessage_StartJob:
+			payload = &agentpb.StartJobResponse{}
+		case *agentpb.ServerMessage_StopJob:
+			payload = &agentpb.StopJobResponse{}
+		default:
+			return &agentpb.AgentMessage{Id: msg.Id}, nil
+		}
+		return &agentpb.AgentMessage{Id: msg.Id, Payload: payload.AgentMessageResponsePayload()}, nil
+	case <-s.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// fakeRemovalService records artifacts which files are removed.
+type fakeRemovalService struct {
+	removed chan string
+}
+
+func (s *fakeRemovalService) DeleteArtifactFiles(_ context.Context, artifactID string) error {
+	s.removed <- artifactID
+	return nil
+}
+
+// requests returns types of requests sent to pmm-agent.
+func (s *fakeAgentStream) requests() []interface{} {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	res := make([]interface{}, len(s.sent))
+	for i, msg := range s.sent {
+		res[i] = msg.Payload
+	}
+	return res
+}
+
+func TestJobsService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	t.Cleanup(func() {
+		cancel()
+		_ = sqlDB.Close()
+	})
+
+	node, err := models.CreateNode(db.Querier, models.GenericNodeType, &models.CreateNodeParams{
+		NodeName: "test-node",
+	})
+	require.NoError(t, err)
+
+	pmmAgent, err := models.CreatePMMAgent(db.Querier, node.NodeID, nil)
+	require.NoError(t, err)
+	pmmAgent.Version = pointer.ToString("2.32.0")
+	require.NoError(t, db.Update(pmmAgent))
+
+	service, err := models.AddNewService(db.Querier, models.MySQLServiceType, &models.AddDBMSServiceParams{
+		ServiceName: "test-service",
+		NodeID:      node.NodeID,
+		Address:     pointer.ToString("127.0.0.1"),
+		Port:        pointer.ToUint16(3306),
+	})
+	require.NoError(t, err)
+
+	_, err = models.CreateAgent(db.Querier, models.MySQLdExporterType, &models.CreateAgentParams{
+		PMMAgentID: pmmAgent.AgentID,
+		ServiceID:  service.ServiceID,
+		Username:   "user",
+		Password:   "password",
+	})
+	require.NoError(t, err)
+
+	location, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+		Name: "Test location",
+		BackupLocationConfig: models.BackupLocationConfig{
+			S3Config: &models.S3LocationConfig{
+				Endpoint:     "https://s3.us-west-2.amazonaws.com/",
+				AccessKey:    "access_key",
+				SecretKey:    "secret_key",
+				BucketName:   "example_bucket",
+				BucketRegion: "us-east-2",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	stream := newFakeAgentStream(ctx)
+	registry := NewRegistry(db)
+	registry.agents[pmmAgent.AgentID] = &pmmAgentInfo{
+		channel: channel.New(stream),
+		id:      pmmAgent.AgentID,
+	}
+	removal := &fakeRemovalService{removed: make(chan string, 1)}
+	s := NewJobsService(db, registry, nil, nil, nil, nil, removal)
+
+	createBackup := func(t *testing.T, name string, status models.BackupStatus) (*models.Artifact, *models.Job) {
+		t.Helper()
+
+		artifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       name,
+			Vendor:     "MySQL",
+			LocationID: location.ID,
+			ServiceID:  service.ServiceID,
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     status,
+		})
+		require.NoError(t, err)
+
+		job, err := models.CreateJob(db.Querier, models.CreateJobParams{
+			PMMAgentID: pmmAgent.AgentID,
+			Type:       models.MySQLBackupJob,
+			Data: &models.JobData{
+				MySQLBackup: &models.MySQLBackupJobData{
+					ServiceID:  service.ServiceID,
+					ArtifactID: artifact.ID,
+				},
+			},
+			Retries:  1,
+			Interval: time.Second,
+		})
+		require.NoError(t, err)
+
+		return artifact, job
+	}
+
+	t.Run("cancelled during retry wait", func(t *testing.T) {
+		_, job := createBackup(t, "retried-backup", models.ErrorBackupStatus)
+		job.Error = "backup failed"
+		job.SetState(models.DoneJobState)
+		require.NoError(t, db.Update(job))
+
+		res := make(chan error, 1)
+		go func() {
+			res <- s.RestartJob(ctx, job.ID)
+		}()
+
+		// job waits for the retry interval
+		require.Eventually(t, func() bool {
+			j, err := models.FindJobByID(db.Querier, job.ID)
+			return err == nil && j.State == models.RetryingJobState
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, s.CancelJob(job.ID, "cancelled"))
+
+		select {
+		case err = <-res:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RestartJob didn't return in time")
+		}
+
+		job, err = models.FindJobByID(db.Querier, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DoneJobState, job.State)
+		assert.Equal(t, "cancelled", job.Error)
+
+		requests := stream.requests()
+		require.Len(t, requests, 1)
+		assert.IsType(t, &agentpb.ServerMessage_StopJob{}, requests[0])
+	})
+
+	t.Run("cancelled while pmm-agent is disconnected", func(t *testing.T) {
+		_, job := createBackup(t, "disconnected-backup", models.PendingBackupStatus)
+		require.NoError(t, models.UpdateJobState(db.Querier, job.ID, models.RunningJobState))
+
+		registry.rw.Lock()
+		agentInfo := registry.agents[pmmAgent.AgentID]
+		delete(registry.agents, pmmAgent.AgentID)
+		registry.rw.Unlock()
+
+		sent := len(stream.requests())
+		require.NoError(t, s.CancelJob(job.ID, "cancelled"))
+		assert.Len(t, stream.requests(), sent)
+
+		job, err := models.FindJobByID(db.Querier, job.ID)
+		require.NoError(t, err)
+		assert.True(t, job.StopRequested)
+
+		registry.rw.Lock()
+		registry.agents[pmmAgent.AgentID] = agentInfo
+		registry.rw.Unlock()
+
+		// stop request is stored, so it isn't lost on pmm-managed restart
+		s := NewJobsService(db, registry, nil, nil, nil, nil, removal)
+		s.stopCancelledJobs(logrus.WithField("test", t.Name()), pmmAgent.AgentID)
+
+		requests := stream.requests()
+		require.Len(t, requests, sent+1)
+		assert.IsType(t, &agentpb.ServerMessage_StopJob{}, requests[sent])
+
+		job, err = models.FindJobByID(db.Querier, job.ID)
+		require.NoError(t, err)
+		assert.False(t, job.StopRequested)
+		assert.Equal(t, models.DoneJobState, job.State)
+	})
+
+	t.Run("reconciled retrying job waits for retry time", func(t *testing.T) {
+		_, job := createBackup(t, "reconciled-backup", models.PendingBackupStatus)
+		job.SetState(models.RetryingJobState)
+		job.RetryAt = pointer.ToTime(models.Now().Add(time.Second))
+		require.NoError(t, db.Update(job))
+
+		// new service has no in-memory state, as after pmm-managed restart
+		s := NewJobsService(db, registry, nil, nil, nil, nil, removal)
+		sent := len(stream.requests())
+		require.NoError(t, s.reconcileJob(job))
+
+		job, err := models.FindJobByID(db.Querier, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RetryingJobState, job.State)
+		assert.Len(t, stream.requests(), sent)
+
+		require.Eventually(t, func() bool {
+			j, err := models.FindJobByID(db.Querier, job.ID)
+			return err == nil && j.State == models.RunningJobState
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.False(t, models.Now().Before(*job.RetryAt))
+
+		requests := stream.requests()
+		require.Len(t, requests, sent+1)
+		assert.IsType(t, &agentpb.ServerMessage_StartJob{}, requests[sent])
+	})
+
+	t.Run("result of cancelled backup", func(t *testing.T) {
+		artifact, job := createBackup(t, "cancelled-backup", models.PendingBackupStatus)
+		require.NoError(t, models.UpdateJobState(db.Querier, job.ID, models.RunningJobState))
+
+		// see backup.Service.CancelBackup
+		_, err := models.UpdateArtifact(db.Querier, artifact.ID, models.UpdateArtifactParams{
+			Status: models.BackupStatusPointer(models.ErrorBackupStatus),
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.CancelJob(job.ID, "cancelled"))
+		assert.Empty(t, removal.removed)
+
+		s.handleJobResult(ctx, logrus.WithField("test", t.Name()), &agentpb.JobResult{
+			JobId: job.ID,
+			Result: &agentpb.JobResult_Error_{
+				Error: &agentpb.JobResult_Error{Message: "job is stopped"},
+			},
+		})
+
+		select {
+		case removed := <-removal.removed:
+			assert.Equal(t, artifact.ID, removed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("files of cancelled artifact aren't removed")
+		}
+
+		job, err = models.FindJobByID(db.Querier, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "cancelled", job.Error)
+	})
+}
@@ -30,13 +30,16 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	"github.com/hashicorp/go-version"
 	"github.com/percona/pmm/api/alertmanager/amclient"
 	"github.com/percona/pmm/api/alertmanager/amclient/alert"
+	"github.com/percona/pmm/api/alertmanager/amclient/general"
 	"github.com/percona/pmm/api/alertmanager/amclient/silence"
 	"github.com/percona/pmm/api/alertmanager/ammodels"
 	"github.com/percona/pmm/utils/pdeathsig"
@@ -45,6 +48,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/reform.v1"
 	"gopkg.in/yaml.v3"
 
@@ -66,6 +71,7 @@ const (
 	alertmanagerBaseConfigPath = "/srv/alertmanager/alertmanager.base.yml"
 
 	receiverNameSeparator = " + "
+	ruleReceiverSeparator = " / "
 
 	// CheckFilter represents AlertManager filter for Checks/Advisor results.
 	CheckFilter = "stt_check=1"
@@ -81,22 +87,43 @@ var notificationLabels = []string{
 //go:embed email_template.html
 var emailTemplate string
 
+//go:embed email_alert_template.html
+var emailAlertTemplate string
+
+// emailAlertPlaceholder in emailTemplate is replaced with HTML template of a single alert, emailAlertTemplate by default.
+const emailAlertPlaceholder = "{{/* alert */}}"
+
+// Default notification title templates: defaultSummary also includes alerts summaries.
+const (
+	defaultTitle   = `[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ end }}]`
+	defaultSummary = defaultTitle +
+		"{{ range .Alerts -}}{{ if .Labels.severity }}[{{ .Labels.severity | toUpper }}]{{ end }} {{ .Annotations.summary }}{{ end }}"
+)
+
 // Service is responsible for interactions with Alertmanager.
 type Service struct {
 	db     *reform.DB
 	client *http.Client
 
-	l        *logrus.Entry
-	reloadCh chan struct{}
+	l             *logrus.Entry
+	reloadCh      chan struct{}
+	maintenanceCh chan struct{}
+
+	// maintenanceMx serializes changes of maintenance windows silences made by API and by sync loop.
+	maintenanceMx sync.Mutex
+
+	versionMx sync.Mutex
+	version   *version.Version // cached Alertmanager version, nil if not known yet
 }
 
 // New creates new service.
 func New(db *reform.DB) *Service {
 	return &Service{
-		db:       db,
-		client:   &http.Client{}, // TODO instrument with utils/irt; see vmalert package https://jira.percona.com/browse/PMM-7229
-		l:        logrus.WithField("component", "alertmanager"),
-		reloadCh: make(chan struct{}, 1),
+		db:            db,
+		client:        &http.Client{}, // TODO instrument with utils/irt; see vmalert package https://jira.percona.com/browse/PMM-7229
+		l:             logrus.WithField("component", "alertmanager"),
+		reloadCh:      make(chan struct{}, 1),
+		maintenanceCh: make(chan struct{}, 1),
 	}
 }
 
@@ -202,8 +229,13 @@ func (svc *Service) updateConfiguration(ctx context.Context) error {
 		}
 	}()
 
+	amVersion, err := svc.getVersion(ctx)
+	if err != nil {
+		return err
+	}
+
 	base := svc.loadBaseConfig()
-	b, err := svc.marshalConfig(base)
+	b, err := svc.marshalConfig(base, amVersion)
 	if err != nil {
 		return err
 	}
@@ -237,30 +269,30 @@ func (svc *Service) reload(ctx context.Context) error {
 }
 
 // loadBaseConfig returns parsed base configuration file, or empty configuration on error.
-func (svc *Service) loadBaseConfig() *alertmanager.Config {
+func (svc *Service) loadBaseConfig() *amConfig {
 	buf, err := ioutil.ReadFile(alertmanagerBaseConfigPath)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			svc.l.Errorf("Failed to load base Alertmanager config %s: %s", alertmanagerBaseConfigPath, err)
 		}
 
-		return &alertmanager.Config{}
+		return &amConfig{}
 	}
 
-	var cfg alertmanager.Config
+	var cfg amConfig
 	if err := yaml.Unmarshal(buf, &cfg); err != nil {
 		svc.l.Errorf("Failed to parse base Alertmanager config %s: %s.", alertmanagerBaseConfigPath, err)
 
-		return &alertmanager.Config{}
+		return &amConfig{}
 	}
 
 	return &cfg
 }
 
 // marshalConfig marshals Alertmanager configuration.
-func (svc *Service) marshalConfig(base *alertmanager.Config) ([]byte, error) {
+func (svc *Service) marshalConfig(base *amConfig, amVersion *version.Version) ([]byte, error) {
 	cfg := base
-	if err := svc.populateConfig(cfg); err != nil {
+	if err := svc.populateConfig(cfg, amVersion); err != nil {
 		return nil, err
 	}
 
@@ -442,9 +474,11 @@ func recreateTLSConfigFiles(chanMap map[string]*models.Channel) error {
 }
 
 // populateConfig adds configuration from the database to cfg.
-func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
+// Channels not supported by given Alertmanager version are skipped.
+func (svc *Service) populateConfig(cfg *amConfig, amVersion *version.Version) error {
 	var settings *models.Settings
 	var rules []*models.Rule
+	var routes []*models.AlertRoute
 	var channels []*models.Channel
 	e := svc.db.InTransaction(func(tx *reform.TX) error {
 		var err error
@@ -458,6 +492,11 @@ func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
 			return err
 		}
 
+		routes, err = models.FindAlertRoutes(tx.Querier)
+		if err != nil {
+			return err
+		}
+
 		channels, err = models.FindChannels(tx.Querier)
 		if err != nil {
 			return err
@@ -491,13 +530,17 @@ func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
 
 	// make sure that "empty" receiver is there
 	if findReceiverIdx("empty") == -1 {
-		cfg.Receivers = append(cfg.Receivers, &alertmanager.Receiver{
-			Name: "empty",
+		cfg.Receivers = append(cfg.Receivers, &receiver{
+			Receiver: alertmanager.Receiver{
+				Name: "empty",
+			},
 		})
 	}
 
-	disabledReceiver := &alertmanager.Receiver{
-		Name: "disabled",
+	disabledReceiver := &receiver{
+		Receiver: alertmanager.Receiver{
+			Name: "disabled",
+		},
 	}
 	// Override if there is any user defined receiver `disabled`, needs to be empty
 	if disabledReceiverIdx := findReceiverIdx("disabled"); disabledReceiverIdx != -1 {
@@ -532,10 +575,15 @@ func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
 		cfg.Global.SlackAPIURL = settings.IntegratedAlerting.SlackAlertingSettings.URL
 	}
 
-	recvSet := make(map[string]models.ChannelIDs) // stores unique combinations of channel IDs
+	recvSet := make(map[string]models.ChannelIDs)                  // stores unique combinations of channel IDs
+	recvTemplates := make(map[string]*models.NotificationTemplate) // stores rules notification templates by receiver names
+	treeTemplates := make(map[string]*models.NotificationTemplate) // stores notification templates of rules routed by routing tree
 	for _, r := range rules {
 		// skip rules with 0 notification channels
 		if len(r.ChannelIDs) == 0 {
+			if r.NotificationTemplate != nil {
+				treeTemplates[r.ID] = r.NotificationTemplate
+			}
 			continue
 		}
 
@@ -552,32 +600,29 @@ func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
 				route.Match[f.Key] = f.Val
 			case models.Regex:
 				route.MatchRE[f.Key] = f.Val
+			case models.NotEqual, models.NotRegex, models.Greater, models.GreaterOrEqual, models.Less, models.LessOrEqual:
+				// applied to the rule expression in vmalert rule files
 			default:
 				svc.l.Warnf("Unhandled filter: %+v", f)
 			}
 		}
-		enabledChannels := make(models.ChannelIDs, 0, len(r.ChannelIDs))
-		for _, chID := range r.ChannelIDs {
-			if channel, ok := chanMap[chID]; ok {
-				if !channel.Disabled {
-					enabledChannels = append(enabledChannels, chID)
-				}
-			}
-		}
-		// make sure same slice with different order are not considered unique.
-		sort.Strings(enabledChannels)
-		recv := strings.Join(enabledChannels, receiverNameSeparator)
-		if len(enabledChannels) == 0 {
-			recv = "disabled"
-		} else {
-			recvSet[recv] = enabledChannels
+		route.Receiver = receiverName(r.ChannelIDs, chanMap, recvSet)
+
+		// Route matches alerts of this rule only, so dedicated receiver renders them with rule's notification template.
+		if r.NotificationTemplate != nil && route.Receiver != "disabled" {
+			recv := route.Receiver + ruleReceiverSeparator + r.ID
+			recvSet[recv] = recvSet[route.Receiver]
+			recvTemplates[recv] = r.NotificationTemplate
+			route.Receiver = recv
 		}
-		route.Receiver = recv
 
 		cfg.Route.Routes = append(cfg.Route.Routes, route)
 	}
 
-	receivers, err := svc.generateReceivers(chanMap, recvSet)
+	// Alerts of rules with notification channels are routed above, routing tree handles all other alerts.
+	cfg.Route.Routes = append(cfg.Route.Routes, generateAlertRoutes(routes, treeTemplates, chanMap, recvSet, recvTemplates)...)
+
+	receivers, err := svc.generateReceivers(amVersion, chanMap, recvSet, recvTemplates)
 	if err != nil {
 		return err
 	}
@@ -586,46 +631,227 @@ func (svc *Service) populateConfig(cfg *alertmanager.Config) error {
 	return nil
 }
 
-func formatSlackText(labels ...string) string {
+// receiverName returns name of the receiver for enabled channels with given IDs, and adds it to recvSet.
+func receiverName(channelIDs []string, chanMap map[string]*models.Channel, recvSet map[string]models.ChannelIDs) string {
+	enabledChannels := make(models.ChannelIDs, 0, len(channelIDs))
+	for _, chID := range channelIDs {
+		if channel, ok := chanMap[chID]; ok {
+			if !channel.Disabled {
+				enabledChannels = append(enabledChannels, chID)
+			}
+		}
+	}
+	if len(enabledChannels) == 0 {
+		return "disabled"
+	}
+
+	// make sure same slice with different order are not considered unique.
+	sort.Strings(enabledChannels)
+	recv := strings.Join(enabledChannels, receiverNameSeparator)
+	recvSet[recv] = enabledChannels
+	return recv
+}
+
+// generateAlertRoutes converts routing tree to Alertmanager routes. Disabled routes are skipped with their child routes.
+// Child routes are matched before escalation steps of the parent route, so the most specific route handles the alert;
+// child routes without own escalation steps use the parent's ones, so matching them doesn't stop the escalation.
+// Escalation steps are rendered as routes without matchers, where group_wait is set to step delay.
+// That way channels of the step are notified only if alert is still firing and isn't silenced (acknowledged) after delay.
+// Alerts of rules with given notification templates (by rule IDs) are sent by steps to dedicated receivers
+// that render them with rule's notification template; those receivers are added to recvTemplates.
+func generateAlertRoutes(routes []*models.AlertRoute, ruleTemplates map[string]*models.NotificationTemplate, chanMap map[string]*models.Channel, recvSet map[string]models.ChannelIDs, recvTemplates map[string]*models.NotificationTemplate) []*alertmanager.Route {
+	children := make(map[string][]*models.AlertRoute)
+	for _, r := range routes {
+		parentID := pointer.GetString(r.ParentID)
+		children[parentID] = append(children[parentID], r)
+	}
+
+	ruleIDs := make([]string, 0, len(ruleTemplates))
+	for id := range ruleTemplates {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	var convert func(parentID string, parentSteps models.EscalationSteps) []*alertmanager.Route
+	convert = func(parentID string, parentSteps models.EscalationSteps) []*alertmanager.Route {
+		var res []*alertmanager.Route
+		for _, r := range children[parentID] {
+			if r.Disabled {
+				continue
+			}
+
+			// filters are validated on route creation, invalid route would match too many alerts
+			match, matchRE, err := models.AlertRouteMatchers(r.Filters)
+			if err != nil {
+				logrus.WithField("component", "alertmanager").Warnf("Skipping alert route %s: %s", r.ID, err)
+				continue
+			}
+
+			steps := r.EscalationSteps
+			if len(steps) == 0 {
+				steps = parentSteps
+			}
+
+			route := &alertmanager.Route{
+				GroupBy:        r.GroupBy,
+				Match:          match,
+				MatchRE:        matchRE,
+				Continue:       r.Continue,
+				GroupWait:      promconfig.Duration(r.GroupWait),
+				GroupInterval:  promconfig.Duration(r.GroupInterval),
+				RepeatInterval: promconfig.Duration(r.RepeatInterval),
+				Routes:         convert(r.ID, steps),
+			}
+
+			for i, step := range steps {
+				stepRoute := &alertmanager.Route{
+					Receiver:  receiverName(step.ChannelIDs, chanMap, recvSet),
+					Continue:  i != len(steps)-1,
+					GroupWait: promconfig.Duration(step.Delay),
+				}
+
+				if stepRoute.Receiver != "disabled" {
+					for _, id := range ruleIDs {
+						recv := stepRoute.Receiver + ruleReceiverSeparator + id
+						recvSet[recv] = recvSet[stepRoute.Receiver]
+						recvTemplates[recv] = ruleTemplates[id]
+						stepRoute.Routes = append(stepRoute.Routes, &alertmanager.Route{
+							Match:    map[string]string{"rule_id": id},
+							Receiver: recv,
+						})
+					}
+				}
+
+				route.Routes = append(route.Routes, stepRoute)
+			}
+
+			res = append(res, route)
+		}
+
+		return res
+	}
+
+	return convert("", nil)
+}
+
+// slackAlertText returns default Slack text template of a single alert.
+func slackAlertText(labels ...string) string {
 	const listEntryFormat = "{{ if .Labels.%[1]s }}     • *%[1]s:* `{{ .Labels.%[1]s }}`\n{{ end }}"
 
-	text := "{{ range .Alerts -}}\n" +
-		"*Alert:* {{ if .Labels.severity }}`{{ .Labels.severity | toUpper }}`{{ end }} {{ .Annotations.summary }}\n" +
+	text := "*Alert:* {{ if .Labels.severity }}`{{ .Labels.severity | toUpper }}`{{ end }} {{ .Annotations.summary }}\n" +
 		"*Description:* {{ .Annotations.description }}\n" +
 		"*Details:*\n"
 	for _, l := range labels {
 		text += fmt.Sprintf(listEntryFormat, l)
 	}
 
-	text += "\n\n{{ end }}"
-
 	return text
 }
 
-func formatPagerDutyFiringDetails(labels ...string) string {
+// pagerDutyAlertText returns default plain text template of a single alert.
+func pagerDutyAlertText(labels ...string) string {
 	const listEntryFormat = "{{ if .Labels.%[1]s }}  - %[1]s: {{ .Labels.%[1]s }}\n{{ end }}"
 
-	text := "{{ range .Alerts -}}\n" +
-		"Alert: {{ if .Labels.severity }}[{{ .Labels.severity | toUpper }}]{{ end }} {{ .Annotations.summary }}\n" +
+	text := "Alert: {{ if .Labels.severity }}[{{ .Labels.severity | toUpper }}]{{ end }} {{ .Annotations.summary }}\n" +
 		"Description: {{ .Annotations.description }}\n" +
 		"Details:\n"
 	for _, l := range labels {
 		text += fmt.Sprintf(listEntryFormat, l)
 	}
 
-	text += "\n\n{{ end }}"
+	return text
+}
+
+// opsGenieAlertText returns default Opsgenie alert description template of a single alert.
+// Opsgenie renders description as plain text, while alert message is limited to 130 characters
+// and contains only the summary.
+func opsGenieAlertText(labels ...string) string {
+	const listEntryFormat = "{{ if .Labels.%[1]s }}%[1]s: {{ .Labels.%[1]s }}\n{{ end }}"
+
+	text := "{{ if .Labels.severity }}Severity: {{ .Labels.severity | toUpper }}\n{{ end }}" +
+		"Summary: {{ .Annotations.summary }}\n" +
+		"Description: {{ .Annotations.description }}\n"
+	for _, l := range labels {
+		text += fmt.Sprintf(listEntryFormat, l)
+	}
 
 	return text
 }
 
+// msTeamsAlertText returns default Microsoft Teams markdown template of a single alert.
+func msTeamsAlertText(labels ...string) string {
+	const listEntryFormat = "{{ if .Labels.%[1]s }}- **%[1]s:** `{{ .Labels.%[1]s }}`\n{{ end }}"
+
+	text := "**Alert:** {{ if .Labels.severity }}`{{ .Labels.severity | toUpper }}`{{ end }} {{ .Annotations.summary }}\n\n" +
+		"**Description:** {{ .Annotations.description }}\n\n" +
+		"**Details:**\n\n"
+	for _, l := range labels {
+		text += fmt.Sprintf(listEntryFormat, l)
+	}
+
+	return text
+}
+
+// telegramAlertText returns default Telegram template of a single alert for HTML parse mode.
+func telegramAlertText(labels ...string) string {
+	const listEntryFormat = "{{ if .Labels.%[1]s }}  • <b>%[1]s:</b> <code>{{ .Labels.%[1]s | html }}</code>\n{{ end }}"
+
+	text := "<b>Alert:</b> {{ if .Labels.severity }}<code>{{ .Labels.severity | toUpper }}</code>{{ end }} {{ .Annotations.summary | html }}\n" +
+		"<b>Description:</b> {{ .Annotations.description | html }}\n" +
+		"<b>Details:</b>\n"
+	for _, l := range labels {
+		text += fmt.Sprintf(listEntryFormat, l)
+	}
+
+	return text
+}
+
+// formatAlertsText returns template that renders given alert text template for each alert of notification.
+func formatAlertsText(alertText string) string {
+	return "{{ range .Alerts -}}\n" + alertText + "\n\n{{ end }}"
+}
+
+// formatEmailHTML returns email HTML template with given alert text template.
+func formatEmailHTML(alertText string) string {
+	return strings.Replace(emailTemplate, emailAlertPlaceholder, strings.TrimSuffix(alertText, "\n"), 1)
+}
+
+// formatTitle returns notification title template: rule title, channel title or given default one.
+func formatTitle(defaultTitle string, channel, rule *models.NotificationTemplate) string {
+	switch {
+	case rule != nil && rule.Title != "":
+		return rule.Title
+	case channel != nil && channel.Title != "":
+		return channel.Title
+	default:
+		return defaultTitle
+	}
+}
+
+// formatAlertText returns template of a single alert: rule text, channel text or given default one.
+func formatAlertText(defaultText string, channel, rule *models.NotificationTemplate) string {
+	switch {
+	case rule != nil && rule.Text != "":
+		return rule.Text
+	case channel != nil && channel.Text != "":
+		return channel.Text
+	default:
+		return defaultText
+	}
+}
+
 // generateReceivers takes the channel map and a unique set of rule combinations and generates a slice of receivers.
-func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSet map[string]models.ChannelIDs) ([]*alertmanager.Receiver, error) {
-	receivers := make([]*alertmanager.Receiver, 0, len(recvSet))
+// Notification templates of channels and given rules notification templates (by receiver names) override default ones.
+func (svc *Service) generateReceivers(amVersion *version.Version, chanMap map[string]*models.Channel, recvSet map[string]models.ChannelIDs, templates map[string]*models.NotificationTemplate) ([]*receiver, error) {
+	receivers := make([]*receiver, 0, len(recvSet))
 
 	for name, channelIDs := range recvSet {
-		recv := &alertmanager.Receiver{
-			Name: name,
+		recv := &receiver{
+			Receiver: alertmanager.Receiver{
+				Name: name,
+			},
 		}
+		rule := templates[name]
 
 		for _, ch := range channelIDs {
 			channel, ok := chanMap[ch]
@@ -633,6 +859,11 @@ func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSe
 				svc.l.Warnf("Missing channel %s, skip it.", ch)
 				continue
 			}
+			if err := checkChannelType(channel.Type, amVersion); err != nil {
+				svc.l.Warnf("Channel %s: %s Skip it.", ch, status.Convert(err).Message())
+				continue
+			}
+			title := formatTitle(defaultTitle, channel.NotificationTemplate, rule)
 			switch channel.Type {
 			case models.Email:
 				for _, to := range channel.EmailConfig.To {
@@ -641,9 +872,9 @@ func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSe
 							SendResolved: channel.EmailConfig.SendResolved,
 						},
 						To:   to,
-						HTML: emailTemplate,
+						HTML: formatEmailHTML(formatAlertText(emailAlertTemplate, channel.NotificationTemplate, rule)),
 						Headers: map[string]string{
-							"Subject": `[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ end }}]`,
+							"Subject": title,
 						},
 					})
 				}
@@ -653,10 +884,9 @@ func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSe
 					NotifierConfig: alertmanager.NotifierConfig{
 						SendResolved: channel.PagerDutyConfig.SendResolved,
 					},
-					Description: `[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ end }}]` +
-						"{{ range .Alerts -}}{{ if .Labels.severity }}[{{ .Labels.severity | toUpper }}]{{ end }} {{ .Annotations.summary }}{{ end }}",
+					Description: formatTitle(defaultSummary, channel.NotificationTemplate, rule),
 					Details: map[string]string{
-						"firing": formatPagerDutyFiringDetails(notificationLabels...),
+						"firing": formatAlertsText(formatAlertText(pagerDutyAlertText(notificationLabels...), channel.NotificationTemplate, rule)),
 					},
 				}
 				if channel.PagerDutyConfig.RoutingKey != "" {
@@ -673,8 +903,8 @@ func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSe
 						SendResolved: channel.SlackConfig.SendResolved,
 					},
 					Channel: channel.SlackConfig.Channel,
-					Title:   `[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ end }}]`,
-					Text:    formatSlackText(notificationLabels...),
+					Title:   title,
+					Text:    formatAlertsText(formatAlertText(slackAlertText(notificationLabels...), channel.NotificationTemplate, rule)),
 				})
 
 			case models.WebHook:
@@ -707,6 +937,41 @@ func (svc *Service) generateReceivers(chanMap map[string]*models.Channel, recvSe
 
 				recv.WebhookConfigs = append(recv.WebhookConfigs, webhookConfig)
 
+			case models.OpsGenie:
+				recv.OpsGenieConfigs = append(recv.OpsGenieConfigs, &alertmanager.OpsGenieConfig{
+					NotifierConfig: alertmanager.NotifierConfig{
+						SendResolved: channel.OpsGenieConfig.SendResolved,
+					},
+					APIKey:      channel.OpsGenieConfig.APIKey,
+					APIURL:      channel.OpsGenieConfig.APIURL,
+					Priority:    channel.OpsGenieConfig.Priority,
+					Tags:        strings.Join(channel.OpsGenieConfig.Tags, ","),
+					Message:     formatTitle(defaultSummary, channel.NotificationTemplate, rule),
+					Description: formatAlertsText(formatAlertText(opsGenieAlertText(notificationLabels...), channel.NotificationTemplate, rule)),
+				})
+
+			case models.MSTeams:
+				recv.MSTeamsConfigs = append(recv.MSTeamsConfigs, &msTeamsConfig{
+					NotifierConfig: alertmanager.NotifierConfig{
+						SendResolved: channel.MSTeamsConfig.SendResolved,
+					},
+					WebhookURL: channel.MSTeamsConfig.WebhookURL,
+					Title:      title,
+					Text:       formatAlertsText(formatAlertText(msTeamsAlertText(notificationLabels...), channel.NotificationTemplate, rule)),
+				})
+
+			case models.Telegram:
+				recv.TelegramConfigs = append(recv.TelegramConfigs, &telegramConfig{
+					NotifierConfig: alertmanager.NotifierConfig{
+						SendResolved: channel.TelegramConfig.SendResolved,
+					},
+					BotToken: channel.TelegramConfig.BotToken,
+					ChatID:   channel.TelegramConfig.ChatID,
+					Message: "<b>" + title + "</b>\n\n" +
+						formatAlertsText(formatAlertText(telegramAlertText(notificationLabels...), channel.NotificationTemplate, rule)),
+					ParseMode: "HTML",
+				})
+
 			default:
 				return nil, errors.Errorf("invalid channel type: %q", channel.Type)
 			}
@@ -874,6 +1139,61 @@ func (svc *Service) IsReady(ctx context.Context) error {
 	return nil
 }
 
+// getVersion returns Alertmanager version. It is cached after the first successful request.
+func (svc *Service) getVersion(ctx context.Context) (*version.Version, error) {
+	svc.versionMx.Lock()
+	defer svc.versionMx.Unlock()
+
+	if svc.version != nil {
+		return svc.version, nil
+	}
+
+	resp, err := amclient.Default.General.GetStatus(&general.GetStatusParams{
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Alertmanager status")
+	}
+	if resp.Payload.VersionInfo == nil || resp.Payload.VersionInfo.Version == nil {
+		return nil, errors.New("Alertmanager status doesn't contain version")
+	}
+
+	v, err := version.NewVersion(*resp.Payload.VersionInfo.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse Alertmanager version")
+	}
+
+	svc.version = v
+	return v, nil
+}
+
+// CheckChannelType returns FailedPrecondition error if channels of given type
+// are not supported by running Alertmanager.
+func (svc *Service) CheckChannelType(ctx context.Context, channelType models.ChannelType) error {
+	if _, ok := channelTypesMinVersions[channelType]; !ok {
+		return nil
+	}
+
+	v, err := svc.getVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checkChannelType(channelType, v)
+}
+
+// checkChannelType returns FailedPrecondition error if channels of given type
+// are not supported by given Alertmanager version.
+func checkChannelType(channelType models.ChannelType, amVersion *version.Version) error {
+	minVersion, ok := channelTypesMinVersions[channelType]
+	if !ok || !amVersion.LessThan(minVersion) {
+		return nil
+	}
+
+	return status.Errorf(codes.FailedPrecondition, "%s channels require Alertmanager %s or later, running version is %s.",
+		channelType, minVersion, amVersion)
+}
+
 // configure default client; we use it mainly because we can't remove it from generated code
 //nolint:gochecknoinits
 func init() {
@@ -24,12 +24,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AlekSi/pointer"
+	"github.com/hashicorp/go-version"
 	"github.com/percona-platform/saas/pkg/alert"
 	"github.com/percona-platform/saas/pkg/common"
 	"github.com/percona/promconfig"
 	"github.com/percona/promconfig/alertmanager"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/reform.v1"
 	"gopkg.in/reform.v1/dialects/postgresql"
 	"gopkg.in/yaml.v3"
@@ -39,6 +43,9 @@ import (
 	"github.com/percona/pmm-managed/utils/tests"
 )
 
+// testVersion is Alertmanager version supporting all channel types.
+var testVersion = version.Must(version.NewVersion("0.26.0"))
+
 var htmlTemplate = `|
             <!--
             Style and HTML derived from https://github.com/mailgun/transactional-email-templates
@@ -342,8 +349,8 @@ func TestIsReady(t *testing.T) {
 }
 
 // marshalAndValidate populates, marshals and validates config.
-func marshalAndValidate(t *testing.T, svc *Service, base *alertmanager.Config) string {
-	b, err := svc.marshalConfig(base)
+func marshalAndValidate(t *testing.T, svc *Service, base *amConfig) string {
+	b, err := svc.marshalConfig(base, testVersion)
 	require.NoError(t, err)
 
 	t.Logf("config:\n%s", b)
@@ -729,7 +736,7 @@ func TestGenerateReceivers(t *testing.T) {
 		"1+2": {"1", "2"},
 	}
 	s := New(nil)
-	actualR, err := s.generateReceivers(chanMap, recvSet)
+	actualR, err := s.generateReceivers(testVersion, chanMap, recvSet, nil)
 	require.NoError(t, err)
 	actual, err := yaml.Marshal(actualR)
 	require.NoError(t, err)
@@ -775,3 +782,291 @@ func TestGenerateReceivers(t *testing.T) {
 `+slackConfigs("channel2")) + "\n"
 	assert.Equal(t, expected, string(actual), "actual:\n%s", actual)
 }
+
+func TestGenerateReceiversExtraChannels(t *testing.T) {
+	t.Parallel()
+
+	chanMap := map[string]*models.Channel{
+		"opsgenie": {
+			ID:   "opsgenie",
+			Type: models.OpsGenie,
+			OpsGenieConfig: &models.OpsGenieConfig{
+				SendResolved: true,
+				APIKey:       "key",
+				Priority:     "P2",
+				Tags:         []string{"pmm", "db"},
+			},
+		},
+		"msteams": {
+			ID:   "msteams",
+			Type: models.MSTeams,
+			MSTeamsConfig: &models.MSTeamsConfig{
+				SendResolved: true,
+				WebhookURL:   "https://example.webhook.office.com/webhookb2/test",
+			},
+		},
+		"telegram": {
+			ID:   "telegram",
+			Type: models.Telegram,
+			TelegramConfig: &models.TelegramConfig{
+				BotToken: "token",
+				ChatID:   -100123,
+			},
+		},
+	}
+	recvSet := map[string]models.ChannelIDs{
+		"all": {"opsgenie", "msteams", "telegram"},
+	}
+	s := New(nil)
+	actual, err := s.generateReceivers(testVersion, chanMap, recvSet, nil)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+
+	recv := actual[0]
+	assert.Equal(t, "all", recv.Name)
+
+	require.Len(t, recv.OpsGenieConfigs, 1)
+	assert.True(t, recv.OpsGenieConfigs[0].SendResolved)
+	assert.Equal(t, "key", recv.OpsGenieConfigs[0].APIKey)
+	assert.Equal(t, "P2", recv.OpsGenieConfigs[0].Priority)
+	assert.Equal(t, "pmm,db", recv.OpsGenieConfigs[0].Tags)
+
+	require.Len(t, recv.MSTeamsConfigs, 1)
+	assert.True(t, recv.MSTeamsConfigs[0].SendResolved)
+	assert.Equal(t, "https://example.webhook.office.com/webhookb2/test", recv.MSTeamsConfigs[0].WebhookURL)
+
+	require.Len(t, recv.TelegramConfigs, 1)
+	assert.False(t, recv.TelegramConfigs[0].SendResolved)
+	assert.Equal(t, "token", recv.TelegramConfigs[0].BotToken)
+	assert.Equal(t, int64(-100123), recv.TelegramConfigs[0].ChatID)
+
+	b, err := yaml.Marshal(recv)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "msteams_configs:\n    - send_resolved: true\n      webhook_url: https://example.webhook.office.com/webhookb2/test\n")
+	assert.Contains(t, string(b), "telegram_configs:\n    - send_resolved: false\n      bot_token: token\n      chat_id: -100123\n")
+	assert.Contains(t, string(b), "parse_mode: HTML\n")
+
+	t.Run("OldAlertmanager", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := s.generateReceivers(version.Must(version.NewVersion("0.23.0")), chanMap, recvSet, nil)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+
+		recv := actual[0]
+		assert.Len(t, recv.OpsGenieConfigs, 1)
+		assert.Empty(t, recv.MSTeamsConfigs)
+		assert.Empty(t, recv.TelegramConfigs)
+	})
+}
+
+func TestCheckChannelType(t *testing.T) {
+	t.Parallel()
+
+	v024 := version.Must(version.NewVersion("0.24.0"))
+	assert.NoError(t, checkChannelType(models.Telegram, v024))
+	assert.NoError(t, checkChannelType(models.OpsGenie, v024))
+	tests.AssertGRPCError(t, status.New(codes.FailedPrecondition, "msteams channels require Alertmanager 0.26.0 or later, running version is 0.24.0."),
+		checkChannelType(models.MSTeams, v024))
+}
+
+func TestGenerateReceiversNotificationTemplates(t *testing.T) {
+	t.Parallel()
+
+	chanMap := map[string]*models.Channel{
+		"slack": {
+			ID:   "slack",
+			Type: models.Slack,
+			SlackConfig: &models.SlackConfig{
+				Channel: "channel",
+			},
+			NotificationTemplate: &models.NotificationTemplate{
+				Title: "Channel title",
+				Text:  "Channel text",
+			},
+		},
+		"email": {
+			ID:   "email",
+			Type: models.Email,
+			EmailConfig: &models.EmailConfig{
+				To: []string{"test@test.test"},
+			},
+		},
+	}
+	recvSet := map[string]models.ChannelIDs{
+		"all":                {"slack", "email"},
+		"all / /rule_id/1":   {"slack", "email"},
+		"slack / /rule_id/2": {"slack"},
+	}
+	templates := map[string]*models.NotificationTemplate{
+		"all / /rule_id/1":   {Title: "Rule 1 title", Text: "Rule 1 text"},
+		"slack / /rule_id/2": {Text: "Rule 2 text"},
+	}
+	s := New(nil)
+	actual, err := s.generateReceivers(testVersion, chanMap, recvSet, templates)
+	require.NoError(t, err)
+	require.Len(t, actual, 3)
+	receivers := make(map[string]*receiver, len(actual))
+	for _, recv := range actual {
+		receivers[recv.Name] = recv
+	}
+
+	t.Run("channel templates", func(t *testing.T) {
+		recv := receivers["all"]
+		require.Len(t, recv.SlackConfigs, 1)
+		assert.Equal(t, "Channel title", recv.SlackConfigs[0].Title)
+		assert.Equal(t, "{{ range .Alerts -}}\nChannel text\n\n{{ end }}", recv.SlackConfigs[0].Text)
+
+		require.Len(t, recv.EmailConfigs, 1)
+		assert.Equal(t, defaultTitle, recv.EmailConfigs[0].Headers["Subject"])
+		assert.NotContains(t, recv.EmailConfigs[0].HTML, emailAlertPlaceholder)
+	})
+
+	t.Run("rule templates", func(t *testing.T) {
+		recv := receivers["all / /rule_id/1"]
+		require.Len(t, recv.SlackConfigs, 1)
+		assert.Equal(t, "Rule 1 title", recv.SlackConfigs[0].Title)
+		assert.Equal(t, "{{ range .Alerts -}}\nRule 1 text\n\n{{ end }}", recv.SlackConfigs[0].Text)
+
+		require.Len(t, recv.EmailConfigs, 1)
+		assert.Equal(t, "Rule 1 title", recv.EmailConfigs[0].Headers["Subject"])
+		assert.Contains(t, recv.EmailConfigs[0].HTML, "Rule 1 text")
+		assert.NotContains(t, recv.EmailConfigs[0].HTML, "Rule 2 text")
+
+		recv = receivers["slack / /rule_id/2"]
+		require.Len(t, recv.SlackConfigs, 1)
+		assert.Equal(t, "Channel title", recv.SlackConfigs[0].Title)
+		assert.Equal(t, "{{ range .Alerts -}}\nRule 2 text\n\n{{ end }}", recv.SlackConfigs[0].Text)
+	})
+}
+
+func TestGenerateAlertRoutes(t *testing.T) {
+	t.Parallel()
+
+	chanMap := map[string]*models.Channel{
+		"slack":     {ID: "slack", Type: models.Slack},
+		"pagerduty": {ID: "pagerduty", Type: models.PagerDuty},
+		"disabled":  {ID: "disabled", Type: models.Email, Disabled: true},
+	}
+	routes := []*models.AlertRoute{
+		{
+			ID:        "root",
+			Filters:   models.Filters{{Type: models.Equal, Key: "environment", Val: "prod"}},
+			GroupBy:   []string{"alertname", "service_name"},
+			GroupWait: time.Minute,
+			EscalationSteps: models.EscalationSteps{
+				{ChannelIDs: []string{"slack", "disabled"}},
+				{ChannelIDs: []string{"pagerduty"}, Delay: 30 * time.Minute},
+			},
+		},
+		{
+			ID:              "child",
+			ParentID:        pointer.ToString("root"),
+			Filters:         models.Filters{{Type: models.Regex, Key: "severity", Val: "critical|emergency"}},
+			EscalationSteps: models.EscalationSteps{{ChannelIDs: []string{"pagerduty", "slack"}}},
+		},
+		{
+			ID:       "inherited",
+			ParentID: pointer.ToString("root"),
+			Filters:  models.Filters{{Type: models.Equal, Key: "service_name", Val: "mysql"}},
+			GroupBy:  []string{"alertname"},
+		},
+		{
+			ID:              "disabled",
+			Disabled:        true,
+			EscalationSteps: models.EscalationSteps{{ChannelIDs: []string{"slack"}}},
+		},
+	}
+
+	recvSet := make(map[string]models.ChannelIDs)
+	recvTemplates := make(map[string]*models.NotificationTemplate)
+	actual := generateAlertRoutes(routes, nil, chanMap, recvSet, recvTemplates)
+
+	expected := []*alertmanager.Route{
+		{
+			GroupBy:   []string{"alertname", "service_name"},
+			Match:     map[string]string{"environment": "prod"},
+			GroupWait: promconfig.Duration(time.Minute),
+			Routes: []*alertmanager.Route{
+				{
+					MatchRE: map[string]string{"severity": "critical|emergency"},
+					Routes: []*alertmanager.Route{
+						{Receiver: "pagerduty + slack"},
+					},
+				},
+				{
+					GroupBy: []string{"alertname"},
+					Match:   map[string]string{"service_name": "mysql"},
+					Routes: []*alertmanager.Route{
+						{
+							Receiver: "slack",
+							Continue: true,
+						},
+						{
+							Receiver:  "pagerduty",
+							GroupWait: promconfig.Duration(30 * time.Minute),
+						},
+					},
+				},
+				{
+					Receiver: "slack",
+					Continue: true,
+				},
+				{
+					Receiver:  "pagerduty",
+					GroupWait: promconfig.Duration(30 * time.Minute),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+
+	expectedRecvSet := map[string]models.ChannelIDs{
+		"slack":             {"slack"},
+		"pagerduty":         {"pagerduty"},
+		"pagerduty + slack": {"pagerduty", "slack"},
+	}
+	assert.Equal(t, expectedRecvSet, recvSet)
+	assert.Empty(t, recvTemplates)
+
+	t.Run("rule notification template", func(t *testing.T) {
+		t.Parallel()
+
+		ruleTemplate := &models.NotificationTemplate{Title: "Rule title"}
+		recvSet := make(map[string]models.ChannelIDs)
+		recvTemplates := make(map[string]*models.NotificationTemplate)
+		actual := generateAlertRoutes(routes[:1], map[string]*models.NotificationTemplate{"/rule_id/1": ruleTemplate},
+			chanMap, recvSet, recvTemplates)
+		expected := []*alertmanager.Route{
+			{
+				GroupBy:   []string{"alertname", "service_name"},
+				Match:     map[string]string{"environment": "prod"},
+				GroupWait: promconfig.Duration(time.Minute),
+				Routes: []*alertmanager.Route{
+					{
+						Receiver: "slack",
+						Continue: true,
+						Routes: []*alertmanager.Route{
+							{Match: map[string]string{"rule_id": "/rule_id/1"}, Receiver: "slack / /rule_id/1"},
+						},
+					},
+					{
+						Receiver:  "pagerduty",
+						GroupWait: promconfig.Duration(30 * time.Minute),
+						Routes: []*alertmanager.Route{
+							{Match: map[string]string{"rule_id": "/rule_id/1"}, Receiver: "pagerduty / /rule_id/1"},
+						},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expected, actual)
+
+		expectedRecvTemplates := map[string]*models.NotificationTemplate{
+			"slack / /rule_id/1":     ruleTemplate,
+			"pagerduty / /rule_id/1": ruleTemplate,
+		}
+		assert.Equal(t, expectedRecvTemplates, recvTemplates)
+		assert.Equal(t, models.ChannelIDs{"pagerduty"}, recvSet["pagerduty / /rule_id/1"])
+	})
+}
@@ -0,0 +1,70 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package alertmanager
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/percona/promconfig/alertmanager"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// channelTypesMinVersions contains minimal Alertmanager versions for channel types
+// which integrations were added after the first Alertmanager version shipped with PMM.
+var channelTypesMinVersions = map[models.ChannelType]*version.Version{
+	models.MSTeams:  version.Must(version.NewVersion("0.26.0")),
+	models.Telegram: version.Must(version.NewVersion("0.24.0")),
+}
+
+// amConfig is Alertmanager configuration file.
+// It mirrors alertmanager.Config, but uses receivers with integrations missing in promconfig.
+type amConfig struct {
+	Global       *alertmanager.GlobalConfig  `yaml:"global,omitempty"`
+	Route        *alertmanager.Route         `yaml:"route,omitempty"`
+	InhibitRules []*alertmanager.InhibitRule `yaml:"inhibit_rules,omitempty"`
+	Receivers    []*receiver                 `yaml:"receivers,omitempty"`
+	Templates    []string                    `yaml:"templates"`
+}
+
+// receiver is Alertmanager receiver extended with Microsoft Teams and Telegram integrations.
+type receiver struct {
+	alertmanager.Receiver `yaml:",inline"`
+
+	// Requires Alertmanager 0.26 or later.
+	MSTeamsConfigs []*msTeamsConfig `yaml:"msteams_configs,omitempty"`
+	// Requires Alertmanager 0.24 or later.
+	TelegramConfigs []*telegramConfig `yaml:"telegram_configs,omitempty"`
+}
+
+// msTeamsConfig configures notifications via Microsoft Teams incoming webhook.
+type msTeamsConfig struct {
+	alertmanager.NotifierConfig `yaml:",inline"`
+
+	WebhookURL string `yaml:"webhook_url"`
+	Title      string `yaml:"title,omitempty"`
+	Text       string `yaml:"text,omitempty"`
+}
+
+// telegramConfig configures notifications via Telegram bot.
+type telegramConfig struct {
+	alertmanager.NotifierConfig `yaml:",inline"`
+
+	BotToken  string `yaml:"bot_token"`
+	ChatID    int64  `yaml:"chat_id"`
+	Message   string `yaml:"message,omitempty"`
+	ParseMode string `yaml:"parse_mode,omitempty"`
+}
@@ -62,7 +62,27 @@ func (e *Emailer) Send(ctx context.Context, settings *models.EmailAlertingSettin
 		return models.NewInvalidArgumentError("address %q: port cannot be empty", port)
 	}
 
-	emailConfig := &config.EmailConfig{
+	emailConfig := newEmailConfig(settings, host, port, emailTo)
+	emailConfig.Headers = map[string]string{
+		"Subject": `Test alert.`,
+	}
+
+	tmpl, err := newTestTemplate()
+	if err != nil {
+		return err
+	}
+
+	alertmanagerEmail := email.New(emailConfig, tmpl, loggerFunc(e.l.Log))
+	if _, err := alertmanagerEmail.Notify(ctx, newTestAlert()); err != nil {
+		return models.NewInvalidArgumentError(err.Error())
+	}
+
+	return nil
+}
+
+// newEmailConfig returns Alertmanager email notifier configuration for given SMTP settings and recipient.
+func newEmailConfig(settings *models.EmailAlertingSettings, host, port, emailTo string) *config.EmailConfig {
+	return &config.EmailConfig{
 		NotifierConfig: config.NotifierConfig{},
 		To:             emailTo,
 		From:           settings.From,
@@ -75,24 +95,27 @@ func (e *Emailer) Send(ctx context.Context, settings *models.EmailAlertingSettin
 		AuthPassword: config.Secret(settings.Password),
 		AuthSecret:   config.Secret(settings.Secret),
 		AuthIdentity: settings.Identity,
-		Headers: map[string]string{
-			"Subject": `Test alert.`,
-		},
-		HTML:       emailTemplate,
-		RequireTLS: &settings.RequireTLS,
+		HTML:         formatEmailHTML(emailAlertTemplate),
+		RequireTLS:   &settings.RequireTLS,
 	}
+}
 
+// newTestTemplate returns Alertmanager notifications template used for test notifications.
+func newTestTemplate() (*template.Template, error) {
 	tmpl, err := template.FromGlobs()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	tmpl.ExternalURL, err = url.Parse("https://example.com")
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return tmpl, nil
+}
 
-	alertmanagerEmail := email.New(emailConfig, tmpl, loggerFunc(e.l.Log))
-	if _, err := alertmanagerEmail.Notify(ctx, &types.Alert{
+// newTestAlert returns synthetic alert used for test notifications.
+func newTestAlert() *types.Alert {
+	return &types.Alert{
 		Alert: model.Alert{
 			Labels: model.LabelSet{
 				model.AlertNameLabel: model.LabelValue(fmt.Sprintf("Test alert %s", time.Now().String())),
@@ -107,9 +130,5 @@ func (e *Emailer) Send(ctx context.Context, settings *models.EmailAlertingSettin
 			EndsAt:   time.Now().Add(time.Minute),
 		},
 		Timeout: true,
-	}); err != nil {
-		return models.NewInvalidArgumentError(err.Error())
 	}
-
-	return nil
 }
@@ -0,0 +1,265 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package alertmanager
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/go-openapi/strfmt"
+	"github.com/percona/pmm/api/alertmanager/amclient"
+	"github.com/percona/pmm/api/alertmanager/amclient/silence"
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"github.com/pkg/errors"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+const maintenanceWindowsSyncInterval = time.Minute
+
+// RunMaintenanceWindows creates Alertmanager silences for active maintenance windows until ctx is canceled.
+// Silences end together with the maintenance window occurrence, so Alertmanager expires them automatically.
+func (svc *Service) RunMaintenanceWindows(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceWindowsSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := svc.syncMaintenanceWindows(ctx); err != nil {
+			svc.l.Errorf("Failed to sync maintenance windows: %+v.", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-svc.maintenanceCh:
+		}
+	}
+}
+
+// requestMaintenanceWindowsSync requests silences update after maintenance windows change.
+func (svc *Service) requestMaintenanceWindowsSync() {
+	select {
+	case svc.maintenanceCh <- struct{}{}:
+	default:
+	}
+}
+
+// CreateMaintenanceWindow creates maintenance window, silence is created when it becomes active.
+func (svc *Service) CreateMaintenanceWindow(params models.CreateMaintenanceWindowParams) (*models.MaintenanceWindow, error) {
+	window, err := models.CreateMaintenanceWindow(svc.db.Querier, params)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.requestMaintenanceWindowsSync()
+	return window, nil
+}
+
+// ChangeMaintenanceWindow changes maintenance window. Silence of the active occurrence is replaced.
+func (svc *Service) ChangeMaintenanceWindow(ctx context.Context, id string, params models.ChangeMaintenanceWindowParams) (*models.MaintenanceWindow, error) {
+	svc.maintenanceMx.Lock()
+	defer svc.maintenanceMx.Unlock()
+
+	window, err := models.ChangeMaintenanceWindow(svc.db.Querier, id, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Silence is expired after the change is stored, so Alertmanager isn't called inside the transaction.
+	// If it fails, silence of the changed window is kept and will be expired on the next change or sync.
+	if err = svc.expireMaintenanceWindowSilence(ctx, window); err != nil {
+		return nil, err
+	}
+
+	svc.requestMaintenanceWindowsSync()
+	return window, nil
+}
+
+// RemoveMaintenanceWindow removes maintenance window and expires its silence.
+func (svc *Service) RemoveMaintenanceWindow(ctx context.Context, id string) error {
+	svc.maintenanceMx.Lock()
+	defer svc.maintenanceMx.Unlock()
+
+	window, err := models.FindMaintenanceWindowByID(svc.db.Querier, id)
+	if err != nil {
+		return err
+	}
+
+	// Window is removed only after its silence is expired, so the silence isn't left without the window.
+	if err = svc.expireMaintenanceWindowSilence(ctx, window); err != nil {
+		return err
+	}
+
+	return models.RemoveMaintenanceWindow(svc.db.Querier, id)
+}
+
+// syncMaintenanceWindows creates silences for active maintenance windows and expires silences of inactive ones.
+// Failure of one window doesn't prevent syncing of others.
+func (svc *Service) syncMaintenanceWindows(ctx context.Context) error {
+	svc.maintenanceMx.Lock()
+	defer svc.maintenanceMx.Unlock()
+
+	windows, err := models.FindMaintenanceWindows(svc.db.Querier)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []string
+	for _, window := range windows {
+		if err = svc.syncMaintenanceWindow(ctx, window, now); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Errorf("failed to sync %d of %d maintenance windows: %s", len(errs), len(windows), strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// syncMaintenanceWindow creates silence for the active occurrence of the maintenance window or expires it.
+func (svc *Service) syncMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow, now time.Time) error {
+	start, end, active, err := window.ActivePeriod(now)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check maintenance window %s", window.ID)
+	}
+
+	switch {
+	case active && window.SilencedUntil != nil && window.SilencedUntil.Equal(end):
+		// occurrence is already silenced
+		return nil
+	case active:
+		// expire silence of the previous occurrence if it overlaps with this one
+		if err = svc.expireMaintenanceWindowSilence(ctx, window); err != nil {
+			return err
+		}
+
+		silenceID, err := svc.postMaintenanceWindowSilence(ctx, window, start, end)
+		if err != nil {
+			return err
+		}
+
+		if err = models.SetMaintenanceWindowSilence(svc.db.Querier, window.ID, silenceID, &end); err != nil {
+			return err
+		}
+		svc.l.Infof("Maintenance window %q is active until %s.", window.Name, end)
+		return nil
+	default:
+		return svc.expireMaintenanceWindowSilence(ctx, window)
+	}
+}
+
+// expireMaintenanceWindowSilence removes the silence of the maintenance window if it wasn't expired by Alertmanager yet.
+// Silence expired or deleted by hand is treated as removed.
+func (svc *Service) expireMaintenanceWindowSilence(ctx context.Context, window *models.MaintenanceWindow) error {
+	if window.SilenceID == "" {
+		return nil
+	}
+
+	if window.SilencedUntil != nil && window.SilencedUntil.After(time.Now()) {
+		_, err := amclient.Default.Silence.DeleteSilence(&silence.DeleteSilenceParams{
+			SilenceID: strfmt.UUID(window.SilenceID),
+			Context:   ctx,
+		})
+		if err != nil && !isSilenceGone(ctx, window.SilenceID) {
+			return errors.Wrapf(err, "failed to delete silence with id %s for maintenance window %s", window.SilenceID, window.ID)
+		}
+	}
+
+	window.SilenceID = ""
+	window.SilencedUntil = nil
+	return models.SetMaintenanceWindowSilence(svc.db.Querier, window.ID, "", nil)
+}
+
+// isSilenceGone returns true if Alertmanager doesn't have the silence or it's already expired.
+func isSilenceGone(ctx context.Context, silenceID string) bool {
+	resp, err := amclient.Default.Silence.GetSilence(&silence.GetSilenceParams{
+		SilenceID: strfmt.UUID(silenceID),
+		Context:   ctx,
+	})
+	if err != nil {
+		var notFound *silence.GetSilenceNotFound
+		return errors.As(err, &notFound)
+	}
+
+	return resp.Payload.Status != nil && pointer.GetString(resp.Payload.Status.State) == ammodels.SilenceStatusStateExpired
+}
+
+func (svc *Service) postMaintenanceWindowSilence(ctx context.Context, window *models.MaintenanceWindow, start, end time.Time) (string, error) {
+	starts := strfmt.DateTime(start)
+	ends := strfmt.DateTime(end)
+	resp, err := amclient.Default.Silence.PostSilences(&silence.PostSilencesParams{
+		Silence: &ammodels.PostableSilence{
+			Silence: ammodels.Silence{
+				Comment:   pointer.ToString("Maintenance window: " + window.Name),
+				CreatedBy: pointer.ToString("PMM"),
+				StartsAt:  &starts,
+				EndsAt:    &ends,
+				Matchers:  maintenanceWindowMatchers(window.Scope),
+			},
+		},
+		Context: ctx,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create silence for maintenance window %s", window.ID)
+	}
+
+	return resp.Payload.SilenceID, nil
+}
+
+// maintenanceWindowMatchers returns silence matchers for alerts in the maintenance window scope.
+func maintenanceWindowMatchers(scope models.MaintenanceWindowScope) []*ammodels.Matcher {
+	var res []*ammodels.Matcher
+	for _, m := range []struct {
+		label  string
+		values []string
+	}{
+		{label: "service_id", values: scope.ServiceIDs},
+		{label: "node_id", values: scope.NodeIDs},
+		{label: "environment", values: scope.Environments},
+		{label: "cluster", values: scope.Clusters},
+	} {
+		switch len(m.values) {
+		case 0:
+			continue
+		case 1:
+			res = append(res, &ammodels.Matcher{
+				IsRegex: pointer.ToBool(false),
+				Name:    pointer.ToString(m.label),
+				Value:   pointer.ToString(m.values[0]),
+			})
+		default:
+			values := make([]string, len(m.values))
+			for i, v := range m.values {
+				values[i] = regexp.QuoteMeta(v)
+			}
+			res = append(res, &ammodels.Matcher{
+				IsRegex: pointer.ToBool(true),
+				Name:    pointer.ToString(m.label),
+				Value:   pointer.ToString(strings.Join(values, "|")),
+			})
+		}
+	}
+
+	return res
+}
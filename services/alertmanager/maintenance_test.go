@@ -0,0 +1,138 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/percona/pmm/api/alertmanager/amclient"
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+)
+
+func TestMaintenanceWindowMatchers(t *testing.T) {
+	t.Parallel()
+
+	matchers := maintenanceWindowMatchers(models.MaintenanceWindowScope{
+		NodeIDs:      []string{"/node_id/1"},
+		Environments: []string{"prod", "prod.eu"},
+	})
+
+	expected := []*ammodels.Matcher{
+		{IsRegex: pointer.ToBool(false), Name: pointer.ToString("node_id"), Value: pointer.ToString("/node_id/1")},
+		{IsRegex: pointer.ToBool(true), Name: pointer.ToString("environment"), Value: pointer.ToString(`prod|prod\.eu`)},
+	}
+	assert.Equal(t, expected, matchers)
+}
+
+func TestExpireMaintenanceWindowSilence(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	svc := New(db)
+	ctx := context.Background()
+
+	// silenceState is the state of silence reported by fake Alertmanager, empty if it doesn't have the silence.
+	var silenceState string
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id := strings.TrimPrefix(req.URL.Path, "/api/v2/silence/")
+		switch {
+		case silenceState == "":
+			rw.WriteHeader(http.StatusNotFound)
+		case req.Method == http.MethodDelete:
+			rw.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(rw).Encode("silence " + id + " already expired")
+		default:
+			_ = json.NewEncoder(rw).Encode(&ammodels.GettableSilence{
+				ID:     pointer.ToString(id),
+				Status: &ammodels.SilenceStatus{State: pointer.ToString(silenceState)},
+			})
+		}
+	}))
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	amclient.Default.SetTransport(httptransport.New(u.Host, "/api/v2", []string{"http"}))
+
+	t.Cleanup(func() {
+		amclient.Default.SetTransport(httptransport.New("127.0.0.1:9093", "/alertmanager/api/v2", []string{"http"}))
+		srv.Close()
+		require.NoError(t, sqlDB.Close())
+	})
+
+	createWindow := func(t *testing.T) *models.MaintenanceWindow {
+		t.Helper()
+
+		window, err := models.CreateMaintenanceWindow(db.Querier, models.CreateMaintenanceWindowParams{
+			Name:     "test-" + t.Name(),
+			StartsAt: time.Now().Add(-time.Minute),
+			Duration: time.Hour,
+		})
+		require.NoError(t, err)
+
+		silencedUntil := time.Now().Add(time.Hour)
+		require.NoError(t, models.SetMaintenanceWindowSilence(db.Querier, window.ID, "7d5e3b5c-1a4d-4c4e-9b59-3c2b4d5e6f70", &silencedUntil))
+		window, err = models.FindMaintenanceWindowByID(db.Querier, window.ID)
+		require.NoError(t, err)
+		return window
+	}
+
+	for _, state := range []string{"", ammodels.SilenceStatusStateExpired} {
+		state := state
+		name := "deleted"
+		if state != "" {
+			name = state
+		}
+
+		t.Run(name, func(t *testing.T) {
+			silenceState = state
+			window := createWindow(t)
+
+			require.NoError(t, svc.expireMaintenanceWindowSilence(ctx, window))
+
+			window, err := models.FindMaintenanceWindowByID(db.Querier, window.ID)
+			require.NoError(t, err)
+			assert.Empty(t, window.SilenceID)
+			assert.Nil(t, window.SilencedUntil)
+		})
+	}
+
+	t.Run("active", func(t *testing.T) {
+		silenceState = ammodels.SilenceStatusStateActive
+		window := createWindow(t)
+
+		err := svc.expireMaintenanceWindowSilence(ctx, window)
+		require.Error(t, err)
+
+		window, err = models.FindMaintenanceWindowByID(db.Querier, window.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, window.SilenceID)
+	})
+}
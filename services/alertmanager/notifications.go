@@ -0,0 +1,389 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/email"
+	"github.com/prometheus/alertmanager/notify/opsgenie"
+	"github.com/prometheus/alertmanager/notify/pagerduty"
+	"github.com/prometheus/alertmanager/notify/slack"
+	"github.com/prometheus/alertmanager/notify/telegram"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// testNotificationReceiver is a receiver name passed to notifiers for test notifications.
+const testNotificationReceiver = "test"
+
+// SendTestNotification sends synthetic alert to given notification channel using the same templates
+// as generated Alertmanager configuration. Channel may be not persisted yet.
+// Returned error contains delivery error reported by the notification service.
+func (svc *Service) SendTestNotification(ctx context.Context, channel *models.Channel) error {
+	settings, err := models.GetSettings(svc.db)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := newTestTemplate()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ctx = notify.WithReceiverName(ctx, testNotificationReceiver)
+	ctx = notify.WithGroupKey(ctx, testNotificationReceiver+channel.ID)
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{})
+
+	var notifiers []notify.Notifier
+	switch channel.Type {
+	case models.Email:
+		emailSettings := settings.IntegratedAlerting.EmailAlertingSettings
+		if emailSettings == nil {
+			return status.Error(codes.FailedPrecondition, "Email alerting settings are not set.")
+		}
+
+		host, port, err := net.SplitHostPort(emailSettings.Smarthost)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "Invalid smarthost: %s.", err)
+		}
+
+		for _, to := range channel.EmailConfig.To {
+			emailConfig := newEmailConfig(emailSettings, host, port, to)
+			emailConfig.HTML = formatEmailHTML(formatAlertText(emailAlertTemplate, channel.NotificationTemplate, nil))
+			emailConfig.Headers = map[string]string{
+				"Subject": formatTitle(defaultTitle, channel.NotificationTemplate, nil),
+			}
+			notifiers = append(notifiers, email.New(emailConfig, tmpl, loggerFunc(svc.l.Log)))
+		}
+
+	case models.PagerDuty:
+		pdConfig := config.DefaultPagerdutyConfig
+		pdConfig.HTTPConfig = newDefaultHTTPConfig()
+		pdConfig.URL = config.DefaultGlobalConfig().PagerdutyURL
+		pdConfig.RoutingKey = config.Secret(channel.PagerDutyConfig.RoutingKey)
+		pdConfig.ServiceKey = config.Secret(channel.PagerDutyConfig.ServiceKey)
+		pdConfig.Description = formatTitle(defaultSummary, channel.NotificationTemplate, nil)
+		pdConfig.Details = map[string]string{
+			"firing": formatAlertsText(formatAlertText(pagerDutyAlertText(notificationLabels...), channel.NotificationTemplate, nil)),
+		}
+
+		n, err := pagerduty.New(&pdConfig, tmpl, loggerFunc(svc.l.Log))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		notifiers = append(notifiers, n)
+
+	case models.Slack:
+		slackSettings := settings.IntegratedAlerting.SlackAlertingSettings
+		if slackSettings == nil {
+			return status.Error(codes.FailedPrecondition, "Slack alerting settings are not set.")
+		}
+
+		apiURL, err := url.Parse(slackSettings.URL)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "Invalid Slack URL: %s.", err)
+		}
+
+		slackConfig := config.DefaultSlackConfig
+		slackConfig.HTTPConfig = newDefaultHTTPConfig()
+		slackConfig.APIURL = &config.SecretURL{URL: apiURL}
+		slackConfig.Channel = channel.SlackConfig.Channel
+		slackConfig.Title = formatTitle(defaultTitle, channel.NotificationTemplate, nil)
+		slackConfig.Text = formatAlertsText(formatAlertText(slackAlertText(notificationLabels...), channel.NotificationTemplate, nil))
+
+		n, err := slack.New(&slackConfig, tmpl, loggerFunc(svc.l.Log))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		notifiers = append(notifiers, n)
+
+	case models.WebHook:
+		webhookURL, err := url.Parse(channel.WebHookConfig.URL)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid webhook URL: %s.", err)
+		}
+
+		httpConfig, cleanup, err := newTestHTTPConfig(channel.WebHookConfig.HTTPConfig)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		webhookConfig := config.DefaultWebhookConfig
+		webhookConfig.HTTPConfig = httpConfig
+		webhookConfig.URL = &config.URL{URL: webhookURL}
+		webhookConfig.MaxAlerts = uint64(channel.WebHookConfig.MaxAlerts)
+
+		n, err := webhook.New(&webhookConfig, tmpl, loggerFunc(svc.l.Log))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		notifiers = append(notifiers, n)
+
+	case models.OpsGenie:
+		ogConfig := config.DefaultOpsGenieConfig
+		ogConfig.HTTPConfig = newDefaultHTTPConfig()
+		ogConfig.APIURL = config.DefaultGlobalConfig().OpsGenieAPIURL
+		if channel.OpsGenieConfig.APIURL != "" {
+			apiURL, err := url.Parse(channel.OpsGenieConfig.APIURL)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "Invalid Opsgenie API URL: %s.", err)
+			}
+			ogConfig.APIURL = &config.URL{URL: apiURL}
+		}
+		ogConfig.APIKey = config.Secret(channel.OpsGenieConfig.APIKey)
+		ogConfig.Priority = channel.OpsGenieConfig.Priority
+		ogConfig.Tags = strings.Join(channel.OpsGenieConfig.Tags, ",")
+		ogConfig.Message = formatTitle(defaultSummary, channel.NotificationTemplate, nil)
+		ogConfig.Description = formatAlertsText(formatAlertText(opsGenieAlertText(notificationLabels...), channel.NotificationTemplate, nil))
+
+		n, err := opsgenie.New(&ogConfig, tmpl, loggerFunc(svc.l.Log))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		notifiers = append(notifiers, n)
+
+	case models.Telegram:
+		tgConfig := config.DefaultTelegramConfig
+		tgConfig.HTTPConfig = newDefaultHTTPConfig()
+		tgConfig.APIUrl = config.DefaultGlobalConfig().TelegramAPIUrl
+		tgConfig.BotToken = config.Secret(channel.TelegramConfig.BotToken)
+		tgConfig.ChatID = channel.TelegramConfig.ChatID
+		tgConfig.Message = "<b>" + formatTitle(defaultTitle, channel.NotificationTemplate, nil) + "</b>\n\n" +
+			formatAlertsText(formatAlertText(telegramAlertText(notificationLabels...), channel.NotificationTemplate, nil))
+		tgConfig.ParseMode = "HTML"
+
+		n, err := telegram.New(&tgConfig, tmpl, loggerFunc(svc.l.Log))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		notifiers = append(notifiers, n)
+
+	case models.MSTeams:
+		client, err := commoncfg.NewClientFromConfig(*newDefaultHTTPConfig(), "msteams")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		notifiers = append(notifiers, &msTeamsNotifier{
+			conf: &msTeamsConfig{
+				WebhookURL: channel.MSTeamsConfig.WebhookURL,
+				Title:      formatTitle(defaultTitle, channel.NotificationTemplate, nil),
+				Text:       formatAlertsText(formatAlertText(msTeamsAlertText(notificationLabels...), channel.NotificationTemplate, nil)),
+			},
+			tmpl:   tmpl,
+			client: client,
+			logger: loggerFunc(svc.l.Log),
+		})
+
+	default:
+		return status.Errorf(codes.Unimplemented, "Test notifications for %s channels are not supported.", channel.Type)
+	}
+
+	alert := newTestAlert()
+	for _, n := range notifiers {
+		if _, err := n.Notify(ctx, alert); err != nil {
+			return status.Errorf(codes.FailedPrecondition, "Failed to send test notification: %s.", err)
+		}
+	}
+
+	return nil
+}
+
+// msTeamsNotifier sends notifications to Microsoft Teams incoming webhook the same way Alertmanager does,
+// Alertmanager library used by pmm-managed doesn't have Microsoft Teams notifier.
+type msTeamsNotifier struct {
+	conf   *msTeamsConfig
+	tmpl   *template.Template
+	client *http.Client
+	logger loggerFunc
+}
+
+// msTeamsMessage is a message card accepted by Microsoft Teams incoming webhook.
+type msTeamsMessage struct {
+	Context    string `json:"@context"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Summary    string `json:"summary"`
+	Text       string `json:"text"`
+	ThemeColor string `json:"themeColor"`
+}
+
+// Notify implements notify.Notifier interface.
+func (n *msTeamsNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	data := notify.GetTemplateData(ctx, n.tmpl, alerts, n.logger)
+
+	var err error
+	tmplText := notify.TmplText(n.tmpl, data, &err)
+	title := tmplText(n.conf.Title)
+	text := tmplText(n.conf.Text)
+	if err != nil {
+		return false, err
+	}
+
+	color := "8C1A1A" // red
+	if types.Alerts(alerts...).Status() == model.AlertResolved {
+		color = "2DC72D" // green
+	}
+
+	var buf bytes.Buffer
+	if err = json.NewEncoder(&buf).Encode(&msTeamsMessage{
+		Context:    "http://schema.org/extensions",
+		Type:       "MessageCard",
+		Title:      title,
+		Summary:    title,
+		Text:       text,
+		ThemeColor: color,
+	}); err != nil {
+		return false, err
+	}
+
+	resp, err := notify.PostJSON(ctx, n.client, n.conf.WebhookURL, &buf)
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return (&notify.Retrier{}).Check(resp.StatusCode, resp.Body)
+}
+
+// RenderNotificationTemplate renders given notification template for the synthetic alert with
+// labels and annotations overridden by given ones. Title and text are rendered with defaults if empty.
+func (svc *Service) RenderNotificationTemplate(t *models.NotificationTemplate, labels, annotations map[string]string) (string, string, error) {
+	tmpl, err := newTestTemplate()
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	alert := newTestAlert()
+	for k, v := range labels {
+		alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for k, v := range annotations {
+		alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+	data := tmpl.Data(testNotificationReceiver, model.LabelSet{}, alert)
+
+	title, err := tmpl.ExecuteTextString(formatTitle(defaultTitle, t, nil), data)
+	if err != nil {
+		return "", "", status.Errorf(codes.InvalidArgument, "Failed to render notification title: %s.", err)
+	}
+
+	text, err := tmpl.ExecuteTextString(formatAlertsText(formatAlertText(pagerDutyAlertText(notificationLabels...), t, nil)), data)
+	if err != nil {
+		return "", "", status.Errorf(codes.InvalidArgument, "Failed to render notification text: %s.", err)
+	}
+
+	return strings.TrimSpace(title), strings.TrimSpace(text), nil
+}
+
+// newTestHTTPConfig converts HTTP config of the channel, TLS files contents are written to the temporary directory
+// removed by returned cleanup function.
+func newTestHTTPConfig(httpConfig *models.HTTPConfig) (*commoncfg.HTTPClientConfig, func(), error) {
+	res := newDefaultHTTPConfig()
+	cleanup := func() {}
+	if httpConfig == nil {
+		return res, cleanup, nil
+	}
+
+	res.BearerToken = commoncfg.Secret(httpConfig.BearerToken)
+	res.BearerTokenFile = httpConfig.BearerTokenFile
+	if httpConfig.ProxyURL != "" {
+		proxyURL, err := url.Parse(httpConfig.ProxyURL)
+		if err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "Invalid proxy URL: %s.", err)
+		}
+		res.ProxyURL = commoncfg.URL{URL: proxyURL}
+	}
+	if httpConfig.BasicAuth != nil {
+		res.BasicAuth = &commoncfg.BasicAuth{
+			Username:     httpConfig.BasicAuth.Username,
+			Password:     commoncfg.Secret(httpConfig.BasicAuth.Password),
+			PasswordFile: httpConfig.BasicAuth.PasswordFile,
+		}
+	}
+
+	tls := httpConfig.TLSConfig
+	if tls == nil {
+		return res, cleanup, nil
+	}
+
+	dir, err := os.MkdirTemp("", "pmm-managed-test-notification-")
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+
+	tlsFile := func(file, content, name string) (string, error) {
+		if content == "" {
+			return file, nil
+		}
+		file = filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			return "", errors.WithStack(err)
+		}
+		return file, nil
+	}
+
+	res.TLSConfig = commoncfg.TLSConfig{
+		ServerName:         tls.ServerName,
+		InsecureSkipVerify: tls.InsecureSkipVerify,
+	}
+	files := []struct {
+		path    *string
+		file    string
+		content string
+		name    string
+	}{
+		{&res.TLSConfig.CAFile, tls.CAFile, tls.CAFileContent, "ca"},
+		{&res.TLSConfig.CertFile, tls.CertFile, tls.CertFileContent, "crt"},
+		{&res.TLSConfig.KeyFile, tls.KeyFile, tls.KeyFileContent, "key"},
+	}
+	for _, f := range files {
+		if *f.path, err = tlsFile(f.file, f.content, f.name); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+
+	return res, cleanup, nil
+}
+
+// newDefaultHTTPConfig returns a copy of default HTTP client configuration.
+func newDefaultHTTPConfig() *commoncfg.HTTPClientConfig {
+	res := commoncfg.DefaultHTTPClientConfig
+	return &res
+}
@@ -0,0 +1,203 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestSendTestNotification(t *testing.T) {
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	defer func() {
+		require.NoError(t, sqlDB.Close())
+	}()
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	svc := New(db)
+	ctx := context.Background()
+
+	t.Run("webhook", func(t *testing.T) {
+		var received map[string]interface{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		}))
+		defer srv.Close()
+
+		channel, err := models.NewChannel(&models.CreateChannelParams{
+			Summary:       "test",
+			WebHookConfig: &models.WebHookConfig{URL: srv.URL},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.SendTestNotification(ctx, channel))
+		assert.Equal(t, testNotificationReceiver, received["receiver"])
+		assert.Equal(t, "firing", received["status"])
+	})
+
+	t.Run("webhook delivery error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		channel, err := models.NewChannel(&models.CreateChannelParams{
+			Summary:       "test",
+			WebHookConfig: &models.WebHookConfig{URL: srv.URL},
+		})
+		require.NoError(t, err)
+
+		err = svc.SendTestNotification(ctx, channel)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, `Failed to send test notification: .*400.*`, err)
+	})
+
+	t.Run("opsgenie", func(t *testing.T) {
+		var received map[string]interface{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "GenieKey key", req.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		}))
+		defer srv.Close()
+
+		channel, err := models.NewChannel(&models.CreateChannelParams{
+			Summary:        "test",
+			OpsGenieConfig: &models.OpsGenieConfig{APIKey: "key", APIURL: srv.URL + "/", Priority: "P2"},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.SendTestNotification(ctx, channel))
+		assert.Equal(t, "P2", received["priority"])
+	})
+
+	t.Run("msteams", func(t *testing.T) {
+		var received map[string]interface{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		}))
+		defer srv.Close()
+
+		channel, err := models.NewChannel(&models.CreateChannelParams{
+			Summary:       "test",
+			MSTeamsConfig: &models.MSTeamsConfig{WebhookURL: srv.URL},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.SendTestNotification(ctx, channel))
+		assert.Equal(t, "MessageCard", received["type"])
+		assert.Equal(t, "[FIRING:1]", received["title"])
+		assert.Contains(t, received["text"], "This is a test alert.")
+	})
+
+	t.Run("slack without settings", func(t *testing.T) {
+		channel, err := models.NewChannel(&models.CreateChannelParams{
+			Summary:     "test",
+			SlackConfig: &models.SlackConfig{Channel: "test"},
+		})
+		require.NoError(t, err)
+
+		err = svc.SendTestNotification(ctx, channel)
+		tests.AssertGRPCError(t, status.New(codes.FailedPrecondition, "Slack alerting settings are not set."), err)
+	})
+}
+
+func TestNewTestHTTPConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, cleanup, err := newTestHTTPConfig(nil)
+		require.NoError(t, err)
+		defer cleanup()
+		assert.True(t, cfg.FollowRedirects)
+	})
+
+	t.Run("tls file contents", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, cleanup, err := newTestHTTPConfig(&models.HTTPConfig{
+			BearerToken: "token",
+			TLSConfig: &models.TLSConfig{
+				CAFileContent: "ca content",
+				CertFile:      "/some/cert",
+				ServerName:    "example.com",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "token", string(cfg.BearerToken))
+		assert.Equal(t, "/some/cert", cfg.TLSConfig.CertFile)
+		assert.Empty(t, cfg.TLSConfig.KeyFile)
+		assert.Equal(t, "example.com", cfg.TLSConfig.ServerName)
+
+		b, err := os.ReadFile(cfg.TLSConfig.CAFile)
+		require.NoError(t, err)
+		assert.Equal(t, "ca content", string(b))
+
+		cleanup()
+		_, err = os.Stat(cfg.TLSConfig.CAFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestRenderNotificationTemplate(t *testing.T) {
+	t.Parallel()
+
+	svc := New(nil)
+
+	t.Run("custom", func(t *testing.T) {
+		t.Parallel()
+
+		title, text, err := svc.RenderNotificationTemplate(&models.NotificationTemplate{
+			Title: "{{ .Status | toUpper }}: {{ .CommonAnnotations.summary }}",
+			Text:  "Runbook: https://example.com/runbooks/{{ .Labels.service_name }}",
+		}, map[string]string{"service_name": "mysql1"}, map[string]string{"summary": "MySQL is down."})
+		require.NoError(t, err)
+		assert.Equal(t, "FIRING: MySQL is down.", title)
+		assert.Equal(t, "Runbook: https://example.com/runbooks/mysql1", text)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		title, text, err := svc.RenderNotificationTemplate(nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "[FIRING:1]", title)
+		assert.Equal(t, "Alert: [NOTICE] This is a test alert.\nDescription: Long description.\nDetails:\n  - severity: notice", text)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := svc.RenderNotificationTemplate(&models.NotificationTemplate{Text: "{{ .Foo }}"}, nil, nil)
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, `Failed to render notification text: .*`, err)
+	})
+}
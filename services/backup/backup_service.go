@@ -19,6 +19,7 @@ package backup
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/AlekSi/pointer"
@@ -43,26 +44,55 @@ var (
 	ErrIncompatibleXtrabackup = errors.New("incompatible xtrabackup")
 	// ErrIncompatibleTargetMySQL is returned if target version of MySQL is not compatible for restoring selected artifact.
 	ErrIncompatibleTargetMySQL = errors.New("incompatible version of target mysql")
+	// ErrIncompatibleArtifactMode is returned if artifact backup mode is incompatible with requested restore operation.
+	ErrIncompatibleArtifactMode = errors.New("incompatible artifact mode")
+	// ErrTimestampOutOfRange is returned if requested point-in-time is not covered by the artifact oplog.
+	ErrTimestampOutOfRange = errors.New("timestamp value out of range")
+	// ErrLocationQuotaExceeded is returned when backup can't be made as location quota is exceeded.
+	ErrLocationQuotaExceeded = errors.New("backup location quota exceeded")
+	// ErrBackupLimitReached is returned when backup can't be started as too many backups are running.
+	ErrBackupLimitReached = errors.New("concurrent backups limit reached")
 )
 
 // Service represents core logic for db backup.
 type Service struct {
-	db             *reform.DB
-	jobsService    jobsService
-	agentsRegistry agentsRegistry
-	v              versioner
+	db                   *reform.DB
+	jobsService          jobsService
+	agentsRegistry       agentsRegistry
+	v                    versioner
+	pitrTimerangeService pitrTimerangeService
+	replicationService   replicationService
+	removalService       removalService
+	s3                   s3
+
+	// admissionMx serializes checking of concurrency limits with creation of pending artifacts,
+	// so backups started at the same time can't exceed the limits together.
+	admissionMx sync.Mutex
 
 	l *logrus.Entry
 }
 
 // NewService creates new backups logic service.
-func NewService(db *reform.DB, jobsService jobsService, agentsRegistry agentsRegistry, v versioner) *Service {
+func NewService(
+	db *reform.DB,
+	jobsService jobsService,
+	agentsRegistry agentsRegistry,
+	v versioner,
+	pitrTimerangeService pitrTimerangeService,
+	replicationService replicationService,
+	removalService removalService,
+	s3 s3,
+) *Service {
 	return &Service{
-		l:              logrus.WithField("component", "management/backup/backup"),
-		db:             db,
-		jobsService:    jobsService,
-		agentsRegistry: agentsRegistry,
-		v:              v,
+		l:                    logrus.WithField("component", "management/backup/backup"),
+		db:                   db,
+		jobsService:          jobsService,
+		agentsRegistry:       agentsRegistry,
+		v:                    v,
+		pitrTimerangeService: pitrTimerangeService,
+		replicationService:   replicationService,
+		removalService:       removalService,
+		s3:                   s3,
 	}
 }
 
@@ -133,6 +163,7 @@ func (s *Service) PerformBackup(ctx context.Context, params PerformBackupParams)
 		name = name + "_" + time.Now().Format(time.RFC3339)
 	}
 
+	s.admissionMx.Lock()
 	errTX := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
 		var err error
 		svc, err = models.FindServiceByID(tx.Querier, params.ServiceID)
@@ -140,11 +171,22 @@ func (s *Service) PerformBackup(ctx context.Context, params PerformBackupParams)
 			return err
 		}
 
+		if params.Mode == models.Snapshot {
+			if err = checkConcurrencyLimits(tx.Querier, svc); err != nil {
+				return err
+			}
+		}
+
 		location, err = models.FindBackupLocationByID(tx.Querier, params.LocationID)
 		if err != nil {
 			return err
 		}
 
+		if location.QuotaExceeded() && location.QuotaAction == models.BlockQuotaAction {
+			return errors.Wrapf(ErrLocationQuotaExceeded, "location %q uses %d of %d bytes",
+				location.Name, location.UsageBytes, location.QuotaBytes)
+		}
+
 		var jobType models.JobType
 		switch svc.ServiceType {
 		case models.MySQLServiceType:
@@ -214,6 +256,7 @@ func (s *Service) PerformBackup(ctx context.Context, params PerformBackupParams)
 		}
 		return nil
 	})
+	s.admissionMx.Unlock()
 	if errTX != nil {
 		return "", errTX
 	}
@@ -265,41 +308,65 @@ func checkMongoBackupPreconditions(q *reform.Querier, service *models.Service, s
 }
 
 type prepareRestoreJobParams struct {
-	AgentID      string
-	ArtifactName string
-	DBVersion    string
-	Location     *models.BackupLocation
-	ServiceType  models.ServiceType
-	DBConfig     *models.DBConfig
+	AgentID       string
+	ArtifactName  string
+	DBVersion     string
+	DataModel     models.DataModel
+	Mode          models.BackupMode
+	Location      *models.BackupLocation
+	ServiceType   models.ServiceType
+	DBConfig      *models.DBConfig
+	PITRTimestamp time.Time
+}
+
+// RestoreBackupParams are params for restoring backup.
+type RestoreBackupParams struct {
+	ServiceID  string
+	ArtifactID string
+	// Non-zero PITRTimestamp restores MongoDB PITR artifact to the given point in time,
+	// otherwise the whole artifact is restored.
+	PITRTimestamp time.Time
 }
 
 // RestoreBackup starts restore backup job.
-func (s *Service) RestoreBackup(ctx context.Context, serviceID, artifactID string) (string, error) {
+func (s *Service) RestoreBackup(ctx context.Context, restoreParams RestoreBackupParams) (string, error) {
+	serviceID, artifactID, pitrTimestamp := restoreParams.ServiceID, restoreParams.ArtifactID, restoreParams.PITRTimestamp
 	dbVersion, err := s.checkSoftwareCompatibilityForService(ctx, serviceID)
 	if err != nil {
 		return "", err
 	}
 
-	var params *prepareRestoreJobParams
-	var jobID, restoreID string
-	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
-		var err error
-		params, err = s.prepareRestoreJob(tx.Querier, serviceID, artifactID)
-		if err != nil {
-			return err
+	// Restore is validated before the transaction as location files may be listed for that.
+	params, err := s.prepareRestoreJob(ctx, s.db.Querier, serviceID, artifactID, pitrTimestamp)
+	if err != nil {
+		return "", err
+	}
+
+	if !pitrTimestamp.IsZero() {
+		if err = s.checkPITRTimestamp(ctx, params); err != nil {
+			return "", err
 		}
+	}
 
-		if params.ServiceType == models.MySQLServiceType && params.DBVersion != "" {
-			if params.DBVersion != dbVersion {
-				return errors.Wrapf(ErrIncompatibleTargetMySQL, "artifact db version %q != db version %q",
-					params.DBVersion, dbVersion)
-			}
+	if params.ServiceType == models.MySQLServiceType && params.DBVersion != "" {
+		if params.DBVersion != dbVersion {
+			return "", errors.Wrapf(ErrIncompatibleTargetMySQL, "artifact db version %q != db version %q",
+				params.DBVersion, dbVersion)
+		}
+	}
+
+	var jobID, restoreID string
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		var restorePITRTimestamp *time.Time
+		if !pitrTimestamp.IsZero() {
+			restorePITRTimestamp = pointer.ToTime(pitrTimestamp.UTC())
 		}
 
 		restore, err := models.CreateRestoreHistoryItem(tx.Querier, models.CreateRestoreHistoryItemParams{
-			ArtifactID: artifactID,
-			ServiceID:  serviceID,
-			Status:     models.InProgressRestoreStatus,
+			ArtifactID:    artifactID,
+			ServiceID:     serviceID,
+			PITRTimestamp: restorePITRTimestamp,
+			Status:        models.InProgressRestoreStatus,
 		})
 		if err != nil {
 			return err
@@ -444,10 +511,32 @@ func (s *Service) FindArtifactCompatibleServices(
 	return compatibleServices, nil
 }
 
+// checkPITRTimestamp checks that the artifact is MongoDB PITR artifact and its oplog covers requested timestamp.
+func (s *Service) checkPITRTimestamp(ctx context.Context, params *prepareRestoreJobParams) error {
+	if params.ServiceType != models.MongoDBServiceType || params.Mode != models.PITR {
+		return errors.Wrapf(ErrIncompatibleArtifactMode,
+			"point-in-time recovery is available only for MongoDB artifacts in %s mode", models.PITR)
+	}
+
+	timelines, err := s.pitrTimerangeService.ListPITRTimeranges(ctx, params.ArtifactName, params.Location)
+	if err != nil {
+		return err
+	}
+
+	if !inPITRTimerange(params.PITRTimestamp, timelines) {
+		return errors.Wrapf(ErrTimestampOutOfRange, "timestamp %s is not covered by artifact %q oplog",
+			params.PITRTimestamp.UTC().Format(time.RFC3339), params.ArtifactName)
+	}
+
+	return nil
+}
+
 func (s *Service) prepareRestoreJob(
+	ctx context.Context,
 	q *reform.Querier,
 	serviceID string,
 	artifactID string,
+	pitrTimestamp time.Time,
 ) (*prepareRestoreJobParams, error) {
 	service, err := models.FindServiceByID(q, serviceID)
 	if err != nil {
@@ -462,7 +551,18 @@ func (s *Service) prepareRestoreJob(
 		return nil, errors.Errorf("artifact %q status is not successful, status: %q", artifactID, artifact.Status)
 	}
 
-	location, err := models.FindBackupLocationByID(q, artifact.LocationID)
+	// Artifact may be restored to a service other than the one it was made from, but of the same type only.
+	serviceType, err := vendorToServiceType(artifact.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	if serviceType != service.ServiceType {
+		return nil, errors.Wrapf(ErrIncompatibleService, "artifact %q of %s can't be restored to %s service %q",
+			artifactID, serviceType, service.ServiceType, serviceID)
+	}
+
+	// Replica is used if artifact files are lost in the primary location.
+	location, err := s.replicationService.FindRestoreLocation(ctx, q, artifact)
 	if err != nil {
 		return nil, err
 	}
@@ -481,12 +581,15 @@ func (s *Service) prepareRestoreJob(
 	}
 
 	return &prepareRestoreJobParams{
-		AgentID:      pmmAgents[0].AgentID,
-		ArtifactName: artifact.Name,
-		DBVersion:    artifact.DBVersion,
-		Location:     location,
-		ServiceType:  service.ServiceType,
-		DBConfig:     dbConfig,
+		AgentID:       pmmAgents[0].AgentID,
+		ArtifactName:  artifact.Name,
+		DBVersion:     artifact.DBVersion,
+		DataModel:     artifact.DataModel,
+		Mode:          artifact.Mode,
+		Location:      location,
+		ServiceType:   service.ServiceType,
+		DBConfig:      dbConfig,
+		PITRTimestamp: pitrTimestamp,
 	}, nil
 }
 
@@ -515,6 +618,7 @@ func (s *Service) startRestoreJob(jobID, serviceID string, params *prepareRestor
 			0,
 			params.ArtifactName,
 			params.DBConfig,
+			params.PITRTimestamp,
 			locationConfig); err != nil {
 			return err
 		}
@@ -71,7 +71,11 @@ func TestBackup(t *testing.T) {
 		mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockedAgentsRegistry := &mockAgentsRegistry{}
 	mockedVersioner := &mockVersioner{}
-	backupService := NewService(db, mockedJobsService, mockedAgentsRegistry, mockedVersioner)
+	mockedPitrTimerangeService := &mockPitrTimerangeService{}
+	mockedReplicationService := &mockReplicationService{}
+	removalService := NewRemovalService(db, &mockS3{})
+	backupService := NewService(db, mockedJobsService, mockedAgentsRegistry, mockedVersioner, mockedPitrTimerangeService,
+		mockedReplicationService, removalService, &mockS3{})
 
 	t.Cleanup(func() {
 		_ = sqlDB.Close()
@@ -180,7 +184,11 @@ func TestRestoreBackup(t *testing.T) {
 	mockedJobsService := &mockJobsService{}
 	mockedAgentsRegistry := &mockAgentsRegistry{}
 	mockedVersioner := &mockVersioner{}
-	backupService := NewService(db, mockedJobsService, mockedAgentsRegistry, mockedVersioner)
+	mockedPitrTimerangeService := &mockPitrTimerangeService{}
+	mockedReplicationService := &mockReplicationService{}
+	removalService := NewRemovalService(db, &mockS3{})
+	backupService := NewService(db, mockedJobsService, mockedAgentsRegistry, mockedVersioner, mockedPitrTimerangeService,
+		mockedReplicationService, removalService, &mockS3{})
 
 	t.Cleanup(func() {
 		_ = sqlDB.Close()
@@ -215,6 +223,8 @@ func TestRestoreBackup(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	mockedReplicationService.On("FindRestoreLocation", ctx, mock.Anything, mock.Anything).Return(locationRes, nil)
+
 	softwares := []agents.Software{
 		&agents.Mysqld{},
 		&agents.Xtrabackup{},
@@ -270,12 +280,42 @@ func TestRestoreBackup(t *testing.T) {
 	} {
 		t.Run(tc.testName, func(t *testing.T) {
 			mockedVersioner.On("GetVersions", *agent.PMMAgentID, softwares).Return(tc.versions, nil).Once()
-			restoreID, err := backupService.RestoreBackup(ctx, pointer.GetString(agent.ServiceID), artifact.ID)
+			restoreID, err := backupService.RestoreBackup(ctx, RestoreBackupParams{
+				ServiceID:  pointer.GetString(agent.ServiceID),
+				ArtifactID: artifact.ID,
+			})
 			assert.True(t, errors.Is(err, tc.expectedError), err)
 			assert.Empty(t, restoreID)
 		})
 	}
 
+	t.Run("incompatible artifact vendor", func(t *testing.T) {
+		mongoArtifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       "mongodb-artifact-name",
+			Vendor:     string(models.MongoDBServiceType),
+			LocationID: locationRes.ID,
+			ServiceID:  "mongodb-service-id",
+			DataModel:  models.LogicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     models.SuccessBackupStatus,
+		})
+		require.NoError(t, err)
+
+		versions := []agents.Version{
+			{Version: "8.0.26"},
+			{Version: "8.0.26"},
+			{Version: "8.0.26"},
+			{Version: "1.1"},
+		}
+		mockedVersioner.On("GetVersions", *agent.PMMAgentID, softwares).Return(versions, nil).Once()
+		restoreID, err := backupService.RestoreBackup(ctx, RestoreBackupParams{
+			ServiceID:  pointer.GetString(agent.ServiceID),
+			ArtifactID: mongoArtifact.ID,
+		})
+		assert.True(t, errors.Is(err, ErrIncompatibleService), err)
+		assert.Empty(t, restoreID)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		versions1 := []agents.Version{
 			{Version: "8.0.26"},
@@ -291,7 +331,10 @@ func TestRestoreBackup(t *testing.T) {
 		require.NotNil(t, updatedArtifact)
 
 		mockedVersioner.On("GetVersions", *agent.PMMAgentID, softwares).Return(versions1, nil).Once()
-		restoreID, err := backupService.RestoreBackup(ctx, pointer.GetString(agent.ServiceID), artifact.ID)
+		restoreID, err := backupService.RestoreBackup(ctx, RestoreBackupParams{
+			ServiceID:  pointer.GetString(agent.ServiceID),
+			ArtifactID: artifact.ID,
+		})
 		require.Errorf(t, err, "artifact %q status is not successful, status: \"pending\"", artifact.ID)
 		assert.Empty(t, restoreID)
 
@@ -334,7 +377,10 @@ func TestRestoreBackup(t *testing.T) {
 		mockedVersioner.On("GetVersions", *agent.PMMAgentID, softwares).Return(versions1, nil).Once()
 		mockedJobsService.On("StartMySQLRestoreBackupJob", mock.Anything, pointer.GetString(agent.PMMAgentID),
 			pointer.GetString(agent.ServiceID), mock.Anything, artifact.Name, mock.Anything).Return(nil).Once()
-		restoreID, err = backupService.RestoreBackup(ctx, pointer.GetString(agent.ServiceID), artifact.ID)
+		restoreID, err = backupService.RestoreBackup(ctx, RestoreBackupParams{
+			ServiceID:  pointer.GetString(agent.ServiceID),
+			ArtifactID: artifact.ID,
+		})
 		require.NoError(t, err)
 		assert.NotEmpty(t, restoreID)
 	})
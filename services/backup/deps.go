@@ -20,19 +20,28 @@ import (
 	"context"
 	"time"
 
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"gopkg.in/reform.v1"
+
 	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services/agents"
+	"github.com/percona/pmm-managed/services/minio"
 )
 
 //go:generate mockery -name=jobsService -case=snake -inpkg -testonly
 //go:generate mockery -name=s3 -case=snake -inpkg -testonly
 //go:generate mockery -name=agentsRegistry -case=snake -inpkg -testonly
 //go:generate mockery -name=versioner -case=snake -inpkg -testonly
+//go:generate mockery -name=pitrTimerangeService -case=snake -inpkg -testonly
+//go:generate mockery -name=replicationService -case=snake -inpkg -testonly
+//go:generate mockery -name=alertManager -case=snake -inpkg -testonly
 
 // jobsService is a subset of methods of agents.JobsService used by this package.
 // We use it instead of real type for testing and to avoid dependency cycle.
 type jobsService interface {
 	StopJob(jobID string) error
+	CancelJob(jobID, reason string) error
+	ResumeJob(jobID string) error
 	StartMySQLBackupJob(
 		jobID string,
 		pmmAgentID string,
@@ -64,21 +73,32 @@ type jobsService interface {
 		timeout time.Duration,
 		name string,
 		dbConfig *models.DBConfig,
+		pitrTimestamp time.Time,
 		locationConfig *models.BackupLocationConfig,
 	) error
 }
 
+// alertManager is a subset of methods of alertmanager.Service used by this package.
+// We use it instead of real type for testing and to avoid dependency cycle.
+type alertManager interface {
+	SendAlerts(ctx context.Context, alerts ammodels.PostableAlerts)
+}
+
 type s3 interface {
+	List(ctx context.Context, endpoint, accessKey, secretKey, bucketName, prefix, suffix string) ([]minio.FileInfo, error)
 	RemoveRecursive(ctx context.Context, endpoint, accessKey, secretKey, bucketName, prefix string) error
+	CopyRecursive(ctx context.Context, src, dst *models.S3LocationConfig, prefix string) error
 }
 
 type removalService interface {
 	DeleteArtifact(ctx context.Context, artifactID string, removeFiles bool) error
+	DeleteArtifactFiles(ctx context.Context, artifactID string) error
 }
 
 // agentsRegistry is a subset of methods of agents.Registry used by this package.
 // We use it instead of real type for testing and to avoid dependency cycle
 type agentsRegistry interface {
+	IsConnected(pmmAgentID string) bool
 	PBMSwitchPITR(pmmAgentID, dsn string, files map[string]string, tdp *models.DelimiterPair, enabled bool) error
 }
 
@@ -86,3 +106,13 @@ type agentsRegistry interface {
 type versioner interface {
 	GetVersions(pmmAgentID string, softwares []agents.Software) ([]agents.Version, error)
 }
+
+// pitrTimerangeService lists time ranges available for MongoDB point-in-time recovery.
+type pitrTimerangeService interface {
+	ListPITRTimeranges(ctx context.Context, artifactName string, location *models.BackupLocation) ([]Timeline, error)
+}
+
+// replicationService finds locations of artifact replicas.
+type replicationService interface {
+	FindRestoreLocation(ctx context.Context, q *reform.Querier, artifact *models.Artifact) (*models.BackupLocation, error)
+}
@@ -0,0 +1,330 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// Reasons of stopping jobs stored in the job error.
+const (
+	pausedJobReason    = "paused by user"
+	cancelledJobReason = "cancelled by user"
+)
+
+var (
+	backupJobTypes  = []models.JobType{models.MySQLBackupJob, models.MongoDBBackupJob}
+	restoreJobTypes = []models.JobType{models.MySQLRestoreBackupJob, models.MongoDBRestoreBackupJob}
+)
+
+// PauseBackup stops running backup job of the artifact and sets paused status.
+// Only snapshot backups can be paused, PITR backups are controlled by their schedules.
+func (s *Service) PauseBackup(ctx context.Context, artifactID string) error {
+	var job *models.Job
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		artifact, err := findControllableArtifact(tx.Querier, artifactID)
+		if err != nil {
+			return err
+		}
+
+		if artifact.Status != models.PendingBackupStatus && artifact.Status != models.InProgressBackupStatus {
+			return status.Errorf(codes.FailedPrecondition, "Backup of artifact with ID %q isn't running, status: %q.",
+				artifactID, artifact.Status)
+		}
+
+		if job, err = findJob(tx.Querier, models.JobsFilter{ArtifactID: artifactID, Types: backupJobTypes}); err != nil {
+			return err
+		}
+
+		_, err = models.UpdateArtifact(tx.Querier, artifactID, models.UpdateArtifactParams{
+			Status: models.BackupStatusPointer(models.PausedBackupStatus),
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Job is stopped when pmm-agent connects again if it can't be reached now, so the artifact stays paused.
+	return s.jobsService.CancelJob(job.ID, pausedJobReason)
+}
+
+// ResumeBackup starts paused backup again. Partial artifact files are removed as backup is made from scratch.
+func (s *Service) ResumeBackup(ctx context.Context, artifactID string) error {
+	var job *models.Job
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		artifact, err := findControllableArtifact(tx.Querier, artifactID)
+		if err != nil {
+			return err
+		}
+
+		if artifact.Status != models.PausedBackupStatus {
+			return status.Errorf(codes.FailedPrecondition, "Backup of artifact with ID %q isn't paused, status: %q.",
+				artifactID, artifact.Status)
+		}
+
+		job, err = findJob(tx.Querier, models.JobsFilter{ArtifactID: artifactID, Types: backupJobTypes})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := s.removalService.DeleteArtifactFiles(ctx, artifactID); err != nil {
+		return errors.Wrapf(err, "failed to remove partial files of artifact %q", artifactID)
+	}
+
+	if _, err := models.UpdateArtifact(s.db.Querier, artifactID, models.UpdateArtifactParams{
+		Status: models.BackupStatusPointer(models.PendingBackupStatus),
+	}); err != nil {
+		return err
+	}
+
+	if err := s.jobsService.ResumeJob(job.ID); err != nil {
+		if _, updateErr := models.UpdateArtifact(s.db.Querier, artifactID, models.UpdateArtifactParams{
+			Status: models.BackupStatusPointer(models.PausedBackupStatus),
+		}); updateErr != nil {
+			s.l.Errorf("failed to update artifact %q status: %s", artifactID, updateErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CancelBackup stops running or paused backup, sets error status of the artifact and removes its partial files.
+// Files of the backup running on pmm-agent are removed when pmm-agent reports the job result, so they aren't written after removal.
+func (s *Service) CancelBackup(ctx context.Context, artifactID string) error {
+	var job *models.Job
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		artifact, err := findControllableArtifact(tx.Querier, artifactID)
+		if err != nil {
+			return err
+		}
+
+		switch artifact.Status {
+		case models.PendingBackupStatus,
+			models.InProgressBackupStatus,
+			models.PausedBackupStatus:
+		default:
+			return status.Errorf(codes.FailedPrecondition, "Backup of artifact with ID %q can't be cancelled, status: %q.",
+				artifactID, artifact.Status)
+		}
+
+		if job, err = findJob(tx.Querier, models.JobsFilter{ArtifactID: artifactID, Types: backupJobTypes}); err != nil {
+			return err
+		}
+
+		_, err = models.UpdateArtifact(tx.Querier, artifactID, models.UpdateArtifactParams{
+			Status: models.BackupStatusPointer(models.ErrorBackupStatus),
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Paused job is already stopped, CancelJob does nothing for it.
+	if err := s.jobsService.CancelJob(job.ID, cancelledJobReason); err != nil {
+		return err
+	}
+
+	// pmm-agent doesn't report result of the job it doesn't run, so files are removed here.
+	if job.State != models.DispatchedJobState && job.State != models.RunningJobState {
+		return s.removalService.DeleteArtifactFiles(ctx, artifactID)
+	}
+
+	return nil
+}
+
+// PauseRestore stops running restore and sets paused status of the restore history item.
+// Partially restored database can't be continued, so the restore is started from scratch on resume.
+func (s *Service) PauseRestore(ctx context.Context, restoreID string) error {
+	var job *models.Job
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		item, err := findRestoreHistoryItem(tx.Querier, restoreID)
+		if err != nil {
+			return err
+		}
+
+		if item.Status != models.InProgressRestoreStatus {
+			return status.Errorf(codes.FailedPrecondition, "Restore with ID %q isn't running, status: %q.",
+				restoreID, item.Status)
+		}
+
+		if job, err = findJob(tx.Querier, models.JobsFilter{RestoreID: restoreID, Types: restoreJobTypes}); err != nil {
+			return err
+		}
+
+		_, err = models.ChangeRestoreHistoryItem(tx.Querier, restoreID, models.ChangeRestoreHistoryItemParams{
+			Status: models.PausedRestoreStatus,
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Job is stopped when pmm-agent connects again if it can't be reached now, so the restore stays paused.
+	return s.jobsService.CancelJob(job.ID, pausedJobReason)
+}
+
+// ResumeRestore starts paused restore again from scratch.
+// Restore location is checked again, so the artifact replica is used if the primary location became unavailable.
+func (s *Service) ResumeRestore(ctx context.Context, restoreID string) error {
+	item, err := findRestoreHistoryItem(s.db.Querier, restoreID)
+	if err != nil {
+		return err
+	}
+
+	if item.Status != models.PausedRestoreStatus {
+		return status.Errorf(codes.FailedPrecondition, "Restore with ID %q isn't paused, status: %q.",
+			restoreID, item.Status)
+	}
+
+	// Location files may be listed, so it's done before the transaction.
+	params, err := s.prepareRestoreJob(ctx, s.db.Querier, item.ServiceID, item.ArtifactID, pointer.GetTime(item.PITRTimestamp))
+	if err != nil {
+		return err
+	}
+
+	var job *models.Job
+	if err = s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		// Status is checked again as restore may be changed concurrently.
+		if item, err = findRestoreHistoryItem(tx.Querier, restoreID); err != nil {
+			return err
+		}
+
+		if item.Status != models.PausedRestoreStatus {
+			return status.Errorf(codes.FailedPrecondition, "Restore with ID %q isn't paused, status: %q.",
+				restoreID, item.Status)
+		}
+
+		if job, err = findJob(tx.Querier, models.JobsFilter{RestoreID: restoreID, Types: restoreJobTypes}); err != nil {
+			return err
+		}
+
+		job.SetState(models.QueuedJobState)
+		job.Error = ""
+		if err = tx.Update(job); err != nil {
+			return errors.WithStack(err)
+		}
+
+		_, err = models.ChangeRestoreHistoryItem(tx.Querier, restoreID, models.ChangeRestoreHistoryItemParams{
+			Status: models.InProgressRestoreStatus,
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err = s.startRestoreJob(job.ID, item.ServiceID, params); err != nil {
+		// Job isn't running, so keep restore paused to allow resuming it later.
+		if stopErr := s.jobsService.CancelJob(job.ID, pausedJobReason); stopErr != nil {
+			s.l.Errorf("failed to stop job %s: %s", job.ID, stopErr)
+		}
+		if _, updateErr := models.ChangeRestoreHistoryItem(s.db.Querier, restoreID, models.ChangeRestoreHistoryItemParams{
+			Status: models.PausedRestoreStatus,
+		}); updateErr != nil {
+			s.l.Errorf("failed to update restore %q status: %s", restoreID, updateErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CancelRestore stops running or paused restore and sets error status of the restore history item.
+func (s *Service) CancelRestore(ctx context.Context, restoreID string) error {
+	var job *models.Job
+	if err := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		item, err := findRestoreHistoryItem(tx.Querier, restoreID)
+		if err != nil {
+			return err
+		}
+
+		if item.Status != models.InProgressRestoreStatus && item.Status != models.PausedRestoreStatus {
+			return status.Errorf(codes.FailedPrecondition, "Restore with ID %q can't be cancelled, status: %q.",
+				restoreID, item.Status)
+		}
+
+		if job, err = findJob(tx.Querier, models.JobsFilter{RestoreID: restoreID, Types: restoreJobTypes}); err != nil {
+			return err
+		}
+
+		_, err = models.ChangeRestoreHistoryItem(tx.Querier, restoreID, models.ChangeRestoreHistoryItemParams{
+			Status: models.ErrorRestoreStatus,
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Paused job is already stopped, CancelJob does nothing for it.
+	return s.jobsService.CancelJob(job.ID, cancelledJobReason)
+}
+
+// findRestoreHistoryItem returns restore history item which can be paused, resumed or cancelled.
+func findRestoreHistoryItem(q *reform.Querier, restoreID string) (*models.RestoreHistoryItem, error) {
+	item, err := models.FindRestoreHistoryItemByID(q, restoreID)
+	switch {
+	case err == nil:
+		return item, nil
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Restore with ID %q not found.", restoreID)
+	default:
+		return nil, err
+	}
+}
+
+// findControllableArtifact returns artifact which backup can be paused, resumed or cancelled.
+func findControllableArtifact(q *reform.Querier, artifactID string) (*models.Artifact, error) {
+	artifact, err := models.FindArtifactByID(q, artifactID)
+	switch {
+	case err == nil:
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Artifact with ID %q not found.", artifactID)
+	default:
+		return nil, err
+	}
+
+	if artifact.Mode != models.Snapshot {
+		return nil, status.Errorf(codes.FailedPrecondition, "Backup of artifact with ID %q in %s mode "+
+			"is controlled by its schedule.", artifactID, artifact.Mode)
+	}
+
+	return artifact, nil
+}
+
+// findJob returns the latest job matching the filters.
+func findJob(q *reform.Querier, filters models.JobsFilter) (*models.Job, error) {
+	jobs, err := models.FindJobs(q, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// jobs are sorted by creation time in descending order
+	if len(jobs) == 0 {
+		return nil, errors.Wrap(models.ErrNotFound, "job")
+	}
+
+	return jobs[0], nil
+}
@@ -0,0 +1,187 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestBackupJobControl(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	mockedJobsService := &mockJobsService{}
+	mockedS3 := &mockS3{}
+	backupService := NewService(db, mockedJobsService, &mockAgentsRegistry{}, &mockVersioner{}, &mockPitrTimerangeService{},
+		&mockReplicationService{}, NewRemovalService(db, mockedS3), mockedS3)
+
+	agent := setup(t, db.Querier, "test-service")
+	endpoint := "https://s3.us-west-2.amazonaws.com/"
+	accessKey, secretKey, bucketName := "access_key", "secret_key", "example_bucket"
+
+	location, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+		Name: "Test location",
+		BackupLocationConfig: models.BackupLocationConfig{
+			S3Config: &models.S3LocationConfig{
+				Endpoint:     endpoint,
+				AccessKey:    accessKey,
+				SecretKey:    secretKey,
+				BucketName:   bucketName,
+				BucketRegion: "us-east-2",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	createBackup := func(t *testing.T, name string) (*models.Artifact, *models.Job) {
+		t.Helper()
+
+		artifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       name,
+			Vendor:     "MySQL",
+			LocationID: location.ID,
+			ServiceID:  *agent.ServiceID,
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     models.PendingBackupStatus,
+		})
+		require.NoError(t, err)
+
+		job, err := models.CreateJob(db.Querier, models.CreateJobParams{
+			PMMAgentID: *agent.PMMAgentID,
+			Type:       models.MySQLBackupJob,
+			Data: &models.JobData{
+				MySQLBackup: &models.MySQLBackupJobData{
+					ServiceID:  *agent.ServiceID,
+					ArtifactID: artifact.ID,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		return artifact, job
+	}
+
+	assertStatus := func(t *testing.T, artifactID string, expected models.BackupStatus) {
+		t.Helper()
+
+		artifact, err := models.FindArtifactByID(db.Querier, artifactID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, artifact.Status)
+	}
+
+	t.Run("pause and resume", func(t *testing.T) {
+		artifact, job := createBackup(t, "pause-resume")
+
+		err := backupService.ResumeBackup(ctx, artifact.ID)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, "isn't paused", err)
+
+		mockedJobsService.On("CancelJob", job.ID, pausedJobReason).Return(nil).Once()
+		require.NoError(t, backupService.PauseBackup(ctx, artifact.ID))
+		assertStatus(t, artifact.ID, models.PausedBackupStatus)
+
+		err = backupService.PauseBackup(ctx, artifact.ID)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, "isn't running", err)
+
+		mockedS3.On("RemoveRecursive", mock.Anything, endpoint, accessKey, secretKey, bucketName, artifact.Name+"/").
+			Return(nil).Once()
+		mockedJobsService.On("ResumeJob", job.ID).Return(nil).Once()
+		require.NoError(t, backupService.ResumeBackup(ctx, artifact.ID))
+		assertStatus(t, artifact.ID, models.PendingBackupStatus)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		artifact, job := createBackup(t, "cancel")
+
+		mockedJobsService.On("CancelJob", job.ID, cancelledJobReason).Return(nil).Once()
+		mockedS3.On("RemoveRecursive", mock.Anything, endpoint, accessKey, secretKey, bucketName, artifact.Name+"/").
+			Return(nil).Once()
+		require.NoError(t, backupService.CancelBackup(ctx, artifact.ID))
+		assertStatus(t, artifact.ID, models.ErrorBackupStatus)
+
+		err := backupService.CancelBackup(ctx, artifact.ID)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, "can't be cancelled", err)
+	})
+
+	t.Run("cancel running", func(t *testing.T) {
+		artifact, job := createBackup(t, "cancel-running")
+		require.NoError(t, models.UpdateJobState(db.Querier, job.ID, models.RunningJobState))
+
+		// files are removed when pmm-agent reports result of the stopped job
+		mockedJobsService.On("CancelJob", job.ID, cancelledJobReason).Return(nil).Once()
+		require.NoError(t, backupService.CancelBackup(ctx, artifact.ID))
+		assertStatus(t, artifact.ID, models.ErrorBackupStatus)
+	})
+
+	t.Run("pause and cancel restore", func(t *testing.T) {
+		artifact, _ := createBackup(t, "restore")
+		restore, err := models.CreateRestoreHistoryItem(db.Querier, models.CreateRestoreHistoryItemParams{
+			ArtifactID: artifact.ID,
+			ServiceID:  *agent.ServiceID,
+			Status:     models.InProgressRestoreStatus,
+		})
+		require.NoError(t, err)
+
+		job, err := models.CreateJob(db.Querier, models.CreateJobParams{
+			PMMAgentID: *agent.PMMAgentID,
+			Type:       models.MySQLRestoreBackupJob,
+			Data: &models.JobData{
+				MySQLRestoreBackup: &models.MySQLRestoreBackupJobData{
+					ServiceID: *agent.ServiceID,
+					RestoreID: restore.ID,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		err = backupService.ResumeRestore(ctx, restore.ID)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, "isn't paused", err)
+
+		mockedJobsService.On("CancelJob", job.ID, pausedJobReason).Return(nil).Once()
+		require.NoError(t, backupService.PauseRestore(ctx, restore.ID))
+
+		item, err := models.FindRestoreHistoryItemByID(db.Querier, restore.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PausedRestoreStatus, item.Status)
+
+		mockedJobsService.On("CancelJob", job.ID, cancelledJobReason).Return(nil).Once()
+		require.NoError(t, backupService.CancelRestore(ctx, restore.ID))
+
+		item, err = models.FindRestoreHistoryItemByID(db.Querier, restore.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ErrorRestoreStatus, item.Status)
+	})
+
+	mockedJobsService.AssertExpectations(t)
+	mockedS3.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// checkConcurrencyLimits returns ErrBackupLimitReached if snapshot backup of the service can't be started
+// because of concurrency limits from settings.
+func checkConcurrencyLimits(q *reform.Querier, service *models.Service) error {
+	settings, err := models.GetSettings(q)
+	if err != nil {
+		return err
+	}
+
+	perNode := settings.BackupManagement.MaxConcurrentBackupsPerNode
+	perReplicationSet := settings.BackupManagement.MaxConcurrentBackupsPerReplicationSet
+	if perNode == 0 && perReplicationSet == 0 {
+		return nil
+	}
+
+	running, err := findRunningBackupServices(q)
+	if err != nil {
+		return err
+	}
+
+	if reason := checkBackupLimits(service, running, perNode, perReplicationSet); reason != "" {
+		return errors.Wrap(ErrBackupLimitReached, reason)
+	}
+
+	return nil
+}
+
+// findRunningBackupServices returns services of running backups, one entry per backup.
+func findRunningBackupServices(q *reform.Querier) ([]*models.Service, error) {
+	var serviceIDs []string
+	for _, status := range []models.BackupStatus{models.PendingBackupStatus, models.InProgressBackupStatus} {
+		artifacts, err := models.FindArtifacts(q, models.ArtifactFilters{Status: status})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range artifacts {
+			// PITR artifacts stay in progress while oplog is streamed, they don't load the node as snapshots do.
+			if a.Mode == models.Snapshot {
+				serviceIDs = append(serviceIDs, a.ServiceID)
+			}
+		}
+	}
+
+	services, err := models.FindServicesByIDs(q, serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.Service, 0, len(serviceIDs))
+	for _, id := range serviceIDs {
+		if svc, ok := services[id]; ok {
+			res = append(res, svc)
+		}
+	}
+
+	return res, nil
+}
+
+// checkBackupLimits returns the reason why backup of the service can't be started while backups of
+// running services are in progress, empty string if it can. Zero limit means no limit.
+func checkBackupLimits(service *models.Service, running []*models.Service, perNode, perReplicationSet uint32) string {
+	var onNode, inReplicationSet uint32
+	for _, svc := range running {
+		if svc.NodeID == service.NodeID {
+			onNode++
+		}
+
+		if service.ReplicationSet != "" && svc.Cluster == service.Cluster && svc.ReplicationSet == service.ReplicationSet {
+			inReplicationSet++
+		}
+	}
+
+	if perNode != 0 && onNode >= perNode {
+		return fmt.Sprintf("%d backup(s) running on node %s, limit is %d", onNode, service.NodeID, perNode)
+	}
+
+	if perReplicationSet != 0 && inReplicationSet >= perReplicationSet {
+		return fmt.Sprintf("%d backup(s) running in replication set %s, limit is %d",
+			inReplicationSet, service.ReplicationSet, perReplicationSet)
+	}
+
+	return ""
+}
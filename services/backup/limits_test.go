@@ -0,0 +1,76 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestCheckBackupLimits(t *testing.T) {
+	t.Parallel()
+
+	service := &models.Service{ServiceID: "s1", NodeID: "n1", Cluster: "c1", ReplicationSet: "rs0"}
+	running := []*models.Service{
+		{ServiceID: "s2", NodeID: "n1"},
+		{ServiceID: "s3", NodeID: "n2", Cluster: "c1", ReplicationSet: "rs0"},
+		{ServiceID: "s4", NodeID: "n3", Cluster: "c2", ReplicationSet: "rs0"},
+	}
+
+	for _, tc := range []struct {
+		name              string
+		perNode           uint32
+		perReplicationSet uint32
+		expected          string
+	}{
+		{
+			name:     "no limits",
+			expected: "",
+		},
+		{
+			name:     "node limit reached",
+			perNode:  1,
+			expected: "1 backup(s) running on node n1, limit is 1",
+		},
+		{
+			name:     "node limit not reached",
+			perNode:  2,
+			expected: "",
+		},
+		{
+			name:              "replication set limit reached",
+			perReplicationSet: 1,
+			expected:          "1 backup(s) running in replication set rs0, limit is 1",
+		},
+		{
+			name:              "replication set limit not reached",
+			perReplicationSet: 2,
+			expected:          "",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := checkBackupLimits(service, running, tc.perNode, tc.perReplicationSet)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+const (
+	prometheusNamespace = "pmm_managed"
+	prometheusSubsystem = "backup"
+)
+
+// Labels of the latest successful artifact metrics. Artifact ID and pmm-agent ID are not included
+// so that series stay the same between backups and could be used for alerting, see mInfoDesc.
+var artifactLabels = []string{"service_id", "location_id", "vendor", "data_model", "mode"}
+
+var (
+	mSizeDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "artifact_size_bytes"),
+		"Size of the latest successful backup artifact.",
+		artifactLabels,
+		nil)
+	mDurationDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "artifact_duration_seconds"),
+		"Time spent on making the latest successful backup artifact.",
+		artifactLabels,
+		nil)
+	mFinishedDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "artifact_finished_timestamp_seconds"),
+		"Time when the latest successful backup artifact was finished.",
+		artifactLabels,
+		nil)
+	mInfoDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "artifact_info"),
+		"Information about the latest successful backup artifact.",
+		append([]string{"artifact_id", "name", "pmm_agent_id"}, artifactLabels...),
+		nil)
+)
+
+// MetricsService records backup artifacts statistics and exposes them as Prometheus metrics.
+type MetricsService struct {
+	db *reform.DB
+	s3 s3
+	l  *logrus.Entry
+}
+
+// NewMetricsService creates new backup metrics service.
+func NewMetricsService(db *reform.DB, s3 s3) *MetricsService {
+	return &MetricsService{
+		db: db,
+		s3: s3,
+		l:  logrus.WithField("component", "management/backup/metrics"),
+	}
+}
+
+// UpdateArtifactSize calculates size of artifact files in the backup location and stores it in the artifact.
+// Only files in S3 locations can be listed by pmm-managed, size of other artifacts stays zero.
+func (s *MetricsService) UpdateArtifactSize(ctx context.Context, artifactID string) error {
+	artifact, err := models.FindArtifactByID(s.db.Querier, artifactID)
+	if err != nil {
+		return err
+	}
+
+	location, err := models.FindBackupLocationByID(s.db.Querier, artifact.LocationID)
+	if err != nil {
+		return err
+	}
+
+	s3Config := location.S3Config
+	if s3Config == nil {
+		s.l.Debugf("Size of artifact %q can't be calculated for location of type %q.", artifactID, location.Type)
+		return nil
+	}
+
+	// Slash is appended to avoid matching artifacts which names start with the same prefix.
+	files, err := s.s3.List(ctx, s3Config.Endpoint, s3Config.AccessKey, s3Config.SecretKey, s3Config.BucketName, artifact.Name+"/", "")
+	if err != nil {
+		return errors.Wrapf(err, "failed to list files of artifact %q", artifactID)
+	}
+	var size int64
+	for _, f := range files {
+		size += f.Size
+	}
+
+	_, err = models.UpdateArtifact(s.db.Querier, artifactID, models.UpdateArtifactParams{
+		Size: pointer.ToInt64(size),
+	})
+	return err
+}
+
+// artifactKey identifies series of artifacts which metrics are comparable between backups.
+type artifactKey struct {
+	serviceID  string
+	locationID string
+	dataModel  models.DataModel
+	mode       models.BackupMode
+}
+
+// latestArtifacts returns the latest finished artifact of each series.
+// Artifacts are expected to be sorted by creation time in descending order.
+func latestArtifacts(artifacts []*models.Artifact) []*models.Artifact {
+	seen := make(map[artifactKey]struct{}, len(artifacts))
+	res := make([]*models.Artifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		if a.FinishedAt == nil {
+			continue
+		}
+
+		key := artifactKey{serviceID: a.ServiceID, locationID: a.LocationID, dataModel: a.DataModel, mode: a.Mode}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		res = append(res, a)
+	}
+
+	return res
+}
+
+// Describe implements prom.Collector.
+func (s *MetricsService) Describe(ch chan<- *prom.Desc) {
+	ch <- mSizeDesc
+	ch <- mDurationDesc
+	ch <- mFinishedDesc
+	ch <- mInfoDesc
+}
+
+// Collect implements prom.Collector.
+func (s *MetricsService) Collect(ch chan<- prom.Metric) {
+	artifacts, err := models.FindArtifacts(s.db.Querier, models.ArtifactFilters{Status: models.SuccessBackupStatus})
+	if err != nil {
+		s.l.Errorf("Failed to find artifacts: %s.", err)
+		return
+	}
+
+	for _, a := range latestArtifacts(artifacts) {
+		labels := []string{a.ServiceID, a.LocationID, a.Vendor, string(a.DataModel), string(a.Mode)}
+
+		// Only measured values are exported, size and start time are unknown for some artifacts.
+		if a.Size != 0 {
+			ch <- prom.MustNewConstMetric(mSizeDesc, prom.GaugeValue, float64(a.Size), labels...)
+		}
+		if a.StartedAt != nil {
+			ch <- prom.MustNewConstMetric(mDurationDesc, prom.GaugeValue, a.Duration().Seconds(), labels...)
+		}
+		ch <- prom.MustNewConstMetric(mFinishedDesc, prom.GaugeValue, float64(a.FinishedAt.Unix()), labels...)
+		ch <- prom.MustNewConstMetric(mInfoDesc, prom.GaugeValue, 1, append([]string{a.ID, a.Name, a.PMMAgentID}, labels...)...)
+	}
+}
+
+// check interfaces.
+var (
+	_ prom.Collector = (*MetricsService)(nil)
+)
@@ -0,0 +1,74 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestLatestArtifacts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	newArtifact := func(id, serviceID string, finished bool) *models.Artifact {
+		a := &models.Artifact{
+			ID:         id,
+			ServiceID:  serviceID,
+			LocationID: "location",
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+		}
+		if finished {
+			a.FinishedAt = pointer.ToTime(now)
+		}
+		return a
+	}
+
+	artifacts := []*models.Artifact{
+		newArtifact("a4", "s1", false),
+		newArtifact("a3", "s1", true),
+		newArtifact("a2", "s2", true),
+		newArtifact("a1", "s1", true),
+	}
+
+	actual := latestArtifacts(artifacts)
+	assert.Equal(t, []*models.Artifact{artifacts[1], artifacts[2]}, actual)
+}
+
+func TestArtifactDuration(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Unix(1000, 0)
+	a := &models.Artifact{
+		StartedAt:  pointer.ToTime(startedAt),
+		FinishedAt: pointer.ToTime(startedAt.Add(10 * time.Second)),
+	}
+	assert.Equal(t, 10*time.Second, a.Duration())
+
+	a.FinishedAt = nil
+	assert.Equal(t, time.Duration(0), a.Duration())
+
+	a.StartedAt = nil
+	a.FinishedAt = pointer.ToTime(startedAt)
+	assert.Equal(t, time.Duration(0), a.Duration())
+}
@@ -13,6 +13,20 @@ type mockAgentsRegistry struct {
 	mock.Mock
 }
 
+// IsConnected provides a mock function with given fields: pmmAgentID
+func (_m *mockAgentsRegistry) IsConnected(pmmAgentID string) bool {
+	ret := _m.Called(pmmAgentID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(pmmAgentID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // PBMSwitchPITR provides a mock function with given fields: pmmAgentID, dsn, files, tdp, enabled
 func (_m *mockAgentsRegistry) PBMSwitchPITR(pmmAgentID string, dsn string, files map[string]string, tdp *models.DelimiterPair, enabled bool) error {
 	ret := _m.Called(pmmAgentID, dsn, files, tdp, enabled)
@@ -15,6 +15,34 @@ type mockJobsService struct {
 	mock.Mock
 }
 
+// CancelJob provides a mock function with given fields: jobID, reason
+func (_m *mockJobsService) CancelJob(jobID string, reason string) error {
+	ret := _m.Called(jobID, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(jobID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeJob provides a mock function with given fields: jobID
+func (_m *mockJobsService) ResumeJob(jobID string) error {
+	ret := _m.Called(jobID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(jobID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StartMongoDBBackupJob provides a mock function with given fields: jobID, pmmAgentID, timeout, name, dbConfig, mode, locationConfig
 func (_m *mockJobsService) StartMongoDBBackupJob(jobID string, pmmAgentID string, timeout time.Duration, name string, dbConfig *models.DBConfig, mode models.BackupMode, locationConfig *models.BackupLocationConfig) error {
 	ret := _m.Called(jobID, pmmAgentID, timeout, name, dbConfig, mode, locationConfig)
@@ -29,13 +57,13 @@ func (_m *mockJobsService) StartMongoDBBackupJob(jobID string, pmmAgentID string
 	return r0
 }
 
-// StartMongoDBRestoreBackupJob provides a mock function with given fields: jobID, pmmAgentID, timeout, name, dbConfig, locationConfig
-func (_m *mockJobsService) StartMongoDBRestoreBackupJob(jobID string, pmmAgentID string, timeout time.Duration, name string, dbConfig *models.DBConfig, locationConfig *models.BackupLocationConfig) error {
-	ret := _m.Called(jobID, pmmAgentID, timeout, name, dbConfig, locationConfig)
+// StartMongoDBRestoreBackupJob provides a mock function with given fields: jobID, pmmAgentID, timeout, name, dbConfig, pitrTimestamp, locationConfig
+func (_m *mockJobsService) StartMongoDBRestoreBackupJob(jobID string, pmmAgentID string, timeout time.Duration, name string, dbConfig *models.DBConfig, pitrTimestamp time.Time, locationConfig *models.BackupLocationConfig) error {
+	ret := _m.Called(jobID, pmmAgentID, timeout, name, dbConfig, pitrTimestamp, locationConfig)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, time.Duration, string, *models.DBConfig, *models.BackupLocationConfig) error); ok {
-		r0 = rf(jobID, pmmAgentID, timeout, name, dbConfig, locationConfig)
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration, string, *models.DBConfig, time.Time, *models.BackupLocationConfig) error); ok {
+		r0 = rf(jobID, pmmAgentID, timeout, name, dbConfig, pitrTimestamp, locationConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -6,6 +6,10 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/percona/pmm-managed/models"
+
+	minio "github.com/percona/pmm-managed/services/minio"
 )
 
 // mockS3 is an autogenerated mock type for the s3 type
@@ -13,6 +17,43 @@ type mockS3 struct {
 	mock.Mock
 }
 
+// CopyRecursive provides a mock function with given fields: ctx, src, dst, prefix
+func (_m *mockS3) CopyRecursive(ctx context.Context, src *models.S3LocationConfig, dst *models.S3LocationConfig, prefix string) error {
+	ret := _m.Called(ctx, src, dst, prefix)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.S3LocationConfig, *models.S3LocationConfig, string) error); ok {
+		r0 = rf(ctx, src, dst, prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: ctx, endpoint, accessKey, secretKey, bucketName, prefix, suffix
+func (_m *mockS3) List(ctx context.Context, endpoint string, accessKey string, secretKey string, bucketName string, prefix string, suffix string) ([]minio.FileInfo, error) {
+	ret := _m.Called(ctx, endpoint, accessKey, secretKey, bucketName, prefix, suffix)
+
+	var r0 []minio.FileInfo
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, string) []minio.FileInfo); ok {
+		r0 = rf(ctx, endpoint, accessKey, secretKey, bucketName, prefix, suffix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]minio.FileInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, string, string) error); ok {
+		r1 = rf(ctx, endpoint, accessKey, secretKey, bucketName, prefix, suffix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveRecursive provides a mock function with given fields: ctx, endpoint, accessKey, secretKey, bucketName, prefix
 func (_m *mockS3) RemoveRecursive(ctx context.Context, endpoint string, accessKey string, secretKey string, bucketName string, prefix string) error {
 	ret := _m.Called(ctx, endpoint, accessKey, secretKey, bucketName, prefix)
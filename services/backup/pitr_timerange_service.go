@@ -0,0 +1,268 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+const (
+	// pitrFSPrefix is a directory inside the artifact folder where PBM stores oplog chunks.
+	pitrFSPrefix = "pbmPitr"
+	// pitrTimestampLayout is a layout of timestamps in PBM oplog chunk file names.
+	pitrTimestampLayout = "20060102150405"
+)
+
+// oplogTimestamp is a MongoDB oplog timestamp: seconds since epoch and ordinal within the second.
+type oplogTimestamp struct {
+	T uint32
+	I uint32
+}
+
+// before returns true if ts is before other.
+func (ts oplogTimestamp) before(other oplogTimestamp) bool {
+	return ts.T < other.T || (ts.T == other.T && ts.I < other.I)
+}
+
+// oplogChunk is a single oplog chunk file stored by PBM.
+type oplogChunk struct {
+	ReplicaSet string
+	FileName   string
+	StartTS    oplogTimestamp
+	EndTS      oplogTimestamp
+}
+
+// Timeline is a contiguous range of oplog available for point-in-time recovery.
+type Timeline struct {
+	ReplicaSet string
+	Start      uint32
+	End        uint32
+}
+
+// PITRTimerangeService helps to find time ranges available for MongoDB point-in-time recovery.
+type PITRTimerangeService struct {
+	s3 s3
+	l  *logrus.Entry
+}
+
+// NewPITRTimerangeService creates new PITR timerange service.
+func NewPITRTimerangeService(s3 s3) *PITRTimerangeService {
+	return &PITRTimerangeService{
+		s3: s3,
+		l:  logrus.WithField("component", "services/backup/pitr_timerange"),
+	}
+}
+
+// ListPITRTimeranges returns the time ranges available for point-in-time recovery of the given artifact.
+func (s *PITRTimerangeService) ListPITRTimeranges(
+	ctx context.Context,
+	artifactName string,
+	location *models.BackupLocation,
+) ([]Timeline, error) {
+	if location.S3Config == nil {
+		return nil, errors.Errorf("point-in-time recovery is supported only for S3 locations, location: %q", location.ID)
+	}
+
+	s3Config := location.S3Config
+	prefix := path.Join(artifactName, pitrFSPrefix) + "/"
+	files, err := s.s3.List(ctx, s3Config.Endpoint, s3Config.AccessKey, s3Config.SecretKey, s3Config.BucketName, prefix, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list oplog chunks of artifact %q", artifactName)
+	}
+
+	chunks := make([]oplogChunk, 0, len(files))
+	for _, f := range files {
+		if f.Size == 0 {
+			continue
+		}
+
+		chunk, err := parseOplogChunkName(f.Name)
+		if err != nil {
+			s.l.Debugf("Skipping file %q: %s.", f.Name, err)
+			continue
+		}
+		chunks = append(chunks, *chunk)
+	}
+
+	return oplogTimelines(chunks), nil
+}
+
+// parseOplogChunkName parses PBM oplog chunk file name relative to the pbmPitr directory,
+// e.g. "rs0/20220310/20220310103451-1.20220310104451-5.oplog.s2".
+func parseOplogChunkName(name string) (*oplogChunk, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 {
+		return nil, errors.Errorf("unexpected oplog chunk path %q", name)
+	}
+
+	fileName := parts[2]
+	idx := strings.Index(fileName, ".oplog")
+	if idx == -1 {
+		return nil, errors.Errorf("unexpected oplog chunk file name %q", fileName)
+	}
+
+	timestamps := strings.Split(fileName[:idx], ".")
+	if len(timestamps) != 2 {
+		return nil, errors.Errorf("unexpected oplog chunk file name %q", fileName)
+	}
+
+	start, err := parseOplogTimestamp(timestamps[0])
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseOplogTimestamp(timestamps[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if end.before(start) {
+		return nil, errors.Errorf("oplog chunk %q ends before it starts", fileName)
+	}
+
+	return &oplogChunk{
+		ReplicaSet: parts[0],
+		FileName:   fileName,
+		StartTS:    start,
+		EndTS:      end,
+	}, nil
+}
+
+// parseOplogTimestamp parses timestamps like "20220310103451-1".
+func parseOplogTimestamp(s string) (oplogTimestamp, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return oplogTimestamp{}, errors.Errorf("unexpected oplog timestamp %q", s)
+	}
+
+	t, err := time.Parse(pitrTimestampLayout, parts[0])
+	if err != nil {
+		return oplogTimestamp{}, errors.Wrapf(err, "failed to parse oplog timestamp %q", s)
+	}
+
+	i, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return oplogTimestamp{}, errors.Wrapf(err, "failed to parse oplog timestamp ordinal %q", s)
+	}
+
+	return oplogTimestamp{T: uint32(t.Unix()), I: uint32(i)}, nil
+}
+
+// oplogTimelines merges contiguous chunks of each replica set into timelines
+// and returns the ranges covered by all replica sets.
+func oplogTimelines(chunks []oplogChunk) []Timeline {
+	byReplicaSet := make(map[string][]oplogChunk)
+	for _, c := range chunks {
+		byReplicaSet[c.ReplicaSet] = append(byReplicaSet[c.ReplicaSet], c)
+	}
+
+	replicaSets := make([]string, 0, len(byReplicaSet))
+	for rs := range byReplicaSet {
+		replicaSets = append(replicaSets, rs)
+	}
+	sort.Strings(replicaSets)
+
+	var res []Timeline
+	for i, rs := range replicaSets {
+		timelines := mergeOplogChunks(rs, byReplicaSet[rs])
+		if i == 0 {
+			res = timelines
+			continue
+		}
+		res = intersectTimelines(res, timelines)
+	}
+
+	return res
+}
+
+// mergeOplogChunks merges contiguous oplog chunks of a single replica set into timelines.
+// Chunks are contiguous when the next one starts exactly where the previous one ends.
+func mergeOplogChunks(replicaSet string, chunks []oplogChunk) []Timeline {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].StartTS.before(chunks[j].StartTS)
+	})
+
+	var res []Timeline
+	current := Timeline{ReplicaSet: replicaSet, Start: chunks[0].StartTS.T, End: chunks[0].EndTS.T}
+	prevEnd := chunks[0].EndTS
+	for _, c := range chunks[1:] {
+		if c.StartTS.before(prevEnd) || c.StartTS == prevEnd {
+			if prevEnd.before(c.EndTS) {
+				prevEnd = c.EndTS
+				current.End = c.EndTS.T
+			}
+			continue
+		}
+
+		res = append(res, current)
+		current = Timeline{ReplicaSet: replicaSet, Start: c.StartTS.T, End: c.EndTS.T}
+		prevEnd = c.EndTS
+	}
+
+	return append(res, current)
+}
+
+// intersectTimelines returns ranges covered by both lists of sorted timelines.
+func intersectTimelines(a, b []Timeline) []Timeline {
+	var res []Timeline
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start > start {
+			start = b[j].Start
+		}
+		if b[j].End < end {
+			end = b[j].End
+		}
+		if start <= end {
+			res = append(res, Timeline{Start: start, End: end})
+		}
+
+		if a[i].End < b[j].End {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return res
+}
+
+// inPITRTimerange returns true if the timestamp is inside one of the timelines.
+func inPITRTimerange(timestamp time.Time, timelines []Timeline) bool {
+	ts := timestamp.Unix()
+	for _, tl := range timelines {
+		if ts >= int64(tl.Start) && ts <= int64(tl.End) {
+			return true
+		}
+	}
+
+	return false
+}
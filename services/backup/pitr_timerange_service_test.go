@@ -0,0 +1,164 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/minio"
+)
+
+func unixTS(t *testing.T, s string) uint32 {
+	t.Helper()
+	ts, err := time.Parse(pitrTimestampLayout, s)
+	require.NoError(t, err)
+	return uint32(ts.Unix())
+}
+
+func TestParseOplogChunkName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal", func(t *testing.T) {
+		t.Parallel()
+
+		chunk, err := parseOplogChunkName("rs0/20220310/20220310103451-1.20220310104451-5.oplog.s2")
+		require.NoError(t, err)
+		assert.Equal(t, "rs0", chunk.ReplicaSet)
+		assert.Equal(t, oplogTimestamp{T: unixTS(t, "20220310103451"), I: 1}, chunk.StartTS)
+		assert.Equal(t, oplogTimestamp{T: unixTS(t, "20220310104451"), I: 5}, chunk.EndTS)
+	})
+
+	t.Run("without compression", func(t *testing.T) {
+		t.Parallel()
+
+		chunk, err := parseOplogChunkName("rs0/20220310/20220310103451-1.20220310104451-5.oplog")
+		require.NoError(t, err)
+		assert.Equal(t, oplogTimestamp{T: unixTS(t, "20220310104451"), I: 5}, chunk.EndTS)
+	})
+
+	for _, name := range []string{
+		"20220310103451-1.20220310104451-5.oplog.s2",
+		"rs0/20220310/20220310103451-1.20220310104451-5.s2",
+		"rs0/20220310/20220310103451-1.oplog.s2",
+		"rs0/20220310/20220310103451.20220310104451-5.oplog.s2",
+		"rs0/20220310/2022031010345a-1.20220310104451-5.oplog.s2",
+		"rs0/20220310/20220310104451-5.20220310103451-1.oplog.s2",
+	} {
+		_, err := parseOplogChunkName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestOplogTimelines(t *testing.T) {
+	t.Parallel()
+
+	chunk := func(rs, start, end string) oplogChunk {
+		s, err := parseOplogTimestamp(start)
+		require.NoError(t, err)
+		e, err := parseOplogTimestamp(end)
+		require.NoError(t, err)
+		return oplogChunk{ReplicaSet: rs, StartTS: s, EndTS: e}
+	}
+
+	t.Run("single replica set", func(t *testing.T) {
+		t.Parallel()
+
+		timelines := oplogTimelines([]oplogChunk{
+			chunk("rs0", "20220310110000-1", "20220310111000-1"),
+			chunk("rs0", "20220310100000-1", "20220310101000-3"),
+			chunk("rs0", "20220310101000-3", "20220310102000-1"),
+		})
+		expected := []Timeline{
+			{ReplicaSet: "rs0", Start: unixTS(t, "20220310100000"), End: unixTS(t, "20220310102000")},
+			{ReplicaSet: "rs0", Start: unixTS(t, "20220310110000"), End: unixTS(t, "20220310111000")},
+		}
+		assert.Equal(t, expected, timelines)
+	})
+
+	t.Run("several replica sets", func(t *testing.T) {
+		t.Parallel()
+
+		timelines := oplogTimelines([]oplogChunk{
+			chunk("rs0", "20220310100000-1", "20220310102000-1"),
+			chunk("rs0", "20220310110000-1", "20220310111000-1"),
+			chunk("rs1", "20220310101000-1", "20220310110500-1"),
+		})
+		expected := []Timeline{
+			{Start: unixTS(t, "20220310101000"), End: unixTS(t, "20220310102000")},
+			{Start: unixTS(t, "20220310110000"), End: unixTS(t, "20220310110500")},
+		}
+		assert.Equal(t, expected, timelines)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, oplogTimelines(nil))
+	})
+}
+
+func TestListPITRTimeranges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	location := &models.BackupLocation{
+		ID: "location_id",
+		S3Config: &models.S3LocationConfig{
+			Endpoint:   "https://s3.us-west-2.amazonaws.com/",
+			AccessKey:  "access_key",
+			SecretKey:  "secret_key",
+			BucketName: "example_bucket",
+		},
+	}
+
+	mockedS3 := &mockS3{}
+	mockedS3.On("List", mock.Anything, location.S3Config.Endpoint, location.S3Config.AccessKey,
+		location.S3Config.SecretKey, location.S3Config.BucketName, "artifact/pbmPitr/", "").
+		Return([]minio.FileInfo{
+			{Name: "rs0/20220310/20220310100000-1.20220310101000-1.oplog.s2", Size: 1024},
+			{Name: "rs0/20220310/20220310101000-1.20220310102000-1.oplog.s2", Size: 1024},
+			{Name: "rs0/20220310/20220310102000-1.20220310103000-1.oplog.s2", Size: 0},
+			{Name: "rs0/20220310/unknown", Size: 1024},
+		}, nil).Once()
+
+	s := NewPITRTimerangeService(mockedS3)
+	timelines, err := s.ListPITRTimeranges(ctx, "artifact", location)
+	require.NoError(t, err)
+	require.Len(t, timelines, 1)
+	assert.Equal(t, unixTS(t, "20220310100000"), timelines[0].Start)
+	assert.Equal(t, unixTS(t, "20220310102000"), timelines[0].End)
+
+	inRange, err := time.Parse(pitrTimestampLayout, "20220310101500")
+	require.NoError(t, err)
+	assert.True(t, inPITRTimerange(inRange, timelines))
+
+	outOfRange, err := time.Parse(pitrTimestampLayout, "20220310102500")
+	require.NoError(t, err)
+	assert.False(t, inPITRTimerange(outOfRange, timelines))
+
+	mockedS3.AssertExpectations(t)
+
+	_, err = s.ListPITRTimeranges(ctx, "artifact", &models.BackupLocation{ID: "local"})
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ package backup
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -44,25 +45,24 @@ func NewRemovalService(db *reform.DB, s3 s3) *RemovalService {
 	}
 }
 
-// DeleteArtifact deletes specified artifact.
+// DeleteArtifact deletes specified artifact. Files of artifact replicas are removed along with the artifact files.
 func (s *RemovalService) DeleteArtifact(ctx context.Context, artifactID string, removeFiles bool) error {
-	artifactName, s3Config, err := s.beginDeletingArtifact(artifactID)
+	artifactName, s3Configs, err := s.beginDeletingArtifact(artifactID)
 	if err != nil {
 		return err
 	}
 
-	if s3Config != nil && removeFiles {
-		if err := s.s3.RemoveRecursive(
-			ctx,
-			s3Config.Endpoint,
-			s3Config.AccessKey,
-			s3Config.SecretKey,
-			s3Config.BucketName,
-			// Recursive listing finds all the objects with the specified prefix.
-			// There could be a problem e.g. when we have artifacts `backup-daily` and `backup-daily-1`, so
-			// listing by prefix `backup-daily` gives us both artifacts.
-			// To avoid such a situation we need to append a slash.
-			artifactName+"/"); err != nil {
+	if removeFiles {
+		// Files are removed from all locations even if some of them fail, so retry has less to remove.
+		var errs []string
+		for _, s3Config := range s3Configs {
+			if err := s.removeFiles(ctx, s3Config, artifactName); err != nil {
+				s.l.WithError(err).Errorf("failed to remove files of artifact %q from bucket %q", artifactID, s3Config.BucketName)
+				errs = append(errs, err.Error())
+			}
+		}
+
+		if len(errs) != 0 {
 			if _, updateErr := models.UpdateArtifact(s.db.Querier, artifactID, models.UpdateArtifactParams{
 				Status: models.BackupStatusPointer(models.FailedToDeleteBackupStatus),
 			}); updateErr != nil {
@@ -70,7 +70,8 @@ func (s *RemovalService) DeleteArtifact(ctx context.Context, artifactID string,
 					Errorf("failed to set status %q for artifact %q", models.FailedToDeleteBackupStatus, artifactID)
 			}
 
-			return err
+			return errors.Errorf("failed to remove files of artifact %q from %d of %d locations: %s",
+				artifactID, len(errs), len(s3Configs), strings.Join(errs, "; "))
 		}
 	}
 
@@ -88,16 +89,93 @@ func (s *RemovalService) DeleteArtifact(ctx context.Context, artifactID string,
 			}
 		}
 
+		if removeFiles {
+			if err := decreaseLocationUsage(tx.Querier, artifactID); err != nil {
+				return err
+			}
+		}
+
 		return models.DeleteArtifact(tx.Querier, artifactID)
 	})
 }
 
+// decreaseLocationUsage subtracts artifact size from the cached usage of its location and locations of its replicas,
+// so quota isn't considered exceeded until usage is computed again.
+func decreaseLocationUsage(q *reform.Querier, artifactID string) error {
+	artifact, err := models.FindArtifactByID(q, artifactID)
+	if err != nil {
+		return err
+	}
+
+	replicas, err := models.FindArtifactReplicas(q, models.ArtifactReplicaFilters{ArtifactID: artifactID})
+	if err != nil {
+		return err
+	}
+
+	locationIDs := []string{artifact.LocationID}
+	for _, replica := range replicas {
+		locationIDs = append(locationIDs, replica.LocationID)
+	}
+
+	for _, id := range locationIDs {
+		location, err := models.FindBackupLocationByID(q, id)
+		if err != nil {
+			return err
+		}
+
+		if location.UsageUpdatedAt == nil {
+			continue
+		}
+
+		if _, err = models.UpdateBackupLocationUsage(q, location.ID, location.UsageBytes-artifact.Size); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteArtifactFiles removes files of the artifact from its location, artifact itself is kept.
+// It's used for cleaning up partial artifacts of stopped backups.
+func (s *RemovalService) DeleteArtifactFiles(ctx context.Context, artifactID string) error {
+	artifact, err := models.FindArtifactByID(s.db.Querier, artifactID)
+	if err != nil {
+		return err
+	}
+
+	location, err := models.FindBackupLocationByID(s.db.Querier, artifact.LocationID)
+	if err != nil {
+		return err
+	}
+
+	if location.S3Config == nil {
+		s.l.Debugf("Files of artifact %q can't be removed from location of type %q.", artifactID, location.Type)
+		return nil
+	}
+
+	return s.removeFiles(ctx, location.S3Config, artifact.Name)
+}
+
+func (s *RemovalService) removeFiles(ctx context.Context, s3Config *models.S3LocationConfig, artifactName string) error {
+	return s.s3.RemoveRecursive(
+		ctx,
+		s3Config.Endpoint,
+		s3Config.AccessKey,
+		s3Config.SecretKey,
+		s3Config.BucketName,
+		// Recursive listing finds all the objects with the specified prefix.
+		// There could be a problem e.g. when we have artifacts `backup-daily` and `backup-daily-1`, so
+		// listing by prefix `backup-daily` gives us both artifacts.
+		// To avoid such a situation we need to append a slash.
+		artifactName+"/")
+}
+
 // beginDeletingArtifact checks if the artifact isn't in use at the moment and sets deleting status,
-// so it will not be used to restore backup.
+// so it will not be used to restore backup. It returns configs of S3 locations of the artifact and its replicas.
 func (s *RemovalService) beginDeletingArtifact(
 	artifactID string,
-) (string, *models.S3LocationConfig, error) {
-	var s3Config *models.S3LocationConfig
+) (string, []*models.S3LocationConfig, error) {
+	var s3Configs []*models.S3LocationConfig
 	var artifactName string
 	if err := s.db.InTransaction(func(tx *reform.TX) error {
 		artifact, err := s.canDeleteArtifact(tx.Querier, artifactID)
@@ -126,7 +204,26 @@ func (s *RemovalService) beginDeletingArtifact(
 			return err
 		}
 
-		s3Config = location.S3Config
+		if location.S3Config != nil {
+			s3Configs = append(s3Configs, location.S3Config)
+		}
+
+		// Replicas with any status are included as failed copy may leave some files.
+		replicas, err := models.FindArtifactReplicas(tx.Querier, models.ArtifactReplicaFilters{ArtifactID: artifactID})
+		if err != nil {
+			return err
+		}
+
+		for _, replica := range replicas {
+			replicaLocation, err := models.FindBackupLocationByID(tx.Querier, replica.LocationID)
+			if err != nil {
+				return err
+			}
+
+			if replicaLocation.S3Config != nil {
+				s3Configs = append(s3Configs, replicaLocation.S3Config)
+			}
+		}
 
 		if _, err := models.UpdateArtifact(tx.Querier, artifactID, models.UpdateArtifactParams{
 			Status: models.BackupStatusPointer(models.DeletingBackupStatus),
@@ -139,7 +236,7 @@ func (s *RemovalService) beginDeletingArtifact(
 		return "", nil, err
 	}
 
-	return artifactName, s3Config, nil
+	return artifactName, s3Configs, nil
 }
 
 func (s *RemovalService) canDeleteArtifact(q *reform.Querier, artifactID string) (*models.Artifact, error) {
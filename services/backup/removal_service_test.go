@@ -18,8 +18,10 @@ package backup
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/AlekSi/pointer"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -80,7 +82,7 @@ func TestDeleteArtifact(t *testing.T) {
 			}).Once()
 
 		err := removalService.DeleteArtifact(ctx, artifact.ID, true)
-		require.EqualError(t, err, "failed to remove")
+		require.EqualError(t, err, fmt.Sprintf(`failed to remove files of artifact %q from 1 of 1 locations: failed to remove`, artifact.ID))
 
 		artifact, err := models.FindArtifactByID(db.Querier, artifact.ID)
 		require.NoError(t, err)
@@ -99,5 +101,54 @@ func TestDeleteArtifact(t *testing.T) {
 		assert.True(t, errors.Is(err, models.ErrNotFound))
 	})
 
+	t.Run("usage of replica locations is decreased", func(t *testing.T) {
+		replicaLocation, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+			Name: "Replica location",
+			BackupLocationConfig: models.BackupLocationConfig{
+				S3Config: &models.S3LocationConfig{
+					Endpoint:     endpoint,
+					AccessKey:    accessKey,
+					SecretKey:    secretKey,
+					BucketName:   "replica_bucket",
+					BucketRegion: bucketRegion,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		artifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       "replicated_artifact_name",
+			Vendor:     "MySQL",
+			LocationID: locationRes.ID,
+			ServiceID:  *agent.ServiceID,
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     models.SuccessBackupStatus,
+		})
+		require.NoError(t, err)
+		_, err = models.UpdateArtifact(db.Querier, artifact.ID, models.UpdateArtifactParams{Size: pointer.ToInt64(100)})
+		require.NoError(t, err)
+		_, err = models.CreateArtifactReplica(db.Querier, artifact.ID, replicaLocation.ID)
+		require.NoError(t, err)
+
+		for _, id := range []string{locationRes.ID, replicaLocation.ID} {
+			_, err = models.UpdateBackupLocationUsage(db.Querier, id, 500)
+			require.NoError(t, err)
+		}
+
+		mockedS3.On("RemoveRecursive", mock.Anything, endpoint, accessKey, secretKey, bucketName,
+			artifact.Name+"/").Return(nil).Once()
+		mockedS3.On("RemoveRecursive", mock.Anything, endpoint, accessKey, secretKey, "replica_bucket",
+			artifact.Name+"/").Return(nil).Once()
+
+		require.NoError(t, removalService.DeleteArtifact(ctx, artifact.ID, true))
+
+		for _, id := range []string{locationRes.ID, replicaLocation.ID} {
+			location, err := models.FindBackupLocationByID(db.Querier, id)
+			require.NoError(t, err)
+			assert.Equal(t, int64(400), location.UsageBytes)
+		}
+	})
+
 	mock.AssertExpectationsForObjects(t, mockedS3)
 }
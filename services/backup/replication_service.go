@@ -0,0 +1,188 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// ErrArtifactUnavailable is returned when artifact files can't be found neither in the primary location nor in replicas.
+var ErrArtifactUnavailable = errors.New("artifact is not available")
+
+// ReplicationService copies artifacts to secondary locations according to replication rules.
+type ReplicationService struct {
+	db *reform.DB
+	s3 s3
+	l  *logrus.Entry
+}
+
+// NewReplicationService creates new artifacts replication service.
+func NewReplicationService(db *reform.DB, s3 s3) *ReplicationService {
+	return &ReplicationService{
+		db: db,
+		s3: s3,
+		l:  logrus.WithField("component", "management/backup/replication"),
+	}
+}
+
+// ReplicateArtifact copies successful artifact to target locations of replication rules of the artifact location.
+// Status of each copy is tracked separately, so failed copy doesn't affect the others.
+// PITR artifacts are skipped: oplog chunks are added to them after they are finished, so their copies would go stale.
+func (s *ReplicationService) ReplicateArtifact(ctx context.Context, artifactID string) error {
+	artifact, err := models.FindArtifactByID(s.db.Querier, artifactID)
+	if err != nil {
+		return err
+	}
+
+	if artifact.Status != models.SuccessBackupStatus {
+		return errors.Errorf("artifact %q status is not successful, status: %q", artifactID, artifact.Status)
+	}
+
+	if artifact.Mode == models.PITR {
+		s.l.Debugf("Artifact %q is not replicated as PITR artifacts are not replicated.", artifactID)
+		return nil
+	}
+
+	source, err := models.FindBackupLocationByID(s.db.Querier, artifact.LocationID)
+	if err != nil {
+		return err
+	}
+
+	rules, err := models.FindReplicationRules(s.db.Querier, models.ReplicationRuleFilters{SourceLocationID: source.ID})
+	if err != nil {
+		return err
+	}
+
+	var res error
+	for _, rule := range rules {
+		if err := s.replicate(ctx, artifact, source, rule.TargetLocationID); err != nil {
+			s.l.Errorf("Failed to replicate artifact %q to location %q: %s.", artifactID, rule.TargetLocationID, err)
+			res = err
+		}
+	}
+
+	return res
+}
+
+func (s *ReplicationService) replicate(
+	ctx context.Context,
+	artifact *models.Artifact,
+	source *models.BackupLocation,
+	targetLocationID string,
+) error {
+	target, err := models.FindBackupLocationByID(s.db.Querier, targetLocationID)
+	if err != nil {
+		return err
+	}
+
+	replica, err := models.CreateArtifactReplica(s.db.Querier, artifact.ID, target.ID)
+	if err != nil {
+		return err
+	}
+
+	if source.S3Config == nil || target.S3Config == nil {
+		err = errors.New("only S3 locations can be replicated")
+	} else {
+		if _, err = models.UpdateArtifactReplica(s.db.Querier, replica.ID, models.UpdateArtifactReplicaParams{
+			Status: models.ReplicaStatusPointer(models.InProgressReplicaStatus),
+		}); err != nil {
+			return err
+		}
+
+		// Slash is appended to avoid copying artifacts which names start with the same prefix.
+		err = s.s3.CopyRecursive(ctx, source.S3Config, target.S3Config, artifact.Name+"/")
+	}
+
+	params := models.UpdateArtifactReplicaParams{
+		Status: models.ReplicaStatusPointer(models.SuccessReplicaStatus),
+	}
+	if err != nil {
+		params.Status = models.ReplicaStatusPointer(models.ErrorReplicaStatus)
+		params.Error = pointer.ToString(err.Error())
+	}
+
+	if _, updateErr := models.UpdateArtifactReplica(s.db.Querier, replica.ID, params); updateErr != nil {
+		return updateErr
+	}
+
+	return err
+}
+
+// FindRestoreLocation returns location to restore the artifact from. It's the artifact location if artifact files
+// are available there, otherwise location of the first available successful replica.
+// Replicas of PITR artifacts may miss oplog chunks, so they are never used.
+func (s *ReplicationService) FindRestoreLocation(
+	ctx context.Context,
+	q *reform.Querier,
+	artifact *models.Artifact,
+) (*models.BackupLocation, error) {
+	primary, err := models.FindBackupLocationByID(q, artifact.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas, err := models.FindArtifactReplicas(q, models.ArtifactReplicaFilters{
+		ArtifactID: artifact.ID,
+		Status:     models.SuccessReplicaStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Availability of artifact can't be checked for non-S3 locations, so they are used as is.
+	if len(replicas) == 0 || artifact.Mode == models.PITR || primary.S3Config == nil || s.isAvailable(ctx, primary, artifact.Name) {
+		return primary, nil
+	}
+
+	for _, replica := range replicas {
+		location, err := models.FindBackupLocationByID(q, replica.LocationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.isAvailable(ctx, location, artifact.Name) {
+			s.l.Warnf("Artifact %q is not available in location %q, replica in location %q is used.",
+				artifact.ID, primary.ID, location.ID)
+			return location, nil
+		}
+	}
+
+	return nil, errors.Wrapf(ErrArtifactUnavailable, "artifact %q", artifact.ID)
+}
+
+// isAvailable returns true if artifact files can be listed in the location.
+func (s *ReplicationService) isAvailable(ctx context.Context, location *models.BackupLocation, artifactName string) bool {
+	c := location.S3Config
+	if c == nil {
+		return false
+	}
+
+	files, err := s.s3.List(ctx, c.Endpoint, c.AccessKey, c.SecretKey, c.BucketName, artifactName+"/", "")
+	if err != nil {
+		s.l.Warnf("Failed to list artifact %q files in location %q: %s.", artifactName, location.ID, err)
+		return false
+	}
+
+	return len(files) != 0
+}
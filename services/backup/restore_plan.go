@@ -0,0 +1,196 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/percona/pmm/api/inventorypb"
+	"github.com/pkg/errors"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// SoftwareVersionCheck compares software version of the target service with the artifact.
+type SoftwareVersionCheck struct {
+	Name models.SoftwareName
+	// ArtifactVersion is set for the database software only.
+	ArtifactVersion string
+	// ServiceVersion is empty if software isn't installed.
+	ServiceVersion string
+}
+
+// RestorePlan is a result of restore pre-flight checks.
+type RestorePlan struct {
+	ServiceID  string
+	ArtifactID string
+	// LocationID is ID of the location artifact is restored from, it differs from the artifact location
+	// if artifact replica is used.
+	LocationID       string
+	SoftwareVersions []SoftwareVersionCheck
+	// RequiredSpace is the size in bytes of artifact files in the restore location, it's the minimal amount
+	// of free space required on the target. It's nil if files can't be listed by pmm-managed,
+	// i.e. for non-S3 locations. Restored data may take more space as artifacts are usually compressed.
+	// Free space of the target isn't reported by pmm-agent yet, so it's not checked.
+	RequiredSpace *int64
+	// ServiceRunning is true if pmm-agent of the service is connected and its agents report running status.
+	ServiceRunning bool
+	// Problems lists the reasons why restore would fail.
+	Problems []string
+}
+
+// Ready returns true if no problems are found and restore could be started.
+func (p *RestorePlan) Ready() bool {
+	return len(p.Problems) == 0
+}
+
+// PlanRestore performs restore pre-flight checks without starting the restore.
+// Cached software versions of the service are used, so they may differ from the ones checked by RestoreBackup.
+func (s *Service) PlanRestore(ctx context.Context, params RestoreBackupParams) (*RestorePlan, error) {
+	// Plan is read-only, so no transaction is used as location files may be listed for it.
+	q := s.db.Querier
+	jobParams, err := s.prepareRestoreJob(ctx, q, params.ServiceID, params.ArtifactID, params.PITRTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &RestorePlan{
+		ServiceID:  params.ServiceID,
+		ArtifactID: params.ArtifactID,
+		LocationID: jobParams.Location.ID,
+	}
+
+	// restore jobs accept S3 locations only
+	if c := jobParams.Location.S3Config; c != nil {
+		files, err := s.s3.List(ctx, c.Endpoint, c.AccessKey, c.SecretKey, c.BucketName, jobParams.ArtifactName+"/", "")
+		if err != nil {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("failed to list artifact files: %s", err))
+		} else {
+			var size int64
+			for _, f := range files {
+				size += f.Size
+			}
+			plan.RequiredSpace = &size
+		}
+	} else {
+		plan.Problems = append(plan.Problems, fmt.Sprintf("artifacts can't be restored from location of type %q", jobParams.Location.Type))
+	}
+
+	if !params.PITRTimestamp.IsZero() {
+		if err = s.checkPITRTimestamp(ctx, jobParams); err != nil {
+			plan.Problems = append(plan.Problems, err.Error())
+		}
+	}
+
+	if jobParams.ServiceType == models.MySQLServiceType {
+		var svm map[models.SoftwareName]string
+		sv, err := models.FindServiceSoftwareVersionsByServiceID(q, params.ServiceID)
+		switch {
+		case err == nil:
+			svm = softwareVersionsMap(sv.SoftwareVersions)
+		case errors.Is(err, models.ErrNotFound):
+		default:
+			return nil, err
+		}
+
+		if len(svm) == 0 {
+			plan.Problems = append(plan.Problems, "software versions of the service are unknown yet")
+		} else {
+			plan.SoftwareVersions, err = restoreSoftwareChecks(jobParams.ServiceType, jobParams.DBVersion, svm)
+			if err != nil {
+				plan.Problems = append(plan.Problems, err.Error())
+			}
+		}
+	}
+
+	if !s.agentsRegistry.IsConnected(jobParams.AgentID) {
+		plan.Problems = append(plan.Problems, "pmm-agent of the service is not connected")
+		return plan, nil
+	}
+
+	if plan.ServiceRunning, err = serviceAgentsRunning(q, params.ServiceID); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// serviceAgentsRunning returns true if any agent of the service reports running status.
+func serviceAgentsRunning(q *reform.Querier, serviceID string) (bool, error) {
+	agents, err := models.FindAgents(q, models.AgentFilters{ServiceID: serviceID})
+	if err != nil {
+		return false, err
+	}
+
+	for _, agent := range agents {
+		if agent.Status == inventorypb.AgentStatus_RUNNING.String() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// restoreSoftwareChecks compares software versions of the service with the artifact and checks
+// that artifact can be restored with them. Checks are returned even if an error occurs.
+func restoreSoftwareChecks(
+	serviceType models.ServiceType,
+	artifactDBVersion string,
+	svm map[models.SoftwareName]string,
+) ([]SoftwareVersionCheck, error) {
+	var dbName models.SoftwareName
+	var names []models.SoftwareName
+	var check func() error
+	switch serviceType {
+	case models.MySQLServiceType:
+		dbName = models.MysqldSoftwareName
+		names = []models.SoftwareName{
+			models.MysqldSoftwareName,
+			models.XtrabackupSoftwareName,
+			models.XbcloudSoftwareName,
+			models.QpressSoftwareName,
+		}
+		check = func() error {
+			if err := mySQLSoftwaresInstalledAndCompatible(svm); err != nil {
+				return err
+			}
+			if artifactDBVersion != "" && artifactDBVersion != svm[dbName] {
+				return errors.Wrapf(ErrIncompatibleTargetMySQL, "artifact db version %q != db version %q",
+					artifactDBVersion, svm[dbName])
+			}
+			return nil
+		}
+	default:
+		return nil, nil
+	}
+
+	checks := make([]SoftwareVersionCheck, 0, len(names))
+	for _, name := range names {
+		c := SoftwareVersionCheck{
+			Name:           name,
+			ServiceVersion: svm[name],
+		}
+		if name == dbName {
+			c.ArtifactVersion = artifactDBVersion
+		}
+		checks = append(checks, c)
+	}
+
+	return checks, check()
+}
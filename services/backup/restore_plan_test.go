@@ -0,0 +1,62 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestRestoreSoftwareChecks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+
+		svm := map[models.SoftwareName]string{
+			models.MysqldSoftwareName:     "8.0.25",
+			models.XtrabackupSoftwareName: "8.0.25",
+			models.XbcloudSoftwareName:    "8.0.25",
+			models.QpressSoftwareName:     "1.1",
+		}
+
+		checks, err := restoreSoftwareChecks(models.MySQLServiceType, "8.0.25", svm)
+		require.NoError(t, err)
+		assert.Equal(t, []SoftwareVersionCheck{
+			{Name: models.MysqldSoftwareName, ArtifactVersion: "8.0.25", ServiceVersion: "8.0.25"},
+			{Name: models.XtrabackupSoftwareName, ServiceVersion: "8.0.25"},
+			{Name: models.XbcloudSoftwareName, ServiceVersion: "8.0.25"},
+			{Name: models.QpressSoftwareName, ServiceVersion: "1.1"},
+		}, checks)
+
+		checks, err = restoreSoftwareChecks(models.MySQLServiceType, "8.0.26", svm)
+		assert.ErrorIs(t, err, ErrIncompatibleTargetMySQL)
+		assert.Len(t, checks, 4)
+	})
+
+	t.Run("mongodb", func(t *testing.T) {
+		t.Parallel()
+
+		checks, err := restoreSoftwareChecks(models.MongoDBServiceType, "", nil)
+		require.NoError(t, err)
+		assert.Empty(t, checks)
+	})
+}
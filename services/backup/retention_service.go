@@ -18,6 +18,8 @@ package backup
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -43,18 +45,14 @@ func NewRetentionService(db *reform.DB, removalSVC removalService) *RetentionSer
 }
 
 // EnforceRetention enforce retention on provided scheduled backup task
-// it removes any old successful artifacts below retention threshold.
+// it removes any old successful artifacts which aren't kept by retention rules.
 func (s *RetentionService) EnforceRetention(ctx context.Context, scheduleID string) error {
-	artifacts, retention, err := s.findArtifacts(s.db.Querier, scheduleID)
+	artifacts, err := s.ArtifactsToDelete(scheduleID, nil)
 	if err != nil {
 		return err
 	}
 
-	if retention == 0 || int(retention) > len(artifacts) {
-		return nil
-	}
-
-	for _, artifact := range artifacts[retention:] {
+	for _, artifact := range artifacts {
 		if err := s.removalSVC.DeleteArtifact(ctx, artifact.ID, true); err != nil {
 			return err
 		}
@@ -63,35 +61,114 @@ func (s *RetentionService) EnforceRetention(ctx context.Context, scheduleID stri
 	return nil
 }
 
-// findArtifacts returns successful artifacts belong to scheduled task and it's retention.
-func (s *RetentionService) findArtifacts(q *reform.Querier, scheduleID string) ([]*models.Artifact, uint32, error) {
-	var retention uint32
+// ArtifactsToDelete returns successful artifacts of scheduled backup task which are not kept by retention rules
+// and will be removed by the next EnforceRetention call. It doesn't remove anything and can be used as a dry-run.
+// If policy is not nil, it is used instead of the task's retention policy to preview the policy change.
+func (s *RetentionService) ArtifactsToDelete(scheduleID string, policy *models.RetentionPolicy) ([]*models.Artifact, error) {
+	data, err := findBackupTaskData(s.db.Querier, scheduleID)
+	if err != nil {
+		return nil, err
+	}
 
+	if policy == nil {
+		policy = data.RetentionPolicy
+	}
+	if data.Retention == 0 && policy.IsEmpty() {
+		return nil, nil
+	}
+
+	artifacts, err := models.FindArtifacts(s.db.Querier, models.ArtifactFilters{
+		ScheduleID: scheduleID,
+		Status:     models.SuccessBackupStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keep := artifactsToKeep(artifacts, data.Retention, policy, time.Now())
+	res := make([]*models.Artifact, 0, len(artifacts)-len(keep))
+	for _, artifact := range artifacts {
+		if _, ok := keep[artifact.ID]; !ok {
+			res = append(res, artifact)
+		}
+	}
+
+	return res, nil
+}
+
+// findBackupTaskData returns common data of scheduled backup task including its retention settings.
+func findBackupTaskData(q *reform.Querier, scheduleID string) (*models.CommonBackupTaskData, error) {
 	task, err := models.FindScheduledTaskByID(q, scheduleID)
 	if err != nil {
-		return nil, retention, err
+		return nil, err
 	}
 
 	switch task.Type {
 	case models.ScheduledMySQLBackupTask:
-		retention = task.Data.MySQLBackupTask.Retention
+		return &task.Data.MySQLBackupTask.CommonBackupTaskData, nil
 	case models.ScheduledMongoDBBackupTask:
-		retention = task.Data.MongoDBBackupTask.Retention
+		return &task.Data.MongoDBBackupTask.CommonBackupTaskData, nil
 	default:
-		return nil, retention, errors.Errorf("invalid backup type %s", task.Type)
+		return nil, errors.Errorf("invalid backup type %s", task.Type)
 	}
+}
 
-	if retention == 0 {
-		return nil, retention, nil
+// artifactsToKeep returns IDs of artifacts kept by at least one of the retention rules:
+// the last retention artifacts, artifacts younger than policy max age,
+// and the latest artifact of each of the most recent days, ISO weeks and months, see models.RetentionPolicy.
+// Zero retention keeps no artifacts by count; callers keep all artifacts if policy is empty too.
+// Artifacts are expected to be sorted by creation time in descending order.
+func artifactsToKeep(artifacts []*models.Artifact, retention uint32, policy *models.RetentionPolicy, now time.Time) map[string]struct{} {
+	keep := make(map[string]struct{}, len(artifacts))
+	for i, artifact := range artifacts {
+		if i >= int(retention) {
+			break
+		}
+		keep[artifact.ID] = struct{}{}
 	}
 
-	artifacts, err := models.FindArtifacts(q, models.ArtifactFilters{
-		ScheduleID: scheduleID,
-		Status:     models.SuccessBackupStatus,
-	})
-	if err != nil {
-		return nil, 0, err
+	if policy == nil {
+		return keep
+	}
+
+	if policy.MaxAge != 0 {
+		for _, artifact := range artifacts {
+			// PITR artifact is reused by all runs of the task, so its creation time doesn't show its age.
+			if artifact.Mode == models.PITR || now.Sub(artifact.CreatedAt) < policy.MaxAge {
+				keep[artifact.ID] = struct{}{}
+			}
+		}
+	}
+
+	periods := []struct {
+		count  uint32
+		period func(t time.Time) string
+	}{
+		{policy.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{policy.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{policy.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, p := range periods {
+		if p.count == 0 {
+			continue
+		}
+
+		seen := make(map[string]struct{}, p.count)
+		for _, artifact := range artifacts {
+			key := p.period(artifact.CreatedAt.UTC())
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			if len(seen) == int(p.count) {
+				break
+			}
+			seen[key] = struct{}{}
+			keep[artifact.ID] = struct{}{}
+		}
 	}
 
-	return artifacts, retention, nil
+	return keep
 }
@@ -18,7 +18,10 @@ package backup
 
 import (
 	"context"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/stretchr/testify/assert"
@@ -135,7 +138,89 @@ func TestEnsureRetention(t *testing.T) {
 	assert.NoError(t, retentionService.EnforceRetention(ctx, task.ID))
 	assert.Equal(t, 4, countArtifacts())
 
+	toDelete, err := retentionService.ArtifactsToDelete(task.ID, &models.RetentionPolicy{MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, toDelete)
+
 	changeRetention(2)
+	toDelete, err = retentionService.ArtifactsToDelete(task.ID, nil)
+	require.NoError(t, err)
+	assert.Len(t, toDelete, 2)
+	assert.Equal(t, 4, countArtifacts())
+
 	assert.NoError(t, retentionService.EnforceRetention(ctx, task.ID))
 	assert.Equal(t, 2, countArtifacts())
 }
+
+func TestArtifactsToKeep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 5, 16, 12, 0, 0, 0, time.UTC) // Monday
+	// Daily artifacts for the last 60 days, sorted from the newest to the oldest.
+	artifacts := make([]*models.Artifact, 0, 60)
+	for i := 0; i < 60; i++ {
+		artifacts = append(artifacts, &models.Artifact{
+			ID:        strconv.Itoa(i),
+			CreatedAt: now.Add(-time.Duration(i) * 24 * time.Hour),
+		})
+	}
+
+	ids := func(keep map[string]struct{}) []string {
+		res := make([]string, 0, len(keep))
+		for id := range keep {
+			res = append(res, id)
+		}
+		sort.Slice(res, func(i, j int) bool {
+			a, _ := strconv.Atoi(res[i])
+			b, _ := strconv.Atoi(res[j])
+			return a < b
+		})
+		return res
+	}
+
+	t.Run("count", func(t *testing.T) {
+		t.Parallel()
+
+		keep := artifactsToKeep(artifacts, 3, nil, now)
+		assert.Equal(t, []string{"0", "1", "2"}, ids(keep))
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		t.Parallel()
+
+		keep := artifactsToKeep(artifacts, 0, &models.RetentionPolicy{MaxAge: 72 * time.Hour}, now)
+		assert.Equal(t, []string{"0", "1", "2"}, ids(keep))
+	})
+
+	t.Run("zero count with max age", func(t *testing.T) {
+		t.Parallel()
+
+		// zero count-based retention doesn't keep all artifacts when policy is set
+		keep := artifactsToKeep(artifacts, 0, &models.RetentionPolicy{MaxAge: 36 * time.Hour}, now)
+		assert.Equal(t, []string{"0", "1"}, ids(keep))
+		assert.Empty(t, artifactsToKeep(artifacts, 0, &models.RetentionPolicy{MaxAge: time.Hour}, now.Add(24*time.Hour)))
+	})
+
+	t.Run("GFS", func(t *testing.T) {
+		t.Parallel()
+
+		keep := artifactsToKeep(artifacts, 0, &models.RetentionPolicy{Daily: 2, Weekly: 3, Monthly: 2}, now)
+		// daily: 05-16, 05-15; weekly: 05-16 (W20), 05-15 (W19), 05-08 (W18); monthly: 05-16, 04-30.
+		assert.Equal(t, []string{"0", "1", "8", "16"}, ids(keep))
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		t.Parallel()
+
+		keep := artifactsToKeep(artifacts, 1, &models.RetentionPolicy{MaxAge: 48 * time.Hour, Monthly: 3}, now)
+		assert.Equal(t, []string{"0", "1", "16", "46"}, ids(keep))
+	})
+
+	t.Run("max age keeps PITR artifact", func(t *testing.T) {
+		t.Parallel()
+
+		pitr := []*models.Artifact{{ID: "0", Mode: models.PITR, CreatedAt: now.Add(-30 * 24 * time.Hour)}}
+		keep := artifactsToKeep(pitr, 0, &models.RetentionPolicy{MaxAge: 72 * time.Hour}, now)
+		assert.Equal(t, []string{"0"}, ids(keep))
+	})
+}
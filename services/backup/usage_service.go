@@ -0,0 +1,239 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+var locationLabels = []string{"location_id", "name", "type"}
+
+var (
+	mLocationUsageDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "location_usage_bytes"),
+		"Space taken in the backup location when it was computed last time.",
+		locationLabels,
+		nil)
+	mLocationQuotaDesc = prom.NewDesc(
+		prom.BuildFQName(prometheusNamespace, prometheusSubsystem, "location_quota_bytes"),
+		"Quota of the backup location, zero if location has no quota.",
+		locationLabels,
+		nil)
+)
+
+// UsageService computes space taken by artifacts in backup locations and enforces location quotas.
+type UsageService struct {
+	db             *reform.DB
+	s3             s3
+	removalService removalService
+	l              *logrus.Entry
+}
+
+// NewUsageService creates new backup locations usage service.
+func NewUsageService(db *reform.DB, s3 s3, removalService removalService) *UsageService {
+	return &UsageService{
+		db:             db,
+		s3:             s3,
+		removalService: removalService,
+		l:              logrus.WithField("component", "management/backup/usage"),
+	}
+}
+
+// UpdateLocationUsage computes and stores space taken by artifacts and artifact replicas in the backup location.
+// Files of the artifacts are listed in S3 buckets, other files in the location aren't counted.
+// Artifacts are made in S3 locations only, so usage of other locations is unknown and location is returned as is.
+func (s *UsageService) UpdateLocationUsage(ctx context.Context, locationID string) (*models.BackupLocation, error) {
+	location, err := models.FindBackupLocationByID(s.db.Querier, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if location.S3Config == nil {
+		return location, nil
+	}
+
+	artifacts, err := models.FindArtifacts(s.db.Querier, models.ArtifactFilters{LocationID: locationID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Replicas with any status are counted as failed copy may leave some files.
+	replicas, err := models.FindArtifactReplicas(s.db.Querier, models.ArtifactReplicaFilters{LocationID: locationID})
+	if err != nil {
+		return nil, err
+	}
+	for _, replica := range replicas {
+		artifact, err := models.FindArtifactByID(s.db.Querier, replica.ArtifactID)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	sizes, err := s.artifactSizes(ctx, location, artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage int64
+	for _, size := range sizes {
+		usage += size
+	}
+
+	return models.UpdateBackupLocationUsage(s.db.Querier, locationID, usage)
+}
+
+// artifactSizes returns space taken by files of each artifact in the location by artifact ID.
+func (s *UsageService) artifactSizes(ctx context.Context, location *models.BackupLocation, artifacts []*models.Artifact) (map[string]int64, error) {
+	sizes := make(map[string]int64, len(artifacts))
+	for _, a := range artifacts {
+		var size int64
+		switch {
+		case location.S3Config != nil:
+			c := location.S3Config
+			files, err := s.s3.List(ctx, c.Endpoint, c.AccessKey, c.SecretKey, c.BucketName, a.Name+"/", "")
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list files of artifact %q in location %q", a.ID, location.ID)
+			}
+			for _, f := range files {
+				size += f.Size
+			}
+		default:
+			return nil, errors.Errorf("files of location %q can't be accessed", location.ID)
+		}
+		sizes[a.ID] = size
+	}
+
+	return sizes, nil
+}
+
+// UpdateArtifactLocationUsage updates usage of the artifact location and enforces its quota.
+// It's called when a new artifact is made.
+func (s *UsageService) UpdateArtifactLocationUsage(ctx context.Context, artifactID string) error {
+	artifact, err := models.FindArtifactByID(s.db.Querier, artifactID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.EnforceLocationQuota(ctx, artifact.LocationID)
+	return err
+}
+
+// EnforceLocationQuota updates usage of the backup location and removes its oldest artifacts
+// if the quota is exceeded and its action is retention. Location with updated usage is returned.
+func (s *UsageService) EnforceLocationQuota(ctx context.Context, locationID string) (*models.BackupLocation, error) {
+	location, err := s.UpdateLocationUsage(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !location.QuotaExceeded() || location.QuotaAction != models.RetentionQuotaAction {
+		return location, nil
+	}
+
+	if err = s.enforceQuota(ctx, location); err != nil {
+		return nil, err
+	}
+
+	return models.FindBackupLocationByID(s.db.Querier, locationID)
+}
+
+// enforceQuota removes the oldest successful artifacts of the location until its usage fits the quota.
+// The latest artifact of each service is always kept.
+func (s *UsageService) enforceQuota(ctx context.Context, location *models.BackupLocation) error {
+	artifacts, err := models.FindArtifacts(s.db.Querier, models.ArtifactFilters{
+		LocationID: location.ID,
+		Status:     models.SuccessBackupStatus,
+	})
+	if err != nil {
+		return err
+	}
+
+	sizes, err := s.artifactSizes(ctx, location, artifacts)
+	if err != nil {
+		return err
+	}
+
+	usage := location.UsageBytes
+	for _, a := range artifactsToFreeSpace(artifacts, sizes, usage-location.QuotaBytes) {
+		s.l.Infof("Quota of location %q is exceeded, removing artifact %q.", location.ID, a.ID)
+		if err := s.removalService.DeleteArtifact(ctx, a.ID, true); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.UpdateLocationUsage(ctx, location.ID)
+	return err
+}
+
+// artifactsToFreeSpace returns the oldest artifacts which sizes sum is at least the given amount of space.
+// The latest artifact of each service is not returned. Artifacts are expected to be sorted by creation time
+// in descending order, sizes are artifact sizes by artifact ID.
+func artifactsToFreeSpace(artifacts []*models.Artifact, sizes map[string]int64, space int64) []*models.Artifact {
+	latest := make(map[string]struct{}, len(artifacts))
+	candidates := make([]*models.Artifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		if _, ok := latest[a.ServiceID]; !ok {
+			latest[a.ServiceID] = struct{}{}
+			continue
+		}
+		candidates = append(candidates, a)
+	}
+
+	var res []*models.Artifact
+	for i := len(candidates) - 1; i >= 0 && space >= 0; i-- {
+		res = append(res, candidates[i])
+		space -= sizes[candidates[i].ID]
+	}
+
+	return res
+}
+
+// Describe implements prom.Collector.
+func (s *UsageService) Describe(ch chan<- *prom.Desc) {
+	ch <- mLocationUsageDesc
+	ch <- mLocationQuotaDesc
+}
+
+// Collect implements prom.Collector.
+func (s *UsageService) Collect(ch chan<- prom.Metric) {
+	locations, err := models.FindBackupLocations(s.db.Querier)
+	if err != nil {
+		s.l.Errorf("Failed to find backup locations: %s.", err)
+		return
+	}
+
+	for _, l := range locations {
+		labels := []string{l.ID, l.Name, string(l.Type)}
+		if l.UsageUpdatedAt != nil {
+			ch <- prom.MustNewConstMetric(mLocationUsageDesc, prom.GaugeValue, float64(l.UsageBytes), labels...)
+		}
+		ch <- prom.MustNewConstMetric(mLocationQuotaDesc, prom.GaugeValue, float64(l.QuotaBytes), labels...)
+	}
+}
+
+// check interfaces.
+var (
+	_ prom.Collector = (*UsageService)(nil)
+)
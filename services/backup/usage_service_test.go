@@ -0,0 +1,77 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+func TestArtifactsToFreeSpace(t *testing.T) {
+	t.Parallel()
+
+	// sorted by creation time in descending order
+	artifacts := []*models.Artifact{
+		{ID: "a5", ServiceID: "s1"},
+		{ID: "a4", ServiceID: "s2"},
+		{ID: "a3", ServiceID: "s1"},
+		{ID: "a2", ServiceID: "s2"},
+		{ID: "a1", ServiceID: "s1"},
+	}
+	sizes := map[string]int64{"a5": 10, "a4": 20, "a3": 30, "a2": 40, "a1": 50}
+
+	for _, tc := range []struct {
+		name     string
+		space    int64
+		expected []string
+	}{
+		{
+			name:     "nothing",
+			space:    -1,
+			expected: nil,
+		},
+		{
+			name:     "usage equals quota",
+			space:    0,
+			expected: []string{"a1"},
+		},
+		{
+			name:     "oldest",
+			space:    50,
+			expected: []string{"a1", "a2"},
+		},
+		{
+			name:     "latest artifacts are kept",
+			space:    1000,
+			expected: []string{"a1", "a2", "a3"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, a := range artifactsToFreeSpace(artifacts, sizes, tc.space) {
+				actual = append(actual, a.ID)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
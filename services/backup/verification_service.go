@@ -0,0 +1,256 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/go-openapi/strfmt"
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/minio"
+)
+
+const (
+	artifactVerificationAlertName = "pmm_backup_artifact_verification_failed"
+	// artifactVerificationAlertTTL defines how long the alert fires if it is not sent again.
+	artifactVerificationAlertTTL = 24 * time.Hour
+)
+
+// VerificationService checks that files of backup artifacts are present and intact in their locations.
+type VerificationService struct {
+	db           *reform.DB
+	s3           s3
+	alertManager alertManager
+	l            *logrus.Entry
+}
+
+// NewVerificationService creates new backup artifacts verification service.
+func NewVerificationService(db *reform.DB, s3 s3, alertManager alertManager) *VerificationService {
+	return &VerificationService{
+		db:           db,
+		s3:           s3,
+		alertManager: alertManager,
+		l:            logrus.WithField("component", "management/backup/verification"),
+	}
+}
+
+// VerifyArtifactResult contains result of artifact verification.
+type VerifyArtifactResult struct {
+	Artifact *models.Artifact
+	// Problem describes why verification failed, it's empty if verification succeeded.
+	Problem string
+}
+
+// VerifyArtifact checks files of the artifact in its location and records verification result and time on the artifact.
+// Files are checked for presence of the backup tool manifest, and their checksums are compared with the ones
+// recorded by previous successful verifications, see artifactChecksums. Failed verification raises an alert.
+//
+// pmm-agent can't verify artifacts, so only artifacts in S3 locations are verified by pmm-managed itself.
+func (s *VerificationService) VerifyArtifact(ctx context.Context, artifactID string) (*VerifyArtifactResult, error) {
+	artifact, err := models.FindArtifactByID(s.db.Querier, artifactID)
+	switch {
+	case err == nil:
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Artifact with ID %q not found.", artifactID)
+	default:
+		return nil, err
+	}
+
+	if artifact.Status != models.SuccessBackupStatus {
+		return nil, status.Errorf(codes.FailedPrecondition, "Artifact %q status is not successful, status: %q.",
+			artifactID, artifact.Status)
+	}
+
+	location, err := models.FindBackupLocationByID(s.db.Querier, artifact.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.listArtifactFiles(ctx, location, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.UpdateArtifactParams{
+		VerificationStatus: models.VerificationStatusPointer(models.SuccessVerificationStatus),
+		VerifiedAt:         pointer.ToTime(models.Now()),
+	}
+	problem := checkArtifactFiles(artifact, files)
+	if problem == "" {
+		params.FileChecksums = artifactChecksums(artifact, files)
+	} else {
+		params.VerificationStatus = models.VerificationStatusPointer(models.FailedVerificationStatus)
+		s.l.Warnf("Verification of artifact %q failed: %s.", artifactID, problem)
+	}
+
+	artifact, err = models.UpdateArtifact(s.db.Querier, artifactID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if problem != "" {
+		s.alertManager.SendAlerts(ctx, ammodels.PostableAlerts{artifactVerificationFailedAlert(artifact, problem, models.Now())})
+	}
+
+	return &VerifyArtifactResult{
+		Artifact: artifact,
+		Problem:  problem,
+	}, nil
+}
+
+// listArtifactFiles returns files of the artifact with names relative to the artifact directory.
+func (s *VerificationService) listArtifactFiles(ctx context.Context, location *models.BackupLocation, artifact *models.Artifact) ([]minio.FileInfo, error) {
+	switch {
+	case location.S3Config != nil:
+		s3Config := location.S3Config
+		// Slash is appended to avoid matching artifacts which names start with the same prefix.
+		files, err := s.s3.List(ctx, s3Config.Endpoint, s3Config.AccessKey, s3Config.SecretKey, s3Config.BucketName, artifact.Name+"/", "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list files of artifact %q", artifact.ID)
+		}
+		return files, nil
+
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "Artifacts can't be verified in location %q of type %q: "+
+			"its files are not accessible from PMM Server.", location.Name, location.Type)
+	}
+}
+
+// artifactChecksums returns file checksums to record after successful verification, nil to keep the recorded ones.
+// Checksums of snapshot artifacts are recorded once, so files replaced after that are always reported.
+// PITR artifacts get new files over time, so checksums of new files are added to the recorded ones.
+func artifactChecksums(artifact *models.Artifact, files []minio.FileInfo) models.ArtifactFileChecksums {
+	if artifact.Mode != models.PITR && len(artifact.FileChecksums) != 0 {
+		return nil
+	}
+
+	res := make(models.ArtifactFileChecksums, len(files))
+	for name, checksum := range artifact.FileChecksums {
+		res[name] = checksum
+	}
+	for _, f := range files {
+		if _, ok := res[f.Name]; !ok {
+			res[f.Name] = f.ETag
+		}
+	}
+
+	return res
+}
+
+// hasManifest returns true if files contain the metadata file the backup tool writes last,
+// so its absence means an incomplete backup.
+func hasManifest(artifact *models.Artifact, files []minio.FileInfo) bool {
+	for _, f := range files {
+		base := path.Base(f.Name)
+		switch models.ServiceType(artifact.Vendor) {
+		case models.MySQLServiceType:
+			// xbcloud splits files into chunks with numeric suffixes
+			if strings.HasPrefix(base, "xtrabackup_checkpoints") {
+				return true
+			}
+		case models.MongoDBServiceType:
+			if strings.HasSuffix(base, ".pbm.json") {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkArtifactFiles returns description of the problem with artifact files, or empty string if there are none.
+func checkArtifactFiles(artifact *models.Artifact, files []minio.FileInfo) string {
+	if len(files) == 0 {
+		return "artifact files are not found"
+	}
+
+	current := make(map[string]minio.FileInfo, len(files))
+	var size int64
+	for _, f := range files {
+		if f.Size == 0 {
+			return fmt.Sprintf("artifact file %q is empty", f.Name)
+		}
+		size += f.Size
+		current[f.Name] = f
+	}
+
+	if !hasManifest(artifact, files) {
+		return "backup manifest file is not found"
+	}
+
+	// Files of PITR artifacts are added after the artifact is finished, so their size isn't compared.
+	if artifact.Mode != models.PITR && artifact.Size != 0 && artifact.Size != size {
+		return fmt.Sprintf("artifact files size %d doesn't match the size %d recorded after the backup", size, artifact.Size)
+	}
+
+	for name, checksum := range artifact.FileChecksums {
+		f, ok := current[name]
+		if !ok {
+			return fmt.Sprintf("artifact file %q is missing", name)
+		}
+		if checksum != "" && f.ETag != checksum {
+			return fmt.Sprintf("checksum of artifact file %q changed since the previous verification", name)
+		}
+	}
+
+	return ""
+}
+
+// artifactVerificationFailedAlert creates an alert about failed verification of the given artifact.
+func artifactVerificationFailedAlert(artifact *models.Artifact, problem string, now time.Time) *ammodels.PostableAlert {
+	labels := map[string]string{
+		model.AlertNameLabel:  artifactVerificationAlertName,
+		"severity":            "error",
+		"backup_verification": "1",
+		"alert_id":            "/alert_id/backup_verification/" + artifact.ID,
+		"artifact_id":         artifact.ID,
+		"artifact_name":       artifact.Name,
+		"location_id":         artifact.LocationID,
+		"service_id":          artifact.ServiceID,
+		"vendor":              artifact.Vendor,
+		"backup_data_model":   string(artifact.DataModel),
+	}
+
+	endsAt := now.Add(artifactVerificationAlertTTL).UTC().Round(0) // strip a monotonic clock reading
+	return &ammodels.PostableAlert{
+		Alert: ammodels.Alert{
+			Labels: labels,
+		},
+		StartsAt: strfmt.DateTime(now.UTC().Round(0)),
+		EndsAt:   strfmt.DateTime(endsAt),
+		Annotations: map[string]string{
+			"summary": "Backup artifact verification failed",
+			"description": fmt.Sprintf("Backup artifact %q failed verification, it may be impossible to restore it: %s.",
+				artifact.Name, problem),
+		},
+	}
+}
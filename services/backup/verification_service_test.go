@@ -0,0 +1,243 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/minio"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestCheckArtifactFiles(t *testing.T) {
+	t.Parallel()
+
+	files := []minio.FileInfo{
+		{Name: "xtrabackup_checkpoints.00000000000000000000", Size: 10, ETag: "a"},
+		{Name: "backup.xbstream.00000000000000000000", Size: 100, ETag: "b"},
+		{Name: "backup.xbstream.00000000000000000001", Size: 40, ETag: "c"},
+	}
+	mysql := string(models.MySQLServiceType)
+	mongodb := string(models.MongoDBServiceType)
+
+	for _, tc := range []struct {
+		name     string
+		artifact *models.Artifact
+		files    []minio.FileInfo
+		problem  string
+	}{{
+		name:     "ok",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, Size: 150},
+		files:    files,
+	}, {
+		name:     "size is not recorded",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot},
+		files:    files,
+	}, {
+		name:     "no files",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, Size: 150},
+		problem:  "artifact files are not found",
+	}, {
+		name:     "empty file",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, Size: 150},
+		files:    append([]minio.FileInfo{{Name: "xtrabackup_info.00000000000000000000"}}, files...),
+		problem:  `artifact file "xtrabackup_info.00000000000000000000" is empty`,
+	}, {
+		name:     "no manifest",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, Size: 140},
+		files:    files[1:],
+		problem:  "backup manifest file is not found",
+	}, {
+		name:     "mongodb manifest",
+		artifact: &models.Artifact{Vendor: mongodb, Mode: models.Snapshot, Size: 150},
+		files: []minio.FileInfo{
+			{Name: "2022-07-01T10:00:00Z.pbm.json", Size: 50},
+			{Name: "2022-07-01T10:00:00Z/rs0/metadata.json", Size: 100},
+		},
+	}, {
+		name:     "no mongodb manifest",
+		artifact: &models.Artifact{Vendor: mongodb, Mode: models.Snapshot, Size: 150},
+		files:    files,
+		problem:  "backup manifest file is not found",
+	}, {
+		name:     "size mismatch",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, Size: 200},
+		files:    files,
+		problem:  "artifact files size 150 doesn't match the size 200 recorded after the backup",
+	}, {
+		name:     "pitr size is not compared",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.PITR, Size: 100},
+		files:    files,
+	}, {
+		name: "same checksums",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.PITR, FileChecksums: models.ArtifactFileChecksums{
+			"xtrabackup_checkpoints.00000000000000000000": "a",
+			"backup.xbstream.00000000000000000000":        "b",
+		}},
+		files: files,
+	}, {
+		name: "changed checksum",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, FileChecksums: models.ArtifactFileChecksums{
+			"backup.xbstream.00000000000000000001": "d",
+		}},
+		files:   files,
+		problem: `checksum of artifact file "backup.xbstream.00000000000000000001" changed since the previous verification`,
+	}, {
+		name: "missing file",
+		artifact: &models.Artifact{Vendor: mysql, Mode: models.Snapshot, FileChecksums: models.ArtifactFileChecksums{
+			"backup.xbstream.00000000000000000002": "e",
+		}},
+		files:   files,
+		problem: `artifact file "backup.xbstream.00000000000000000002" is missing`,
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.problem, checkArtifactFiles(tc.artifact, tc.files))
+		})
+	}
+}
+
+func TestArtifactChecksums(t *testing.T) {
+	t.Parallel()
+
+	files := []minio.FileInfo{
+		{Name: "rs0/oplog.1", ETag: "a"},
+		{Name: "rs0/oplog.2", ETag: "b"},
+	}
+
+	t.Run("first verification", func(t *testing.T) {
+		t.Parallel()
+
+		actual := artifactChecksums(&models.Artifact{Mode: models.Snapshot}, files)
+		assert.Equal(t, models.ArtifactFileChecksums{"rs0/oplog.1": "a", "rs0/oplog.2": "b"}, actual)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		artifact := &models.Artifact{Mode: models.Snapshot, FileChecksums: models.ArtifactFileChecksums{"rs0/oplog.1": "c"}}
+		assert.Nil(t, artifactChecksums(artifact, files))
+	})
+
+	t.Run("PITR", func(t *testing.T) {
+		t.Parallel()
+
+		artifact := &models.Artifact{Mode: models.PITR, FileChecksums: models.ArtifactFileChecksums{"rs0/oplog.1": "c"}}
+		actual := artifactChecksums(artifact, files)
+		assert.Equal(t, models.ArtifactFileChecksums{"rs0/oplog.1": "c", "rs0/oplog.2": "b"}, actual)
+	})
+}
+
+func TestVerifyArtifact(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+
+	mockedAlertManager := &mockAlertManager{}
+	mockedS3 := &mockS3{}
+	verificationService := NewVerificationService(db, mockedS3, mockedAlertManager)
+
+	agent := setup(t, db.Querier, "test-service")
+	location, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+		Name: "Test location",
+		BackupLocationConfig: models.BackupLocationConfig{
+			S3Config: &models.S3LocationConfig{
+				Endpoint:     "https://s3.us-west-2.amazonaws.com/",
+				AccessKey:    "access_key",
+				SecretKey:    "secret_key",
+				BucketName:   "example_bucket",
+				BucketRegion: "us-east-2",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	artifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+		Name:       "artifact_name",
+		Vendor:     string(models.MySQLServiceType),
+		LocationID: location.ID,
+		ServiceID:  *agent.ServiceID,
+		DataModel:  models.PhysicalDataModel,
+		Mode:       models.Snapshot,
+		Status:     models.SuccessBackupStatus,
+	})
+	require.NoError(t, err)
+
+	listFiles := func(files ...minio.FileInfo) {
+		mockedS3.On("List", ctx, "https://s3.us-west-2.amazonaws.com/", "access_key", "secret_key", "example_bucket",
+			"artifact_name/", "").Return(files, nil).Once()
+	}
+	checkpoints := minio.FileInfo{Name: "xtrabackup_checkpoints", Size: 27, ETag: "a"}
+
+	t.Run("success", func(t *testing.T) {
+		listFiles(checkpoints, minio.FileInfo{Name: "ibdata1", Size: 4, ETag: "b"})
+
+		res, err := verificationService.VerifyArtifact(ctx, artifact.ID)
+		require.NoError(t, err)
+		assert.Empty(t, res.Problem)
+		assert.Equal(t, models.SuccessVerificationStatus, res.Artifact.VerificationStatus)
+		assert.NotNil(t, res.Artifact.VerifiedAt)
+		assert.Equal(t, models.ArtifactFileChecksums{"xtrabackup_checkpoints": "a", "ibdata1": "b"}, res.Artifact.FileChecksums)
+	})
+
+	t.Run("changed file", func(t *testing.T) {
+		listFiles(checkpoints, minio.FileInfo{Name: "ibdata1", Size: 9, ETag: "c"})
+		mockedAlertManager.On("SendAlerts", ctx, mock.Anything).Once()
+
+		res, err := verificationService.VerifyArtifact(ctx, artifact.ID)
+		require.NoError(t, err)
+		assert.Equal(t, `checksum of artifact file "ibdata1" changed since the previous verification`, res.Problem)
+		assert.Equal(t, models.FailedVerificationStatus, res.Artifact.VerificationStatus)
+		assert.Equal(t, models.ArtifactFileChecksums{"xtrabackup_checkpoints": "a", "ibdata1": "b"}, res.Artifact.FileChecksums)
+	})
+
+	t.Run("PMM Server location", func(t *testing.T) {
+		pmmLocation, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+			Name: "PMM Server location",
+			BackupLocationConfig: models.BackupLocationConfig{
+				PMMServerConfig: &models.PMMServerLocationConfig{Path: "/tmp"},
+			},
+		})
+		require.NoError(t, err)
+		pmmArtifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       "pmm_artifact_name",
+			Vendor:     string(models.MySQLServiceType),
+			LocationID: pmmLocation.ID,
+			ServiceID:  *agent.ServiceID,
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     models.SuccessBackupStatus,
+		})
+		require.NoError(t, err)
+
+		_, err = verificationService.VerifyArtifact(ctx, pmmArtifact.ID)
+		tests.AssertGRPCErrorRE(t, codes.FailedPrecondition, `Artifacts can't be verified in location "PMM Server location"`, err)
+	})
+
+	mock.AssertExpectationsForObjects(t, mockedAlertManager, mockedS3)
+}
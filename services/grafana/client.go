@@ -513,6 +513,28 @@ func (c *Client) IsReady(ctx context.Context) error {
 
 const grpcGatewayCookie = "grpcgateway-cookie"
 
+// GetUserLogin returns login of the user who made the request.
+// Empty login is returned for requests authenticated with API keys as they don't belong to any user.
+func (c *Client) GetUserLogin(ctx context.Context) (string, error) {
+	authHeaders, err := c.authHeadersFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if c.isAPIKeyAuth(authHeaders.Get("Authorization")) {
+		return "", nil
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/user", "", authHeaders, nil, &user); err != nil {
+		return "", err
+	}
+
+	return user.Login, nil
+}
+
 type currentUser struct {
 	AccessToken string `json:"access_token"`
 }
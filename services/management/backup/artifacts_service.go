@@ -19,10 +19,14 @@ package backup
 
 import (
 	"context"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	backupv1beta1 "github.com/percona/pmm/api/managementpb/backup"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/reform.v1"
 
@@ -31,19 +35,28 @@ import (
 
 // ArtifactsService represents artifacts API.
 type ArtifactsService struct {
-	l          *logrus.Entry
-	db         *reform.DB
-	removalSVC removalService
+	l               *logrus.Entry
+	db              *reform.DB
+	removalSVC      removalService
+	verificationSVC verificationService
+	replicationSVC  replicationService
 
 	backupv1beta1.UnimplementedArtifactsServer
 }
 
 // NewArtifactsService creates new artifacts API service.
-func NewArtifactsService(db *reform.DB, removalSVC removalService) *ArtifactsService {
+func NewArtifactsService(
+	db *reform.DB,
+	removalSVC removalService,
+	verificationSVC verificationService,
+	replicationSVC replicationService,
+) *ArtifactsService {
 	return &ArtifactsService{
-		l:          logrus.WithField("component", "management/backup/artifacts"),
-		db:         db,
-		removalSVC: removalSVC,
+		l:               logrus.WithField("component", "management/backup/artifacts"),
+		db:              db,
+		removalSVC:      removalSVC,
+		verificationSVC: verificationSVC,
+		replicationSVC:  replicationSVC,
 	}
 }
 
@@ -112,6 +125,175 @@ func (s *ArtifactsService) DeleteArtifact(
 	return &backupv1beta1.DeleteArtifactResponse{}, nil
 }
 
+// VerifyRequest is a Verify JSON API request.
+type VerifyRequest struct {
+	ArtifactID string `json:"artifact_id"`
+}
+
+// VerifyResponse is a Verify JSON API response.
+type VerifyResponse struct {
+	// VerificationStatus is "success" or "failed".
+	VerificationStatus models.VerificationStatus `json:"verification_status"`
+	VerifiedAt         time.Time                 `json:"verified_at"`
+	// Problem describes why verification failed.
+	Problem string `json:"problem,omitempty"`
+}
+
+// Verify checks artifact files in its location and records verification result on the artifact.
+func (s *ArtifactsService) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
+	if req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Artifact ID is required.")
+	}
+
+	res, err := s.verificationSVC.VerifyArtifact(ctx, req.ArtifactID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifyResponse{
+		VerificationStatus: res.Artifact.VerificationStatus,
+		VerifiedAt:         pointer.GetTime(res.Artifact.VerifiedAt),
+		Problem:            res.Problem,
+	}, nil
+}
+
+// ListStatsRequest is a ListStats JSON API request.
+type ListStatsRequest struct {
+	// ServiceID and LocationID optionally filter artifacts.
+	ServiceID  string `json:"service_id"`
+	LocationID string `json:"location_id"`
+}
+
+// ArtifactStats contains statistics of the backup artifact.
+type ArtifactStats struct {
+	ArtifactID string `json:"artifact_id"`
+	ServiceID  string `json:"service_id"`
+	LocationID string `json:"location_id"`
+	// PMMAgentID is the pmm-agent that performed the backup job.
+	PMMAgentID string `json:"pmm_agent_id,omitempty"`
+	// SizeBytes is not set if size is unknown, e.g. for pmm-client locations.
+	SizeBytes  *int64     `json:"size_bytes,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// DurationSeconds is not set if the artifact is not finished or its start time is unknown.
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+}
+
+// ListStatsResponse is a ListStats JSON API response.
+type ListStatsResponse struct {
+	Artifacts []*ArtifactStats `json:"artifacts"`
+}
+
+// ListStats returns size, duration and pmm-agent of backup artifacts, newest first.
+func (s *ArtifactsService) ListStats(ctx context.Context, req *ListStatsRequest) (*ListStatsResponse, error) {
+	artifacts, err := models.FindArtifacts(s.db.Querier, models.ArtifactFilters{
+		ServiceID:  req.ServiceID,
+		LocationID: req.LocationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListStatsResponse{
+		Artifacts: make([]*ArtifactStats, 0, len(artifacts)),
+	}
+	for _, a := range artifacts {
+		res.Artifacts = append(res.Artifacts, convertArtifactStats(a))
+	}
+
+	return res, nil
+}
+
+// ArtifactReplica represents a copy of the artifact in the secondary location in JSON API.
+type ArtifactReplica struct {
+	ReplicaID  string `json:"replica_id"`
+	LocationID string `json:"location_id"`
+	// Status is "pending", "in_progress", "success" or "error".
+	Status models.ReplicaStatus `json:"status"`
+	// Error describes why the copy failed.
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListReplicasRequest is a ListReplicas and Replicate JSON API request.
+type ListReplicasRequest struct {
+	ArtifactID string `json:"artifact_id"`
+}
+
+// ListReplicasResponse is a ListReplicas and Replicate JSON API response.
+type ListReplicasResponse struct {
+	Replicas []*ArtifactReplica `json:"replicas"`
+}
+
+// ListReplicas returns copies of the artifact made by replication rules with their statuses.
+func (s *ArtifactsService) ListReplicas(ctx context.Context, req *ListReplicasRequest) (*ListReplicasResponse, error) {
+	if req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Artifact ID is required.")
+	}
+
+	if _, err := models.FindArtifactByID(s.db.Querier, req.ArtifactID); err != nil {
+		return nil, convertArtifactError(req.ArtifactID, err)
+	}
+
+	return s.listReplicas(req.ArtifactID)
+}
+
+// Replicate copies successful artifact to target locations of the current replication rules of its location.
+// It can be used to retry failed copies or to replicate artifacts made before the rule was added.
+// PITR artifacts can't be replicated, see backup.ReplicationService.ReplicateArtifact.
+// Copy errors are not returned, they are reported in statuses of the returned replicas.
+func (s *ArtifactsService) Replicate(ctx context.Context, req *ListReplicasRequest) (*ListReplicasResponse, error) {
+	if req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Artifact ID is required.")
+	}
+
+	artifact, err := models.FindArtifactByID(s.db.Querier, req.ArtifactID)
+	if err != nil {
+		return nil, convertArtifactError(req.ArtifactID, err)
+	}
+	if artifact.Status != models.SuccessBackupStatus {
+		return nil, status.Errorf(codes.FailedPrecondition, "Artifact with ID %q is not successful, status: %q.", artifact.ID, artifact.Status)
+	}
+	if artifact.Mode == models.PITR {
+		return nil, status.Errorf(codes.FailedPrecondition, "Artifact with ID %q is a PITR artifact, they are not replicated.", artifact.ID)
+	}
+
+	if err = s.replicationSVC.ReplicateArtifact(ctx, artifact.ID); err != nil {
+		s.l.Warnf("Failed to replicate artifact %q: %s.", artifact.ID, err)
+	}
+
+	return s.listReplicas(artifact.ID)
+}
+
+func (s *ArtifactsService) listReplicas(artifactID string) (*ListReplicasResponse, error) {
+	replicas, err := models.FindArtifactReplicas(s.db.Querier, models.ArtifactReplicaFilters{ArtifactID: artifactID})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListReplicasResponse{
+		Replicas: make([]*ArtifactReplica, 0, len(replicas)),
+	}
+	for _, r := range replicas {
+		res.Replicas = append(res.Replicas, &ArtifactReplica{
+			ReplicaID:  r.ID,
+			LocationID: r.LocationID,
+			Status:     r.Status,
+			Error:      r.Error,
+			UpdatedAt:  r.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func convertArtifactError(artifactID string, err error) error {
+	if errors.Is(err, models.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "Artifact with ID %q not found.", artifactID)
+	}
+	return err
+}
+
 func convertDataModel(model models.DataModel) (backupv1beta1.DataModel, error) {
 	switch model {
 	case models.PhysicalDataModel:
@@ -180,6 +362,8 @@ func convertArtifact(
 		return nil, errors.Wrapf(err, "artifact id '%s'", a.ID)
 	}
 
+	// API doesn't have fields for size, duration and pmm-agent of the artifact yet,
+	// they are exposed by ListStats JSON API and as pmm_managed_backup_artifact_* metrics.
 	return &backupv1beta1.Artifact{
 		ArtifactId:   a.ID,
 		Name:         a.Name,
@@ -195,6 +379,25 @@ func convertArtifact(
 	}, nil
 }
 
+func convertArtifactStats(a *models.Artifact) *ArtifactStats {
+	res := &ArtifactStats{
+		ArtifactID: a.ID,
+		ServiceID:  a.ServiceID,
+		LocationID: a.LocationID,
+		PMMAgentID: a.PMMAgentID,
+		StartedAt:  a.StartedAt,
+		FinishedAt: a.FinishedAt,
+	}
+	if a.Size != 0 {
+		res.SizeBytes = pointer.ToInt64(a.Size)
+	}
+	if a.StartedAt != nil && a.FinishedAt != nil {
+		res.DurationSeconds = pointer.ToFloat64(a.Duration().Seconds())
+	}
+
+	return res
+}
+
 // Check interfaces.
 var (
 	_ backupv1beta1.ArtifactsServer = (*ArtifactsService)(nil)
@@ -0,0 +1,148 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestConvertArtifactStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finished", func(t *testing.T) {
+		t.Parallel()
+
+		startedAt := time.Unix(1000, 0)
+		a := &models.Artifact{
+			ID:         "artifact_id",
+			ServiceID:  "service_id",
+			LocationID: "location_id",
+			PMMAgentID: "pmm_agent_id",
+			Size:       1024,
+			StartedAt:  pointer.ToTime(startedAt),
+			FinishedAt: pointer.ToTime(startedAt.Add(time.Minute)),
+		}
+
+		expected := &ArtifactStats{
+			ArtifactID:      "artifact_id",
+			ServiceID:       "service_id",
+			LocationID:      "location_id",
+			PMMAgentID:      "pmm_agent_id",
+			SizeBytes:       pointer.ToInt64(1024),
+			StartedAt:       a.StartedAt,
+			FinishedAt:      a.FinishedAt,
+			DurationSeconds: pointer.ToFloat64(60),
+		}
+		assert.Equal(t, expected, convertArtifactStats(a))
+	})
+
+	t.Run("unknown size and start time", func(t *testing.T) {
+		t.Parallel()
+
+		a := &models.Artifact{
+			ID:         "artifact_id",
+			FinishedAt: pointer.ToTime(time.Unix(1000, 0)),
+		}
+
+		expected := &ArtifactStats{
+			ArtifactID: "artifact_id",
+			FinishedAt: a.FinishedAt,
+		}
+		assert.Equal(t, expected, convertArtifactStats(a))
+	})
+}
+
+func TestReplicate(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	replicationService := &mockReplicationService{}
+	artifactsService := NewArtifactsService(db, nil, nil, replicationService)
+
+	agent := setup(t, db.Querier, t.Name())
+	location, err := models.CreateBackupLocation(db.Querier, models.CreateBackupLocationParams{
+		Name: "Test location",
+		BackupLocationConfig: models.BackupLocationConfig{
+			S3Config: &models.S3LocationConfig{
+				Endpoint:     "https://s3.us-west-2.amazonaws.com/",
+				AccessKey:    "access_key",
+				SecretKey:    "secret_key",
+				BucketName:   "example_bucket",
+				BucketRegion: "us-east-2",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	createArtifact := func(status models.BackupStatus) *models.Artifact {
+		artifact, err := models.CreateArtifact(db.Querier, models.CreateArtifactParams{
+			Name:       "artifact-" + string(status),
+			Vendor:     "mysql",
+			LocationID: location.ID,
+			ServiceID:  *agent.ServiceID,
+			DataModel:  models.PhysicalDataModel,
+			Mode:       models.Snapshot,
+			Status:     status,
+		})
+		require.NoError(t, err)
+		return artifact
+	}
+
+	t.Run("not successful", func(t *testing.T) {
+		artifact := createArtifact(models.PendingBackupStatus)
+
+		res, err := artifactsService.Replicate(ctx, &ListReplicasRequest{ArtifactID: artifact.ID})
+		assert.Nil(t, res)
+		tests.AssertGRPCError(t, status.Newf(codes.FailedPrecondition,
+			"Artifact with ID %q is not successful, status: %q.", artifact.ID, models.PendingBackupStatus), err)
+	})
+
+	t.Run("copy failed", func(t *testing.T) {
+		artifact := createArtifact(models.SuccessBackupStatus)
+		replicationService.On("ReplicateArtifact", ctx, artifact.ID).Return(errors.New("copy failed")).Once()
+
+		res, err := artifactsService.Replicate(ctx, &ListReplicasRequest{ArtifactID: artifact.ID})
+		require.NoError(t, err)
+		assert.Empty(t, res.Replicas)
+		replicationService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		res, err := artifactsService.ListReplicas(ctx, &ListReplicasRequest{ArtifactID: "missing"})
+		assert.Nil(t, res)
+		tests.AssertGRPCError(t, status.New(codes.NotFound, `Artifact with ID "missing" not found.`), err)
+	})
+}
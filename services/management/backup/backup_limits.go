@@ -0,0 +1,156 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+// BlackoutWindow is a recurring period of time during which scheduled tasks are deferred.
+type BlackoutWindow struct {
+	// StartTime is a start time of the window in UTC in HH:MM format.
+	StartTime string           `json:"start_time"`
+	Duration  httpapi.Duration `json:"duration"`
+	// Weekdays limits days the window starts on (0 is Sunday), window starts every day if it's empty.
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+}
+
+// GetBackupLimitsRequest is a GetBackupLimits JSON API request.
+type GetBackupLimitsRequest struct{}
+
+// ChangeBackupLimitsRequest is a ChangeBackupLimits JSON API request, fields which aren't set are left unchanged.
+type ChangeBackupLimitsRequest struct {
+	// Zero removes the limit.
+	MaxConcurrentBackupsPerNode           *uint32           `json:"max_concurrent_backups_per_node,omitempty"`
+	MaxConcurrentBackupsPerReplicationSet *uint32           `json:"max_concurrent_backups_per_replication_set,omitempty"`
+	BlackoutWindows                       []*BlackoutWindow `json:"blackout_windows,omitempty"`
+	RemoveBlackoutWindows                 bool              `json:"remove_blackout_windows"`
+}
+
+// BackupLimitsResponse is a GetBackupLimits and ChangeBackupLimits JSON API response.
+type BackupLimitsResponse struct {
+	MaxConcurrentBackupsPerNode           uint32            `json:"max_concurrent_backups_per_node"`
+	MaxConcurrentBackupsPerReplicationSet uint32            `json:"max_concurrent_backups_per_replication_set"`
+	BlackoutWindows                       []*BlackoutWindow `json:"blackout_windows"`
+}
+
+// GetScheduledBackupStatusRequest is a GetScheduledBackupStatus JSON API request.
+type GetScheduledBackupStatusRequest struct {
+	ScheduledBackupID string `json:"scheduled_backup_id"`
+}
+
+// GetScheduledBackupStatusResponse is a GetScheduledBackupStatus JSON API response.
+type GetScheduledBackupStatusResponse struct {
+	Running bool `json:"running"`
+	// DeferredReason is set while the run is queued because of blackout window or concurrency limits.
+	DeferredReason string `json:"deferred_reason,omitempty"`
+	// Error is an error of the last run.
+	Error string `json:"error,omitempty"`
+}
+
+// GetBackupLimits returns concurrency limits of backups and blackout windows of scheduled tasks.
+func (s *BackupsService) GetBackupLimits(ctx context.Context, req *GetBackupLimitsRequest) (*BackupLimitsResponse, error) {
+	settings, err := models.GetSettings(s.db.Querier)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBackupLimits(settings), nil
+}
+
+// ChangeBackupLimits changes concurrency limits of backups and blackout windows of scheduled tasks.
+func (s *BackupsService) ChangeBackupLimits(ctx context.Context, req *ChangeBackupLimitsRequest) (*BackupLimitsResponse, error) {
+	params := &models.ChangeSettingsParams{
+		MaxConcurrentBackupsPerNode:           req.MaxConcurrentBackupsPerNode,
+		MaxConcurrentBackupsPerReplicationSet: req.MaxConcurrentBackupsPerReplicationSet,
+		RemoveBlackoutWindows:                 req.RemoveBlackoutWindows,
+	}
+	for _, w := range req.BlackoutWindows {
+		params.BlackoutWindows = append(params.BlackoutWindows, models.BlackoutWindow{
+			StartTime: w.StartTime,
+			Duration:  time.Duration(w.Duration),
+			Weekdays:  w.Weekdays,
+		})
+	}
+
+	var settings *models.Settings
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		settings, err = models.UpdateSettings(tx.Querier, params)
+		return err
+	})
+	if errTX != nil {
+		var e *models.ErrInvalidArgument
+		if errors.As(errTX, &e) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid argument: %s.", e.Details)
+		}
+		return nil, errTX
+	}
+
+	return convertBackupLimits(settings), nil
+}
+
+// GetScheduledBackupStatus returns state of the scheduled backup which isn't available via gRPC API yet.
+func (s *BackupsService) GetScheduledBackupStatus(ctx context.Context, req *GetScheduledBackupStatusRequest) (*GetScheduledBackupStatusResponse, error) {
+	if req.ScheduledBackupID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Scheduled backup ID is required.")
+	}
+
+	task, err := models.FindScheduledTaskByID(s.db.Querier, req.ScheduledBackupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Scheduled backup with ID %q not found.", req.ScheduledBackupID)
+		}
+		return nil, err
+	}
+
+	if task.Type != models.ScheduledMySQLBackupTask && task.Type != models.ScheduledMongoDBBackupTask {
+		return nil, status.Errorf(codes.FailedPrecondition, "Scheduled task with ID %q isn't a backup.", task.ID)
+	}
+
+	return &GetScheduledBackupStatusResponse{
+		Running:        task.Running,
+		DeferredReason: task.DeferredReason,
+		Error:          task.Error,
+	}, nil
+}
+
+func convertBackupLimits(settings *models.Settings) *BackupLimitsResponse {
+	res := &BackupLimitsResponse{
+		MaxConcurrentBackupsPerNode:           settings.BackupManagement.MaxConcurrentBackupsPerNode,
+		MaxConcurrentBackupsPerReplicationSet: settings.BackupManagement.MaxConcurrentBackupsPerReplicationSet,
+		BlackoutWindows:                       make([]*BlackoutWindow, 0, len(settings.BackupManagement.BlackoutWindows)),
+	}
+	for _, w := range settings.BackupManagement.BlackoutWindows {
+		res.BlackoutWindows = append(res.BlackoutWindows, &BlackoutWindow{
+			StartTime: w.StartTime,
+			Duration:  httpapi.Duration(w.Duration),
+			Weekdays:  w.Weekdays,
+		})
+	}
+
+	return res
+}
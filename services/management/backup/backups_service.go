@@ -36,14 +36,16 @@ import (
 	"github.com/percona/pmm-managed/services"
 	"github.com/percona/pmm-managed/services/backup"
 	"github.com/percona/pmm-managed/services/scheduler"
+	"github.com/percona/pmm-managed/utils/httpapi"
 )
 
 // BackupsService represents backups API.
 type BackupsService struct {
-	db              *reform.DB
-	backupService   backupService
-	scheduleService scheduleService
-	l               *logrus.Entry
+	db               *reform.DB
+	backupService    backupService
+	scheduleService  scheduleService
+	retentionService retentionService
+	l                *logrus.Entry
 
 	backupv1beta1.UnimplementedBackupsServer
 }
@@ -58,12 +60,14 @@ func NewBackupsService(
 	db *reform.DB,
 	backupService backupService,
 	scheduleService scheduleService,
+	retentionService retentionService,
 ) *BackupsService {
 	return &BackupsService{
-		l:               logrus.WithField("component", "management/backup/backups"),
-		db:              db,
-		backupService:   backupService,
-		scheduleService: scheduleService,
+		l:                logrus.WithField("component", "management/backup/backups"),
+		db:               db,
+		backupService:    backupService,
+		scheduleService:  scheduleService,
+		retentionService: retentionService,
 	}
 }
 
@@ -76,6 +80,9 @@ func convertBackupError(restoreError error) error {
 	switch {
 	case errors.Is(restoreError, backup.ErrIncompatibleService):
 		return status.Error(codes.FailedPrecondition, restoreError.Error())
+	case errors.Is(restoreError, backup.ErrLocationQuotaExceeded),
+		errors.Is(restoreError, backup.ErrBackupLimitReached):
+		return status.Error(codes.ResourceExhausted, restoreError.Error())
 	case errors.Is(restoreError, backup.ErrXtrabackupNotInstalled):
 		code = backupv1beta1.ErrorCode_ERROR_CODE_XTRABACKUP_NOT_INSTALLED
 	case errors.Is(restoreError, backup.ErrInvalidXtrabackup):
@@ -143,8 +150,11 @@ func convertRestoreBackupError(restoreError error) error {
 
 	var code backupv1beta1.ErrorCode
 	switch {
-	case errors.Is(restoreError, backup.ErrIncompatibleService):
+	case errors.Is(restoreError, backup.ErrIncompatibleService),
+		errors.Is(restoreError, backup.ErrIncompatibleArtifactMode):
 		return status.Error(codes.FailedPrecondition, restoreError.Error())
+	case errors.Is(restoreError, backup.ErrTimestampOutOfRange):
+		return status.Error(codes.OutOfRange, restoreError.Error())
 	case errors.Is(restoreError, backup.ErrXtrabackupNotInstalled):
 		code = backupv1beta1.ErrorCode_ERROR_CODE_XTRABACKUP_NOT_INSTALLED
 	case errors.Is(restoreError, backup.ErrInvalidXtrabackup):
@@ -172,7 +182,11 @@ func (s *BackupsService) RestoreBackup(
 	ctx context.Context,
 	req *backupv1beta1.RestoreBackupRequest,
 ) (*backupv1beta1.RestoreBackupResponse, error) {
-	id, err := s.backupService.RestoreBackup(ctx, req.ServiceId, req.ArtifactId)
+	// RestoreBackupRequest doesn't have point-in-time yet, see Restore JSON API for that.
+	id, err := s.backupService.RestoreBackup(ctx, backup.RestoreBackupParams{
+		ServiceID:  req.ServiceId,
+		ArtifactID: req.ArtifactId,
+	})
 	if err != nil {
 		return nil, convertRestoreBackupError(err)
 	}
@@ -182,6 +196,176 @@ func (s *BackupsService) RestoreBackup(
 	}, nil
 }
 
+// RestoreRequest is a Restore JSON API request.
+type RestoreRequest struct {
+	ServiceID  string `json:"service_id"`
+	ArtifactID string `json:"artifact_id"`
+	// PITRTimestamp restores MongoDB PITR artifact to the given point in time, the whole artifact is restored if it's not set.
+	PITRTimestamp *time.Time `json:"pitr_timestamp,omitempty"`
+}
+
+// RestoreResponse is a Restore JSON API response.
+type RestoreResponse struct {
+	RestoreID string `json:"restore_id"`
+}
+
+// Restore starts restore backup job like RestoreBackup does, with the options not available in RestoreBackupRequest yet.
+// Artifact is always restored in place of the service data, as pmm-agent restore jobs have no target directory,
+// so restoring into a fresh target isn't supported. Free space of the target isn't checked before the restore
+// as pmm-agent doesn't report it, see PlanRestore for the space artifact files take.
+func (s *BackupsService) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	if req.ServiceID == "" || req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Service ID and artifact ID are required.")
+	}
+
+	params := backup.RestoreBackupParams{
+		ServiceID:  req.ServiceID,
+		ArtifactID: req.ArtifactID,
+	}
+	if req.PITRTimestamp != nil {
+		params.PITRTimestamp = *req.PITRTimestamp
+	}
+
+	id, err := s.backupService.RestoreBackup(ctx, params)
+	if err != nil {
+		return nil, convertRestoreBackupError(err)
+	}
+
+	return &RestoreResponse{
+		RestoreID: id,
+	}, nil
+}
+
+// PlanRestoreRequest is a PlanRestore JSON API request.
+type PlanRestoreRequest RestoreRequest
+
+// SoftwareVersionCheck is a software version of the service compared with the artifact one.
+type SoftwareVersionCheck struct {
+	Name            models.SoftwareName `json:"name"`
+	ArtifactVersion string              `json:"artifact_version,omitempty"`
+	// ServiceVersion is empty if software isn't installed.
+	ServiceVersion string `json:"service_version,omitempty"`
+}
+
+// PlanRestoreResponse is a PlanRestore JSON API response.
+type PlanRestoreResponse struct {
+	// LocationID is ID of the location artifact would be restored from.
+	LocationID       string                  `json:"location_id"`
+	SoftwareVersions []*SoftwareVersionCheck `json:"software_versions"`
+	// RequiredSpace is the size of artifact files in bytes, it's not set if it's unknown.
+	// It isn't compared with free space of the target, as pmm-agent doesn't report it.
+	RequiredSpace  *int64   `json:"required_space,omitempty"`
+	ServiceRunning bool     `json:"service_running"`
+	Ready          bool     `json:"ready"`
+	Problems       []string `json:"problems"`
+}
+
+// PlanRestore performs restore pre-flight checks without starting the restore.
+// Required space is reported, but it isn't compared with free space of the target and doesn't make the plan not ready.
+func (s *BackupsService) PlanRestore(ctx context.Context, req *PlanRestoreRequest) (*PlanRestoreResponse, error) {
+	if req.ServiceID == "" || req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Service ID and artifact ID are required.")
+	}
+
+	params := backup.RestoreBackupParams{
+		ServiceID:  req.ServiceID,
+		ArtifactID: req.ArtifactID,
+	}
+	if req.PITRTimestamp != nil {
+		params.PITRTimestamp = *req.PITRTimestamp
+	}
+
+	plan, err := s.backupService.PlanRestore(ctx, params)
+	if err != nil {
+		return nil, convertRestoreBackupError(err)
+	}
+
+	softwareVersions := make([]*SoftwareVersionCheck, 0, len(plan.SoftwareVersions))
+	for _, sv := range plan.SoftwareVersions {
+		softwareVersions = append(softwareVersions, &SoftwareVersionCheck{
+			Name:            sv.Name,
+			ArtifactVersion: sv.ArtifactVersion,
+			ServiceVersion:  sv.ServiceVersion,
+		})
+	}
+
+	return &PlanRestoreResponse{
+		LocationID:       plan.LocationID,
+		SoftwareVersions: softwareVersions,
+		RequiredSpace:    plan.RequiredSpace,
+		ServiceRunning:   plan.ServiceRunning,
+		Ready:            plan.Ready(),
+		Problems:         plan.Problems,
+	}, nil
+}
+
+// BackupControlRequest is a PauseBackup, ResumeBackup and CancelBackup JSON API request.
+type BackupControlRequest struct {
+	ArtifactID string `json:"artifact_id"`
+}
+
+// RestoreControlRequest is a PauseRestore, ResumeRestore and CancelRestore JSON API request.
+type RestoreControlRequest struct {
+	RestoreID string `json:"restore_id"`
+}
+
+// ControlResponse is a response of backup and restore control JSON APIs.
+type ControlResponse struct{}
+
+// PauseBackup stops running snapshot backup, it can be resumed later.
+func (s *BackupsService) PauseBackup(ctx context.Context, req *BackupControlRequest) (*ControlResponse, error) {
+	return controlBackup(ctx, req, s.backupService.PauseBackup)
+}
+
+// ResumeBackup starts paused snapshot backup again.
+func (s *BackupsService) ResumeBackup(ctx context.Context, req *BackupControlRequest) (*ControlResponse, error) {
+	return controlBackup(ctx, req, s.backupService.ResumeBackup)
+}
+
+// CancelBackup stops running or paused snapshot backup and removes its partial files.
+func (s *BackupsService) CancelBackup(ctx context.Context, req *BackupControlRequest) (*ControlResponse, error) {
+	return controlBackup(ctx, req, s.backupService.CancelBackup)
+}
+
+// PauseRestore stops running restore, it's started from scratch on resume.
+func (s *BackupsService) PauseRestore(ctx context.Context, req *RestoreControlRequest) (*ControlResponse, error) {
+	return controlRestore(ctx, req, s.backupService.PauseRestore)
+}
+
+// ResumeRestore starts paused restore again.
+func (s *BackupsService) ResumeRestore(ctx context.Context, req *RestoreControlRequest) (*ControlResponse, error) {
+	return controlRestore(ctx, req, s.backupService.ResumeRestore)
+}
+
+// CancelRestore stops running or paused restore.
+func (s *BackupsService) CancelRestore(ctx context.Context, req *RestoreControlRequest) (*ControlResponse, error) {
+	return controlRestore(ctx, req, s.backupService.CancelRestore)
+}
+
+func controlBackup(ctx context.Context, req *BackupControlRequest, f func(context.Context, string) error) (*ControlResponse, error) {
+	if req.ArtifactID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Artifact ID is required.")
+	}
+
+	if err := f(ctx, req.ArtifactID); err != nil {
+		return nil, err
+	}
+
+	return &ControlResponse{}, nil
+}
+
+func controlRestore(ctx context.Context, req *RestoreControlRequest, f func(context.Context, string) error) (*ControlResponse, error) {
+	if req.RestoreID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Restore ID is required.")
+	}
+
+	if err := f(ctx, req.RestoreID); err != nil {
+		return nil, convertRestoreBackupError(err)
+	}
+
+	return &ControlResponse{}, nil
+}
+
 // ScheduleBackup add new backup task to scheduler.
 func (s *BackupsService) ScheduleBackup(ctx context.Context, req *backupv1beta1.ScheduleBackupRequest) (*backupv1beta1.ScheduleBackupResponse, error) {
 	var id string
@@ -210,6 +394,7 @@ func (s *BackupsService) ScheduleBackup(ctx context.Context, req *backupv1beta1.
 			return err
 		}
 
+		// API doesn't allow to set time-based and GFS retention policy yet, only count-based retention is used.
 		backupParams := &scheduler.BackupTaskParams{
 			ServiceID:     req.ServiceId,
 			LocationID:    req.LocationId,
@@ -249,6 +434,8 @@ func (s *BackupsService) ScheduleBackup(ctx context.Context, req *backupv1beta1.
 			t = time.Time{}
 		}
 
+		// API doesn't have catch-up policy yet, so missed runs are skipped until it's changed
+		// by ScheduledTasks/ChangeCatchUpPolicy JSON API.
 		scheduledTask, err := s.scheduleService.Add(task, scheduler.AddParams{
 			CronExpression: req.CronExpression,
 			Disabled:       !req.Enabled,
@@ -413,6 +600,187 @@ func (s *BackupsService) ChangeScheduledBackup(ctx context.Context, req *backupv
 	return &backupv1beta1.ChangeScheduledBackupResponse{}, nil
 }
 
+// RetentionPolicy is a JSON API representation of models.RetentionPolicy.
+type RetentionPolicy struct {
+	MaxAge  httpapi.Duration `json:"max_age,omitempty"`
+	Daily   uint32           `json:"daily,omitempty"`
+	Weekly  uint32           `json:"weekly,omitempty"`
+	Monthly uint32           `json:"monthly,omitempty"`
+}
+
+// ChangeRetentionPolicyRequest is a ChangeRetentionPolicy JSON API request.
+type ChangeRetentionPolicyRequest struct {
+	ScheduledBackupID string `json:"scheduled_backup_id"`
+	// RetentionPolicy is removed if it's not set.
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+}
+
+// ChangeRetentionPolicyResponse is a ChangeRetentionPolicy JSON API response.
+type ChangeRetentionPolicyResponse struct{}
+
+// ChangeRetentionPolicy changes time-based and GFS retention rules of the scheduled backup,
+// they are kept in addition to the count-based retention of ChangeScheduledBackup.
+func (s *BackupsService) ChangeRetentionPolicy(ctx context.Context, req *ChangeRetentionPolicyRequest) (*ChangeRetentionPolicyResponse, error) {
+	policy, err := convertRetentionPolicy(req.RetentionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	errTx := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		scheduledTask, err := models.FindScheduledTaskByID(tx.Querier, req.ScheduledBackupID)
+		switch {
+		case err == nil:
+		case errors.Is(err, models.ErrNotFound):
+			return status.Errorf(codes.NotFound, "Scheduled backup with ID %q not found.", req.ScheduledBackupID)
+		default:
+			return err
+		}
+
+		switch scheduledTask.Type {
+		case models.ScheduledMySQLBackupTask:
+			scheduledTask.Data.MySQLBackupTask.RetentionPolicy = policy
+		case models.ScheduledMongoDBBackupTask:
+			scheduledTask.Data.MongoDBBackupTask.RetentionPolicy = policy
+		default:
+			return status.Errorf(codes.InvalidArgument, "Unknown type: %s", scheduledTask.Type)
+		}
+
+		return s.scheduleService.Update(req.ScheduledBackupID, models.ChangeScheduledTaskParams{
+			Data: scheduledTask.Data,
+		})
+	})
+	if errTx != nil {
+		return nil, errTx
+	}
+
+	return &ChangeRetentionPolicyResponse{}, nil
+}
+
+// GetRetentionPolicyRequest is a GetRetentionPolicy JSON API request.
+type GetRetentionPolicyRequest struct {
+	ScheduledBackupID string `json:"scheduled_backup_id"`
+}
+
+// GetRetentionPolicyResponse is a GetRetentionPolicy JSON API response.
+type GetRetentionPolicyResponse struct {
+	// Retention is the count-based retention of ChangeScheduledBackup, zero disables the count-based rule.
+	// If retention policy is empty too, all artifacts are kept; otherwise only artifacts kept by the policy are.
+	Retention       uint32           `json:"retention"`
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+}
+
+// GetRetentionPolicy returns count-based, time-based and GFS retention rules of the scheduled backup.
+func (s *BackupsService) GetRetentionPolicy(ctx context.Context, req *GetRetentionPolicyRequest) (*GetRetentionPolicyResponse, error) {
+	scheduledTask, err := models.FindScheduledTaskByID(s.db.Querier, req.ScheduledBackupID)
+	switch {
+	case err == nil:
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Scheduled backup with ID %q not found.", req.ScheduledBackupID)
+	default:
+		return nil, err
+	}
+
+	var data *models.CommonBackupTaskData
+	switch scheduledTask.Type {
+	case models.ScheduledMySQLBackupTask:
+		data = &scheduledTask.Data.MySQLBackupTask.CommonBackupTaskData
+	case models.ScheduledMongoDBBackupTask:
+		data = &scheduledTask.Data.MongoDBBackupTask.CommonBackupTaskData
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown type: %s", scheduledTask.Type)
+	}
+
+	res := &GetRetentionPolicyResponse{
+		Retention: data.Retention,
+	}
+	if p := data.RetentionPolicy; !p.IsEmpty() {
+		res.RetentionPolicy = &RetentionPolicy{
+			MaxAge:  httpapi.Duration(p.MaxAge),
+			Daily:   p.Daily,
+			Weekly:  p.Weekly,
+			Monthly: p.Monthly,
+		}
+	}
+
+	return res, nil
+}
+
+// ListArtifactsToDeleteRequest is a ListArtifactsToDelete JSON API request.
+type ListArtifactsToDeleteRequest struct {
+	ScheduledBackupID string `json:"scheduled_backup_id"`
+	// RetentionPolicy is used instead of the current one if it's set, so the policy change can be previewed.
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+}
+
+// ArtifactToDelete is an artifact which is not kept by retention rules.
+type ArtifactToDelete struct {
+	ArtifactID string    `json:"artifact_id"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ListArtifactsToDeleteResponse is a ListArtifactsToDelete JSON API response.
+type ListArtifactsToDeleteResponse struct {
+	Artifacts []*ArtifactToDelete `json:"artifacts"`
+}
+
+// ListArtifactsToDelete returns artifacts of the scheduled backup which will be removed by retention
+// after the next backup. It's a dry-run of retention rules, nothing is removed.
+func (s *BackupsService) ListArtifactsToDelete(ctx context.Context, req *ListArtifactsToDeleteRequest) (*ListArtifactsToDeleteResponse, error) {
+	policy, err := convertRetentionPolicy(req.RetentionPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if req.RetentionPolicy != nil && policy == nil {
+		// empty policy should be previewed as no policy, not as the current one
+		policy = new(models.RetentionPolicy)
+	}
+
+	artifacts, err := s.retentionService.ArtifactsToDelete(req.ScheduledBackupID, policy)
+	switch {
+	case err == nil:
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Scheduled backup with ID %q not found.", req.ScheduledBackupID)
+	default:
+		return nil, err
+	}
+
+	res := &ListArtifactsToDeleteResponse{
+		Artifacts: make([]*ArtifactToDelete, 0, len(artifacts)),
+	}
+	for _, a := range artifacts {
+		res.Artifacts = append(res.Artifacts, &ArtifactToDelete{
+			ArtifactID: a.ID,
+			Name:       a.Name,
+			CreatedAt:  a.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// convertRetentionPolicy converts and validates JSON API retention policy; nil is returned for empty policy.
+func convertRetentionPolicy(p *RetentionPolicy) (*models.RetentionPolicy, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	policy := &models.RetentionPolicy{
+		MaxAge:  time.Duration(p.MaxAge),
+		Daily:   p.Daily,
+		Weekly:  p.Weekly,
+		Monthly: p.Monthly,
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if policy.IsEmpty() {
+		return nil, nil
+	}
+
+	return policy, nil
+}
+
 // RemoveScheduledBackup stops and removes existing scheduled backup task.
 func (s *BackupsService) RemoveScheduledBackup(ctx context.Context, req *backupv1beta1.RemoveScheduledBackupRequest) (*backupv1beta1.RemoveScheduledBackupResponse, error) {
 	task, err := models.FindScheduledTaskByID(s.db.Querier, req.ScheduledBackupId)
@@ -464,6 +832,7 @@ func (s *BackupsService) RemoveScheduledBackup(ctx context.Context, req *backupv
 }
 
 // GetLogs returns logs for artifact.
+// Job progress and streaming of job updates are available via Jobs JSON API, see JobsService.
 func (s *BackupsService) GetLogs(ctx context.Context, req *backupv1beta1.GetLogsRequest) (*backupv1beta1.GetLogsResponse, error) {
 	jobs, err := models.FindJobs(s.db.Querier, models.JobsFilter{
 		ArtifactID: req.ArtifactId,
@@ -569,6 +938,8 @@ func convertTaskToScheduledBackup(task *models.ScheduledTask,
 		scheduledBackup.StartTime = timestamppb.New(task.StartAt)
 	}
 
+	// API doesn't have a field for the reason of deferring the run yet, it's returned by GetScheduledBackupStatus JSON API.
+
 	var commonBackupData models.CommonBackupTaskData
 	switch task.Type {
 	case models.ScheduledMySQLBackupTask:
@@ -38,6 +38,7 @@ import (
 	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services/backup"
 	"github.com/percona/pmm-managed/services/scheduler"
+	"github.com/percona/pmm-managed/utils/httpapi"
 	"github.com/percona/pmm-managed/utils/testdb"
 	"github.com/percona/pmm-managed/utils/tests"
 )
@@ -75,7 +76,7 @@ func TestStartBackupErrors(t *testing.T) {
 	backupService := &mockBackupService{}
 	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
 	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
-	backupSvc := NewBackupsService(db, backupService, nil)
+	backupSvc := NewBackupsService(db, backupService, nil, nil)
 	agent := setup(t, db.Querier, t.Name())
 
 	for _, tc := range []struct {
@@ -127,7 +128,7 @@ func TestStartBackupErrors(t *testing.T) {
 
 func TestRestoreBackupErrors(t *testing.T) {
 	backupService := &mockBackupService{}
-	backupSvc := NewBackupsService(nil, backupService, nil)
+	backupSvc := NewBackupsService(nil, backupService, nil, nil)
 
 	for _, tc := range []struct {
 		testName    string
@@ -157,7 +158,10 @@ func TestRestoreBackupErrors(t *testing.T) {
 	} {
 		t.Run(tc.testName, func(t *testing.T) {
 			backupError := fmt.Errorf("error: %w", tc.backupError)
-			backupService.On("RestoreBackup", mock.Anything, "serviceID1", "artifactID1").
+			backupService.On("RestoreBackup", mock.Anything, backup.RestoreBackupParams{
+				ServiceID:  "serviceID1",
+				ArtifactID: "artifactID1",
+			}).
 				Return("", backupError).Once()
 			ctx := context.Background()
 			resp, err := backupSvc.RestoreBackup(ctx, &backupv1beta1.RestoreBackupRequest{
@@ -184,8 +188,8 @@ func TestScheduledBackups(t *testing.T) {
 
 	backupService := &mockBackupService{}
 	backupService.On("SwitchMongoPITR", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	schedulerService := scheduler.New(db, backupService)
-	backupSvc := NewBackupsService(db, backupService, schedulerService)
+	schedulerService := scheduler.New(db, backupService, nil, nil, nil)
+	backupSvc := NewBackupsService(db, backupService, schedulerService, nil)
 	t.Cleanup(func() {
 		_ = sqlDB.Close()
 	})
@@ -258,6 +262,24 @@ func TestScheduledBackups(t *testing.T) {
 		assert.Equal(t, changeReq.Description.GetValue(), data.Description)
 		assert.Equal(t, changeReq.Retries.GetValue(), data.Retries)
 		assert.Equal(t, changeReq.RetryInterval.AsDuration(), data.RetryInterval)
+
+		policy := &RetentionPolicy{MaxAge: httpapi.Duration(720 * time.Hour), Daily: 7, Weekly: 4, Monthly: 12}
+		_, err = backupSvc.ChangeRetentionPolicy(ctx, &ChangeRetentionPolicyRequest{
+			ScheduledBackupID: task.ID,
+			RetentionPolicy:   policy,
+		})
+		require.NoError(t, err)
+
+		policyRes, err := backupSvc.GetRetentionPolicy(ctx, &GetRetentionPolicyRequest{ScheduledBackupID: task.ID})
+		require.NoError(t, err)
+		assert.Equal(t, &GetRetentionPolicyResponse{RetentionPolicy: policy}, policyRes)
+
+		_, err = backupSvc.ChangeRetentionPolicy(ctx, &ChangeRetentionPolicyRequest{ScheduledBackupID: task.ID})
+		require.NoError(t, err)
+
+		policyRes, err = backupSvc.GetRetentionPolicy(ctx, &GetRetentionPolicyRequest{ScheduledBackupID: task.ID})
+		require.NoError(t, err)
+		assert.Equal(t, &GetRetentionPolicyResponse{}, policyRes)
 	})
 
 	t.Run("list", func(t *testing.T) {
@@ -312,7 +334,7 @@ func TestGetLogs(t *testing.T) {
 	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
 	backupService := &mockBackupService{}
 	schedulerService := &mockScheduleService{}
-	backupSvc := NewBackupsService(db, backupService, schedulerService)
+	backupSvc := NewBackupsService(db, backupService, schedulerService, nil)
 	t.Cleanup(func() {
 		_ = sqlDB.Close()
 	})
@@ -28,6 +28,11 @@ import (
 //go:generate mockery -name=backupService -case=snake -inpkg -testonly
 //go:generate mockery -name=scheduleService -case=snake -inpkg -testonly
 //go:generate mockery -name=removalService -case=snake -inpkg -testonly
+//go:generate mockery -name=retentionService -case=snake -inpkg -testonly
+//go:generate mockery -name=verificationService -case=snake -inpkg -testonly
+//go:generate mockery -name=replicationService -case=snake -inpkg -testonly
+//go:generate mockery -name=usageService -case=snake -inpkg -testonly
+//go:generate mockery -name=jobWatcher -case=snake -inpkg -testonly
 
 type awsS3 interface {
 	GetBucketLocation(ctx context.Context, host string, accessKey, secretKey, name string) (string, error)
@@ -37,7 +42,14 @@ type awsS3 interface {
 
 type backupService interface {
 	PerformBackup(ctx context.Context, params backup.PerformBackupParams) (string, error)
-	RestoreBackup(ctx context.Context, serviceID, artifactID string) (string, error)
+	RestoreBackup(ctx context.Context, params backup.RestoreBackupParams) (string, error)
+	PlanRestore(ctx context.Context, params backup.RestoreBackupParams) (*backup.RestorePlan, error)
+	PauseBackup(ctx context.Context, artifactID string) error
+	ResumeBackup(ctx context.Context, artifactID string) error
+	CancelBackup(ctx context.Context, artifactID string) error
+	PauseRestore(ctx context.Context, restoreID string) error
+	ResumeRestore(ctx context.Context, restoreID string) error
+	CancelRestore(ctx context.Context, restoreID string) error
 	SwitchMongoPITR(ctx context.Context, serviceID string, enabled bool) error
 	FindArtifactCompatibleServices(ctx context.Context, artifactID string) ([]*models.Service, error)
 }
@@ -54,3 +66,24 @@ type scheduleService interface {
 type removalService interface {
 	DeleteArtifact(ctx context.Context, artifactID string, removeFiles bool) error
 }
+
+type retentionService interface {
+	ArtifactsToDelete(scheduleID string, policy *models.RetentionPolicy) ([]*models.Artifact, error)
+}
+
+type verificationService interface {
+	VerifyArtifact(ctx context.Context, artifactID string) (*backup.VerifyArtifactResult, error)
+}
+
+type replicationService interface {
+	ReplicateArtifact(ctx context.Context, artifactID string) error
+}
+
+type usageService interface {
+	UpdateLocationUsage(ctx context.Context, locationID string) (*models.BackupLocation, error)
+	EnforceLocationQuota(ctx context.Context, locationID string) (*models.BackupLocation, error)
+}
+
+type jobWatcher interface {
+	WatchJob(ctx context.Context, jobID string) (<-chan *models.Job, error)
+}
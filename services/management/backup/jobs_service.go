@@ -0,0 +1,155 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// JobsService represents API for progress of backup and restore jobs.
+type JobsService struct {
+	l          *logrus.Entry
+	db         *reform.DB
+	jobWatcher jobWatcher
+}
+
+// NewJobsService creates new jobs API service.
+func NewJobsService(db *reform.DB, jobWatcher jobWatcher) *JobsService {
+	return &JobsService{
+		l:          logrus.WithField("component", "management/backup/jobs"),
+		db:         db,
+		jobWatcher: jobWatcher,
+	}
+}
+
+// JobRequest is a GetJob and WatchJob JSON API request, exactly one field should be set.
+// The latest job of the artifact or the restore is used.
+type JobRequest struct {
+	JobID      string `json:"job_id,omitempty"`
+	ArtifactID string `json:"artifact_id,omitempty"`
+	RestoreID  string `json:"restore_id,omitempty"`
+}
+
+// Job is a GetJob and WatchJob JSON API response.
+type Job struct {
+	JobID string         `json:"job_id"`
+	Type  models.JobType `json:"type"`
+	// State is "queued", "dispatched", "running", "retrying" or "done".
+	State models.JobState `json:"state"`
+	Error string          `json:"error,omitempty"`
+	// Percentage and Phase are rough estimates made from the job log, not measured by pmm-agent.
+	// They are empty until known step of the job is logged, percentage is 100 for successfully finished job only.
+	Percentage   uint32     `json:"percentage"`
+	Phase        string     `json:"phase,omitempty"`
+	LastLogLines []string   `json:"last_log_lines,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// GetJob returns the current state and progress of the job.
+func (s *JobsService) GetJob(ctx context.Context, req *JobRequest) (*Job, error) {
+	job, err := s.findJob(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertJob(job), nil
+}
+
+// WatchJob sends the current state and progress of the job, and then every update of it until the job is done.
+func (s *JobsService) WatchJob(ctx context.Context, req *JobRequest, send func(*Job) error) error {
+	job, err := s.findJob(req)
+	if err != nil {
+		return err
+	}
+
+	updates, err := s.jobWatcher.WatchJob(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+
+	for job := range updates {
+		if err = send(convertJob(job)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *JobsService) findJob(req *JobRequest) (*models.Job, error) {
+	var filter models.JobsFilter
+	switch {
+	case req.JobID != "" && req.ArtifactID == "" && req.RestoreID == "":
+		job, err := models.FindJobByID(s.db.Querier, req.JobID)
+		if err != nil {
+			return nil, err
+		}
+		return job, nil
+	case req.ArtifactID != "" && req.JobID == "" && req.RestoreID == "":
+		filter = models.JobsFilter{
+			ArtifactID: req.ArtifactID,
+			Types:      []models.JobType{models.MySQLBackupJob, models.MongoDBBackupJob},
+		}
+	case req.RestoreID != "" && req.JobID == "" && req.ArtifactID == "":
+		filter = models.JobsFilter{
+			RestoreID: req.RestoreID,
+			Types:     []models.JobType{models.MySQLRestoreBackupJob, models.MongoDBRestoreBackupJob},
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Exactly one of job ID, artifact ID and restore ID should be set.")
+	}
+
+	jobs, err := models.FindJobs(s.db.Querier, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, status.Error(codes.NotFound, "Job was not found.")
+	}
+
+	// jobs are sorted by creation time, the latest goes first
+	return jobs[0], nil
+}
+
+func convertJob(job *models.Job) *Job {
+	res := &Job{
+		JobID:     job.ID,
+		Type:      job.Type,
+		State:     job.State,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+	}
+	if p := job.Progress; p != nil {
+		res.Percentage = p.Percentage
+		res.Phase = p.Phase
+		res.LastLogLines = p.LastLogLines
+		if !p.UpdatedAt.IsZero() {
+			res.UpdatedAt = &p.UpdatedAt
+		}
+	}
+
+	return res
+}
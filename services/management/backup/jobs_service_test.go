@@ -0,0 +1,82 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestJobsService(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid request", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewJobsService(nil, &mockJobWatcher{})
+		for _, req := range []*JobRequest{
+			{},
+			{JobID: "job", ArtifactID: "artifact"},
+			{ArtifactID: "artifact", RestoreID: "restore"},
+		} {
+			_, err := s.GetJob(context.Background(), req)
+			tests.AssertGRPCError(t, status.New(codes.InvalidArgument,
+				"Exactly one of job ID, artifact ID and restore ID should be set."), err)
+		}
+	})
+
+	t.Run("convert job", func(t *testing.T) {
+		t.Parallel()
+
+		createdAt := time.Date(2022, 5, 12, 10, 0, 0, 0, time.UTC)
+		updatedAt := createdAt.Add(time.Minute)
+		job := &models.Job{
+			ID:        "job",
+			Type:      models.MySQLBackupJob,
+			State:     models.RunningJobState,
+			CreatedAt: createdAt,
+		}
+		assert.Equal(t, &Job{
+			JobID:     "job",
+			Type:      models.MySQLBackupJob,
+			State:     models.RunningJobState,
+			CreatedAt: createdAt,
+		}, convertJob(job))
+
+		job.Progress = &models.JobProgress{
+			Percentage:   70,
+			Phase:        "copying non-InnoDB files",
+			LastLogLines: []string{"Starting to backup non-InnoDB tables and files"},
+			UpdatedAt:    updatedAt,
+		}
+		actual := convertJob(job)
+		require.NotNil(t, actual.UpdatedAt)
+		assert.Equal(t, updatedAt, *actual.UpdatedAt)
+		assert.Equal(t, uint32(70), actual.Percentage)
+		assert.Equal(t, "copying non-InnoDB files", actual.Phase)
+		assert.Equal(t, job.Progress.LastLogLines, actual.LastLogLines)
+	})
+}
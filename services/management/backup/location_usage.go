@@ -0,0 +1,123 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// GetLocationUsageRequest is a GetLocationUsage JSON API request.
+type GetLocationUsageRequest struct {
+	LocationID string `json:"location_id"`
+	// Refresh computes usage again instead of returning the cached one.
+	Refresh bool `json:"refresh"`
+}
+
+// SetLocationQuotaRequest is a SetLocationQuota JSON API request.
+type SetLocationQuotaRequest struct {
+	LocationID string `json:"location_id"`
+	// QuotaBytes is the maximum space artifacts can take in the location, zero removes the quota.
+	QuotaBytes int64 `json:"quota_bytes"`
+	// QuotaAction is "block" or "retention".
+	QuotaAction models.QuotaAction `json:"quota_action"`
+}
+
+// LocationUsageResponse is a GetLocationUsage and SetLocationQuota JSON API response.
+type LocationUsageResponse struct {
+	// UsageBytes is not set if usage is unknown, e.g. for pmm-client locations.
+	UsageBytes     *int64             `json:"usage_bytes,omitempty"`
+	UsageUpdatedAt *time.Time         `json:"usage_updated_at,omitempty"`
+	QuotaBytes     int64              `json:"quota_bytes"`
+	QuotaAction    models.QuotaAction `json:"quota_action,omitempty"`
+	QuotaExceeded  bool               `json:"quota_exceeded"`
+}
+
+// GetLocationUsage returns space taken by artifacts in the backup location and its quota.
+func (s *LocationsService) GetLocationUsage(ctx context.Context, req *GetLocationUsageRequest) (*LocationUsageResponse, error) {
+	if req.LocationID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Location ID is required.")
+	}
+
+	var location *models.BackupLocation
+	var err error
+	if req.Refresh {
+		location, err = s.usageSVC.UpdateLocationUsage(ctx, req.LocationID)
+	} else {
+		location, err = models.FindBackupLocationByID(s.db.Querier, req.LocationID)
+	}
+	if err != nil {
+		return nil, convertLocationError(req.LocationID, err)
+	}
+
+	return convertLocationUsage(location), nil
+}
+
+// SetLocationQuota sets quota of the backup location. With retention action, the oldest artifacts are removed
+// if the location already exceeds the new quota.
+func (s *LocationsService) SetLocationQuota(ctx context.Context, req *SetLocationQuotaRequest) (*LocationUsageResponse, error) {
+	if req.LocationID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Location ID is required.")
+	}
+
+	location, err := models.SetBackupLocationQuota(s.db.Querier, req.LocationID, req.QuotaBytes, req.QuotaAction)
+	if err != nil {
+		return nil, convertLocationError(req.LocationID, err)
+	}
+
+	// Quota is applied to the current usage right away instead of waiting for the next backup.
+	if location.QuotaBytes != 0 && location.QuotaAction == models.RetentionQuotaAction {
+		if location, err = s.usageSVC.EnforceLocationQuota(ctx, req.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	return convertLocationUsage(location), nil
+}
+
+func convertLocationError(locationID string, err error) error {
+	var errInvalidArgument *models.ErrInvalidArgument
+	switch {
+	case errors.As(err, &errInvalidArgument):
+		return status.Errorf(codes.InvalidArgument, "Invalid argument: %s.", errInvalidArgument.Details)
+	case errors.Is(err, models.ErrNotFound):
+		return status.Errorf(codes.NotFound, "Location with ID %q not found.", locationID)
+	default:
+		return err
+	}
+}
+
+func convertLocationUsage(location *models.BackupLocation) *LocationUsageResponse {
+	res := &LocationUsageResponse{
+		UsageUpdatedAt: location.UsageUpdatedAt,
+		QuotaBytes:     location.QuotaBytes,
+		QuotaAction:    location.QuotaAction,
+		QuotaExceeded:  location.QuotaExceeded(),
+	}
+	if location.UsageUpdatedAt != nil {
+		usage := location.UsageBytes
+		res.UsageBytes = &usage
+	}
+
+	return res
+}
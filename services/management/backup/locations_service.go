@@ -32,19 +32,21 @@ import (
 
 // LocationsService represents backup locations API.
 type LocationsService struct {
-	db *reform.DB
-	s3 awsS3
-	l  *logrus.Entry
+	db       *reform.DB
+	s3       awsS3
+	usageSVC usageService
+	l        *logrus.Entry
 
 	backupv1beta1.UnimplementedLocationsServer
 }
 
 // NewLocationsService creates new backup locations API service.
-func NewLocationsService(db *reform.DB, s3 awsS3) *LocationsService {
+func NewLocationsService(db *reform.DB, s3 awsS3, usageSVC usageService) *LocationsService {
 	return &LocationsService{
-		l:  logrus.WithField("component", "management/backup/locations"),
-		db: db,
-		s3: s3,
+		l:        logrus.WithField("component", "management/backup/locations"),
+		db:       db,
+		s3:       s3,
+		usageSVC: usageSVC,
 	}
 }
 
@@ -257,6 +259,7 @@ func (s *LocationsService) RemoveLocation(ctx context.Context, req *backupv1beta
 }
 
 func convertLocation(location *models.BackupLocation) (*backupv1beta1.Location, error) {
+	// Location message doesn't have fields for usage and quota yet, see GetLocationUsage and SetLocationQuota JSON APIs.
 	loc := &backupv1beta1.Location{
 		LocationId:  location.ID,
 		Name:        location.Name,
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/brianvoe/gofakeit/v6"
 	backupv1beta1 "github.com/percona/pmm/api/managementpb/backup"
 	"github.com/stretchr/testify/assert"
@@ -44,7 +46,7 @@ func TestCreateBackupLocation(t *testing.T) {
 	mockedS3 := &mockAwsS3{}
 	mockedS3.On("GetBucketLocation", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
 		mock.Anything).Return("us-east-2", nil)
-	svc := NewLocationsService(db, mockedS3)
+	svc := NewLocationsService(db, mockedS3, &mockUsageService{})
 	t.Run("add server config", func(t *testing.T) {
 		loc, err := svc.AddLocation(ctx, &backupv1beta1.AddLocationRequest{
 			Name: gofakeit.Name(),
@@ -109,7 +111,7 @@ func TestListBackupLocations(t *testing.T) {
 	mockedS3 := &mockAwsS3{}
 	mockedS3.On("GetBucketLocation", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
 		mock.Anything).Return("us-east-2", nil)
-	svc := NewLocationsService(db, mockedS3)
+	svc := NewLocationsService(db, mockedS3, &mockUsageService{})
 
 	req1 := &backupv1beta1.AddLocationRequest{
 		Name: gofakeit.Name(),
@@ -186,7 +188,7 @@ func TestChangeBackupLocation(t *testing.T) {
 	mockedS3 := &mockAwsS3{}
 	mockedS3.On("GetBucketLocation", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
 		mock.Anything).Return("us-east-2", nil)
-	svc := NewLocationsService(db, mockedS3)
+	svc := NewLocationsService(db, mockedS3, &mockUsageService{})
 	t.Run("update existing config", func(t *testing.T) {
 		loc, err := svc.AddLocation(ctx, &backupv1beta1.AddLocationRequest{
 			Name: gofakeit.Name(),
@@ -284,7 +286,7 @@ func TestRemoveBackupLocation(t *testing.T) {
 	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
 
 	mockedS3 := &mockAwsS3{}
-	svc := NewLocationsService(db, mockedS3)
+	svc := NewLocationsService(db, mockedS3, &mockUsageService{})
 	req := &backupv1beta1.AddLocationRequest{
 		Name: gofakeit.Name(),
 		PmmClientConfig: &backupv1beta1.PMMClientLocationConfig{
@@ -333,6 +335,55 @@ func TestRemoveBackupLocation(t *testing.T) {
 	assert.EqualError(t, err, `rpc error: code = NotFound desc = Backup location with ID "non-existing" not found.`)
 }
 
+func TestSetLocationQuota(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	usageService := &mockUsageService{}
+	svc := NewLocationsService(db, &mockAwsS3{}, usageService)
+	res, err := svc.AddLocation(ctx, &backupv1beta1.AddLocationRequest{
+		Name:            gofakeit.Name(),
+		PmmServerConfig: &backupv1beta1.PMMServerLocationConfig{Path: "/tmp"},
+	})
+	require.NoError(t, err)
+
+	t.Run("block", func(t *testing.T) {
+		usage, err := svc.SetLocationQuota(ctx, &SetLocationQuotaRequest{
+			LocationID:  res.LocationId,
+			QuotaBytes:  1024,
+			QuotaAction: models.BlockQuotaAction,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1024), usage.QuotaBytes)
+		assert.Equal(t, models.BlockQuotaAction, usage.QuotaAction)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		location := &models.BackupLocation{
+			ID:             res.LocationId,
+			UsageBytes:     512,
+			UsageUpdatedAt: pointer.ToTime(time.Now()),
+			QuotaBytes:     1024,
+			QuotaAction:    models.RetentionQuotaAction,
+		}
+		usageService.On("EnforceLocationQuota", ctx, res.LocationId).Return(location, nil).Once()
+
+		usage, err := svc.SetLocationQuota(ctx, &SetLocationQuotaRequest{
+			LocationID:  res.LocationId,
+			QuotaBytes:  1024,
+			QuotaAction: models.RetentionQuotaAction,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, pointer.ToInt64(512), usage.UsageBytes)
+		assert.False(t, usage.QuotaExceeded)
+		usageService.AssertExpectations(t)
+	})
+}
+
 func TestVerifyBackupLocationValidation(t *testing.T) {
 	ctx := context.Background()
 	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
@@ -342,7 +393,7 @@ func TestVerifyBackupLocationValidation(t *testing.T) {
 	mockedS3.On("BucketExists", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
 		mock.Anything).Return(true, nil)
 
-	svc := NewLocationsService(db, mockedS3)
+	svc := NewLocationsService(db, mockedS3, &mockUsageService{})
 
 	tableTests := []struct {
 		name     string
@@ -8,7 +8,7 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/percona/pmm-managed/models"
-	"github.com/percona/pmm-managed/services/backup"
+	backup "github.com/percona/pmm-managed/services/backup"
 )
 
 // mockBackupService is an autogenerated mock type for the backupService type
@@ -16,6 +16,34 @@ type mockBackupService struct {
 	mock.Mock
 }
 
+// CancelBackup provides a mock function with given fields: ctx, artifactID
+func (_m *mockBackupService) CancelBackup(ctx context.Context, artifactID string) error {
+	ret := _m.Called(ctx, artifactID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, artifactID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelRestore provides a mock function with given fields: ctx, restoreID
+func (_m *mockBackupService) CancelRestore(ctx context.Context, restoreID string) error {
+	ret := _m.Called(ctx, restoreID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, restoreID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindArtifactCompatibleServices provides a mock function with given fields: ctx, artifactID
 func (_m *mockBackupService) FindArtifactCompatibleServices(ctx context.Context, artifactID string) ([]*models.Service, error) {
 	ret := _m.Called(ctx, artifactID)
@@ -39,6 +67,34 @@ func (_m *mockBackupService) FindArtifactCompatibleServices(ctx context.Context,
 	return r0, r1
 }
 
+// PauseBackup provides a mock function with given fields: ctx, artifactID
+func (_m *mockBackupService) PauseBackup(ctx context.Context, artifactID string) error {
+	ret := _m.Called(ctx, artifactID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, artifactID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PauseRestore provides a mock function with given fields: ctx, restoreID
+func (_m *mockBackupService) PauseRestore(ctx context.Context, restoreID string) error {
+	ret := _m.Called(ctx, restoreID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, restoreID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PerformBackup provides a mock function with given fields: ctx, params
 func (_m *mockBackupService) PerformBackup(ctx context.Context, params backup.PerformBackupParams) (string, error) {
 	ret := _m.Called(ctx, params)
@@ -60,20 +116,43 @@ func (_m *mockBackupService) PerformBackup(ctx context.Context, params backup.Pe
 	return r0, r1
 }
 
-// RestoreBackup provides a mock function with given fields: ctx, serviceID, artifactID
-func (_m *mockBackupService) RestoreBackup(ctx context.Context, serviceID string, artifactID string) (string, error) {
-	ret := _m.Called(ctx, serviceID, artifactID)
+// PlanRestore provides a mock function with given fields: ctx, params
+func (_m *mockBackupService) PlanRestore(ctx context.Context, params backup.RestoreBackupParams) (*backup.RestorePlan, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *backup.RestorePlan
+	if rf, ok := ret.Get(0).(func(context.Context, backup.RestoreBackupParams) *backup.RestorePlan); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backup.RestorePlan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, backup.RestoreBackupParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreBackup provides a mock function with given fields: ctx, params
+func (_m *mockBackupService) RestoreBackup(ctx context.Context, params backup.RestoreBackupParams) (string, error) {
+	ret := _m.Called(ctx, params)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, serviceID, artifactID)
+	if rf, ok := ret.Get(0).(func(context.Context, backup.RestoreBackupParams) string); ok {
+		r0 = rf(ctx, params)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, serviceID, artifactID)
+	if rf, ok := ret.Get(1).(func(context.Context, backup.RestoreBackupParams) error); ok {
+		r1 = rf(ctx, params)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,6 +160,34 @@ func (_m *mockBackupService) RestoreBackup(ctx context.Context, serviceID string
 	return r0, r1
 }
 
+// ResumeBackup provides a mock function with given fields: ctx, artifactID
+func (_m *mockBackupService) ResumeBackup(ctx context.Context, artifactID string) error {
+	ret := _m.Called(ctx, artifactID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, artifactID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeRestore provides a mock function with given fields: ctx, restoreID
+func (_m *mockBackupService) ResumeRestore(ctx context.Context, restoreID string) error {
+	ret := _m.Called(ctx, restoreID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, restoreID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SwitchMongoPITR provides a mock function with given fields: ctx, serviceID, enabled
 func (_m *mockBackupService) SwitchMongoPITR(ctx context.Context, serviceID string, enabled bool) error {
 	ret := _m.Called(ctx, serviceID, enabled)
@@ -0,0 +1,135 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// ReplicationRule represents replication rule in JSON API.
+type ReplicationRule struct {
+	ReplicationRuleID string    `json:"replication_rule_id"`
+	SourceLocationID  string    `json:"source_location_id"`
+	TargetLocationID  string    `json:"target_location_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ListReplicationRulesRequest is a ListReplicationRules JSON API request.
+type ListReplicationRulesRequest struct {
+	// SourceLocationID returns only rules replicating specified location, if set.
+	SourceLocationID string `json:"source_location_id"`
+}
+
+// ListReplicationRulesResponse is a ListReplicationRules JSON API response.
+type ListReplicationRulesResponse struct {
+	ReplicationRules []*ReplicationRule `json:"replication_rules"`
+}
+
+// AddReplicationRuleRequest is an AddReplicationRule JSON API request.
+type AddReplicationRuleRequest struct {
+	SourceLocationID string `json:"source_location_id"`
+	TargetLocationID string `json:"target_location_id"`
+}
+
+// AddReplicationRuleResponse is an AddReplicationRule JSON API response.
+type AddReplicationRuleResponse struct {
+	ReplicationRuleID string `json:"replication_rule_id"`
+}
+
+// RemoveReplicationRuleRequest is a RemoveReplicationRule JSON API request.
+type RemoveReplicationRuleRequest struct {
+	ReplicationRuleID string `json:"replication_rule_id"`
+}
+
+// RemoveReplicationRuleResponse is a RemoveReplicationRule JSON API response.
+type RemoveReplicationRuleResponse struct{}
+
+// ListReplicationRules returns a list of replication rules.
+func (s *LocationsService) ListReplicationRules(ctx context.Context, req *ListReplicationRulesRequest) (*ListReplicationRulesResponse, error) {
+	rules, err := models.FindReplicationRules(s.db.Querier, models.ReplicationRuleFilters{SourceLocationID: req.SourceLocationID})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ReplicationRule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, &ReplicationRule{
+			ReplicationRuleID: r.ID,
+			SourceLocationID:  r.SourceLocationID,
+			TargetLocationID:  r.TargetLocationID,
+			CreatedAt:         r.CreatedAt,
+		})
+	}
+
+	return &ListReplicationRulesResponse{ReplicationRules: res}, nil
+}
+
+// AddReplicationRule adds a rule replicating artifacts of the source location to the target location.
+func (s *LocationsService) AddReplicationRule(ctx context.Context, req *AddReplicationRuleRequest) (*AddReplicationRuleResponse, error) {
+	if req.SourceLocationID == "" || req.TargetLocationID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Source and target location IDs are required.")
+	}
+
+	var rule *models.ReplicationRule
+	errTX := s.db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		var err error
+		rule, err = models.CreateReplicationRule(tx.Querier, models.CreateReplicationRuleParams{
+			SourceLocationID: req.SourceLocationID,
+			TargetLocationID: req.TargetLocationID,
+		})
+		return err
+	})
+
+	var errInvalidArgument *models.ErrInvalidArgument
+	switch {
+	case errTX == nil:
+	case errors.As(errTX, &errInvalidArgument):
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid argument: %s.", errInvalidArgument.Details)
+	case errors.Is(errTX, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Location not found: %s.", errTX)
+	default:
+		return nil, errTX
+	}
+
+	return &AddReplicationRuleResponse{ReplicationRuleID: rule.ID}, nil
+}
+
+// RemoveReplicationRule removes replication rule. Already made replicas are kept.
+func (s *LocationsService) RemoveReplicationRule(ctx context.Context, req *RemoveReplicationRuleRequest) (*RemoveReplicationRuleResponse, error) {
+	if req.ReplicationRuleID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Replication rule ID is required.")
+	}
+
+	err := models.RemoveReplicationRule(s.db.Querier, req.ReplicationRuleID)
+	switch {
+	case err == nil:
+	case errors.Is(err, models.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "Replication rule with ID %q not found.", req.ReplicationRuleID)
+	default:
+		return nil, err
+	}
+
+	return &RemoveReplicationRuleResponse{}, nil
+}
@@ -122,7 +122,8 @@ func (s *RestoreHistoryService) ListRestoreHistory(
 func convertRestoreStatus(status models.RestoreStatus) (*backupv1beta1.RestoreStatus, error) {
 	var s backupv1beta1.RestoreStatus
 	switch status {
-	case models.InProgressRestoreStatus:
+	case models.InProgressRestoreStatus,
+		models.PausedRestoreStatus: // API doesn't have paused status yet, paused restore isn't finished.
 		s = backupv1beta1.RestoreStatus_RESTORE_STATUS_IN_PROGRESS
 	case models.SuccessRestoreStatus:
 		s = backupv1beta1.RestoreStatus_RESTORE_STATUS_SUCCESS
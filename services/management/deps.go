@@ -25,6 +25,7 @@ import (
 
 	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services"
+	"github.com/percona/pmm-managed/services/scheduler"
 )
 
 //go:generate mockery -name=agentsRegistry -case=snake -inpkg -testonly
@@ -34,6 +35,7 @@ import (
 //go:generate mockery -name=grafanaClient -case=snake -inpkg -testonly
 //go:generate mockery -name=jobsService -case=snake -inpkg -testonly
 //go:generate mockery -name=connectionChecker -case=snake -inpkg -testonly
+//go:generate mockery -name=scheduleService -case=snake -inpkg -testonly
 
 // agentsRegistry is a subset of methods of agents.Registry used by this package.
 // We use it instead of real type for testing and to avoid dependency cycle.
@@ -93,3 +95,12 @@ type connectionChecker interface {
 type versionCache interface {
 	RequestSoftwareVersionsUpdate()
 }
+
+// scheduleService is a subset of methods of scheduler.Service used by this package.
+// We use it instead of real type for testing.
+type scheduleService interface {
+	Add(task scheduler.Task, params scheduler.AddParams) (*models.ScheduledTask, error)
+	Remove(id string) error
+	Update(id string, params models.ChangeScheduledTaskParams) error
+	FindTaskRunsOnPage(id string, pageIndex, pageSize int) ([]*models.ScheduledTaskRun, int, error)
+}
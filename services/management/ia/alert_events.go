@@ -0,0 +1,396 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/percona-platform/saas/pkg/common"
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services"
+)
+
+const (
+	// alertEventsSyncInterval is an interval of alert lifecycle events detection.
+	// Silence changes made by users are trusted for that interval, as Alertmanager may report stale alert status.
+	alertEventsSyncInterval = time.Minute
+
+	// alertResolveDelay is a duration for which alert should be missing from Alertmanager to be considered resolved.
+	// Alertmanager doesn't persist alerts, so after its restart they are missing until vmalert sends them again.
+	alertResolveDelay = 3 * alertEventsSyncInterval
+
+	// alertEventsCleanupInterval is an interval of old alert events removal.
+	alertEventsCleanupInterval = time.Hour
+)
+
+// RunAlertEvents detects alert lifecycle events (firing, silencing and resolving) and stores them
+// until ctx is canceled. Events of alerts resolved earlier than data retention period are removed.
+func (s *AlertsService) RunAlertEvents(ctx context.Context) {
+	t := time.NewTicker(alertEventsSyncInterval)
+	defer t.Stop()
+
+	var lastCleanup time.Time
+	for {
+		if s.Enabled() {
+			if err := s.syncAlertEvents(ctx); err != nil {
+				s.l.Errorf("Failed to sync alert events: %+v.", err)
+			}
+		}
+
+		if time.Since(lastCleanup) >= alertEventsCleanupInterval {
+			if err := s.removeOldAlertEvents(); err != nil {
+				s.l.Errorf("Failed to remove old alert events: %+v.", err)
+			} else {
+				lastCleanup = time.Now()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			// nothing, continue
+		}
+	}
+}
+
+// removeOldAlertEvents removes events of alerts resolved earlier than data retention period.
+func (s *AlertsService) removeOldAlertEvents() error {
+	settings, err := models.GetSettings(s.db)
+	if err != nil {
+		return err
+	}
+
+	n, err := models.RemoveOldAlertEvents(s.db.Querier, time.Now().Add(-settings.DataRetention))
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		s.l.Infof("Removed %d old alert events.", n)
+	}
+	return nil
+}
+
+// syncAlertEvents compares current Alertmanager alerts with stored events and records the difference.
+// Alert is resolved only if it is missing from Alertmanager for alertResolveDelay.
+func (s *AlertsService) syncAlertEvents(ctx context.Context) error {
+	s.alertEventsMx.Lock()
+	defer s.alertEventsMx.Unlock()
+
+	alerts, err := s.alertManager.GetAlerts(ctx, &services.FilterParams{IsIA: true})
+	if err != nil {
+		return errors.Wrap(err, "failed to get alerts form alertmanager")
+	}
+
+	now := time.Now()
+	missingSince := make(map[string]time.Time)
+	err = s.db.InTransaction(func(tx *reform.TX) error {
+		events, err := models.FindOpenAlertEvents(tx.Querier)
+		if err != nil {
+			return err
+		}
+
+		open := make(map[string]*models.AlertEvent, len(events))
+		for _, e := range events {
+			open[e.AlertID] = e
+		}
+
+		for _, alert := range alerts {
+			id := getAlertID(alert)
+			last, ok := open[id]
+			delete(open, id)
+
+			silenced := len(alert.Status.SilencedBy) != 0
+			var types []models.AlertEventType
+			switch {
+			case !ok:
+				types = append(types, models.FiredAlertEvent)
+				if silenced {
+					types = append(types, models.SilencedAlertEvent)
+				}
+			case last.Username != "" && time.Since(last.CreatedAt) < alertEventsSyncInterval:
+				// user's change may be not visible in alert status yet
+			case silenced && last.Type != models.SilencedAlertEvent:
+				types = append(types, models.SilencedAlertEvent)
+			case !silenced && last.Type == models.SilencedAlertEvent:
+				types = append(types, models.UnsilencedAlertEvent)
+			}
+
+			for _, t := range types {
+				if _, err = models.CreateAlertEvent(tx.Querier, alertEventParams(alert, t)); err != nil {
+					return err
+				}
+			}
+		}
+
+		// alerts which are not returned by Alertmanager for a while are resolved
+		for id, last := range open {
+			since, ok := s.missingSince[id]
+			if !ok {
+				since = now
+			}
+			if now.Sub(since) < alertResolveDelay {
+				missingSince[id] = since
+				continue
+			}
+
+			labels, err := last.GetLabels()
+			if err != nil {
+				return err
+			}
+
+			if _, err = models.CreateAlertEvent(tx.Querier, &models.CreateAlertEventParams{
+				AlertID:  id,
+				RuleID:   last.RuleID,
+				Type:     models.ResolvedAlertEvent,
+				Summary:  last.Summary,
+				Severity: last.Severity,
+				Labels:   labels,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// keep only alerts which are still missing, forget the returned and resolved ones
+	s.missingSince = missingSince
+	return nil
+}
+
+// alertEventParams returns params of the event of given type for the alert.
+func alertEventParams(alert *ammodels.GettableAlert, eventType models.AlertEventType) *models.CreateAlertEventParams {
+	var ruleID string
+	if alertname := alert.Labels["alertname"]; strings.HasPrefix(alertname, "/rule_id/") {
+		ruleID = alertname
+	}
+
+	params := &models.CreateAlertEventParams{
+		AlertID: getAlertID(alert),
+		RuleID:  ruleID,
+		Type:    eventType,
+		Summary: alert.Annotations["summary"],
+		Labels:  alert.Labels,
+	}
+
+	// alerts from user-defined rule files may have no or invalid severity
+	if severity := common.ParseSeverity(alert.Labels["severity"]); severity.Validate() == nil {
+		s := models.Severity(severity)
+		params.Severity = &s
+	}
+
+	return params
+}
+
+// recordUserAlertEvents records events of given type caused by the current user for given alerts.
+func (s *AlertsService) recordUserAlertEvents(ctx context.Context, alerts []*ammodels.GettableAlert, eventType models.AlertEventType, comment string) error {
+	username, err := s.grafanaClient.GetUserLogin(ctx)
+	if err != nil {
+		s.l.Warnf("Failed to get user login: %s.", err)
+	}
+
+	return s.db.InTransaction(func(tx *reform.TX) error {
+		for _, alert := range alerts {
+			params := alertEventParams(alert, eventType)
+			params.Username = username
+			params.Comment = comment
+			if _, err := models.CreateAlertEvent(tx.Querier, params); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AlertEvent is an alert lifecycle event.
+type AlertEvent struct {
+	AlertEventID string                `json:"alert_event_id"`
+	AlertID      string                `json:"alert_id"`
+	RuleID       string                `json:"rule_id,omitempty"`
+	Type         models.AlertEventType `json:"type"`
+	Summary      string                `json:"summary"`
+	Severity     string                `json:"severity,omitempty"`
+	Labels       map[string]string     `json:"labels"`
+	// Username is a login of the user who caused the event, empty for events detected by pmm-managed.
+	Username  string    `json:"username,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AcknowledgeAlertsRequest is an AcknowledgeAlerts JSON API request.
+type AcknowledgeAlertsRequest struct {
+	AlertIDs []string `json:"alert_ids"`
+	Comment  string   `json:"comment"`
+}
+
+// AcknowledgeAlertsResponse is an AcknowledgeAlerts JSON API response.
+type AcknowledgeAlertsResponse struct{}
+
+// ListAlertEventsRequest is a ListAlertEvents JSON API request. All filters are optional.
+type ListAlertEventsRequest struct {
+	AlertID    string                  `json:"alert_id"`
+	RuleID     string                  `json:"rule_id"`
+	Types      []models.AlertEventType `json:"types"`
+	Severities []string                `json:"severities"`
+	Username   string                  `json:"username"`
+	// Return only events created at or after From.
+	From *time.Time `json:"from,omitempty"`
+	// Return only events created before To.
+	To *time.Time `json:"to,omitempty"`
+	// All events are returned if page size is zero.
+	PageIndex int `json:"page_index"`
+	PageSize  int `json:"page_size"`
+}
+
+// ListAlertEventsResponse is a ListAlertEvents JSON API response.
+type ListAlertEventsResponse struct {
+	Events     []*AlertEvent `json:"events"`
+	TotalItems int32         `json:"total_items"`
+	TotalPages int32         `json:"total_pages"`
+}
+
+// AcknowledgeAlerts records acknowledgement of given alerts by the current user.
+func (s *AlertsService) AcknowledgeAlerts(ctx context.Context, req *AcknowledgeAlertsRequest) (*AcknowledgeAlertsResponse, error) {
+	if len(req.AlertIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Alert IDs can't be empty.")
+	}
+
+	alerts, err := s.alertManager.FindAlertsByID(ctx, &services.FilterParams{IsIA: true}, req.AlertIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure alerts are fired before they are acknowledged
+	if err = s.syncAlertEvents(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = s.recordUserAlertEvents(ctx, alerts, models.AcknowledgedAlertEvent, req.Comment); err != nil {
+		return nil, err
+	}
+
+	return &AcknowledgeAlertsResponse{}, nil
+}
+
+// ListAlertEvents returns page of alert lifecycle events satisfying filters, newest first.
+func (s *AlertsService) ListAlertEvents(ctx context.Context, req *ListAlertEventsRequest) (*ListAlertEventsResponse, error) {
+	if req.PageIndex < 0 || req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Page index and size can't be negative.")
+	}
+
+	filters := models.AlertEventsFilter{
+		AlertID:  req.AlertID,
+		RuleID:   req.RuleID,
+		Types:    req.Types,
+		Username: req.Username,
+	}
+	for _, t := range req.Types {
+		switch t {
+		case models.FiredAlertEvent, models.AcknowledgedAlertEvent, models.SilencedAlertEvent,
+			models.UnsilencedAlertEvent, models.ResolvedAlertEvent:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid event type %q.", t)
+		}
+	}
+	for _, v := range req.Severities {
+		severity := common.ParseSeverity(v)
+		if err := severity.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid severity %q.", v)
+		}
+		filters.Severities = append(filters.Severities, models.Severity(severity))
+	}
+	if req.From != nil {
+		filters.From = *req.From
+	}
+	if req.To != nil {
+		filters.To = *req.To
+	}
+
+	var events []*models.AlertEvent
+	var totalItems int
+	errTx := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		events, err = models.FindAlertEvents(tx.Querier, filters, req.PageIndex, req.PageSize)
+		if err != nil {
+			return err
+		}
+
+		totalItems, err = models.CountAlertEvents(tx.Querier, filters)
+		return err
+	})
+	if errTx != nil {
+		return nil, errors.WithStack(errTx)
+	}
+
+	res := &ListAlertEventsResponse{
+		Events:     make([]*AlertEvent, 0, len(events)),
+		TotalItems: int32(totalItems),
+		TotalPages: 1,
+	}
+	if req.PageSize > 0 {
+		res.TotalPages = int32(totalItems / req.PageSize)
+		if totalItems%req.PageSize > 0 {
+			res.TotalPages++
+		}
+	}
+
+	for _, e := range events {
+		event, err := convertAlertEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		res.Events = append(res.Events, event)
+	}
+
+	return res, nil
+}
+
+func convertAlertEvent(e *models.AlertEvent) (*AlertEvent, error) {
+	labels, err := e.GetLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &AlertEvent{
+		AlertEventID: e.ID,
+		AlertID:      e.AlertID,
+		RuleID:       e.RuleID,
+		Type:         e.Type,
+		Summary:      e.Summary,
+		Labels:       labels,
+		Username:     e.Username,
+		Comment:      e.Comment,
+		CreatedAt:    e.CreatedAt,
+	}
+	if e.Severity != nil {
+		res.Severity = common.Severity(*e.Severity).String()
+	}
+
+	return res, nil
+}
@@ -0,0 +1,155 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/percona-platform/saas/pkg/common"
+	"github.com/percona/pmm/api/alertmanager/ammodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestAlertEventParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rule alert", func(t *testing.T) {
+		t.Parallel()
+
+		alert := &ammodels.GettableAlert{
+			Alert: ammodels.Alert{
+				Labels: map[string]string{
+					"alertname": "/rule_id/1",
+					"severity":  "critical",
+				},
+			},
+			Annotations: map[string]string{"summary": "Node is down"},
+			Fingerprint: pointer.ToString("fingerprint"),
+		}
+
+		params := alertEventParams(alert, models.FiredAlertEvent)
+		severity := models.Severity(common.Critical)
+		assert.Equal(t, &models.CreateAlertEventParams{
+			AlertID:  "fingerprint",
+			RuleID:   "/rule_id/1",
+			Type:     models.FiredAlertEvent,
+			Summary:  "Node is down",
+			Severity: &severity,
+			Labels:   alert.Labels,
+		}, params)
+	})
+
+	t.Run("user-defined alert", func(t *testing.T) {
+		t.Parallel()
+
+		alert := &ammodels.GettableAlert{
+			Alert: ammodels.Alert{
+				Labels: map[string]string{"alertname": "HighLoad"},
+			},
+			Fingerprint: pointer.ToString("fingerprint"),
+		}
+
+		params := alertEventParams(alert, models.ResolvedAlertEvent)
+		assert.Empty(t, params.RuleID)
+		assert.Nil(t, params.Severity)
+	})
+}
+
+func TestSyncAlertEvents(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+	t.Cleanup(func() {
+		_, err := db.DeleteFrom(models.AlertEventTable, "")
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+	})
+
+	alert := &ammodels.GettableAlert{
+		Alert: ammodels.Alert{
+			Labels: map[string]string{"alertname": "/rule_id/1"},
+		},
+		Fingerprint: pointer.ToString("fingerprint"),
+		Status:      &ammodels.AlertStatus{},
+	}
+
+	var alerts []*ammodels.GettableAlert
+	alertManager := new(mockAlertManager)
+	alertManager.Test(t)
+	alertManager.On("GetAlerts", ctx, mock.Anything).Return(func(context.Context, *services.FilterParams) []*ammodels.GettableAlert {
+		return alerts
+	}, nil)
+	s := NewAlertsService(db, alertManager, nil, nil)
+
+	eventTypes := func() []models.AlertEventType {
+		events, err := models.FindAlertEvents(db.Querier, models.AlertEventsFilter{AlertID: "fingerprint"}, 0, 0)
+		require.NoError(t, err)
+		res := make([]models.AlertEventType, len(events))
+		for i, e := range events {
+			res[len(events)-1-i] = e.Type
+		}
+		return res
+	}
+
+	alerts = []*ammodels.GettableAlert{alert}
+	require.NoError(t, s.syncAlertEvents(ctx))
+	assert.Equal(t, []models.AlertEventType{models.FiredAlertEvent}, eventTypes())
+
+	// Alertmanager restart: alert is missing for a while and then sent again by vmalert
+	alerts = nil
+	require.NoError(t, s.syncAlertEvents(ctx))
+	alerts = []*ammodels.GettableAlert{alert}
+	require.NoError(t, s.syncAlertEvents(ctx))
+	assert.Equal(t, []models.AlertEventType{models.FiredAlertEvent}, eventTypes())
+	assert.Empty(t, s.missingSince)
+
+	alerts = nil
+	require.NoError(t, s.syncAlertEvents(ctx))
+	assert.Equal(t, []models.AlertEventType{models.FiredAlertEvent}, eventTypes())
+
+	s.missingSince["fingerprint"] = time.Now().Add(-alertResolveDelay)
+	require.NoError(t, s.syncAlertEvents(ctx))
+	assert.Equal(t, []models.AlertEventType{models.FiredAlertEvent, models.ResolvedAlertEvent}, eventTypes())
+	assert.Empty(t, s.missingSince)
+}
+
+func TestListAlertEventsValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewAlertsService(nil, nil, nil, nil)
+
+	_, err := s.ListAlertEvents(ctx, &ListAlertEventsRequest{Types: []models.AlertEventType{"fired", "unknown"}})
+	tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid event type "unknown".`), err)
+
+	_, err = s.ListAlertEvents(ctx, &ListAlertEventsRequest{Severities: []string{"unknown"}})
+	tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Invalid severity "unknown".`), err)
+}
@@ -0,0 +1,216 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+// AlertRoutesService represents integrated alerting routing tree API.
+type AlertRoutesService struct {
+	l            *logrus.Entry
+	db           *reform.DB
+	alertManager alertManager
+}
+
+// NewAlertRoutesService creates new alert routes API service.
+func NewAlertRoutesService(db *reform.DB, alertManager alertManager) *AlertRoutesService {
+	return &AlertRoutesService{
+		l:            logrus.WithField("component", "management/ia/alert_routes"),
+		db:           db,
+		alertManager: alertManager,
+	}
+}
+
+// EscalationStep represents channels notified if alert is still firing and isn't acknowledged (silenced) after delay.
+// Delay is counted from the moment the alert group is created, see models.EscalationStep.
+type EscalationStep struct {
+	ChannelIDs []string         `json:"channel_ids"`
+	Delay      httpapi.Duration `json:"delay"`
+}
+
+// AlertRouteParams are common fields of alert route JSON API requests and responses.
+type AlertRouteParams struct {
+	Name string `json:"name"`
+	// ParentID is empty for top-level routes.
+	ParentID string `json:"parent_id,omitempty"`
+	// Position orders sibling routes, the first matching sibling handles the alert unless it has continue set.
+	Position int  `json:"position"`
+	Enabled  bool `json:"enabled"`
+	// Filters are "=" and "=~" label matchers, see models.AlertRouteMatchers.
+	Filters        []models.Filter  `json:"filters"`
+	GroupBy        []string         `json:"group_by"`
+	GroupWait      httpapi.Duration `json:"group_wait"`
+	GroupInterval  httpapi.Duration `json:"group_interval"`
+	RepeatInterval httpapi.Duration `json:"repeat_interval"`
+	Continue       bool             `json:"continue"`
+	// EscalationSteps are ordered by increasing delay, route without them uses steps of the parent route.
+	EscalationSteps []*EscalationStep `json:"escalation_steps"`
+}
+
+// AlertRoute is a node of the routing tree.
+type AlertRoute struct {
+	AlertRouteID string `json:"alert_route_id"`
+	AlertRouteParams
+}
+
+// ListAlertRoutesRequest is a ListAlertRoutes JSON API request.
+type ListAlertRoutesRequest struct{}
+
+// ListAlertRoutesResponse is a ListAlertRoutes JSON API response.
+type ListAlertRoutesResponse struct {
+	AlertRoutes []*AlertRoute `json:"alert_routes"`
+}
+
+// CreateAlertRouteRequest is a CreateAlertRoute JSON API request.
+type CreateAlertRouteRequest struct {
+	AlertRouteParams
+}
+
+// ChangeAlertRouteRequest is a ChangeAlertRoute JSON API request, all fields of the route are replaced.
+type ChangeAlertRouteRequest struct {
+	AlertRouteID string `json:"alert_route_id"`
+	AlertRouteParams
+}
+
+// RemoveAlertRouteRequest is a RemoveAlertRoute JSON API request.
+type RemoveAlertRouteRequest struct {
+	AlertRouteID string `json:"alert_route_id"`
+}
+
+// RemoveAlertRouteResponse is a RemoveAlertRoute JSON API response.
+type RemoveAlertRouteResponse struct{}
+
+// ListAlertRoutes returns all alert routes.
+func (s *AlertRoutesService) ListAlertRoutes(ctx context.Context, req *ListAlertRoutesRequest) (*ListAlertRoutesResponse, error) {
+	routes, err := models.FindAlertRoutes(s.db.Querier)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListAlertRoutesResponse{
+		AlertRoutes: make([]*AlertRoute, 0, len(routes)),
+	}
+	for _, r := range routes {
+		res.AlertRoutes = append(res.AlertRoutes, convertAlertRoute(r))
+	}
+
+	return res, nil
+}
+
+// CreateAlertRoute creates alert route.
+func (s *AlertRoutesService) CreateAlertRoute(ctx context.Context, req *CreateAlertRouteRequest) (*AlertRoute, error) {
+	var route *models.AlertRoute
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		route, err = models.CreateAlertRoute(tx.Querier, req.modelParams())
+		return err
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	s.alertManager.RequestConfigurationUpdate()
+	return convertAlertRoute(route), nil
+}
+
+// ChangeAlertRoute changes alert route.
+func (s *AlertRoutesService) ChangeAlertRoute(ctx context.Context, req *ChangeAlertRouteRequest) (*AlertRoute, error) {
+	var route *models.AlertRoute
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		route, err = models.ChangeAlertRoute(tx.Querier, req.AlertRouteID, req.modelParams())
+		return err
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	s.alertManager.RequestConfigurationUpdate()
+	return convertAlertRoute(route), nil
+}
+
+// RemoveAlertRoute removes alert route without child routes.
+func (s *AlertRoutesService) RemoveAlertRoute(ctx context.Context, req *RemoveAlertRouteRequest) (*RemoveAlertRouteResponse, error) {
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		return models.RemoveAlertRoute(tx.Querier, req.AlertRouteID)
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	s.alertManager.RequestConfigurationUpdate()
+	return &RemoveAlertRouteResponse{}, nil
+}
+
+func (p *AlertRouteParams) modelParams() *models.AlertRouteParams {
+	res := &models.AlertRouteParams{
+		Name:           p.Name,
+		ParentID:       p.ParentID,
+		Position:       p.Position,
+		Disabled:       !p.Enabled,
+		Filters:        p.Filters,
+		GroupBy:        p.GroupBy,
+		GroupWait:      time.Duration(p.GroupWait),
+		GroupInterval:  time.Duration(p.GroupInterval),
+		RepeatInterval: time.Duration(p.RepeatInterval),
+		Continue:       p.Continue,
+	}
+	for _, step := range p.EscalationSteps {
+		res.EscalationSteps = append(res.EscalationSteps, models.EscalationStep{
+			ChannelIDs: step.ChannelIDs,
+			Delay:      time.Duration(step.Delay),
+		})
+	}
+
+	return res
+}
+
+func convertAlertRoute(r *models.AlertRoute) *AlertRoute {
+	res := &AlertRoute{
+		AlertRouteID: r.ID,
+		AlertRouteParams: AlertRouteParams{
+			Name:            r.Name,
+			ParentID:        pointer.GetString(r.ParentID),
+			Position:        r.Position,
+			Enabled:         !r.Disabled,
+			Filters:         r.Filters,
+			GroupBy:         r.GroupBy,
+			GroupWait:       httpapi.Duration(r.GroupWait),
+			GroupInterval:   httpapi.Duration(r.GroupInterval),
+			RepeatInterval:  httpapi.Duration(r.RepeatInterval),
+			Continue:        r.Continue,
+			EscalationSteps: make([]*EscalationStep, 0, len(r.EscalationSteps)),
+		},
+	}
+	for _, step := range r.EscalationSteps {
+		res.EscalationSteps = append(res.EscalationSteps, &EscalationStep{
+			ChannelIDs: step.ChannelIDs,
+			Delay:      httpapi.Duration(step.Delay),
+		})
+	}
+
+	return res
+}
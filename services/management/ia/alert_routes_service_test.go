@@ -0,0 +1,74 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+func TestConvertAlertRoute(t *testing.T) {
+	t.Parallel()
+
+	route := &models.AlertRoute{
+		ID:        "/route_id/child",
+		Name:      "critical",
+		ParentID:  pointer.ToString("/route_id/parent"),
+		Position:  1,
+		Filters:   models.Filters{{Type: models.Regex, Key: "severity", Val: "critical|emergency"}},
+		GroupBy:   []string{"alertname"},
+		GroupWait: time.Minute,
+		EscalationSteps: models.EscalationSteps{
+			{ChannelIDs: []string{"slack"}},
+			{ChannelIDs: []string{"pagerduty"}, Delay: 30 * time.Minute},
+		},
+	}
+
+	actual := convertAlertRoute(route)
+	assert.Equal(t, &AlertRoute{
+		AlertRouteID: "/route_id/child",
+		AlertRouteParams: AlertRouteParams{
+			Name:      "critical",
+			ParentID:  "/route_id/parent",
+			Position:  1,
+			Enabled:   true,
+			Filters:   []models.Filter{{Type: models.Regex, Key: "severity", Val: "critical|emergency"}},
+			GroupBy:   []string{"alertname"},
+			GroupWait: httpapi.Duration(time.Minute),
+			EscalationSteps: []*EscalationStep{
+				{ChannelIDs: []string{"slack"}},
+				{ChannelIDs: []string{"pagerduty"}, Delay: httpapi.Duration(30 * time.Minute)},
+			},
+		},
+	}, actual)
+
+	assert.Equal(t, &models.AlertRouteParams{
+		Name:            "critical",
+		ParentID:        "/route_id/parent",
+		Position:        1,
+		Filters:         route.Filters,
+		GroupBy:         []string{"alertname"},
+		GroupWait:       time.Minute,
+		EscalationSteps: route.EscalationSteps,
+	}, actual.modelParams())
+}
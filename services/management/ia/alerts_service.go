@@ -21,6 +21,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/percona-platform/saas/pkg/common"
@@ -44,17 +45,25 @@ type AlertsService struct {
 	l                *logrus.Entry
 	alertManager     alertManager
 	templatesService *TemplatesService
+	grafanaClient    grafanaClient
+
+	// alertEventsMx protects alert events sync state and serializes syncs made by API and by sync loop.
+	alertEventsMx sync.Mutex
+	// missingSince contains times since which open alerts are not returned by Alertmanager.
+	missingSince map[string]time.Time
 
 	iav1beta1.UnimplementedAlertsServer
 }
 
 // NewAlertsService creates new alerts API service.
-func NewAlertsService(db *reform.DB, alertManager alertManager, templatesService *TemplatesService) *AlertsService {
+func NewAlertsService(db *reform.DB, alertManager alertManager, templatesService *TemplatesService, grafanaClient grafanaClient) *AlertsService {
 	return &AlertsService{
 		l:                logrus.WithField("component", "management/ia/alerts"),
 		db:               db,
 		alertManager:     alertManager,
 		templatesService: templatesService,
+		grafanaClient:    grafanaClient,
+		missingSince:     make(map[string]time.Time),
 	}
 }
 
@@ -100,6 +109,7 @@ func (s *AlertsService) ListAlerts(ctx context.Context, req *iav1beta1.ListAlert
 		}
 
 		var rule *iav1beta1.Rule
+		var filters models.Filters
 		// Rules files created by user in directory /srv/prometheus/rules/ doesn't have associated rules in DB.
 		// So alertname field will be empty or will keep invalid value. Don't fill rule field in that case.
 		ruleID, ok := alert.Labels["alertname"]
@@ -131,8 +141,9 @@ func (s *AlertsService) ListAlerts(ctx context.Context, req *iav1beta1.ListAlert
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to convert alert rule")
 			}
+			filters = r.Filters
 		}
-		pass, err := satisfiesFilters(alert, rule.Filters)
+		pass, err := satisfiesRuleFilters(alert, filters)
 		if err != nil {
 			return nil, err
 		}
@@ -216,11 +227,37 @@ func satisfiesFilters(alert *ammodels.GettableAlert, filters []*iav1beta1.Filter
 	return true, nil
 }
 
+// satisfiesRuleFilters checks that alert passes rule filters, returns true in case of success.
+// Filters supported by API are checked the same way as before, other ones are checked by models.Filter.
+func satisfiesRuleFilters(alert *ammodels.GettableAlert, filters models.Filters) (bool, error) {
+	for _, f := range filters {
+		var pass bool
+		var err error
+		switch f.Type {
+		case models.Equal, models.Regex:
+			t, _ := convertModelToFilterType(f.Type)
+			pass, err = satisfiesFilters(alert, []*iav1beta1.Filter{{
+				Type:  t,
+				Key:   f.Key,
+				Value: f.Val,
+			}})
+		default:
+			pass, err = f.Match(alert.Labels)
+		}
+		if err != nil || !pass {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 func getAlertID(alert *ammodels.GettableAlert) string {
 	return *alert.Fingerprint
 }
 
 // ToggleAlerts allows to silence/unsilence specified alerts.
+// Scheduled maintenance windows are managed via MaintenanceWindows JSON API, see MaintenanceWindowsService.
 func (s *AlertsService) ToggleAlerts(ctx context.Context, req *iav1beta1.ToggleAlertsRequest) (*iav1beta1.ToggleAlertsResponse, error) {
 	var err error
 	var alerts []*ammodels.GettableAlert
@@ -237,18 +274,34 @@ func (s *AlertsService) ToggleAlerts(ctx context.Context, req *iav1beta1.ToggleA
 		return nil, err
 	}
 
+	if req.Silenced != iav1beta1.BooleanFlag_DO_NOT_CHANGE {
+		// make sure alerts are fired before they are silenced by the user
+		if err = s.syncAlertEvents(ctx); err != nil {
+			s.l.Errorf("Failed to sync alert events: %+v.", err)
+		}
+	}
+
+	var eventType models.AlertEventType
 	switch req.Silenced {
 	case iav1beta1.BooleanFlag_DO_NOT_CHANGE:
 		// nothing
 	case iav1beta1.BooleanFlag_TRUE:
 		err = s.alertManager.SilenceAlerts(ctx, alerts)
+		eventType = models.SilencedAlertEvent
 	case iav1beta1.BooleanFlag_FALSE:
 		err = s.alertManager.UnsilenceAlerts(ctx, alerts)
+		eventType = models.UnsilencedAlertEvent
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	if eventType != "" {
+		if err = s.recordUserAlertEvents(ctx, alerts, eventType, ""); err != nil {
+			s.l.Errorf("Failed to record alert events: %+v.", err)
+		}
+	}
+
 	return &iav1beta1.ToggleAlertsResponse{}, nil
 }
 
@@ -222,6 +222,41 @@ func TestSatisfiesFilters(t *testing.T) {
 	})
 }
 
+func TestSatisfiesRuleFilters(t *testing.T) {
+	t.Parallel()
+
+	alert := &ammodels.GettableAlert{
+		Alert: ammodels.Alert{
+			Labels: map[string]string{
+				"label1": "value1",
+				"label2": "value2",
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name    string
+		filters models.Filters
+		result  bool
+	}{
+		{"no filters", nil, true},
+		{"equal", models.Filters{{Type: models.Equal, Key: "label1", Val: "value1"}}, true},
+		{"regex is not anchored", models.Filters{{Type: models.Regex, Key: "label2", Val: "v.*"}}, true},
+		{"not equal", models.Filters{{Type: models.NotEqual, Key: "label1", Val: "value1"}}, false},
+		{"not regex", models.Filters{{Type: models.NotRegex, Key: "label2", Val: "other.*"}}, true},
+		{"numeric", models.Filters{{Type: models.Greater, Key: "label1", Val: "1"}}, false},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := satisfiesRuleFilters(alert, tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, res)
+		})
+	}
+}
+
 func TestListAlerts(t *testing.T) {
 	ctx := context.Background()
 	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
@@ -273,7 +308,7 @@ func TestListAlerts(t *testing.T) {
 	tmplSvc, err := NewTemplatesService(db)
 	require.NoError(t, err)
 	tmplSvc.CollectTemplates(ctx)
-	svc := NewAlertsService(db, mockAlert, tmplSvc)
+	svc := NewAlertsService(db, mockAlert, tmplSvc, &mockGrafanaClient{})
 
 	findAlerts := func(alerts []*iav1beta1.Alert, alertIDs ...string) bool {
 		if len(alerts) != len(alertIDs) {
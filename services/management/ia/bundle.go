@@ -0,0 +1,601 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/percona-platform/saas/pkg/common"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+	"gopkg.in/yaml.v3"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// bundle represents Integrated Alerting configuration that can be moved between PMM instances:
+// rule templates created via API, notification channels and alert rules.
+// Rules reference channels by their summaries instead of IDs, so summaries and rule names should be unique.
+type bundle struct {
+	Templates []ruleTemplate   `yaml:"templates,omitempty"`
+	Channels  []*bundleChannel `yaml:"channels,omitempty"`
+	Rules     []*bundleRule    `yaml:"rules,omitempty"`
+}
+
+// redactedSecret replaces channels secrets in the bundle exported without them.
+// On import, redacted secrets of existing channels are kept unchanged.
+const redactedSecret = "<redacted>"
+
+// bundleChannel represents notification channel in the bundle.
+type bundleChannel struct {
+	Summary string `yaml:"summary"`
+
+	EmailConfig     *models.EmailConfig     `yaml:"email_config,omitempty"`
+	PagerDutyConfig *models.PagerDutyConfig `yaml:"pagerduty_config,omitempty"`
+	SlackConfig     *models.SlackConfig     `yaml:"slack_config,omitempty"`
+	WebHookConfig   *models.WebHookConfig   `yaml:"webhook_config,omitempty"`
+	OpsGenieConfig  *models.OpsGenieConfig  `yaml:"opsgenie_config,omitempty"`
+	MSTeamsConfig   *models.MSTeamsConfig   `yaml:"msteams_config,omitempty"`
+	TelegramConfig  *models.TelegramConfig  `yaml:"telegram_config,omitempty"`
+
+	NotificationTemplate *models.NotificationTemplate `yaml:"notification_template,omitempty"`
+
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// newBundleChannel converts notification channel to the bundle one.
+// Configurations are shared with the given channel, not copied.
+func newBundleChannel(c *models.Channel) *bundleChannel {
+	return &bundleChannel{
+		Summary:         c.Summary,
+		EmailConfig:     c.EmailConfig,
+		PagerDutyConfig: c.PagerDutyConfig,
+		SlackConfig:     c.SlackConfig,
+		WebHookConfig:   c.WebHookConfig,
+		OpsGenieConfig:  c.OpsGenieConfig,
+		MSTeamsConfig:   c.MSTeamsConfig,
+		TelegramConfig:  c.TelegramConfig,
+
+		NotificationTemplate: c.NotificationTemplate,
+
+		Disabled: c.Disabled,
+	}
+}
+
+// createParams returns params for creating notification channel from the bundle one.
+func (bc *bundleChannel) createParams() *models.CreateChannelParams {
+	return &models.CreateChannelParams{
+		Summary:         bc.Summary,
+		EmailConfig:     bc.EmailConfig,
+		PagerDutyConfig: bc.PagerDutyConfig,
+		SlackConfig:     bc.SlackConfig,
+		WebHookConfig:   bc.WebHookConfig,
+		OpsGenieConfig:  bc.OpsGenieConfig,
+		MSTeamsConfig:   bc.MSTeamsConfig,
+		TelegramConfig:  bc.TelegramConfig,
+
+		NotificationTemplate: bc.NotificationTemplate,
+
+		Disabled: bc.Disabled,
+	}
+}
+
+// changeParams returns params for changing existing notification channel to the bundle one.
+func (bc *bundleChannel) changeParams() *models.ChangeChannelParams {
+	return &models.ChangeChannelParams{
+		Summary:         bc.Summary,
+		EmailConfig:     bc.EmailConfig,
+		PagerDutyConfig: bc.PagerDutyConfig,
+		SlackConfig:     bc.SlackConfig,
+		WebHookConfig:   bc.WebHookConfig,
+		OpsGenieConfig:  bc.OpsGenieConfig,
+		MSTeamsConfig:   bc.MSTeamsConfig,
+		TelegramConfig:  bc.TelegramConfig,
+
+		NotificationTemplate: bc.NotificationTemplate,
+
+		Disabled: bc.Disabled,
+	}
+}
+
+// secrets returns pointers to non-empty secret fields of channel configuration by their paths in the bundle.
+func (bc *bundleChannel) secrets() map[string]*string {
+	res := make(map[string]*string)
+	add := func(path string, p *string) {
+		if *p != "" {
+			res[path] = p
+		}
+	}
+
+	if c := bc.PagerDutyConfig; c != nil {
+		add("pagerduty_config.routing_key", &c.RoutingKey)
+		add("pagerduty_config.service_key", &c.ServiceKey)
+	}
+	if c := bc.WebHookConfig; c != nil && c.HTTPConfig != nil {
+		add("webhook_config.http_config.bearer_token", &c.HTTPConfig.BearerToken)
+		if c.HTTPConfig.BasicAuth != nil {
+			add("webhook_config.http_config.basic_auth.password", &c.HTTPConfig.BasicAuth.Password)
+		}
+		if c.HTTPConfig.TLSConfig != nil {
+			add("webhook_config.http_config.tls_config.key_file_content", &c.HTTPConfig.TLSConfig.KeyFileContent)
+		}
+	}
+	if c := bc.OpsGenieConfig; c != nil {
+		add("opsgenie_config.api_key", &c.APIKey)
+	}
+	if c := bc.MSTeamsConfig; c != nil {
+		// incoming webhook URL contains the token
+		add("msteams_config.webhook_url", &c.WebhookURL)
+	}
+	if c := bc.TelegramConfig; c != nil {
+		add("telegram_config.bot_token", &c.BotToken)
+	}
+
+	return res
+}
+
+// restoreSecrets replaces redacted secrets with the secrets of the current channel with the same summary.
+// current is nil if channel doesn't exist yet.
+func (bc *bundleChannel) restoreSecrets(current *models.Channel) error {
+	var currentSecrets map[string]*string
+	if current != nil {
+		currentSecrets = newBundleChannel(current).secrets()
+	}
+
+	for path, p := range bc.secrets() {
+		if *p != redactedSecret {
+			continue
+		}
+
+		v, ok := currentSecrets[path]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Notification channel %q has redacted %s without current value to keep.", bc.Summary, path)
+		}
+		*p = *v
+	}
+
+	return nil
+}
+
+// bundleRule represents alert rule in the bundle.
+type bundleRule struct {
+	Name              string                            `yaml:"name"`
+	TemplateName      string                            `yaml:"template_name"`
+	Summary           string                            `yaml:"summary"`
+	Disabled          bool                              `yaml:"disabled,omitempty"`
+	ExprTemplate      string                            `yaml:"expr_template"`
+	ParamsDefinitions models.AlertExprParamsDefinitions `yaml:"params_definitions,omitempty"`
+	ParamsValues      models.AlertExprParamsValues      `yaml:"params_values,omitempty"`
+	DefaultFor        time.Duration                     `yaml:"default_for"`
+	For               time.Duration                     `yaml:"for"`
+	DefaultSeverity   common.Severity                   `yaml:"default_severity"`
+	Severity          common.Severity                   `yaml:"severity"`
+	CustomLabels      map[string]string                 `yaml:"custom_labels,omitempty"`
+	Labels            map[string]string                 `yaml:"labels,omitempty"`
+	Annotations       map[string]string                 `yaml:"annotations,omitempty"`
+	RecordingRules    models.RecordingRules             `yaml:"recording_rules,omitempty"`
+	Conditions        models.AlertConditions            `yaml:"conditions,omitempty"`
+	Filters           models.Filters                    `yaml:"filters,omitempty"`
+	Channels          []string                          `yaml:"channels,omitempty"` // channels summaries
+
+	NotificationTemplate *models.NotificationTemplate `yaml:"notification_template,omitempty"`
+}
+
+// ExportBundleRequest is an ExportBundle JSON API request.
+type ExportBundleRequest struct {
+	// IncludeSecrets includes channels secrets (keys, tokens, passwords) as is, they are redacted by default.
+	IncludeSecrets bool `json:"include_secrets"`
+}
+
+// ExportBundleResponse is an ExportBundle JSON API response.
+type ExportBundleResponse struct {
+	// Bundle is a YAML document.
+	Bundle string `json:"bundle"`
+}
+
+// ImportBundleRequest is an ImportBundle JSON API request.
+type ImportBundleRequest struct {
+	// Bundle is a YAML document returned by ExportBundle.
+	Bundle string `json:"bundle"`
+}
+
+// ImportBundleResponse is an ImportBundle JSON API response.
+type ImportBundleResponse struct{}
+
+// ExportBundle returns YAML bundle with rule templates created via API, notification channels and alert rules.
+// Channels secrets are replaced with redactedSecret unless they are explicitly requested.
+func (s *RulesService) ExportBundle(ctx context.Context, req *ExportBundleRequest) (*ExportBundleResponse, error) {
+	templates, err := s.templates.loadTemplatesFromDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []*models.Channel
+	var rules []*models.Rule
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		if channels, err = models.FindChannels(tx.Querier); err != nil {
+			return err
+		}
+		rules, err = models.FindRules(tx.Querier)
+		return err
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	b := &bundle{
+		Templates: make([]ruleTemplate, 0, len(templates)),
+		Channels:  make([]*bundleChannel, 0, len(channels)),
+		Rules:     make([]*bundleRule, 0, len(rules)),
+	}
+
+	for _, t := range templates {
+		b.Templates = append(b.Templates, t.ruleTemplate)
+	}
+	sort.Slice(b.Templates, func(i, j int) bool { return b.Templates[i].Name < b.Templates[j].Name })
+
+	summaries := make(map[string]string, len(channels))
+	for _, c := range channels {
+		if _, ok := summaries[c.Summary]; ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "Notification channel summary %q is not unique.", c.Summary)
+		}
+		summaries[c.Summary] = c.ID
+
+		// channels are loaded for export only, so their configurations are redacted in place
+		bc := newBundleChannel(c)
+		if !req.IncludeSecrets {
+			for _, p := range bc.secrets() {
+				*p = redactedSecret
+			}
+		}
+		b.Channels = append(b.Channels, bc)
+	}
+	sort.Slice(b.Channels, func(i, j int) bool { return b.Channels[i].Summary < b.Channels[j].Summary })
+
+	channelSummaries := make(map[string]string, len(channels))
+	for summary, id := range summaries {
+		channelSummaries[id] = summary
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if _, ok := names[r.Name]; ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "Alert rule name %q is not unique.", r.Name)
+		}
+		names[r.Name] = struct{}{}
+
+		br, err := convertRuleToBundle(r, channelSummaries)
+		if err != nil {
+			return nil, err
+		}
+		b.Rules = append(b.Rules, br)
+	}
+	sort.Slice(b.Rules, func(i, j int) bool { return b.Rules[i].Name < b.Rules[j].Name })
+
+	res, err := yaml.Marshal(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal bundle")
+	}
+
+	return &ExportBundleResponse{Bundle: string(res)}, nil
+}
+
+// convertRuleToBundle converts alert rule to the bundle one, replacing channels IDs with summaries.
+func convertRuleToBundle(r *models.Rule, channelSummaries map[string]string) (*bundleRule, error) {
+	br := &bundleRule{
+		Name:              r.Name,
+		TemplateName:      r.TemplateName,
+		Summary:           r.Summary,
+		Disabled:          r.Disabled,
+		ExprTemplate:      r.ExprTemplate,
+		ParamsDefinitions: r.ParamsDefinitions,
+		ParamsValues:      r.ParamsValues,
+		DefaultFor:        r.DefaultFor,
+		For:               r.For,
+		DefaultSeverity:   common.Severity(r.DefaultSeverity),
+		Severity:          common.Severity(r.Severity),
+		RecordingRules:    r.RecordingRules,
+		Conditions:        r.Conditions,
+		Filters:           r.Filters,
+		Channels:          make([]string, 0, len(r.ChannelIDs)),
+
+		NotificationTemplate: r.NotificationTemplate,
+	}
+
+	var err error
+	if br.CustomLabels, err = r.GetCustomLabels(); err != nil {
+		return nil, err
+	}
+	if br.Labels, err = r.GetLabels(); err != nil {
+		return nil, err
+	}
+	if br.Annotations, err = r.GetAnnotations(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range r.ChannelIDs {
+		summary, ok := channelSummaries[id]
+		if !ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "Alert rule %q references unknown notification channel %q.", r.Name, id)
+		}
+		br.Channels = append(br.Channels, summary)
+	}
+
+	return br, nil
+}
+
+// ImportBundle creates or updates rule templates, notification channels and alert rules from the given YAML bundle.
+// Templates are matched by name, channels by summary, and rules by name; existing objects that
+// are not present in the bundle are not changed. Redacted secrets of existing channels are kept.
+func (s *RulesService) ImportBundle(ctx context.Context, req *ImportBundleRequest) (*ImportBundleResponse, error) {
+	var b bundle
+	d := yaml.NewDecoder(strings.NewReader(req.Bundle))
+	d.KnownFields(true)
+	if err := d.Decode(&b); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to parse bundle: %s.", err)
+	}
+
+	templateNames := make(map[string]struct{}, len(b.Templates))
+	for i := range b.Templates {
+		if _, ok := templateNames[b.Templates[i].Name]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Rule template name %q is not unique in bundle.", b.Templates[i].Name)
+		}
+		templateNames[b.Templates[i].Name] = struct{}{}
+
+		if err := validateUserTemplate(&b.Templates[i]); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s.", err)
+		}
+	}
+
+	// validate rules before opening transaction, as vmalert calls may be slow
+	for _, br := range b.Rules {
+		params := br.createParams()
+		if err := validateBundleRule(params); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid alert rule %q: %s", br.Name, status.Convert(err).Message())
+		}
+		if err := s.validateNewRule(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		if err := importBundleTemplates(tx.Querier, b.Templates); err != nil {
+			return err
+		}
+
+		channelIDs, err := importBundleChannels(tx.Querier, b.Channels)
+		if err != nil {
+			return err
+		}
+
+		return importBundleRules(tx.Querier, b.Rules, channelIDs)
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	s.templates.CollectTemplates(ctx)
+	s.updateConfigurations()
+
+	return &ImportBundleResponse{}, nil
+}
+
+// importBundleTemplates creates or updates rule templates.
+func importBundleTemplates(q *reform.Querier, templates []ruleTemplate) error {
+	for i := range templates {
+		t := &templates[i]
+
+		// store template the same way as it is created via API
+		y, err := yaml.Marshal(map[string][]ruleTemplate{"templates": {*t}})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal rule template")
+		}
+
+		_, err = models.FindTemplateByName(q, t.Name)
+		switch status.Code(err) {
+		case codes.OK:
+			_, err = models.ChangeTemplate(q, &models.ChangeTemplateParams{
+				Template:       &t.Template,
+				RecordingRules: t.RecordingRules,
+				Conditions:     t.Conditions,
+				Name:           t.Name,
+				Yaml:           string(y),
+			})
+		case codes.NotFound:
+			_, err = models.CreateTemplate(q, &models.CreateTemplateParams{
+				Template:       &t.Template,
+				RecordingRules: t.RecordingRules,
+				Conditions:     t.Conditions,
+				Yaml:           string(y),
+				Source:         models.UserAPISource,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importBundleChannels creates or updates notification channels.
+// It returns IDs of all existing channels by their summaries.
+func importBundleChannels(q *reform.Querier, channels []*bundleChannel) (map[string]string, error) {
+	existing, err := models.FindChannels(q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(existing)+len(channels))
+	current := make(map[string]*models.Channel, len(existing))
+	notUnique := make(map[string]struct{})
+	for _, c := range existing {
+		if _, ok := res[c.Summary]; ok {
+			notUnique[c.Summary] = struct{}{}
+		}
+		res[c.Summary] = c.ID
+		current[c.Summary] = c
+	}
+
+	imported := make(map[string]struct{}, len(channels))
+	for _, bc := range channels {
+		if _, ok := imported[bc.Summary]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Notification channel summary %q is not unique in bundle.", bc.Summary)
+		}
+		imported[bc.Summary] = struct{}{}
+
+		if _, ok := notUnique[bc.Summary]; ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "Notification channel summary %q is not unique.", bc.Summary)
+		}
+
+		if err = bc.restoreSecrets(current[bc.Summary]); err != nil {
+			return nil, err
+		}
+
+		var c *models.Channel
+		if id, ok := res[bc.Summary]; ok {
+			c, err = models.ChangeChannel(q, id, bc.changeParams())
+		} else {
+			c, err = models.CreateChannel(q, bc.createParams())
+		}
+		if err != nil {
+			return nil, err
+		}
+		res[c.Summary] = c.ID
+	}
+
+	return res, nil
+}
+
+// importBundleRules creates or updates alert rules, resolving channels summaries with given IDs.
+func importBundleRules(q *reform.Querier, rules []*bundleRule, channelIDs map[string]string) error {
+	existing, err := models.FindRules(q)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]string, len(existing))
+	notUnique := make(map[string]struct{})
+	for _, r := range existing {
+		if _, ok := ids[r.Name]; ok {
+			notUnique[r.Name] = struct{}{}
+		}
+		ids[r.Name] = r.ID
+	}
+
+	imported := make(map[string]struct{}, len(rules))
+	for _, br := range rules {
+		if _, ok := imported[br.Name]; ok {
+			return status.Errorf(codes.InvalidArgument, "Alert rule name %q is not unique in bundle.", br.Name)
+		}
+		imported[br.Name] = struct{}{}
+
+		if _, ok := notUnique[br.Name]; ok {
+			return status.Errorf(codes.FailedPrecondition, "Alert rule name %q is not unique.", br.Name)
+		}
+
+		params := br.createParams()
+		params.ChannelIDs = make([]string, 0, len(br.Channels))
+		for _, summary := range br.Channels {
+			id, ok := channelIDs[summary]
+			if !ok {
+				return status.Errorf(codes.NotFound, "Alert rule %q references unknown notification channel %q.", br.Name, summary)
+			}
+			params.ChannelIDs = append(params.ChannelIDs, id)
+		}
+
+		id, ok := ids[br.Name]
+		if !ok {
+			if _, err = models.CreateRule(q, params); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// replace the whole rule, including fields copied from template, keeping its identity
+		current, err := models.FindRuleByID(q, id)
+		if err != nil {
+			return err
+		}
+		row, err := models.NewRule(params)
+		if err != nil {
+			return err
+		}
+		row.ID = current.ID
+		row.CreatedAt = current.CreatedAt
+		row.ChannelIDs = params.ChannelIDs
+		if err = q.Update(row); err != nil {
+			return errors.Wrap(err, "failed to update alert rule")
+		}
+	}
+
+	return nil
+}
+
+// createParams returns params for creating alert rule from the bundle one, without channels.
+func (br *bundleRule) createParams() *models.CreateRuleParams {
+	return &models.CreateRuleParams{
+		Name:              br.Name,
+		TemplateName:      br.TemplateName,
+		Summary:           br.Summary,
+		Disabled:          br.Disabled,
+		ExprTemplate:      br.ExprTemplate,
+		ParamsDefinitions: br.ParamsDefinitions,
+		ParamsValues:      br.ParamsValues,
+		DefaultFor:        br.DefaultFor,
+		For:               br.For,
+		DefaultSeverity:   models.Severity(br.DefaultSeverity),
+		Severity:          models.Severity(br.Severity),
+		CustomLabels:      br.CustomLabels,
+		Labels:            br.Labels,
+		Annotations:       br.Annotations,
+		RecordingRules:    br.RecordingRules,
+		Conditions:        br.Conditions,
+		Filters:           br.Filters,
+
+		NotificationTemplate: br.NotificationTemplate,
+	}
+}
+
+// validateBundleRule checks rule parameters, filters and expression the same way as rule creation via API.
+func validateBundleRule(params *models.CreateRuleParams) error {
+	if err := validateParameters(params.ParamsDefinitions, params.ParamsValues); err != nil {
+		return err
+	}
+
+	for _, f := range params.Filters {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+
+	values := exprParams("", params.ParamsValues)
+	if _, err := fillExprWithConditions(params.ExprTemplate, params.Conditions, values); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid expression: %s.", err)
+	}
+	if _, err := prepareRecordingRules(params.RecordingRules, values); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid recording rules: %s.", err)
+	}
+
+	return nil
+}
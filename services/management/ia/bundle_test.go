@@ -0,0 +1,297 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/percona-platform/saas/pkg/alert"
+	"github.com/percona-platform/saas/pkg/common"
+	"github.com/percona/pmm/api/managementpb"
+	iav1beta1 "github.com/percona/pmm/api/managementpb/ia"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/yaml.v3"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/testdb"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestBundleYAML(t *testing.T) {
+	t.Parallel()
+
+	b := &bundle{
+		Channels: []*bundleChannel{{
+			Summary: "slack",
+			SlackConfig: &models.SlackConfig{
+				SendResolved: true,
+				Channel:      "#alerts",
+			},
+		}},
+		Rules: []*bundleRule{{
+			Name:            "rule",
+			TemplateName:    "template",
+			Summary:         "summary",
+			ExprTemplate:    "up == 0",
+			DefaultFor:      time.Minute,
+			For:             5 * time.Minute,
+			DefaultSeverity: common.Warning,
+			Severity:        common.Critical,
+			Filters: models.Filters{{
+				Type: models.NotEqual,
+				Key:  "job",
+				Val:  "node",
+			}},
+			Channels: []string{"slack"},
+		}},
+	}
+
+	actual, err := yaml.Marshal(b)
+	require.NoError(t, err)
+	expected := `channels:
+    - summary: slack
+      slack_config:
+        send_resolved: true
+        channel: '#alerts'
+rules:
+    - name: rule
+      template_name: template
+      summary: summary
+      expr_template: up == 0
+      default_for: 1m0s
+      for: 5m0s
+      default_severity: warning
+      severity: critical
+      filters:
+        - type: '!='
+          key: job
+          value: node
+      channels:
+        - slack
+`
+	assert.Equal(t, expected, string(actual))
+
+	var decoded bundle
+	err = yaml.Unmarshal(actual, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, b, &decoded)
+}
+
+func TestBundleChannelSecrets(t *testing.T) {
+	newChannel := func() *models.Channel {
+		return &models.Channel{
+			Summary: "channel",
+			PagerDutyConfig: &models.PagerDutyConfig{
+				RoutingKey: "routing key",
+			},
+			WebHookConfig: &models.WebHookConfig{
+				URL: "https://example.com",
+				HTTPConfig: &models.HTTPConfig{
+					BasicAuth: &models.HTTPBasicAuth{
+						Username: "user",
+						Password: "password",
+					},
+				},
+			},
+		}
+	}
+
+	bc := newBundleChannel(newChannel())
+	assert.Equal(t, map[string]*string{
+		"pagerduty_config.routing_key":                   &bc.PagerDutyConfig.RoutingKey,
+		"webhook_config.http_config.basic_auth.password": &bc.WebHookConfig.HTTPConfig.BasicAuth.Password,
+	}, bc.secrets())
+
+	t.Run("restore", func(t *testing.T) {
+		bc := newBundleChannel(newChannel())
+		bc.PagerDutyConfig.RoutingKey = redactedSecret
+		bc.WebHookConfig.HTTPConfig.BasicAuth.Password = "new password"
+
+		require.NoError(t, bc.restoreSecrets(newChannel()))
+		assert.Equal(t, "routing key", bc.PagerDutyConfig.RoutingKey)
+		assert.Equal(t, "new password", bc.WebHookConfig.HTTPConfig.BasicAuth.Password)
+	})
+
+	t.Run("new channel", func(t *testing.T) {
+		bc := newBundleChannel(newChannel())
+		bc.PagerDutyConfig.RoutingKey = redactedSecret
+
+		err := bc.restoreSecrets(nil)
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Notification channel "channel" has redacted pagerduty_config.routing_key without current value to keep.`), err)
+	})
+}
+
+func TestImportBundleValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	validationErr := status.Error(codes.InvalidArgument, "Invalid rules.")
+	externalRules := new(mockVmAlertExternalRules)
+	externalRules.Test(t)
+	t.Cleanup(func() { externalRules.AssertExpectations(t) })
+	externalRules.On("ValidateRules", ctx, mock.Anything).Return(validationErr)
+
+	// rules are validated before opening transaction, so service doesn't need the database
+	s := &RulesService{
+		l:             logrus.WithField("test", t.Name()),
+		externalRules: externalRules,
+	}
+	_, err := s.ImportBundle(ctx, &ImportBundleRequest{Bundle: `
+rules:
+    - name: bundle rule
+      template_name: test_template
+      expr_template: up == 0
+`})
+	tests.AssertGRPCError(t, status.Convert(validationErr), err)
+
+	_, err = s.ImportBundle(ctx, &ImportBundleRequest{Bundle: `
+templates:
+    - name: bundle_template
+      version: 1
+      summary: Bundle template
+      expr: up == 0
+      severity: warning
+    - name: bundle_template
+      version: 2
+      summary: Bundle template
+      expr: up == 0
+      severity: warning
+`})
+	tests.AssertGRPCError(t, status.New(codes.InvalidArgument, `Rule template name "bundle_template" is not unique in bundle.`), err)
+}
+
+func TestExportImportBundle(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := testdb.Open(t, models.SkipFixtures, nil)
+	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
+
+	var alertManager mockAlertManager
+	alertManager.On("RequestConfigurationUpdate").Return()
+	var vmAlert mockVmAlert
+	vmAlert.On("RequestConfigurationUpdate").Return()
+	var vmQuerier mockVmQuerier
+	var externalRules mockVmAlertExternalRules
+	externalRules.On("ValidateRules", ctx, mock.Anything).Return(nil)
+
+	templates, err := NewTemplatesService(db)
+	require.NoError(t, err)
+	templates.userTemplatesPath = testTemplates2
+	templates.CollectTemplates(ctx)
+
+	_, err = models.CreateTemplate(db.Querier, &models.CreateTemplateParams{
+		Template: &alert.Template{
+			Name:     "bundle_template",
+			Version:  1,
+			Summary:  "Bundle template",
+			Expr:     "up == 0",
+			Severity: common.Warning,
+		},
+		Source: models.UserAPISource,
+	})
+	require.NoError(t, err)
+
+	channels := NewChannelsService(db, &alertManager)
+	respC, err := channels.AddChannel(ctx, &iav1beta1.AddChannelRequest{
+		Summary: "bundle channel",
+		EmailConfig: &iav1beta1.EmailConfig{
+			To: []string{"test@test.test"},
+		},
+	})
+	require.NoError(t, err)
+
+	rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
+	rules.rulesPath = t.TempDir()
+	_, err = rules.CreateAlertRule(ctx, &iav1beta1.CreateAlertRuleRequest{
+		TemplateName: "test_template",
+		Name:         "bundle rule",
+		Params: []*iav1beta1.ParamValue{{
+			Name:  "param1",
+			Type:  iav1beta1.ParamType_FLOAT,
+			Value: &iav1beta1.ParamValue_Float{Float: 85},
+		}, {
+			Name:  "param2",
+			Type:  iav1beta1.ParamType_FLOAT,
+			Value: &iav1beta1.ParamValue_Float{Float: 1.22},
+		}},
+		For:        durationpb.New(2 * time.Second),
+		Severity:   managementpb.Severity_SEVERITY_INFO,
+		ChannelIds: []string{respC.ChannelId},
+	})
+	require.NoError(t, err)
+
+	resp, err := rules.ExportBundle(ctx, &ExportBundleRequest{})
+	require.NoError(t, err)
+	exported := resp.Bundle
+
+	t.Run("import into empty", func(t *testing.T) {
+		q := db.Querier
+		rs, err := models.FindRules(q)
+		require.NoError(t, err)
+		for _, r := range rs {
+			require.NoError(t, models.RemoveRule(q, r.ID))
+		}
+		require.NoError(t, models.RemoveChannel(q, respC.ChannelId))
+		require.NoError(t, models.RemoveTemplate(q, "bundle_template"))
+
+		_, err = rules.ImportBundle(ctx, &ImportBundleRequest{Bundle: exported})
+		require.NoError(t, err)
+
+		reexported, err := rules.ExportBundle(ctx, &ExportBundleRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, exported, reexported.Bundle)
+	})
+
+	t.Run("import twice", func(t *testing.T) {
+		_, err = rules.ImportBundle(ctx, &ImportBundleRequest{Bundle: exported})
+		require.NoError(t, err)
+
+		rs, err := models.FindRules(db.Querier)
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+		cs, err := models.FindChannels(db.Querier)
+		require.NoError(t, err)
+		require.Len(t, cs, 1)
+		assert.Equal(t, []string{cs[0].ID}, []string(rs[0].ChannelIDs))
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		_, err = rules.ImportBundle(ctx, &ImportBundleRequest{Bundle: `
+rules:
+    - name: bundle rule
+      template_name: test_template
+      expr_template: up == 0
+      channels:
+        - unknown channel
+`})
+		tests.AssertGRPCError(t, status.New(codes.NotFound, `Alert rule "bundle rule" references unknown notification channel "unknown channel".`), err)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err = rules.ImportBundle(ctx, &ImportBundleRequest{Bundle: `foo: bar`})
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, `Failed to parse bundle: .*`, err)
+	})
+}
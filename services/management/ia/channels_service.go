@@ -156,39 +156,9 @@ func (s *ChannelsService) getNotificationChannelsPage(pageIndex, pageSize int) (
 }
 
 // AddChannel adds new notification channel.
+// API doesn't have Opsgenie, Microsoft Teams and Telegram configs, see ExtendedChannelsService.
 func (s *ChannelsService) AddChannel(ctx context.Context, req *iav1beta1.AddChannelRequest) (*iav1beta1.AddChannelResponse, error) {
-	params := &models.CreateChannelParams{
-		Summary:  req.Summary,
-		Disabled: req.Disabled,
-	}
-
-	if req.EmailConfig != nil {
-		params.EmailConfig = &models.EmailConfig{
-			SendResolved: req.EmailConfig.SendResolved,
-			To:           req.EmailConfig.To,
-		}
-	}
-	if req.PagerdutyConfig != nil {
-		params.PagerDutyConfig = &models.PagerDutyConfig{
-			SendResolved: req.PagerdutyConfig.SendResolved,
-			RoutingKey:   req.PagerdutyConfig.RoutingKey,
-			ServiceKey:   req.PagerdutyConfig.ServiceKey,
-		}
-	}
-	if req.SlackConfig != nil {
-		params.SlackConfig = &models.SlackConfig{
-			SendResolved: req.SlackConfig.SendResolved,
-			Channel:      req.SlackConfig.Channel,
-		}
-	}
-	if req.WebhookConfig != nil {
-		params.WebHookConfig = &models.WebHookConfig{
-			SendResolved: req.WebhookConfig.SendResolved,
-			URL:          req.WebhookConfig.Url,
-			MaxAlerts:    req.WebhookConfig.MaxAlerts,
-			HTTPConfig:   convertHTTPConfigToModel(req.WebhookConfig.HttpConfig),
-		}
-	}
+	params := convertAddChannelRequest(req)
 
 	var channel *models.Channel
 	e := s.db.InTransaction(func(tx *reform.TX) error {
@@ -241,7 +211,25 @@ func (s *ChannelsService) ChangeChannel(ctx context.Context, req *iav1beta1.Chan
 	}
 
 	e := s.db.InTransaction(func(tx *reform.TX) error {
-		_, err := models.ChangeChannel(tx.Querier, req.ChannelId, params)
+		channel, err := models.FindChannelByID(tx.Querier, req.ChannelId)
+		if err != nil {
+			return err
+		}
+
+		// gRPC API doesn't have notification templates (see ExtendedChannelsService), keep the current one if channel type supports it
+		if params.WebHookConfig == nil {
+			params.NotificationTemplate = channel.NotificationTemplate
+		}
+
+		// gRPC API doesn't have Opsgenie, Microsoft Teams and Telegram configs, keep the current one
+		// if no other config is set, so such channels can be renamed, enabled and disabled
+		if params.EmailConfig == nil && params.PagerDutyConfig == nil && params.SlackConfig == nil && params.WebHookConfig == nil {
+			params.OpsGenieConfig = channel.OpsGenieConfig
+			params.MSTeamsConfig = channel.MSTeamsConfig
+			params.TelegramConfig = channel.TelegramConfig
+		}
+
+		_, err = models.ChangeChannel(tx.Querier, req.ChannelId, params)
 		return err
 	})
 	if e != nil {
@@ -310,6 +298,9 @@ func convertChannel(channel *models.Channel) (*iav1beta1.Channel, error) {
 				MaxAlerts:    config.MaxAlerts,
 			},
 		}
+	case models.OpsGenie, models.MSTeams, models.Telegram:
+		// API doesn't have Opsgenie, Microsoft Teams and Telegram configs, return such channels
+		// without configuration instead of failing the whole list; ExtendedChannelsService returns them.
 	default:
 		return nil, errors.Errorf("unknown notification channel type %s", channel.Type)
 	}
@@ -317,6 +308,43 @@ func convertChannel(channel *models.Channel) (*iav1beta1.Channel, error) {
 	return c, nil
 }
 
+func convertAddChannelRequest(req *iav1beta1.AddChannelRequest) *models.CreateChannelParams {
+	params := &models.CreateChannelParams{
+		Summary:  req.Summary,
+		Disabled: req.Disabled,
+	}
+
+	if req.EmailConfig != nil {
+		params.EmailConfig = &models.EmailConfig{
+			SendResolved: req.EmailConfig.SendResolved,
+			To:           req.EmailConfig.To,
+		}
+	}
+	if req.PagerdutyConfig != nil {
+		params.PagerDutyConfig = &models.PagerDutyConfig{
+			SendResolved: req.PagerdutyConfig.SendResolved,
+			RoutingKey:   req.PagerdutyConfig.RoutingKey,
+			ServiceKey:   req.PagerdutyConfig.ServiceKey,
+		}
+	}
+	if req.SlackConfig != nil {
+		params.SlackConfig = &models.SlackConfig{
+			SendResolved: req.SlackConfig.SendResolved,
+			Channel:      req.SlackConfig.Channel,
+		}
+	}
+	if req.WebhookConfig != nil {
+		params.WebHookConfig = &models.WebHookConfig{
+			SendResolved: req.WebhookConfig.SendResolved,
+			URL:          req.WebhookConfig.Url,
+			MaxAlerts:    req.WebhookConfig.MaxAlerts,
+			HTTPConfig:   convertHTTPConfigToModel(req.WebhookConfig.HttpConfig),
+		}
+	}
+
+	return params
+}
+
 func convertHTTPConfigToModel(config *iav1beta1.HTTPConfig) *models.HTTPConfig {
 	if config == nil {
 		return nil
@@ -71,7 +71,7 @@ func convertRule(l *logrus.Entry, rule *models.Rule, channels []*models.Channel)
 		return nil, errors.Wrap(err, "failed to convert timestamp")
 	}
 
-	r.Expr, err = fillExprWithParams(rule.ExprTemplate, rule.ParamsValues.AsStringMap())
+	r.Expr, err = fillExprWithConditions(rule.ExprTemplate, rule.Conditions, exprParams(rule.ID, rule.ParamsValues))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fill expression template with parameters values")
 	}
@@ -101,13 +101,18 @@ func convertRule(l *logrus.Entry, rule *models.Rule, channels []*models.Channel)
 		return nil, errors.Wrap(err, "failed to load rule annotations")
 	}
 
-	r.Filters = make([]*iav1beta1.Filter, len(rule.Filters))
-	for i, filter := range rule.Filters {
-		r.Filters[i] = &iav1beta1.Filter{
-			Type:  convertModelToFilterType(filter.Type),
+	// API can't express negative and numeric filters, they are returned by Rules/GetFilters JSON API only
+	r.Filters = make([]*iav1beta1.Filter, 0, len(rule.Filters))
+	for _, filter := range rule.Filters {
+		t, ok := convertModelToFilterType(filter.Type)
+		if !ok {
+			continue
+		}
+		r.Filters = append(r.Filters, &iav1beta1.Filter{
+			Type:  t,
 			Key:   filter.Key,
 			Value: filter.Val,
-		}
+		})
 	}
 
 	cm := make(map[string]*models.Channel)
@@ -144,6 +149,39 @@ func fillExprWithParams(expr string, values map[string]string) (string, error) {
 	return buf.String(), nil
 }
 
+// ruleIDPlaceholder is the name of expressions placeholder filled with rule ID, so alert expression
+// can select time series recorded by recording rules of the same rule.
+const ruleIDPlaceholder = "rule_id"
+
+// exprParams returns values of rule expressions placeholders: parameters values and rule ID.
+func exprParams(ruleID string, values models.AlertExprParamsValues) map[string]string {
+	params := values.AsStringMap()
+	params[ruleIDPlaceholder] = ruleID
+	return params
+}
+
+// fillExprWithConditions fills alert expression with parameters values and named conditions,
+// conditions are filled with parameters values first.
+func fillExprWithConditions(expr string, conditions models.AlertConditions, values map[string]string) (string, error) {
+	if len(conditions) == 0 {
+		return fillExprWithParams(expr, values)
+	}
+
+	data := make(map[string]string, len(values)+len(conditions))
+	for k, v := range values {
+		data[k] = v
+	}
+	for _, c := range conditions {
+		e, err := fillExprWithParams(c.Expr, values)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to fill condition %q", c.Name)
+		}
+		data[c.Name] = "(" + e + ")"
+	}
+
+	return fillExprWithParams(expr, data)
+}
+
 func validateParameters(definitions models.AlertExprParamsDefinitions, values models.AlertExprParamsValues) error {
 	if len(definitions) != len(values) {
 		return status.Errorf(codes.InvalidArgument, "Expression requires %d parameters, but got %d.",
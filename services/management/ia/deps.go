@@ -20,12 +20,19 @@ import (
 	"context"
 
 	"github.com/percona/pmm/api/alertmanager/ammodels"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 
+	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services"
 )
 
 //go:generate mockery -name=alertManager -case=snake -inpkg -testonly
+//go:generate mockery -name=maintenanceWindowsManager -case=snake -inpkg -testonly
 //go:generate mockery -name=vmAlert -case=snake -inpkg -testonly
+//go:generate mockery -name=vmAlertExternalRules -case=snake -inpkg -testonly
+//go:generate mockery -name=grafanaClient -case=snake -inpkg -testonly
+//go:generate mockery -name=vmQuerier -case=snake -inpkg -testonly
 
 // alertManager is is a subset of methods of alertmanager.Service used by this package.
 // We use it instead of real type for testing and to avoid dependency cycle.
@@ -35,6 +42,17 @@ type alertManager interface {
 	SilenceAlerts(ctx context.Context, alerts []*ammodels.GettableAlert) error
 	UnsilenceAlerts(ctx context.Context, alerts []*ammodels.GettableAlert) error
 	RequestConfigurationUpdate()
+	CheckChannelType(ctx context.Context, channelType models.ChannelType) error
+	SendTestNotification(ctx context.Context, channel *models.Channel) error
+	RenderNotificationTemplate(t *models.NotificationTemplate, labels, annotations map[string]string) (string, string, error)
+}
+
+// maintenanceWindowsManager is a subset of methods of alertmanager.Service used by this package
+// for managing maintenance windows.
+type maintenanceWindowsManager interface {
+	CreateMaintenanceWindow(params models.CreateMaintenanceWindowParams) (*models.MaintenanceWindow, error)
+	ChangeMaintenanceWindow(ctx context.Context, id string, params models.ChangeMaintenanceWindowParams) (*models.MaintenanceWindow, error)
+	RemoveMaintenanceWindow(ctx context.Context, id string) error
 }
 
 // vmAlert is is a subset of methods of vmalert.Service used by this package.
@@ -42,3 +60,21 @@ type alertManager interface {
 type vmAlert interface {
 	RequestConfigurationUpdate()
 }
+
+// vmAlertExternalRules is a subset of methods of vmalert.ExternalRules used by this package.
+// We use it instead of real type for testing and to avoid dependency cycle.
+type vmAlertExternalRules interface {
+	ValidateRules(ctx context.Context, rules string) error
+}
+
+// grafanaClient is a subset of methods of grafana.Client used by this package.
+// We use it instead of real type for testing and to avoid dependency cycle.
+type grafanaClient interface {
+	GetUserLogin(ctx context.Context) (string, error)
+}
+
+// vmQuerier is a subset of methods of Prometheus HTTP API client used by this package to query VictoriaMetrics.
+// We use it instead of real type for testing.
+type vmQuerier interface {
+	QueryRange(ctx context.Context, query string, r v1.Range) (model.Value, v1.Warnings, error)
+}
@@ -0,0 +1,342 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// ExtendedChannelsService represents integrated alerting notification channels JSON API.
+// Opsgenie, Microsoft Teams and Telegram channels are managed and all channels are tested only by this API.
+type ExtendedChannelsService struct {
+	l            *logrus.Entry
+	db           *reform.DB
+	alertManager alertManager
+}
+
+// NewExtendedChannelsService creates new notification channels JSON API service.
+func NewExtendedChannelsService(db *reform.DB, alertManager alertManager) *ExtendedChannelsService {
+	return &ExtendedChannelsService{
+		l:            logrus.WithField("component", "management/ia/extended_channels"),
+		db:           db,
+		alertManager: alertManager,
+	}
+}
+
+// ChannelConfig is a notification channel configuration, exactly one field should be set.
+type ChannelConfig struct {
+	EmailConfig     *models.EmailConfig     `json:"email_config,omitempty"`
+	PagerDutyConfig *models.PagerDutyConfig `json:"pagerduty_config,omitempty"`
+	SlackConfig     *models.SlackConfig     `json:"slack_config,omitempty"`
+	WebHookConfig   *models.WebHookConfig   `json:"webhook_config,omitempty"`
+	OpsGenieConfig  *models.OpsGenieConfig  `json:"opsgenie_config,omitempty"`
+	// MSTeamsConfig requires Alertmanager 0.26 or later.
+	MSTeamsConfig *models.MSTeamsConfig `json:"msteams_config,omitempty"`
+	// TelegramConfig requires Alertmanager 0.24 or later.
+	TelegramConfig *models.TelegramConfig `json:"telegram_config,omitempty"`
+}
+
+// ExtendedChannel is a notification channel with configuration of any type.
+type ExtendedChannel struct {
+	ChannelID string             `json:"channel_id"`
+	Type      models.ChannelType `json:"type"`
+	Summary   string             `json:"summary"`
+	Disabled  bool               `json:"disabled"`
+	ChannelConfig
+
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template,omitempty"`
+}
+
+// ListExtendedChannelsRequest is a ListChannels JSON API request.
+type ListExtendedChannelsRequest struct{}
+
+// ListExtendedChannelsResponse is a ListChannels JSON API response.
+type ListExtendedChannelsResponse struct {
+	Channels []*ExtendedChannel `json:"channels"`
+}
+
+// GetExtendedChannelRequest is a GetChannel JSON API request.
+type GetExtendedChannelRequest struct {
+	ChannelID string `json:"channel_id"`
+}
+
+// AddExtendedChannelRequest is an AddChannel JSON API request.
+type AddExtendedChannelRequest struct {
+	Summary  string `json:"summary"`
+	Disabled bool   `json:"disabled"`
+	ChannelConfig
+
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template,omitempty"`
+}
+
+// ChangeExtendedChannelRequest is a ChangeChannel JSON API request.
+// Configuration replaces the current one, empty summary is left unchanged.
+// Absent notification template is left unchanged, empty one resets it to defaults.
+type ChangeExtendedChannelRequest struct {
+	ChannelID string `json:"channel_id"`
+	Summary   string `json:"summary"`
+	Disabled  bool   `json:"disabled"`
+	ChannelConfig
+
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template,omitempty"`
+}
+
+// TestChannelRequest is a TestChannel JSON API request.
+type TestChannelRequest struct {
+	ChannelID string `json:"channel_id"`
+}
+
+// TestChannelConfigRequest is a TestChannelConfig JSON API request.
+type TestChannelConfigRequest struct {
+	Summary string `json:"summary"`
+	ChannelConfig
+
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template,omitempty"`
+}
+
+// TestChannelResponse is a TestChannel and TestChannelConfig JSON API response.
+type TestChannelResponse struct{}
+
+// PreviewNotificationTemplateRequest is a PreviewNotificationTemplate JSON API request.
+// Labels and annotations override ones of the synthetic test alert.
+type PreviewNotificationTemplateRequest struct {
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template"`
+	Labels               map[string]string            `json:"labels"`
+	Annotations          map[string]string            `json:"annotations"`
+}
+
+// PreviewNotificationTemplateResponse is a PreviewNotificationTemplate JSON API response.
+type PreviewNotificationTemplateResponse struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// ListChannels returns all notification channels with their configurations.
+func (s *ExtendedChannelsService) ListChannels(ctx context.Context, req *ListExtendedChannelsRequest) (*ListExtendedChannelsResponse, error) {
+	channels, err := models.FindChannels(s.db.Querier)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListExtendedChannelsResponse{
+		Channels: make([]*ExtendedChannel, 0, len(channels)),
+	}
+	for _, c := range channels {
+		res.Channels = append(res.Channels, convertExtendedChannel(c))
+	}
+
+	return res, nil
+}
+
+// GetChannel returns notification channel with its configuration.
+func (s *ExtendedChannelsService) GetChannel(ctx context.Context, req *GetExtendedChannelRequest) (*ExtendedChannel, error) {
+	channel, err := models.FindChannelByID(s.db.Querier, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertExtendedChannel(channel), nil
+}
+
+// AddChannel adds notification channel of any type supported by running Alertmanager.
+func (s *ExtendedChannelsService) AddChannel(ctx context.Context, req *AddExtendedChannelRequest) (*ExtendedChannel, error) {
+	params := &models.CreateChannelParams{
+		Summary:         req.Summary,
+		Disabled:        req.Disabled,
+		EmailConfig:     req.EmailConfig,
+		PagerDutyConfig: req.PagerDutyConfig,
+		SlackConfig:     req.SlackConfig,
+		WebHookConfig:   req.WebHookConfig,
+		OpsGenieConfig:  req.OpsGenieConfig,
+		MSTeamsConfig:   req.MSTeamsConfig,
+		TelegramConfig:  req.TelegramConfig,
+
+		NotificationTemplate: normalizeNotificationTemplate(req.NotificationTemplate),
+	}
+
+	// validate before checking Alertmanager version to report configuration errors first
+	channel, err := models.NewChannel(params)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.alertManager.CheckChannelType(ctx, channel.Type); err != nil {
+		return nil, err
+	}
+
+	e := s.db.InTransaction(func(tx *reform.TX) error {
+		channel, err = models.CreateChannel(tx.Querier, params)
+		return err
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	s.alertManager.RequestConfigurationUpdate()
+
+	return convertExtendedChannel(channel), nil
+}
+
+// ChangeChannel changes notification channel, its type may be changed to any type supported by running Alertmanager.
+func (s *ExtendedChannelsService) ChangeChannel(ctx context.Context, req *ChangeExtendedChannelRequest) (*ExtendedChannel, error) {
+	params := &models.ChangeChannelParams{
+		Summary:         req.Summary,
+		Disabled:        req.Disabled,
+		EmailConfig:     req.EmailConfig,
+		PagerDutyConfig: req.PagerDutyConfig,
+		SlackConfig:     req.SlackConfig,
+		WebHookConfig:   req.WebHookConfig,
+		OpsGenieConfig:  req.OpsGenieConfig,
+		MSTeamsConfig:   req.MSTeamsConfig,
+		TelegramConfig:  req.TelegramConfig,
+	}
+	if err := s.alertManager.CheckChannelType(ctx, req.channelType()); err != nil {
+		return nil, err
+	}
+
+	var channel *models.Channel
+	e := s.db.InTransaction(func(tx *reform.TX) error {
+		current, err := models.FindChannelByID(tx.Querier, req.ChannelID)
+		if err != nil {
+			return err
+		}
+
+		// keep the current notification template if absent, models.ChangeChannel checks that new type supports it
+		params.NotificationTemplate = current.NotificationTemplate
+		if req.NotificationTemplate != nil {
+			params.NotificationTemplate = normalizeNotificationTemplate(req.NotificationTemplate)
+		}
+
+		channel, err = models.ChangeChannel(tx.Querier, req.ChannelID, params)
+		return err
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	s.alertManager.RequestConfigurationUpdate()
+
+	return convertExtendedChannel(channel), nil
+}
+
+// TestChannel sends test notification to the existing notification channel.
+// Delivery error reported by the notification service is returned as FailedPrecondition error.
+func (s *ExtendedChannelsService) TestChannel(ctx context.Context, req *TestChannelRequest) (*TestChannelResponse, error) {
+	channel, err := models.FindChannelByID(s.db.Querier, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.alertManager.SendTestNotification(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	return &TestChannelResponse{}, nil
+}
+
+// TestChannelConfig sends test notification to the notification channel with given configuration without saving it.
+// Delivery error reported by the notification service is returned as FailedPrecondition error.
+func (s *ExtendedChannelsService) TestChannelConfig(ctx context.Context, req *TestChannelConfigRequest) (*TestChannelResponse, error) {
+	channel, err := models.NewChannel(&models.CreateChannelParams{
+		Summary:         req.Summary,
+		EmailConfig:     req.EmailConfig,
+		PagerDutyConfig: req.PagerDutyConfig,
+		SlackConfig:     req.SlackConfig,
+		WebHookConfig:   req.WebHookConfig,
+		OpsGenieConfig:  req.OpsGenieConfig,
+		MSTeamsConfig:   req.MSTeamsConfig,
+		TelegramConfig:  req.TelegramConfig,
+
+		NotificationTemplate: normalizeNotificationTemplate(req.NotificationTemplate),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.alertManager.SendTestNotification(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	return &TestChannelResponse{}, nil
+}
+
+// PreviewNotificationTemplate returns notification title and text rendered with given notification template
+// for the synthetic alert, the same way as they are rendered for PagerDuty channels. Defaults are used for empty ones.
+func (s *ExtendedChannelsService) PreviewNotificationTemplate(ctx context.Context, req *PreviewNotificationTemplateRequest) (*PreviewNotificationTemplateResponse, error) {
+	title, text, err := s.alertManager.RenderNotificationTemplate(req.NotificationTemplate, req.Labels, req.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreviewNotificationTemplateResponse{Title: title, Text: text}, nil
+}
+
+// normalizeNotificationTemplate returns nil for empty notification template, so defaults are used without it.
+func normalizeNotificationTemplate(t *models.NotificationTemplate) *models.NotificationTemplate {
+	if t == nil || *t == (models.NotificationTemplate{}) {
+		return nil
+	}
+	return t
+}
+
+// channelType returns type of the set configuration, or empty string if none or several are set.
+func (c *ChannelConfig) channelType() models.ChannelType {
+	var res models.ChannelType
+	var n int
+	for t, set := range map[models.ChannelType]bool{
+		models.Email:     c.EmailConfig != nil,
+		models.PagerDuty: c.PagerDutyConfig != nil,
+		models.Slack:     c.SlackConfig != nil,
+		models.WebHook:   c.WebHookConfig != nil,
+		models.OpsGenie:  c.OpsGenieConfig != nil,
+		models.MSTeams:   c.MSTeamsConfig != nil,
+		models.Telegram:  c.TelegramConfig != nil,
+	} {
+		if set {
+			res = t
+			n++
+		}
+	}
+
+	if n != 1 {
+		return ""
+	}
+	return res
+}
+
+func convertExtendedChannel(c *models.Channel) *ExtendedChannel {
+	return &ExtendedChannel{
+		ChannelID: c.ID,
+		Type:      c.Type,
+		Summary:   c.Summary,
+		Disabled:  c.Disabled,
+		ChannelConfig: ChannelConfig{
+			EmailConfig:     c.EmailConfig,
+			PagerDutyConfig: c.PagerDutyConfig,
+			SlackConfig:     c.SlackConfig,
+			WebHookConfig:   c.WebHookConfig,
+			OpsGenieConfig:  c.OpsGenieConfig,
+			MSTeamsConfig:   c.MSTeamsConfig,
+			TelegramConfig:  c.TelegramConfig,
+		},
+		NotificationTemplate: c.NotificationTemplate,
+	}
+}
@@ -0,0 +1,145 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestChannelConfigType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, models.ChannelType(""), (&ChannelConfig{}).channelType())
+	assert.Equal(t, models.Telegram, (&ChannelConfig{TelegramConfig: &models.TelegramConfig{}}).channelType())
+	assert.Equal(t, models.ChannelType(""), (&ChannelConfig{
+		MSTeamsConfig:  &models.MSTeamsConfig{},
+		TelegramConfig: &models.TelegramConfig{},
+	}).channelType())
+}
+
+func TestConvertExtendedChannel(t *testing.T) {
+	t.Parallel()
+
+	channel := &models.Channel{
+		ID:       "/channel_id/teams",
+		Type:     models.MSTeams,
+		Summary:  "Teams",
+		Disabled: true,
+		MSTeamsConfig: &models.MSTeamsConfig{
+			SendResolved: true,
+			WebhookURL:   "https://example.webhook.office.com/webhookb2/test",
+		},
+		NotificationTemplate: &models.NotificationTemplate{Title: "Title"},
+	}
+
+	assert.Equal(t, &ExtendedChannel{
+		ChannelID: "/channel_id/teams",
+		Type:      models.MSTeams,
+		Summary:   "Teams",
+		Disabled:  true,
+		ChannelConfig: ChannelConfig{
+			MSTeamsConfig: channel.MSTeamsConfig,
+		},
+		NotificationTemplate: channel.NotificationTemplate,
+	}, convertExtendedChannel(channel))
+}
+
+func TestNormalizeNotificationTemplate(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, normalizeNotificationTemplate(nil))
+	assert.Nil(t, normalizeNotificationTemplate(&models.NotificationTemplate{}))
+	nt := &models.NotificationTemplate{Text: "Text"}
+	assert.Equal(t, nt, normalizeNotificationTemplate(nt))
+}
+
+func TestPreviewNotificationTemplate(t *testing.T) {
+	t.Parallel()
+
+	nt := &models.NotificationTemplate{Title: "{{ .CommonLabels.severity }}"}
+	labels := map[string]string{"severity": "critical"}
+	alertManager := new(mockAlertManager)
+	alertManager.Test(t)
+	t.Cleanup(func() { alertManager.AssertExpectations(t) })
+	alertManager.On("RenderNotificationTemplate", nt, labels, map[string]string(nil)).Return("critical", "text", nil)
+
+	s := NewExtendedChannelsService(nil, alertManager)
+	resp, err := s.PreviewNotificationTemplate(context.Background(), &PreviewNotificationTemplateRequest{
+		NotificationTemplate: nt,
+		Labels:               labels,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &PreviewNotificationTemplateResponse{Title: "critical", Text: "text"}, resp)
+}
+
+func TestTestChannelConfig(t *testing.T) {
+	t.Parallel()
+
+	req := &TestChannelConfigRequest{
+		Summary: "test",
+		ChannelConfig: ChannelConfig{
+			WebHookConfig: &models.WebHookConfig{URL: "http://127.0.0.1:1/"},
+		},
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		t.Parallel()
+
+		alertManager := new(mockAlertManager)
+		alertManager.Test(t)
+		t.Cleanup(func() { alertManager.AssertExpectations(t) })
+		alertManager.On("SendTestNotification", mock.Anything, mock.MatchedBy(func(c *models.Channel) bool {
+			return c.Type == models.WebHook && c.WebHookConfig.URL == "http://127.0.0.1:1/"
+		})).Return(nil)
+
+		s := NewExtendedChannelsService(nil, alertManager)
+		_, err := s.TestChannelConfig(context.Background(), req)
+		require.NoError(t, err)
+	})
+
+	t.Run("delivery error", func(t *testing.T) {
+		t.Parallel()
+
+		deliveryErr := status.Error(codes.FailedPrecondition, "Failed to send test notification: connection refused.")
+		alertManager := new(mockAlertManager)
+		alertManager.Test(t)
+		t.Cleanup(func() { alertManager.AssertExpectations(t) })
+		alertManager.On("SendTestNotification", mock.Anything, mock.Anything).Return(deliveryErr)
+
+		s := NewExtendedChannelsService(nil, alertManager)
+		_, err := s.TestChannelConfig(context.Background(), req)
+		tests.AssertGRPCError(t, status.Convert(deliveryErr), err)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewExtendedChannelsService(nil, new(mockAlertManager))
+		_, err := s.TestChannelConfig(context.Background(), &TestChannelConfigRequest{Summary: "test"})
+		tests.AssertGRPCError(t, status.New(codes.InvalidArgument, "Missing channel configuration."), err)
+	})
+}
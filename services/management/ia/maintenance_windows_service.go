@@ -0,0 +1,182 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+// MaintenanceWindowsService represents integrated alerting maintenance windows API.
+type MaintenanceWindowsService struct {
+	l                  *logrus.Entry
+	db                 *reform.DB
+	maintenanceWindows maintenanceWindowsManager
+}
+
+// NewMaintenanceWindowsService creates new maintenance windows API service.
+func NewMaintenanceWindowsService(db *reform.DB, maintenanceWindows maintenanceWindowsManager) *MaintenanceWindowsService {
+	return &MaintenanceWindowsService{
+		l:                  logrus.WithField("component", "management/ia/maintenance_windows"),
+		db:                 db,
+		maintenanceWindows: maintenanceWindows,
+	}
+}
+
+// MaintenanceWindow is a period of time during which alerts in its scope are silenced.
+type MaintenanceWindow struct {
+	MaintenanceWindowID string           `json:"maintenance_window_id"`
+	Name                string           `json:"name"`
+	Description         string           `json:"description,omitempty"`
+	StartsAt            time.Time        `json:"starts_at"`
+	Duration            httpapi.Duration `json:"duration"`
+	// CronExpression makes the window recurring, every occurrence starts at the UTC time it matches.
+	CronExpression string                        `json:"cron_expression,omitempty"`
+	Scope          models.MaintenanceWindowScope `json:"scope"`
+	Enabled        bool                          `json:"enabled"`
+	// SilencedUntil is an end of the active occurrence which alerts are silenced for.
+	SilencedUntil *time.Time `json:"silenced_until,omitempty"`
+}
+
+// ListMaintenanceWindowsRequest is a ListMaintenanceWindows JSON API request.
+type ListMaintenanceWindowsRequest struct{}
+
+// ListMaintenanceWindowsResponse is a ListMaintenanceWindows JSON API response.
+type ListMaintenanceWindowsResponse struct {
+	MaintenanceWindows []*MaintenanceWindow `json:"maintenance_windows"`
+}
+
+// CreateMaintenanceWindowRequest is a CreateMaintenanceWindow JSON API request.
+type CreateMaintenanceWindowRequest struct {
+	Name           string                        `json:"name"`
+	Description    string                        `json:"description"`
+	StartsAt       time.Time                     `json:"starts_at"`
+	Duration       httpapi.Duration              `json:"duration"`
+	CronExpression string                        `json:"cron_expression"`
+	Scope          models.MaintenanceWindowScope `json:"scope"`
+	Enabled        bool                          `json:"enabled"`
+}
+
+// ChangeMaintenanceWindowRequest is a ChangeMaintenanceWindow JSON API request, fields which aren't set are left unchanged.
+type ChangeMaintenanceWindowRequest struct {
+	MaintenanceWindowID string                         `json:"maintenance_window_id"`
+	Name                *string                        `json:"name,omitempty"`
+	Description         *string                        `json:"description,omitempty"`
+	StartsAt            *time.Time                     `json:"starts_at,omitempty"`
+	Duration            *httpapi.Duration              `json:"duration,omitempty"`
+	CronExpression      *string                        `json:"cron_expression,omitempty"`
+	Scope               *models.MaintenanceWindowScope `json:"scope,omitempty"`
+	Enabled             *bool                          `json:"enabled,omitempty"`
+}
+
+// RemoveMaintenanceWindowRequest is a RemoveMaintenanceWindow JSON API request.
+type RemoveMaintenanceWindowRequest struct {
+	MaintenanceWindowID string `json:"maintenance_window_id"`
+}
+
+// RemoveMaintenanceWindowResponse is a RemoveMaintenanceWindow JSON API response.
+type RemoveMaintenanceWindowResponse struct{}
+
+// ListMaintenanceWindows returns all maintenance windows.
+func (s *MaintenanceWindowsService) ListMaintenanceWindows(ctx context.Context, req *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse, error) {
+	windows, err := models.FindMaintenanceWindows(s.db.Querier)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListMaintenanceWindowsResponse{
+		MaintenanceWindows: make([]*MaintenanceWindow, 0, len(windows)),
+	}
+	for _, w := range windows {
+		res.MaintenanceWindows = append(res.MaintenanceWindows, convertMaintenanceWindow(w))
+	}
+
+	return res, nil
+}
+
+// CreateMaintenanceWindow creates maintenance window.
+func (s *MaintenanceWindowsService) CreateMaintenanceWindow(ctx context.Context, req *CreateMaintenanceWindowRequest) (*MaintenanceWindow, error) {
+	window, err := s.maintenanceWindows.CreateMaintenanceWindow(models.CreateMaintenanceWindowParams{
+		Name:           req.Name,
+		Description:    req.Description,
+		StartsAt:       req.StartsAt,
+		Duration:       time.Duration(req.Duration),
+		CronExpression: req.CronExpression,
+		Scope:          req.Scope,
+		Disabled:       !req.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertMaintenanceWindow(window), nil
+}
+
+// ChangeMaintenanceWindow changes maintenance window.
+func (s *MaintenanceWindowsService) ChangeMaintenanceWindow(ctx context.Context, req *ChangeMaintenanceWindowRequest) (*MaintenanceWindow, error) {
+	params := models.ChangeMaintenanceWindowParams{
+		Name:           req.Name,
+		Description:    req.Description,
+		StartsAt:       req.StartsAt,
+		CronExpression: req.CronExpression,
+		Scope:          req.Scope,
+	}
+	if req.Duration != nil {
+		d := time.Duration(*req.Duration)
+		params.Duration = &d
+	}
+	if req.Enabled != nil {
+		disabled := !*req.Enabled
+		params.Disabled = &disabled
+	}
+
+	window, err := s.maintenanceWindows.ChangeMaintenanceWindow(ctx, req.MaintenanceWindowID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertMaintenanceWindow(window), nil
+}
+
+// RemoveMaintenanceWindow removes maintenance window.
+func (s *MaintenanceWindowsService) RemoveMaintenanceWindow(ctx context.Context, req *RemoveMaintenanceWindowRequest) (*RemoveMaintenanceWindowResponse, error) {
+	if err := s.maintenanceWindows.RemoveMaintenanceWindow(ctx, req.MaintenanceWindowID); err != nil {
+		return nil, err
+	}
+
+	return &RemoveMaintenanceWindowResponse{}, nil
+}
+
+func convertMaintenanceWindow(w *models.MaintenanceWindow) *MaintenanceWindow {
+	return &MaintenanceWindow{
+		MaintenanceWindowID: w.ID,
+		Name:                w.Name,
+		Description:         w.Description,
+		StartsAt:            w.StartsAt,
+		Duration:            httpapi.Duration(w.Duration),
+		CronExpression:      w.CronExpression,
+		Scope:               w.Scope,
+		Enabled:             !w.Disabled,
+		SilencedUntil:       w.SilencedUntil,
+	}
+}
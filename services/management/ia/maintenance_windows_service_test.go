@@ -0,0 +1,108 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+func TestMaintenanceWindowsService(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	startsAt := time.Date(2022, 5, 12, 22, 0, 0, 0, time.UTC)
+	scope := models.MaintenanceWindowScope{NodeIDs: []string{"node"}}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+
+		mw := &mockMaintenanceWindowsManager{}
+		mw.On("CreateMaintenanceWindow", models.CreateMaintenanceWindowParams{
+			Name:           "nightly",
+			StartsAt:       startsAt,
+			Duration:       time.Hour,
+			CronExpression: "0 22 * * *",
+			Scope:          scope,
+			Disabled:       true,
+		}).Return(&models.MaintenanceWindow{
+			ID:             "/maintenance_window_id/1",
+			Name:           "nightly",
+			StartsAt:       startsAt,
+			Duration:       time.Hour,
+			CronExpression: "0 22 * * *",
+			Scope:          scope,
+			Disabled:       true,
+		}, nil)
+
+		s := NewMaintenanceWindowsService(nil, mw)
+		actual, err := s.CreateMaintenanceWindow(ctx, &CreateMaintenanceWindowRequest{
+			Name:           "nightly",
+			StartsAt:       startsAt,
+			Duration:       httpapi.Duration(time.Hour),
+			CronExpression: "0 22 * * *",
+			Scope:          scope,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &MaintenanceWindow{
+			MaintenanceWindowID: "/maintenance_window_id/1",
+			Name:                "nightly",
+			StartsAt:            startsAt,
+			Duration:            httpapi.Duration(time.Hour),
+			CronExpression:      "0 22 * * *",
+			Scope:               scope,
+		}, actual)
+		mw.AssertExpectations(t)
+	})
+
+	t.Run("change", func(t *testing.T) {
+		t.Parallel()
+
+		mw := &mockMaintenanceWindowsManager{}
+		mw.On("ChangeMaintenanceWindow", mock.Anything, "/maintenance_window_id/1", models.ChangeMaintenanceWindowParams{
+			Duration: pointer.ToDuration(2 * time.Hour),
+			Disabled: pointer.ToBool(false),
+		}).Return(&models.MaintenanceWindow{
+			ID:       "/maintenance_window_id/1",
+			Name:     "nightly",
+			StartsAt: startsAt,
+			Duration: 2 * time.Hour,
+			Scope:    scope,
+		}, nil)
+
+		s := NewMaintenanceWindowsService(nil, mw)
+		duration := httpapi.Duration(2 * time.Hour)
+		actual, err := s.ChangeMaintenanceWindow(ctx, &ChangeMaintenanceWindowRequest{
+			MaintenanceWindowID: "/maintenance_window_id/1",
+			Duration:            &duration,
+			Enabled:             pointer.ToBool(true),
+		})
+		require.NoError(t, err)
+		assert.True(t, actual.Enabled)
+		assert.Equal(t, httpapi.Duration(2*time.Hour), actual.Duration)
+		mw.AssertExpectations(t)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	ammodels "github.com/percona/pmm/api/alertmanager/ammodels"
 	mock "github.com/stretchr/testify/mock"
 
+	models "github.com/percona/pmm-managed/models"
 	services "github.com/percona/pmm-managed/services"
 )
 
@@ -16,6 +17,20 @@ type mockAlertManager struct {
 	mock.Mock
 }
 
+// CheckChannelType provides a mock function with given fields: ctx, channelType
+func (_m *mockAlertManager) CheckChannelType(ctx context.Context, channelType models.ChannelType) error {
+	ret := _m.Called(ctx, channelType)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ChannelType) error); ok {
+		r0 = rf(ctx, channelType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindAlertsByID provides a mock function with given fields: ctx, params, ids
 func (_m *mockAlertManager) FindAlertsByID(ctx context.Context, params *services.FilterParams, ids []string) ([]*ammodels.GettableAlert, error) {
 	ret := _m.Called(ctx, params, ids)
@@ -62,11 +77,53 @@ func (_m *mockAlertManager) GetAlerts(ctx context.Context, params *services.Filt
 	return r0, r1
 }
 
+// RenderNotificationTemplate provides a mock function with given fields: t, labels, annotations
+func (_m *mockAlertManager) RenderNotificationTemplate(t *models.NotificationTemplate, labels map[string]string, annotations map[string]string) (string, string, error) {
+	ret := _m.Called(t, labels, annotations)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*models.NotificationTemplate, map[string]string, map[string]string) string); ok {
+		r0 = rf(t, labels, annotations)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(*models.NotificationTemplate, map[string]string, map[string]string) string); ok {
+		r1 = rf(t, labels, annotations)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*models.NotificationTemplate, map[string]string, map[string]string) error); ok {
+		r2 = rf(t, labels, annotations)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // RequestConfigurationUpdate provides a mock function with given fields:
 func (_m *mockAlertManager) RequestConfigurationUpdate() {
 	_m.Called()
 }
 
+// SendTestNotification provides a mock function with given fields: ctx, channel
+func (_m *mockAlertManager) SendTestNotification(ctx context.Context, channel *models.Channel) error {
+	ret := _m.Called(ctx, channel)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Channel) error); ok {
+		r0 = rf(ctx, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SilenceAlerts provides a mock function with given fields: ctx, alerts
 func (_m *mockAlertManager) SilenceAlerts(ctx context.Context, alerts []*ammodels.GettableAlert) error {
 	ret := _m.Called(ctx, alerts)
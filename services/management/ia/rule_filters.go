@@ -0,0 +1,98 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// GetRuleFiltersRequest is a GetRuleFilters JSON API request.
+type GetRuleFiltersRequest struct {
+	RuleID string `json:"rule_id"`
+}
+
+// ChangeRuleFiltersRequest is a ChangeRuleFilters JSON API request.
+//
+// Filters of all types are accepted: "=", "=~", "!=", "!~", ">", ">=", "<" and "<=".
+// All of them are applied to the rule expression, so alerts not matching them don't fire at all.
+// Filters use MetricsQL label_match, label_mismatch and label_value functions, so they work only
+// with VictoriaMetrics which PMM uses; resulting rule is validated by vmalert before it is saved.
+type ChangeRuleFiltersRequest struct {
+	RuleID  string          `json:"rule_id"`
+	Filters []models.Filter `json:"filters"`
+}
+
+// RuleFiltersResponse is a GetRuleFilters and ChangeRuleFilters JSON API response.
+type RuleFiltersResponse struct {
+	Filters []models.Filter `json:"filters"`
+}
+
+// GetRuleFilters returns filters of all types of the rule.
+func (s *RulesService) GetRuleFilters(ctx context.Context, req *GetRuleFiltersRequest) (*RuleFiltersResponse, error) {
+	rule, err := models.FindRuleByID(s.db.Querier, req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuleFiltersResponse{Filters: convertRuleFilters(rule.Filters)}, nil
+}
+
+// ChangeRuleFilters replaces filters of the rule with given filters of any types.
+func (s *RulesService) ChangeRuleFilters(ctx context.Context, req *ChangeRuleFiltersRequest) (*RuleFiltersResponse, error) {
+	filters := models.Filters(req.Filters)
+	for _, f := range filters {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	rule, err := models.FindRuleByID(s.db.Querier, req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate the rule with new filters before opening transaction, as vmalert call may be slow
+	changed := *rule
+	changed.Filters = filters
+	if err = s.validateRule(ctx, &changed); err != nil {
+		return nil, err
+	}
+
+	e := s.db.InTransaction(func(tx *reform.TX) error {
+		rule, err = models.ChangeRuleFilters(tx.Querier, req.RuleID, filters)
+		return err
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	s.updateConfigurations()
+
+	return &RuleFiltersResponse{Filters: convertRuleFilters(rule.Filters)}, nil
+}
+
+// convertRuleFilters returns rule filters, never nil.
+func convertRuleFilters(filters models.Filters) []models.Filter {
+	if filters == nil {
+		return []models.Filter{}
+	}
+	return filters
+}
@@ -0,0 +1,70 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+// GetRuleNotificationTemplateRequest is a GetRuleNotificationTemplate JSON API request.
+type GetRuleNotificationTemplateRequest struct {
+	RuleID string `json:"rule_id"`
+}
+
+// ChangeRuleNotificationTemplateRequest is a ChangeRuleNotificationTemplate JSON API request.
+// Absent or empty notification template resets it to defaults.
+type ChangeRuleNotificationTemplateRequest struct {
+	RuleID               string                       `json:"rule_id"`
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template"`
+}
+
+// RuleNotificationTemplateResponse is a GetRuleNotificationTemplate and ChangeRuleNotificationTemplate JSON API response.
+type RuleNotificationTemplateResponse struct {
+	NotificationTemplate *models.NotificationTemplate `json:"notification_template,omitempty"`
+}
+
+// GetRuleNotificationTemplate returns notification template of the rule.
+func (s *RulesService) GetRuleNotificationTemplate(ctx context.Context, req *GetRuleNotificationTemplateRequest) (*RuleNotificationTemplateResponse, error) {
+	rule, err := models.FindRuleByID(s.db.Querier, req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuleNotificationTemplateResponse{NotificationTemplate: rule.NotificationTemplate}, nil
+}
+
+// ChangeRuleNotificationTemplate replaces notification template of the rule. It overrides templates of the rule's
+// channels for notifications of that rule; notifications routed by the routing tree use channels templates.
+func (s *RulesService) ChangeRuleNotificationTemplate(ctx context.Context, req *ChangeRuleNotificationTemplateRequest) (*RuleNotificationTemplateResponse, error) {
+	var rule *models.Rule
+	e := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		rule, err = models.ChangeRuleNotificationTemplate(tx.Querier, req.RuleID, normalizeNotificationTemplate(req.NotificationTemplate))
+		return err
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	s.updateConfigurations()
+
+	return &RuleNotificationTemplateResponse{NotificationTemplate: rule.NotificationTemplate}, nil
+}
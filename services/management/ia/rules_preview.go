@@ -0,0 +1,203 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	iav1beta1 "github.com/percona/pmm/api/managementpb/ia"
+	"github.com/pkg/errors"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/percona/pmm-managed/models"
+)
+
+const (
+	// previewEvaluationInterval is the same as vmalert's default -evaluationInterval.
+	previewEvaluationInterval = time.Minute
+	// previewMaxPoints is the same as VictoriaMetrics' default -search.maxPointsPerTimeseries.
+	previewMaxPoints = 30000
+)
+
+// PreviewAlert represents an alert that would have been fired by the previewed alert rule.
+type PreviewAlert struct {
+	Labels  map[string]string `json:"labels"`
+	FiredAt time.Time         `json:"fired_at"`
+	// ResolvedAt is nil if alert is still firing at the end of the previewed time range.
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// PreviewAlertRuleRequest is a PreviewAlertRule JSON API request.
+type PreviewAlertRuleRequest struct {
+	// Rule is the same as CreateAlertRule API request body.
+	Rule json.RawMessage `json:"rule"`
+	// Filters of all types replace rule filters if set, see ChangeRuleFiltersRequest.
+	Filters []models.Filter `json:"filters,omitempty"`
+	From    time.Time       `json:"from"`
+	To      time.Time       `json:"to"`
+}
+
+// PreviewAlertRuleResponse is a PreviewAlertRule JSON API response.
+type PreviewAlertRuleResponse struct {
+	Alerts []*PreviewAlert `json:"alerts"`
+}
+
+// PreviewAlertRule evaluates alert rule that would be created by the given request over the past time range
+// and returns alerts that would have been fired, sorted by firing time.
+func (s *RulesService) PreviewAlertRule(ctx context.Context, req *PreviewAlertRuleRequest) (*PreviewAlertRuleResponse, error) {
+	var rule iav1beta1.CreateAlertRuleRequest
+	if err := protojson.Unmarshal(req.Rule, &rule); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid rule: %s.", err)
+	}
+
+	alerts, err := s.previewAlertRule(ctx, &rule, req.Filters, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		alerts = []*PreviewAlert{}
+	}
+
+	return &PreviewAlertRuleResponse{Alerts: alerts}, nil
+}
+
+// previewAlertRule evaluates alert rule that would be created by the given request over the past time range.
+// If filters are not nil, they replace filters of the request.
+func (s *RulesService) previewAlertRule(ctx context.Context, req *iav1beta1.CreateAlertRuleRequest, filters models.Filters, from, to time.Time) ([]*PreviewAlert, error) {
+	// there are no samples in the future, and alerts firing now should not be reported as resolved
+	if now := models.Now(); to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "Preview time range start should be before its end.")
+	}
+	if to.Sub(from)/previewEvaluationInterval >= previewMaxPoints {
+		return nil, status.Errorf(codes.InvalidArgument, "Preview time range should be shorter than %s.",
+			previewEvaluationInterval*previewMaxPoints)
+	}
+
+	params, err := s.convertCreateAlertRuleRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if filters != nil {
+		for _, f := range filters {
+			if err = f.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		params.Filters = filters
+	}
+	ruleM, err := models.NewRule(params)
+	if err != nil {
+		return nil, err
+	}
+	// time series of recording rules are not recorded before the rule is created
+	if len(ruleM.RecordingRules) != 0 {
+		return nil, status.Error(codes.FailedPrecondition, "Rules with recording rules can't be previewed.")
+	}
+
+	r, match, err := prepareRule(ruleM)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, nil
+	}
+
+	value, warnings, err := s.vmQuerier.QueryRange(ctx, r.Expr, v1.Range{
+		Start: from,
+		End:   to,
+		Step:  previewEvaluationInterval,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Failed to evaluate rule expression: %s.", err)
+	}
+	for _, w := range warnings {
+		s.l.Warnf("Rule preview: %s.", w)
+	}
+
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return nil, errors.Errorf("unexpected query result type %s", value.Type())
+	}
+
+	var res []*PreviewAlert
+	for _, stream := range matrix {
+		// rule labels override series labels like in vmalert
+		labels := make(map[string]string, len(stream.Metric)+len(r.Labels))
+		for k, v := range stream.Metric {
+			labels[string(k)] = string(v)
+		}
+		delete(labels, model.MetricNameLabel)
+		for k, v := range r.Labels {
+			labels[k] = v
+		}
+
+		for _, a := range previewFirings(stream.Values, ruleM.For, previewEvaluationInterval, to) {
+			a.Labels = labels
+			res = append(res, a)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].FiredAt.Before(res[j].FiredAt)
+	})
+
+	return res, nil
+}
+
+// previewFirings returns alerts without labels that would have been fired for the time series
+// evaluated with the given interval, given the rule's For duration.
+// Series is active while it has samples on consecutive evaluations; alert is fired when series
+// is active for the For duration and resolved on the first evaluation without sample.
+func previewFirings(values []model.SamplePair, forDuration, interval time.Duration, end time.Time) []*PreviewAlert {
+	var res []*PreviewAlert
+	var activeAt, prev time.Time
+	var current *PreviewAlert
+	for _, v := range values {
+		t := v.Timestamp.Time().UTC()
+
+		if activeAt.IsZero() || t.Sub(prev) > interval {
+			if current != nil {
+				resolvedAt := prev.Add(interval)
+				current.ResolvedAt = &resolvedAt
+				current = nil
+			}
+			activeAt = t
+		}
+		prev = t
+
+		if current == nil && t.Sub(activeAt) >= forDuration {
+			current = &PreviewAlert{FiredAt: t}
+			res = append(res, current)
+		}
+	}
+
+	if resolvedAt := prev.Add(interval); current != nil && !resolvedAt.After(end) {
+		current.ResolvedAt = &resolvedAt
+	}
+
+	return res
+}
@@ -0,0 +1,302 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package ia
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/percona-platform/saas/pkg/alert"
+	"github.com/percona-platform/saas/pkg/common"
+	iav1beta1 "github.com/percona/pmm/api/managementpb/ia"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestPreviewFirings(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Minute)
+	samples := func(minutes ...int) []model.SamplePair {
+		res := make([]model.SamplePair, len(minutes))
+		for i, m := range minutes {
+			res[i] = model.SamplePair{Timestamp: model.TimeFromUnixNano(start.Add(time.Duration(m) * time.Minute).UnixNano()), Value: 1}
+		}
+		return res
+	}
+	at := func(m int) time.Time {
+		return start.Add(time.Duration(m) * time.Minute)
+	}
+	atP := func(m int) *time.Time {
+		t := at(m)
+		return &t
+	}
+
+	for _, tc := range []struct {
+		name     string
+		values   []model.SamplePair
+		for_     time.Duration
+		expected []*PreviewAlert
+	}{{
+		name:     "no samples",
+		for_:     time.Minute,
+		expected: nil,
+	}, {
+		name:   "fires immediately",
+		values: samples(1, 2, 3),
+		expected: []*PreviewAlert{
+			{FiredAt: at(1), ResolvedAt: atP(4)},
+		},
+	}, {
+		name:   "fires after for duration",
+		values: samples(1, 2, 3, 4, 5),
+		for_:   2 * time.Minute,
+		expected: []*PreviewAlert{
+			{FiredAt: at(3), ResolvedAt: atP(6)},
+		},
+	}, {
+		name:     "pending only",
+		values:   samples(1, 2, 4, 5),
+		for_:     2 * time.Minute,
+		expected: nil,
+	}, {
+		name:   "fires twice",
+		values: samples(0, 1, 2, 5, 6, 7),
+		for_:   time.Minute,
+		expected: []*PreviewAlert{
+			{FiredAt: at(1), ResolvedAt: atP(3)},
+			{FiredAt: at(6), ResolvedAt: atP(8)},
+		},
+	}, {
+		name:   "still firing",
+		values: samples(8, 9, 10),
+		for_:   time.Minute,
+		expected: []*PreviewAlert{
+			{FiredAt: at(9)},
+		},
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := previewFirings(tc.values, tc.for_, time.Minute, end)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestPreviewAlertRule(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	newService := func(vmQuerier vmQuerier) *RulesService {
+		return &RulesService{
+			l: logrus.WithField("test", t.Name()),
+			templates: &TemplatesService{
+				templates: map[string]templateInfo{
+					"test_template": {
+						ruleTemplate: ruleTemplate{
+							Template: alert.Template{
+								Name:     "test_template",
+								Version:  1,
+								Summary:  "Test template",
+								Expr:     "up == 0",
+								Severity: common.Warning,
+								Labels:   map[string]string{"foo": "bar"},
+							},
+						},
+					},
+					"conditions_template": {
+						ruleTemplate: ruleTemplate{
+							Template: alert.Template{
+								Name:     "conditions_template",
+								Version:  1,
+								Summary:  "Test template with conditions",
+								Expr:     "[[ .down ]] and [[ .flapping ]]",
+								Severity: common.Warning,
+							},
+							templateExtensions: templateExtensions{
+								Conditions: models.AlertConditions{
+									{Name: "down", Expr: "up == 0"},
+									{Name: "flapping", Expr: "changes(up[5m]) > 1"},
+								},
+							},
+						},
+					},
+					"recording_template": {
+						ruleTemplate: ruleTemplate{
+							Template: alert.Template{
+								Name:     "recording_template",
+								Version:  1,
+								Summary:  "Test template with recording rules",
+								Expr:     `pmm:up:avg{rule_id="[[ .rule_id ]]"} < 1`,
+								Severity: common.Warning,
+							},
+							templateExtensions: templateExtensions{
+								RecordingRules: models.RecordingRules{{Record: "pmm:up:avg", Expr: "avg(up)"}},
+							},
+						},
+					},
+				},
+			},
+			vmQuerier: vmQuerier,
+		}
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		t.Parallel()
+
+		var vmQuerier mockVmQuerier
+		vmQuerier.On("QueryRange", mock.Anything, `label_match(up == 0, "job", "node")`, v1.Range{
+			Start: from,
+			End:   to,
+			Step:  time.Minute,
+		}).Return(model.Matrix{{
+			Metric: model.Metric{"__name__": "up", "job": "node", "instance": "a"},
+			Values: []model.SamplePair{
+				{Timestamp: model.TimeFromUnixNano(from.Add(time.Minute).UnixNano()), Value: 0},
+				{Timestamp: model.TimeFromUnixNano(from.Add(2 * time.Minute).UnixNano()), Value: 0},
+			},
+		}}, v1.Warnings(nil), nil)
+
+		s := newService(&vmQuerier)
+		actual, err := s.previewAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
+			TemplateName: "test_template",
+			For:          durationpb.New(time.Minute),
+			Filters: []*iav1beta1.Filter{{
+				Type:  iav1beta1.FilterType_EQUAL,
+				Key:   "job",
+				Value: "node",
+			}},
+			CustomLabels: map[string]string{"team": "dba"},
+		}, nil, from, to)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, from.Add(2*time.Minute), actual[0].FiredAt)
+		require.NotNil(t, actual[0].ResolvedAt)
+		assert.Equal(t, from.Add(3*time.Minute), *actual[0].ResolvedAt)
+		assert.Equal(t, "node", actual[0].Labels["job"])
+		assert.Equal(t, "bar", actual[0].Labels["foo"])
+		assert.Equal(t, "dba", actual[0].Labels["team"])
+		assert.NotContains(t, actual[0].Labels, "__name__")
+		vmQuerier.AssertExpectations(t)
+	})
+
+	t.Run("JSON API", func(t *testing.T) {
+		t.Parallel()
+
+		var vmQuerier mockVmQuerier
+		vmQuerier.On("QueryRange", mock.Anything, `label_mismatch(up == 0, "job", "mysql")`, mock.Anything).
+			Return(model.Matrix{{
+				Metric: model.Metric{"job": "node"},
+				Values: []model.SamplePair{
+					{Timestamp: model.TimeFromUnixNano(to.UnixNano()), Value: 0},
+				},
+			}}, v1.Warnings(nil), nil)
+
+		s := newService(&vmQuerier)
+		actual, err := s.PreviewAlertRule(context.Background(), &PreviewAlertRuleRequest{
+			Rule:    []byte(`{"template_name": "test_template", "filters": [{"type": "EQUAL", "key": "job", "value": "mysql"}]}`),
+			Filters: []models.Filter{{Type: models.NotEqual, Key: "job", Val: "mysql"}},
+			From:    from,
+			To:      to,
+		})
+		require.NoError(t, err)
+		require.Len(t, actual.Alerts, 1)
+		assert.Equal(t, to, actual.Alerts[0].FiredAt)
+		assert.Nil(t, actual.Alerts[0].ResolvedAt)
+		vmQuerier.AssertExpectations(t)
+
+		_, err = s.PreviewAlertRule(context.Background(), &PreviewAlertRuleRequest{Rule: []byte(`{"for": 1}`), From: from, To: to})
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, `Invalid rule: .*`, err)
+	})
+
+	t.Run("still firing", func(t *testing.T) {
+		t.Parallel()
+
+		last := time.Now().UTC().Truncate(time.Minute)
+		var vmQuerier mockVmQuerier
+		vmQuerier.On("QueryRange", mock.Anything, "up == 0", mock.MatchedBy(func(r v1.Range) bool {
+			return !r.End.After(time.Now())
+		})).Return(model.Matrix{{
+			Metric: model.Metric{"job": "node"},
+			Values: []model.SamplePair{
+				{Timestamp: model.TimeFromUnixNano(last.UnixNano()), Value: 0},
+			},
+		}}, v1.Warnings(nil), nil)
+
+		s := newService(&vmQuerier)
+		actual, err := s.previewAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
+			TemplateName: "test_template",
+		}, nil, last.Add(-time.Hour), last.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, last, actual[0].FiredAt)
+		assert.Nil(t, actual[0].ResolvedAt)
+		vmQuerier.AssertExpectations(t)
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		t.Parallel()
+
+		var vmQuerier mockVmQuerier
+		vmQuerier.On("QueryRange", mock.Anything, "(up == 0) and (changes(up[5m]) > 1)", mock.Anything).
+			Return(model.Matrix{}, v1.Warnings(nil), nil)
+
+		s := newService(&vmQuerier)
+		actual, err := s.previewAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
+			TemplateName: "conditions_template",
+		}, nil, from, to)
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+		vmQuerier.AssertExpectations(t)
+	})
+
+	t.Run("recording rules", func(t *testing.T) {
+		t.Parallel()
+
+		s := newService(new(mockVmQuerier))
+		_, err := s.previewAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
+			TemplateName: "recording_template",
+		}, nil, from, to)
+		tests.AssertGRPCError(t, status.New(codes.FailedPrecondition, "Rules with recording rules can't be previewed."), err)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		t.Parallel()
+
+		s := newService(new(mockVmQuerier))
+		_, err := s.previewAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
+			TemplateName: "test_template",
+		}, nil, to, from)
+		assert.Error(t, err)
+	})
+}
@@ -19,10 +19,13 @@ package ia
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -50,18 +53,20 @@ const (
 
 // RulesService represents API for Integrated Alerting Rules.
 type RulesService struct {
-	db           *reform.DB
-	l            *logrus.Entry
-	templates    *TemplatesService
-	vmalert      vmAlert
-	alertManager alertManager
-	rulesPath    string // used for testing
+	db            *reform.DB
+	l             *logrus.Entry
+	templates     *TemplatesService
+	vmalert       vmAlert
+	externalRules vmAlertExternalRules
+	alertManager  alertManager
+	vmQuerier     vmQuerier
+	rulesPath     string // used for testing
 
 	iav1beta1.UnimplementedRulesServer
 }
 
 // NewRulesService creates an API for Integrated Alerting Rules.
-func NewRulesService(db *reform.DB, templates *TemplatesService, vmalert vmAlert, alertManager alertManager) *RulesService {
+func NewRulesService(db *reform.DB, templates *TemplatesService, vmalert vmAlert, externalRules vmAlertExternalRules, alertManager alertManager, vmQuerier vmQuerier) *RulesService {
 	l := logrus.WithField("component", "management/ia/rules")
 
 	err := dir.CreateDataDir(rulesDir, "pmm", "pmm", dirPerm)
@@ -70,12 +75,14 @@ func NewRulesService(db *reform.DB, templates *TemplatesService, vmalert vmAlert
 	}
 
 	s := &RulesService{
-		db:           db,
-		l:            l,
-		templates:    templates,
-		vmalert:      vmalert,
-		alertManager: alertManager,
-		rulesPath:    rulesDir,
+		db:            db,
+		l:             l,
+		templates:     templates,
+		vmalert:       vmalert,
+		externalRules: externalRules,
+		alertManager:  alertManager,
+		vmQuerier:     vmQuerier,
+		rulesPath:     rulesDir,
 	}
 	s.updateConfigurations()
 
@@ -103,10 +110,12 @@ type ruleGroup struct {
 	Rules []rule `yaml:"rules"`
 }
 
+// rule represents either alerting or recording rule.
 type rule struct {
-	Alert       string              `yaml:"alert"` // Rule ID.
+	Record      string              `yaml:"record,omitempty"`
+	Alert       string              `yaml:"alert,omitempty"` // Rule ID.
 	Expr        string              `yaml:"expr"`
-	Duration    promconfig.Duration `yaml:"for"`
+	Duration    promconfig.Duration `yaml:"for,omitempty"`
 	Labels      map[string]string   `yaml:"labels,omitempty"`
 	Annotations map[string]string   `yaml:"annotations,omitempty"`
 }
@@ -162,67 +171,187 @@ func (s *RulesService) prepareRulesFiles(rules []*models.Rule) ([]ruleFile, erro
 			continue
 		}
 
-		r := rule{
-			Alert:       ruleM.ID,
-			Duration:    promconfig.Duration(ruleM.For),
-			Labels:      make(map[string]string),
-			Annotations: make(map[string]string),
+		f, match, err := prepareRuleFile(ruleM)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			s.l.Debugf("Skipping rule %s as its filters never match its labels.", ruleM.ID)
+			continue
 		}
 
-		params := ruleM.ParamsValues.AsStringMap()
+		res = append(res, *f)
+	}
 
-		var err error
-		r.Expr, err = fillExprWithParams(ruleM.ExprTemplate, params)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to fill rule expression with parameters")
+	return res, nil
+}
+
+// prepareRuleFile converts IA rule to vmalert rule file with rule's recording rules followed by alerting rule.
+// False is returned if rule filters never match its labels.
+func prepareRuleFile(ruleM *models.Rule) (*ruleFile, bool, error) {
+	r, match, err := prepareRule(ruleM)
+	if err != nil || !match {
+		return nil, match, err
+	}
+
+	rules, err := prepareRecordingRules(ruleM.RecordingRules, exprParams(ruleM.ID, ruleM.ParamsValues))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &ruleFile{
+		Group: []ruleGroup{{
+			Name:  "PMM Integrated Alerting",
+			Rules: append(rules, *r),
+		}},
+	}, true, nil
+}
+
+// prepareRecordingRules converts recording rules to vmalert rules with filled expressions and labels.
+// Rule ID label is added, so time series recorded for different IA rules never clash.
+func prepareRecordingRules(recordingRules models.RecordingRules, params map[string]string) ([]rule, error) {
+	res := make([]rule, 0, len(recordingRules)+1)
+	for _, rr := range recordingRules {
+		r := rule{
+			Record: rr.Record,
+			Labels: make(map[string]string, len(rr.Labels)+1),
 		}
 
-		annotations, err := ruleM.GetAnnotations()
+		var err error
+		r.Expr, err = fillExprWithParams(rr.Expr, params)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to read rule annotations")
+			return nil, errors.Wrapf(err, "failed to fill recording rule %q expression with parameters", rr.Record)
 		}
-		// Copy annotations form template
-		if err = transformMaps(annotations, r.Annotations, params); err != nil {
-			return nil, errors.Wrap(err, "failed to fill template annotations placeholders")
+
+		if err = transformMaps(rr.Labels, r.Labels, params); err != nil {
+			return nil, errors.Wrapf(err, "failed to fill recording rule %q labels placeholders", rr.Record)
 		}
+		r.Labels["rule_id"] = params[ruleIDPlaceholder]
 
-		r.Annotations["rule"] = ruleM.Name
+		res = append(res, r)
+	}
 
-		labels, err := ruleM.GetLabels()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to read rule labels")
-		}
+	return res, nil
+}
 
-		// Copy labels form template
-		if err = transformMaps(labels, r.Labels, params); err != nil {
-			return nil, errors.Wrap(err, "failed to fill template labels placeholders")
-		}
+// prepareRule converts IA rule to vmalert rule with filled expression, labels and annotations.
+// False is returned if rule filters never match its labels.
+func prepareRule(ruleM *models.Rule) (*rule, bool, error) {
+	r := rule{
+		Alert:       ruleM.ID,
+		Duration:    promconfig.Duration(ruleM.For),
+		Labels:      make(map[string]string),
+		Annotations: make(map[string]string),
+	}
 
-		customLabels, err := ruleM.GetCustomLabels()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to read rule custom labels")
+	params := exprParams(ruleM.ID, ruleM.ParamsValues)
+
+	var err error
+	r.Expr, err = fillExprWithConditions(ruleM.ExprTemplate, ruleM.Conditions, params)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to fill rule expression with parameters")
+	}
+
+	annotations, err := ruleM.GetAnnotations()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read rule annotations")
+	}
+	// Copy annotations form template
+	if err = transformMaps(annotations, r.Annotations, params); err != nil {
+		return nil, false, errors.Wrap(err, "failed to fill template annotations placeholders")
+	}
+
+	r.Annotations["rule"] = ruleM.Name
+
+	labels, err := ruleM.GetLabels()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read rule labels")
+	}
+
+	// Copy labels form template
+	if err = transformMaps(labels, r.Labels, params); err != nil {
+		return nil, false, errors.Wrap(err, "failed to fill template labels placeholders")
+	}
+
+	customLabels, err := ruleM.GetCustomLabels()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read rule custom labels")
+	}
+
+	// Add rule labels
+	if err = transformMaps(customLabels, r.Labels, params); err != nil {
+		return nil, false, errors.Wrap(err, "failed to fill rule labels placeholders")
+	}
+
+	// Do not add volatile values like `{{ $value }}` to labels as it will break alerts identity.
+	r.Labels["ia"] = "1"
+	r.Labels["severity"] = common.Severity(ruleM.Severity).String()
+	r.Labels["rule_id"] = ruleM.ID
+	r.Labels["template_name"] = ruleM.TemplateName
+
+	// vmalert adds alertname label to all alerts
+	staticLabels := make(map[string]string, len(r.Labels)+1)
+	for k, v := range r.Labels {
+		staticLabels[k] = v
+	}
+	staticLabels["alertname"] = r.Alert
+
+	var match bool
+	r.Expr, match, err = applyFilters(r.Expr, ruleM.Filters, staticLabels)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to apply rule filters")
+	}
+
+	return &r, match, nil
+}
+
+// applyFilters returns rule expression with filters applied to the resulting time series.
+// Filters on labels set by the rule itself can't be applied to the expression, they are checked against
+// given static labels instead; false is returned if they are not satisfied, so the rule never fires.
+// All numeric filters are combined into a single condition, so the expression is repeated once per numeric filter.
+// MetricsQL functions are used, so expression can be evaluated by VictoriaMetrics only.
+func applyFilters(expr string, filters models.Filters, staticLabels map[string]string) (string, bool, error) {
+	var numeric models.Filters
+	for _, f := range filters {
+		if _, ok := staticLabels[f.Key]; ok {
+			match, err := f.Match(staticLabels)
+			if err != nil || !match {
+				return "", false, err
+			}
+			continue
 		}
 
-		// Add rule labels
-		if err = transformMaps(customLabels, r.Labels, params); err != nil {
-			return nil, errors.Wrap(err, "failed to fill rule labels placeholders")
+		switch f.Type {
+		case models.Equal:
+			expr = fmt.Sprintf("label_match(%s, %q, %q)", expr, f.Key, regexp.QuoteMeta(f.Val))
+		case models.Regex:
+			expr = fmt.Sprintf("label_match(%s, %q, %q)", expr, f.Key, f.Val)
+		case models.NotEqual:
+			expr = fmt.Sprintf("label_mismatch(%s, %q, %q)", expr, f.Key, regexp.QuoteMeta(f.Val))
+		case models.NotRegex:
+			expr = fmt.Sprintf("label_mismatch(%s, %q, %q)", expr, f.Key, f.Val)
+		case models.Greater, models.GreaterOrEqual, models.Less, models.LessOrEqual:
+			// applied below to the expression with all other filters
+			numeric = append(numeric, f)
+		default:
+			return "", false, errors.Errorf("unknown filter type %q", f.Type)
 		}
+	}
 
-		// Do not add volatile values like `{{ $value }}` to labels as it will break alerts identity.
-		r.Labels["ia"] = "1"
-		r.Labels["severity"] = common.Severity(ruleM.Severity).String()
-		r.Labels["rule_id"] = ruleM.ID
-		r.Labels["template_name"] = ruleM.TemplateName
+	if len(numeric) == 0 {
+		return expr, true, nil
+	}
 
-		res = append(res, ruleFile{
-			Group: []ruleGroup{{
-				Name:  "PMM Integrated Alerting",
-				Rules: []rule{r},
-			}},
-		})
+	conds := make([]string, len(numeric))
+	for i, f := range numeric {
+		value, err := strconv.ParseFloat(f.Val, 64)
+		if err != nil {
+			return "", false, errors.Wrapf(err, "invalid numeric filter value %q", f.Val)
+		}
+		conds[i] = fmt.Sprintf("label_value(%s, %q) %s %s", expr, f.Key, f.Type, strconv.FormatFloat(value, 'g', -1, 64))
 	}
 
-	return res, nil
+	return fmt.Sprintf("(%s) if (%s)", expr, strings.Join(conds, " and ")), true, nil
 }
 
 // fills templates found in labels and annotaitons with values.
@@ -244,13 +373,14 @@ func transformMaps(src map[string]string, dest map[string]string, data map[strin
 
 // dump the transformed IA templates to a file.
 func (s *RulesService) writeRuleFile(rule *ruleFile) error {
-	b, err := yaml.Marshal(rule)
+	b, err := marshalRuleFile(rule)
 	if err != nil {
-		return errors.Errorf("failed to marshal rule %v", err)
+		return err
 	}
-	b = append([]byte("---\n"), b...)
 
-	alertRule := rule.Group[0].Rules[0]
+	// alerting rule follows recording rules
+	rules := rule.Group[0].Rules
+	alertRule := rules[len(rules)-1]
 	if alertRule.Alert == "" {
 		return errors.New("alert rule not initialized")
 	}
@@ -264,6 +394,42 @@ func (s *RulesService) writeRuleFile(rule *ruleFile) error {
 	return nil
 }
 
+// marshalRuleFile returns vmalert rule file content.
+func marshalRuleFile(rule *ruleFile) ([]byte, error) {
+	b, err := yaml.Marshal(rule)
+	if err != nil {
+		return nil, errors.Errorf("failed to marshal rule %v", err)
+	}
+	return append([]byte("---\n"), b...), nil
+}
+
+// validateRule validates vmalert rule file that would be written for given IA rule.
+func (s *RulesService) validateRule(ctx context.Context, ruleM *models.Rule) error {
+	f, match, err := prepareRuleFile(ruleM)
+	if err != nil || !match {
+		return err
+	}
+
+	b, err := marshalRuleFile(f)
+	if err != nil {
+		return err
+	}
+
+	return s.externalRules.ValidateRules(ctx, string(b))
+}
+
+// validateNewRule validates vmalert rule file that would be written for IA rule created with given params.
+// Rule ID isn't known before creation, placeholder one doesn't affect validity.
+func (s *RulesService) validateNewRule(ctx context.Context, params *models.CreateRuleParams) error {
+	rule, err := models.NewRule(params)
+	if err != nil {
+		return err
+	}
+	rule.ID = "/rule_id/new"
+
+	return s.validateRule(ctx, rule)
+}
+
 // ListAlertRules returns a list of all Integrated Alerting rules.
 func (s *RulesService) ListAlertRules(ctx context.Context, req *iav1beta1.ListAlertRulesRequest) (*iav1beta1.ListAlertRulesResponse, error) {
 	var pageIndex int
@@ -338,7 +504,35 @@ func (s *RulesService) convertAlertRules(rules []*models.Rule, channels []*model
 }
 
 // CreateAlertRule creates Integrated Alerting rule.
+// Routing tree and escalation policies are managed via AlertRoutes JSON API, see AlertRoutesService.
 func (s *RulesService) CreateAlertRule(ctx context.Context, req *iav1beta1.CreateAlertRuleRequest) (*iav1beta1.CreateAlertRuleResponse, error) {
+	params, err := s.convertCreateAlertRuleRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate before opening transaction, as vmalert call may be slow
+	if err = s.validateNewRule(ctx, params); err != nil {
+		return nil, err
+	}
+
+	var rule *models.Rule
+	errTX := s.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		rule, err = models.CreateRule(tx.Querier, params)
+		return err
+	})
+	if errTX != nil {
+		return nil, errTX
+	}
+
+	s.updateConfigurations()
+
+	return &iav1beta1.CreateAlertRuleResponse{RuleId: rule.ID}, nil
+}
+
+// convertCreateAlertRuleRequest converts and validates alert rule creation request.
+func (s *RulesService) convertCreateAlertRuleRequest(req *iav1beta1.CreateAlertRuleRequest) (*models.CreateRuleParams, error) {
 	if req.TemplateName != "" && req.SourceRuleId != "" {
 		return nil, status.Errorf(codes.InvalidArgument, "Both template name and source rule id are specified.")
 	}
@@ -379,6 +573,8 @@ func (s *RulesService) CreateAlertRule(ctx context.Context, req *iav1beta1.Creat
 		params.DefaultSeverity = models.Severity(template.Severity)
 		params.Labels = template.Labels
 		params.Annotations = template.Annotations
+		params.RecordingRules = template.RecordingRules
+		params.Conditions = template.Conditions
 
 		params.ParamsDefinitions, err = models.ConvertParamsDefinitions(template.Params)
 		if err != nil {
@@ -396,6 +592,8 @@ func (s *RulesService) CreateAlertRule(ctx context.Context, req *iav1beta1.Creat
 		params.DefaultFor = sourceRule.DefaultFor
 		params.DefaultSeverity = sourceRule.DefaultSeverity
 		params.ParamsDefinitions = sourceRule.ParamsDefinitions
+		params.RecordingRules = sourceRule.RecordingRules
+		params.Conditions = sourceRule.Conditions
 
 		params.Labels, err = sourceRule.GetLabels()
 		if err != nil {
@@ -412,25 +610,16 @@ func (s *RulesService) CreateAlertRule(ctx context.Context, req *iav1beta1.Creat
 		return nil, err
 	}
 
-	// Check that we can compile expression with given parameters
-	_, err = fillExprWithParams(params.ExprTemplate, params.ParamsValues.AsStringMap())
-	if err != nil {
+	// Check that we can compile expressions with given parameters, rule ID isn't known yet
+	values := exprParams("", params.ParamsValues)
+	if _, err = fillExprWithConditions(params.ExprTemplate, params.Conditions, values); err != nil {
 		return nil, err
 	}
-
-	var rule *models.Rule
-	errTX := s.db.InTransaction(func(tx *reform.TX) error {
-		var err error
-		rule, err = models.CreateRule(tx.Querier, params)
-		return err
-	})
-	if errTX != nil {
-		return nil, errTX
+	if _, err = prepareRecordingRules(params.RecordingRules, values); err != nil {
+		return nil, err
 	}
 
-	s.updateConfigurations()
-
-	return &iav1beta1.CreateAlertRuleResponse{RuleId: rule.ID}, nil
+	return params, nil
 }
 
 // UpdateAlertRule updates Integrated Alerting rule.
@@ -454,22 +643,41 @@ func (s *RulesService) UpdateAlertRule(ctx context.Context, req *iav1beta1.Updat
 	if err != nil {
 		return nil, err
 	}
-	e := s.db.InTransaction(func(tx *reform.TX) error {
-		rule, err := models.FindRuleByID(tx.Querier, req.RuleId)
-		if err != nil {
-			return err
-		}
 
-		if err = validateParameters(rule.ParamsDefinitions, params.ParamsValues); err != nil {
-			return err
-		}
+	rule, err := models.FindRuleByID(s.db.Querier, req.RuleId)
+	if err != nil {
+		return nil, err
+	}
 
-		// Check that we can compile expression with given parameters
-		if _, err = fillExprWithParams(rule.ExprTemplate, params.ParamsValues.AsStringMap()); err != nil {
-			return errors.Wrap(err, "failed to fill expression template with parameters values")
-		}
+	if err = validateParameters(rule.ParamsDefinitions, params.ParamsValues); err != nil {
+		return nil, err
+	}
 
-		_, err = models.ChangeRule(tx.Querier, req.RuleId, params)
+	// Check that we can compile expressions with given parameters
+	values := exprParams(rule.ID, params.ParamsValues)
+	if _, err = fillExprWithConditions(rule.ExprTemplate, rule.Conditions, values); err != nil {
+		return nil, errors.Wrap(err, "failed to fill expression template with parameters values")
+	}
+	if _, err = prepareRecordingRules(rule.RecordingRules, values); err != nil {
+		return nil, err
+	}
+
+	// gRPC API doesn't have notification templates (see ChangeRuleNotificationTemplate), keep the current one
+	params.NotificationTemplate = rule.NotificationTemplate
+
+	// API can't express negative and numeric filters, keep the current ones
+	params.Filters = append(params.Filters, extendedFilters(rule.Filters)...)
+
+	// validate the changed rule before opening transaction, as vmalert call may be slow
+	if err = models.ApplyChangeRuleParams(rule, params); err != nil {
+		return nil, err
+	}
+	if err = s.validateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	e := s.db.InTransaction(func(tx *reform.TX) error {
+		_, err := models.ChangeRule(tx.Querier, req.RuleId, params)
 		return err
 	})
 	if e != nil {
@@ -621,17 +829,32 @@ func parseBooleanFlag(bf iav1beta1.BooleanFlag) *bool {
 	}
 }
 
-func convertModelToFilterType(filterType models.FilterType) iav1beta1.FilterType {
+// convertModelToFilterType converts filter type to API.
+// API has only equal and regex filter types, false is returned for other ones;
+// such filters are managed via Rules/ChangeFilters JSON API, see ChangeRuleFilters.
+func convertModelToFilterType(filterType models.FilterType) (iav1beta1.FilterType, bool) {
 	switch filterType {
 	case models.Equal:
-		return iav1beta1.FilterType_EQUAL
+		return iav1beta1.FilterType_EQUAL, true
 	case models.Regex:
-		return iav1beta1.FilterType_REGEX
+		return iav1beta1.FilterType_REGEX, true
 	default:
-		return iav1beta1.FilterType_FILTER_TYPE_INVALID
+		return iav1beta1.FilterType_FILTER_TYPE_INVALID, false
 	}
 }
 
+// extendedFilters returns filters of types which API can't express.
+func extendedFilters(filters models.Filters) models.Filters {
+	var res models.Filters
+	for _, f := range filters {
+		if _, ok := convertModelToFilterType(f.Type); !ok {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// convertFiltersToModel converts API filters, which have only equal and regex types.
 func convertFiltersToModel(filters []*iav1beta1.Filter) (models.Filters, error) {
 	res := make(models.Filters, len(filters))
 	for i, filter := range filters {
@@ -660,6 +883,10 @@ func convertFiltersToModel(filters []*iav1beta1.Filter) (models.Filters, error)
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "Unexpected filter type.")
 		}
+
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
 		res[i] = f
 	}
 
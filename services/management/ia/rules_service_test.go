@@ -25,9 +25,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/percona-platform/saas/pkg/common"
 	"github.com/percona/pmm/api/managementpb"
 	iav1beta1 "github.com/percona/pmm/api/managementpb/ia"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -57,6 +60,9 @@ func TestCreateAlertRule(t *testing.T) {
 	alertManager.On("RequestConfigurationUpdate").Return()
 	var vmAlert mockVmAlert
 	vmAlert.On("RequestConfigurationUpdate").Return()
+	var vmQuerier mockVmQuerier
+	var externalRules mockVmAlertExternalRules
+	externalRules.On("ValidateRules", ctx, mock.Anything).Return(nil)
 
 	// Create channel
 	channels := NewChannelsService(db, &alertManager)
@@ -86,7 +92,7 @@ func TestCreateAlertRule(t *testing.T) {
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		resp, err := rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -169,7 +175,7 @@ groups:
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		_, err = rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -210,7 +216,7 @@ groups:
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		_, err = rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -251,7 +257,7 @@ groups:
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		_, err = rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -288,7 +294,7 @@ groups:
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		_, err = rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -330,7 +336,7 @@ groups:
 		})
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		_, err = rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "unknown template",
@@ -370,7 +376,7 @@ groups:
 		vmAlert.On("RequestConfigurationUpdate").Return()
 
 		// Create test rule
-		rules := NewRulesService(db, templates, &vmAlert, &alertManager)
+		rules := NewRulesService(db, templates, &vmAlert, &externalRules, &alertManager, &vmQuerier)
 		rules.rulesPath = testDir
 		resp, err := rules.CreateAlertRule(context.Background(), &iav1beta1.CreateAlertRuleRequest{
 			TemplateName: "test_template",
@@ -429,3 +435,171 @@ func TestTemplatesRuleExpr(t *testing.T) {
 
 	require.Equal(t, "5 > 2 and 2 < 4", actual)
 }
+
+func TestApplyFilters(t *testing.T) {
+	t.Parallel()
+
+	staticLabels := map[string]string{
+		"alertname": "/rule_id/1",
+		"severity":  "critical",
+	}
+
+	t.Run("expression filters", func(t *testing.T) {
+		t.Parallel()
+
+		expr, match, err := applyFilters("up == 0", models.Filters{
+			{Type: models.Equal, Key: "environment", Val: "prod"},
+			{Type: models.NotEqual, Key: "environment", Val: "dev.1"},
+			{Type: models.NotRegex, Key: "service_name", Val: "mongo.*"},
+			{Type: models.Greater, Key: "port", Val: "3000"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.True(t, match)
+		inner := `label_mismatch(label_mismatch(label_match(up == 0, "environment", "prod"), "environment", "dev\\.1"), "service_name", "mongo.*")`
+		assert.Equal(t, `(`+inner+`) if (label_value(`+inner+`, "port") > 3000)`, expr)
+	})
+
+	t.Run("equal filters", func(t *testing.T) {
+		t.Parallel()
+
+		// series without matching label are dropped from the expression result, so they don't fire
+		expr, match, err := applyFilters("up == 0", models.Filters{
+			{Type: models.Equal, Key: "service_name", Val: "mysql.1"},
+			{Type: models.Regex, Key: "environment", Val: "prod|stage"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.True(t, match)
+		assert.Equal(t, `label_match(label_match(up == 0, "service_name", "mysql\\.1"), "environment", "prod|stage")`, expr)
+
+		_, match, err = applyFilters("up == 0", models.Filters{
+			{Type: models.Equal, Key: "severity", Val: "warning"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("numeric filters", func(t *testing.T) {
+		t.Parallel()
+
+		expr, match, err := applyFilters("up == 0", models.Filters{
+			{Type: models.Greater, Key: "port", Val: "3000"},
+			{Type: models.LessOrEqual, Key: "port", Val: "3306.50"},
+			{Type: models.GreaterOrEqual, Key: "replication_lag", Val: "1e3"},
+			{Type: models.Less, Key: "node_id", Val: "-1"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.True(t, match)
+		assert.Equal(t, `(up == 0) if (label_value(up == 0, "port") > 3000 and label_value(up == 0, "port") <= 3306.5`+
+			` and label_value(up == 0, "replication_lag") >= 1000 and label_value(up == 0, "node_id") < -1)`, expr)
+
+		// static label is compared as a number
+		expr, match, err = applyFilters("up == 0", models.Filters{
+			{Type: models.Greater, Key: "threshold", Val: "5"},
+		}, map[string]string{"threshold": "10"})
+		require.NoError(t, err)
+		assert.True(t, match)
+		assert.Equal(t, "up == 0", expr)
+
+		_, match, err = applyFilters("up == 0", models.Filters{
+			{Type: models.Less, Key: "threshold", Val: "5"},
+		}, map[string]string{"threshold": "10"})
+		require.NoError(t, err)
+		assert.False(t, match)
+
+		_, _, err = applyFilters("up == 0", models.Filters{
+			{Type: models.Less, Key: "port", Val: "many"},
+		}, staticLabels)
+		assert.EqualError(t, err, `invalid numeric filter value "many": strconv.ParseFloat: parsing "many": invalid syntax`)
+	})
+
+	t.Run("static labels", func(t *testing.T) {
+		t.Parallel()
+
+		expr, match, err := applyFilters("up == 0", models.Filters{
+			{Type: models.NotEqual, Key: "severity", Val: "warning"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.True(t, match)
+		assert.Equal(t, "up == 0", expr)
+
+		_, match, err = applyFilters("up == 0", models.Filters{
+			{Type: models.NotRegex, Key: "severity", Val: "crit.*"},
+		}, staticLabels)
+		require.NoError(t, err)
+		assert.False(t, match)
+	})
+}
+
+func TestExtendedFilters(t *testing.T) {
+	t.Parallel()
+
+	filters := models.Filters{
+		{Type: models.Equal, Key: "environment", Val: "prod"},
+		{Type: models.NotEqual, Key: "environment", Val: "dev"},
+		{Type: models.Regex, Key: "service_name", Val: "mysql.*"},
+		{Type: models.LessOrEqual, Key: "port", Val: "3306"},
+	}
+	assert.Equal(t, models.Filters{
+		{Type: models.NotEqual, Key: "environment", Val: "dev"},
+		{Type: models.LessOrEqual, Key: "port", Val: "3306"},
+	}, extendedFilters(filters))
+
+	r, err := convertRule(logrus.WithField("test", t.Name()), &models.Rule{ID: "/rule_id/1", Filters: filters}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*iav1beta1.Filter{
+		{Type: iav1beta1.FilterType_EQUAL, Key: "environment", Value: "prod"},
+		{Type: iav1beta1.FilterType_REGEX, Key: "service_name", Value: "mysql.*"},
+	}, r.Filters)
+}
+
+func TestPrepareRuleFile(t *testing.T) {
+	t.Parallel()
+
+	ruleM := &models.Rule{
+		ID:           "/rule_id/1",
+		Name:         "rule",
+		TemplateName: "template",
+		ExprTemplate: `[[ .high_load ]] and [[ .low_memory ]]`,
+		ParamsValues: models.AlertExprParamsValues{{Name: "threshold", Type: models.Float, FloatValue: 0.9}},
+		For:          time.Minute,
+		Severity:     models.Severity(common.Warning),
+		RecordingRules: models.RecordingRules{{
+			Record: "pmm:node_load:ratio",
+			Expr:   "node_load1 / on (node_name) count by (node_name) (node_cpu_seconds_total{mode=\"idle\"})",
+			Labels: map[string]string{"threshold": "[[ .threshold ]]"},
+		}},
+		Conditions: models.AlertConditions{
+			{Name: "high_load", Expr: `pmm:node_load:ratio{rule_id="[[ .rule_id ]]"} > [[ .threshold ]]`},
+			{Name: "low_memory", Expr: "node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes < 0.1"},
+		},
+	}
+
+	f, match, err := prepareRuleFile(ruleM)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	b, err := marshalRuleFile(f)
+	require.NoError(t, err)
+
+	expected := `---
+groups:
+    - name: PMM Integrated Alerting
+      rules:
+        - record: pmm:node_load:ratio
+          expr: node_load1 / on (node_name) count by (node_name) (node_cpu_seconds_total{mode="idle"})
+          labels:
+            rule_id: /rule_id/1
+            threshold: "0.9"
+        - alert: /rule_id/1
+          expr: (pmm:node_load:ratio{rule_id="/rule_id/1"} > 0.9) and (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes < 0.1)
+          for: 1m
+          labels:
+            ia: "1"
+            rule_id: /rule_id/1
+            severity: warning
+            template_name: template
+          annotations:
+            rule: rule
+`
+	assert.Equal(t, expected, string(b))
+}
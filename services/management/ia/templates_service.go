@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"net/http"
@@ -44,6 +45,7 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/reform.v1"
+	"gopkg.in/yaml.v3"
 
 	"github.com/percona/pmm-managed/data"
 	"github.com/percona/pmm-managed/models"
@@ -62,12 +64,57 @@ const (
 // TODO We already have models.Template, iav1beta1.Template, and alert.Template.
 //      We probably can remove that type.
 type templateInfo struct {
-	alert.Template
+	ruleTemplate
 	Yaml      string
 	Source    iav1beta1.TemplateSource
 	CreatedAt *time.Time
 }
 
+// templateExtensions represents rule template fields that alert.Template doesn't have yet.
+type templateExtensions struct {
+	RecordingRules models.RecordingRules  `yaml:"recording_rules,omitempty"`
+	Conditions     models.AlertConditions `yaml:"conditions,omitempty"`
+}
+
+// ruleTemplate represents alert.Template with extensions.
+type ruleTemplate struct {
+	alert.Template     `yaml:",inline"`
+	templateExtensions `yaml:",inline"`
+}
+
+// parseTemplates is the same as alert.Parse, but also parses template extensions.
+func parseTemplates(reader io.Reader, params *alert.ParseParams) ([]ruleTemplate, error) {
+	d := yaml.NewDecoder(reader)
+	d.KnownFields(params.DisallowUnknownFields)
+
+	type templates struct {
+		Templates []ruleTemplate `yaml:"templates"`
+	}
+
+	var res []ruleTemplate
+	for {
+		var c templates
+		if err := d.Decode(&c); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, errors.Wrap(err, "failed to parse templates")
+		}
+
+		for _, t := range c.Templates {
+			if err := t.Validate(); err != nil {
+				if params.DisallowInvalidTemplates {
+					return nil, err
+				}
+
+				continue // skip invalid template
+			}
+
+			res = append(res, t)
+		}
+	}
+}
+
 // TemplatesService is responsible for interactions with IA rule templates.
 type TemplatesService struct {
 	db                *reform.DB
@@ -174,22 +221,22 @@ func (s *TemplatesService) CollectTemplates(ctx context.Context) {
 
 	for _, t := range builtInTemplates {
 		templates = append(templates, templateInfo{
-			Template: t,
-			Source:   iav1beta1.TemplateSource_BUILT_IN,
+			ruleTemplate: t,
+			Source:       iav1beta1.TemplateSource_BUILT_IN,
 		})
 	}
 
 	for _, t := range userDefinedTemplates {
 		templates = append(templates, templateInfo{
-			Template: t,
-			Source:   iav1beta1.TemplateSource_USER_FILE,
+			ruleTemplate: t,
+			Source:       iav1beta1.TemplateSource_USER_FILE,
 		})
 	}
 
 	for _, t := range saasTemplates {
 		templates = append(templates, templateInfo{
-			Template: t,
-			Source:   iav1beta1.TemplateSource_SAAS,
+			ruleTemplate: t,
+			Source:       iav1beta1.TemplateSource_SAAS,
 		})
 	}
 
@@ -209,8 +256,8 @@ func (s *TemplatesService) CollectTemplates(ctx context.Context) {
 }
 
 // loadTemplatesFromAssets loads built-in alerting rule templates from pmm-managed binary's assets.
-func (s *TemplatesService) loadTemplatesFromAssets(ctx context.Context) ([]alert.Template, error) {
-	var res []alert.Template
+func (s *TemplatesService) loadTemplatesFromAssets(ctx context.Context) ([]ruleTemplate, error) {
+	var res []ruleTemplate
 	walkDirFunc := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return errors.Wrapf(err, "error occurred while traversing templates folder: %s", path)
@@ -234,7 +281,7 @@ func (s *TemplatesService) loadTemplatesFromAssets(ctx context.Context) ([]alert
 			DisallowUnknownFields:    true,
 			DisallowInvalidTemplates: true,
 		}
-		templates, err := alert.Parse(bytes.NewReader(data), params)
+		templates, err := parseTemplates(bytes.NewReader(data), params)
 		if err != nil {
 			return errors.Wrapf(err, "failed to parse rule template asset: %s", path)
 		}
@@ -273,13 +320,13 @@ func (s *TemplatesService) loadTemplatesFromAssets(ctx context.Context) ([]alert
 }
 
 // loadTemplatesFromUserFiles loads user's alerting rule templates from /srv/ia/templates.
-func (s *TemplatesService) loadTemplatesFromUserFiles(ctx context.Context) ([]alert.Template, error) {
+func (s *TemplatesService) loadTemplatesFromUserFiles(ctx context.Context) ([]ruleTemplate, error) {
 	paths, err := dir.FindFilesWithExtensions(s.userTemplatesPath, "yml", "yaml")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get paths")
 	}
 
-	res := make([]alert.Template, 0, len(paths))
+	res := make([]ruleTemplate, 0, len(paths))
 	for _, path := range paths {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -296,7 +343,7 @@ func (s *TemplatesService) loadTemplatesFromUserFiles(ctx context.Context) ([]al
 			DisallowUnknownFields:    true,
 			DisallowInvalidTemplates: true,
 		}
-		templates, err := alert.Parse(bytes.NewReader(data), params)
+		templates, err := parseTemplates(bytes.NewReader(data), params)
 		if err != nil {
 			s.l.Warnf("Failed to parse rule template file %s.", path)
 			continue
@@ -365,16 +412,22 @@ func (s *TemplatesService) loadTemplatesFromDB() ([]templateInfo, error) {
 
 		res = append(res,
 			templateInfo{
-				Template: alert.Template{
-					Name:        t.Name,
-					Version:     t.Version,
-					Summary:     t.Summary,
-					Expr:        t.Expr,
-					Params:      params,
-					For:         promconfig.Duration(t.For),
-					Severity:    common.Severity(t.Severity),
-					Labels:      labels,
-					Annotations: annotations,
+				ruleTemplate: ruleTemplate{
+					Template: alert.Template{
+						Name:        t.Name,
+						Version:     t.Version,
+						Summary:     t.Summary,
+						Expr:        t.Expr,
+						Params:      params,
+						For:         promconfig.Duration(t.For),
+						Severity:    common.Severity(t.Severity),
+						Labels:      labels,
+						Annotations: annotations,
+					},
+					templateExtensions: templateExtensions{
+						RecordingRules: t.RecordingRules,
+						Conditions:     t.Conditions,
+					},
 				},
 				Yaml:      t.Yaml,
 				Source:    convertSource(t.Source),
@@ -387,7 +440,7 @@ func (s *TemplatesService) loadTemplatesFromDB() ([]templateInfo, error) {
 }
 
 // downloadTemplates downloads IA templates from SaaS.
-func (s *TemplatesService) downloadTemplates(ctx context.Context) ([]alert.Template, error) {
+func (s *TemplatesService) downloadTemplates(ctx context.Context) ([]ruleTemplate, error) {
 	settings, err := models.GetSettings(s.db)
 	if err != nil {
 		return nil, err
@@ -426,7 +479,7 @@ func (s *TemplatesService) downloadTemplates(ctx context.Context) ([]alert.Templ
 		DisallowUnknownFields:    false,
 		DisallowInvalidTemplates: false,
 	}
-	templates, err := alert.Parse(strings.NewReader(resp.File), params)
+	templates, err := parseTemplates(strings.NewReader(resp.File), params)
 	if err != nil {
 		return nil, err
 	}
@@ -435,7 +488,7 @@ func (s *TemplatesService) downloadTemplates(ctx context.Context) ([]alert.Templ
 }
 
 // validateUserTemplate validates user-provided template (API or file).
-func validateUserTemplate(t *alert.Template) error {
+func validateUserTemplate(t *ruleTemplate) error {
 	// TODO move to some better place
 
 	if strings.HasPrefix(t.Name, "pmm_") || strings.HasPrefix(t.Name, "saas_") {
@@ -462,7 +515,30 @@ func validateUserTemplate(t *alert.Template) error {
 		params[p.Name] = value
 	}
 
-	if _, err := fillExprWithParams(t.Expr, params); err != nil {
+	conditions := make(map[string]struct{}, len(t.Conditions))
+	for _, c := range t.Conditions {
+		if c.Name == ruleIDPlaceholder {
+			return errors.Errorf("%s: alert condition name %q is reserved", t.Name, c.Name)
+		}
+		// conditions and parameters share placeholders namespace
+		if _, ok := params[c.Name]; ok {
+			return errors.Errorf("%s: alert condition name %q clashes with parameter name", t.Name, c.Name)
+		}
+		if _, ok := conditions[c.Name]; ok {
+			return errors.Errorf("%s: alert condition name %q is not unique", t.Name, c.Name)
+		}
+		conditions[c.Name] = struct{}{}
+	}
+	if _, ok := params[ruleIDPlaceholder]; ok {
+		return errors.Errorf("%s: parameter name %q is reserved", t.Name, ruleIDPlaceholder)
+	}
+	params[ruleIDPlaceholder] = "/rule_id/template"
+
+	if _, err := fillExprWithConditions(t.Expr, t.Conditions, params); err != nil {
+		return err
+	}
+
+	if _, err := prepareRecordingRules(t.RecordingRules, params); err != nil {
 		return err
 	}
 
@@ -557,7 +633,7 @@ func (s *TemplatesService) CreateTemplate(ctx context.Context, req *iav1beta1.Cr
 		DisallowInvalidTemplates: true,
 	}
 
-	templates, err := alert.Parse(strings.NewReader(req.Yaml), pParams)
+	templates, err := parseTemplates(strings.NewReader(req.Yaml), pParams)
 	if err != nil {
 		s.l.Errorf("failed to parse rule template form request: +%v", err)
 		return nil, status.Error(codes.InvalidArgument, "Failed to parse rule template.")
@@ -574,9 +650,11 @@ func (s *TemplatesService) CreateTemplate(ctx context.Context, req *iav1beta1.Cr
 	}
 
 	params := &models.CreateTemplateParams{
-		Template: &templates[0],
-		Yaml:     req.Yaml,
-		Source:   models.UserAPISource,
+		Template:       &templates[0].Template,
+		RecordingRules: templates[0].RecordingRules,
+		Conditions:     templates[0].Conditions,
+		Yaml:           req.Yaml,
+		Source:         models.UserAPISource,
 	}
 
 	e := s.db.InTransaction(func(tx *reform.TX) error {
@@ -600,7 +678,7 @@ func (s *TemplatesService) UpdateTemplate(ctx context.Context, req *iav1beta1.Up
 		DisallowInvalidTemplates: true,
 	}
 
-	templates, err := alert.Parse(strings.NewReader(req.Yaml), parseParams)
+	templates, err := parseTemplates(strings.NewReader(req.Yaml), parseParams)
 	if err != nil {
 		s.l.Errorf("failed to parse rule template form request: +%v", err)
 		return nil, status.Error(codes.InvalidArgument, "Failed to parse rule template.")
@@ -617,9 +695,11 @@ func (s *TemplatesService) UpdateTemplate(ctx context.Context, req *iav1beta1.Up
 	}
 
 	changeParams := &models.ChangeTemplateParams{
-		Template: &tmpl,
-		Name:     req.Name,
-		Yaml:     req.Yaml,
+		Template:       &tmpl.Template,
+		RecordingRules: tmpl.RecordingRules,
+		Conditions:     tmpl.Conditions,
+		Name:           req.Name,
+		Yaml:           req.Yaml,
 	}
 
 	e := s.db.InTransaction(func(tx *reform.TX) error {
@@ -19,9 +19,11 @@ package ia
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/percona-platform/saas/pkg/alert"
 	iav1beta1 "github.com/percona/pmm/api/managementpb/ia"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -318,3 +320,111 @@ templates:
 			"placeholders: template: :4:5: executing \"\" at <.threshold>: map has no entry for key \"threshold\".")
 	})
 }
+
+func TestParseTemplates(t *testing.T) {
+	t.Parallel()
+
+	params := &alert.ParseParams{
+		DisallowUnknownFields:    true,
+		DisallowInvalidTemplates: true,
+	}
+
+	t.Run("recording rules and conditions", func(t *testing.T) {
+		t.Parallel()
+
+		templates, err := parseTemplates(strings.NewReader(`
+---
+templates:
+  - name: node_overloaded
+    version: 1
+    summary: Node overloaded
+    expr: "[[ .high_load ]] and [[ .low_memory ]]"
+    params:
+      - name: threshold
+        summary: Load per CPU
+        unit: "%"
+        type: float
+        value: 90
+    for: 5m
+    severity: warning
+    recording_rules:
+      - record: pmm:node_load:ratio
+        expr: node_load1 / on (node_name) count by (node_name) (node_cpu_seconds_total{mode="idle"})
+    conditions:
+      - name: high_load
+        expr: pmm:node_load:ratio{rule_id="[[ .rule_id ]]"} * 100 > [[ .threshold ]]
+      - name: low_memory
+        expr: node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes < 0.1
+`), params)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+
+		tmpl := templates[0]
+		assert.Equal(t, "node_overloaded", tmpl.Name)
+		assert.Equal(t, models.RecordingRules{{
+			Record: "pmm:node_load:ratio",
+			Expr:   `node_load1 / on (node_name) count by (node_name) (node_cpu_seconds_total{mode="idle"})`,
+		}}, tmpl.RecordingRules)
+		assert.Equal(t, models.AlertConditions{
+			{Name: "high_load", Expr: `pmm:node_load:ratio{rule_id="[[ .rule_id ]]"} * 100 > [[ .threshold ]]`},
+			{Name: "low_memory", Expr: "node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes < 0.1"},
+		}, tmpl.Conditions)
+		assert.NoError(t, validateUserTemplate(&tmpl))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseTemplates(strings.NewReader(`
+---
+templates:
+  - name: unknown_field
+    version: 1
+    summary: Unknown field
+    expr: up == 0
+    for: 5m
+    severity: warning
+    foo: bar
+`), params)
+		assert.Error(t, err)
+	})
+
+	t.Run("reserved parameter name", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := &ruleTemplate{
+			Template: alert.Template{
+				Name: "reserved_param",
+				Expr: "up == [[ .rule_id ]]",
+				Params: []alert.Parameter{{
+					Name: "rule_id",
+					Type: alert.Float,
+				}},
+			},
+		}
+		assert.EqualError(t, validateUserTemplate(tmpl), `reserved_param: parameter name "rule_id" is reserved`)
+	})
+
+	t.Run("condition names", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := &ruleTemplate{
+			Template: alert.Template{
+				Name: "condition_names",
+				Expr: "[[ .threshold ]]",
+				Params: []alert.Parameter{{
+					Name: "threshold",
+					Type: alert.Float,
+				}},
+			},
+			templateExtensions: templateExtensions{
+				Conditions: models.AlertConditions{{Name: "threshold", Expr: "up == 0"}},
+			},
+		}
+		assert.EqualError(t, validateUserTemplate(tmpl), `condition_names: alert condition name "threshold" clashes with parameter name`)
+
+		tmpl.Expr = "[[ .down ]]"
+		tmpl.Conditions = models.AlertConditions{{Name: "down", Expr: "up == 0"}, {Name: "down", Expr: "up < 1"}}
+		assert.EqualError(t, validateUserTemplate(tmpl), `condition_names: alert condition name "down" is not unique`)
+	})
+}
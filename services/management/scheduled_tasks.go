@@ -0,0 +1,410 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package management
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/reform.v1"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/scheduler"
+	"github.com/percona/pmm-managed/utils/httpapi"
+)
+
+const (
+	maxTaskRetries       = 10
+	maxTaskRetryInterval = 8 * time.Hour
+)
+
+// ScheduledTasksService represents API for scheduled tasks which aren't backups,
+// scheduled backups are managed by backup.BackupsService.
+type ScheduledTasksService struct {
+	db              *reform.DB
+	scheduleService scheduleService
+	l               *logrus.Entry
+}
+
+// NewScheduledTasksService creates new scheduled tasks API service.
+func NewScheduledTasksService(db *reform.DB, scheduleService scheduleService) *ScheduledTasksService {
+	return &ScheduledTasksService{
+		db:              db,
+		scheduleService: scheduleService,
+		l:               logrus.WithField("component", "management/scheduled_tasks"),
+	}
+}
+
+// ScheduledTaskParams are common fields of scheduled task JSON API requests.
+type ScheduledTaskParams struct {
+	CronExpression string `json:"cron_expression"`
+	// StartAt delays the first run, task is scheduled immediately if it's not set.
+	StartAt       *time.Time       `json:"start_at,omitempty"`
+	Enabled       bool             `json:"enabled"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	Retries       uint32           `json:"retries"`
+	RetryInterval httpapi.Duration `json:"retry_interval"`
+	// CatchUpPolicy is "skip" (default), "run_once" or "run_all".
+	CatchUpPolicy models.CatchUpPolicy `json:"catch_up_policy"`
+}
+
+// AddSecurityChecksTaskRequest is an AddSecurityChecksTask JSON API request.
+type AddSecurityChecksTaskRequest struct {
+	ScheduledTaskParams
+	// CheckNames limits executed checks, all enabled checks are executed if it's empty.
+	CheckNames []string `json:"check_names"`
+}
+
+// AddQueryActionTaskRequest is an AddQueryActionTask JSON API request.
+type AddQueryActionTaskRequest struct {
+	ScheduledTaskParams
+	ServiceID  string                 `json:"service_id"`
+	ActionType models.QueryActionType `json:"action_type"`
+	Query      string                 `json:"query"`
+}
+
+// AddPTSummaryTaskRequest is an AddPTSummaryTask JSON API request.
+type AddPTSummaryTaskRequest struct {
+	ScheduledTaskParams
+	NodeID string `json:"node_id"`
+}
+
+// AddArtifactVerificationTaskRequest is an AddArtifactVerificationTask JSON API request.
+type AddArtifactVerificationTaskRequest struct {
+	ScheduledTaskParams
+	ServiceID string `json:"service_id"`
+	// LocationID limits verified artifacts to the given location, any location is used if it's empty.
+	LocationID string `json:"location_id"`
+}
+
+// AddScheduledTaskResponse is a response of JSON APIs adding scheduled tasks.
+type AddScheduledTaskResponse struct {
+	ScheduledTaskID string `json:"scheduled_task_id"`
+}
+
+// ScheduledTaskRequest is a GetScheduledTask and RemoveScheduledTask JSON API request.
+type ScheduledTaskRequest struct {
+	ScheduledTaskID string `json:"scheduled_task_id"`
+}
+
+// GetScheduledTaskResponse is a GetScheduledTask JSON API response.
+type GetScheduledTaskResponse struct {
+	ScheduledTaskID string                   `json:"scheduled_task_id"`
+	Type            models.ScheduledTaskType `json:"type"`
+	CronExpression  string                   `json:"cron_expression"`
+	Enabled         bool                     `json:"enabled"`
+	Name            string                   `json:"name"`
+	Description     string                   `json:"description"`
+	CatchUpPolicy   models.CatchUpPolicy     `json:"catch_up_policy"`
+	Running         bool                     `json:"running"`
+	// DeferredReason is set while the run is queued because of blackout window.
+	DeferredReason string     `json:"deferred_reason,omitempty"`
+	LastRun        *time.Time `json:"last_run,omitempty"`
+	NextRun        *time.Time `json:"next_run,omitempty"`
+	// Error is an error of the last run.
+	Error string `json:"error,omitempty"`
+	// LastResult is an output of the action started by the last successful run of query action and pt-summary tasks.
+	LastResult *models.ActionTaskResult `json:"last_result,omitempty"`
+}
+
+// RemoveScheduledTaskResponse is a RemoveScheduledTask JSON API response.
+type RemoveScheduledTaskResponse struct{}
+
+// ChangeCatchUpPolicyRequest is a ChangeCatchUpPolicy JSON API request.
+type ChangeCatchUpPolicyRequest struct {
+	ScheduledTaskID string               `json:"scheduled_task_id"`
+	CatchUpPolicy   models.CatchUpPolicy `json:"catch_up_policy"`
+}
+
+// ChangeCatchUpPolicyResponse is a ChangeCatchUpPolicy JSON API response.
+type ChangeCatchUpPolicyResponse struct{}
+
+// ListRunsRequest is a ListRuns JSON API request.
+type ListRunsRequest struct {
+	ScheduledTaskID string `json:"scheduled_task_id"`
+	// PageIndex starts from zero.
+	PageIndex int `json:"page_index"`
+	PageSize  int `json:"page_size"`
+}
+
+// ScheduledTaskRun is a run of the scheduled task.
+type ScheduledTaskRun struct {
+	ScheduledTaskRunID string                        `json:"scheduled_task_run_id"`
+	Status             models.ScheduledTaskRunStatus `json:"status"`
+	Error              string                        `json:"error,omitempty"`
+	ArtifactID         string                        `json:"artifact_id,omitempty"`
+	Retries            uint32                        `json:"retries"`
+	CatchUp            bool                          `json:"catch_up"`
+	StartedAt          time.Time                     `json:"started_at"`
+	FinishedAt         *time.Time                    `json:"finished_at,omitempty"`
+}
+
+// ListRunsResponse is a ListRuns JSON API response.
+type ListRunsResponse struct {
+	Runs       []*ScheduledTaskRun `json:"runs"`
+	TotalItems int                 `json:"total_items"`
+}
+
+// AddSecurityChecksTask schedules security checks.
+func (s *ScheduledTasksService) AddSecurityChecksTask(ctx context.Context, req *AddSecurityChecksTaskRequest) (*AddScheduledTaskResponse, error) {
+	task, err := scheduler.NewSecurityChecksTask(&scheduler.SecurityChecksTaskParams{
+		TaskParams: req.taskParams(),
+		CheckNames: req.CheckNames,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Can't create security checks task: %v.", err)
+	}
+
+	return s.add(task, &req.ScheduledTaskParams)
+}
+
+// AddQueryActionTask schedules Query Action, output of the latest action is stored in the task.
+func (s *ScheduledTasksService) AddQueryActionTask(ctx context.Context, req *AddQueryActionTaskRequest) (*AddScheduledTaskResponse, error) {
+	if _, err := models.FindServiceByID(s.db.Querier, req.ServiceID); err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	task, err := scheduler.NewQueryActionTask(&scheduler.QueryActionTaskParams{
+		TaskParams: req.taskParams(),
+		ServiceID:  req.ServiceID,
+		ActionType: req.ActionType,
+		Query:      req.Query,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Can't create query action task: %v.", err)
+	}
+
+	return s.add(task, &req.ScheduledTaskParams)
+}
+
+// AddPTSummaryTask schedules pt-summary collection, output of the latest collection is stored in the task.
+func (s *ScheduledTasksService) AddPTSummaryTask(ctx context.Context, req *AddPTSummaryTaskRequest) (*AddScheduledTaskResponse, error) {
+	if _, err := models.FindNodeByID(s.db.Querier, req.NodeID); err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	task, err := scheduler.NewPTSummaryTask(&scheduler.PTSummaryTaskParams{
+		TaskParams: req.taskParams(),
+		NodeID:     req.NodeID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Can't create pt-summary task: %v.", err)
+	}
+
+	return s.add(task, &req.ScheduledTaskParams)
+}
+
+// AddArtifactVerificationTask schedules verification of the latest successful artifact of the service.
+func (s *ScheduledTasksService) AddArtifactVerificationTask(ctx context.Context, req *AddArtifactVerificationTaskRequest) (*AddScheduledTaskResponse, error) {
+	if _, err := models.FindServiceByID(s.db.Querier, req.ServiceID); err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+	if req.LocationID != "" {
+		if _, err := models.FindBackupLocationByID(s.db.Querier, req.LocationID); err != nil {
+			return nil, convertScheduledTaskError(err)
+		}
+	}
+
+	task, err := scheduler.NewArtifactVerificationTask(&scheduler.ArtifactVerificationTaskParams{
+		TaskParams: req.taskParams(),
+		ServiceID:  req.ServiceID,
+		LocationID: req.LocationID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Can't create artifact verification task: %v.", err)
+	}
+
+	return s.add(task, &req.ScheduledTaskParams)
+}
+
+// GetScheduledTask returns scheduled task with the result of its last run.
+func (s *ScheduledTasksService) GetScheduledTask(ctx context.Context, req *ScheduledTaskRequest) (*GetScheduledTaskResponse, error) {
+	task, err := s.findTask(req.ScheduledTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GetScheduledTaskResponse{
+		ScheduledTaskID: task.ID,
+		Type:            task.Type,
+		CronExpression:  task.CronExpression,
+		Enabled:         !task.Disabled,
+		CatchUpPolicy:   task.CatchUpPolicy,
+		Running:         task.Running,
+		DeferredReason:  task.DeferredReason,
+		Error:           task.Error,
+	}
+	if !task.LastRun.IsZero() {
+		res.LastRun = &task.LastRun
+	}
+	if !task.NextRun.IsZero() {
+		res.NextRun = &task.NextRun
+	}
+
+	var common models.CommonTaskData
+	switch data := task.Data; task.Type {
+	case models.ScheduledSecurityChecksTask:
+		common = data.SecurityChecksTask.CommonTaskData
+	case models.ScheduledQueryActionTask:
+		common, res.LastResult = data.QueryActionTask.CommonTaskData, data.QueryActionTask.LastResult
+	case models.ScheduledPTSummaryTask:
+		common, res.LastResult = data.PTSummaryTask.CommonTaskData, data.PTSummaryTask.LastResult
+	case models.ScheduledArtifactVerificationTask:
+		common = data.ArtifactVerificationTask.CommonTaskData
+	}
+	res.Name, res.Description = common.Name, common.Description
+
+	return res, nil
+}
+
+// RemoveScheduledTask removes scheduled task.
+func (s *ScheduledTasksService) RemoveScheduledTask(ctx context.Context, req *ScheduledTaskRequest) (*RemoveScheduledTaskResponse, error) {
+	if _, err := s.findTask(req.ScheduledTaskID); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleService.Remove(req.ScheduledTaskID); err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	return &RemoveScheduledTaskResponse{}, nil
+}
+
+// ChangeCatchUpPolicy changes what is done with runs of the scheduled task missed during pmm-managed downtime.
+// It's used for scheduled backups too.
+func (s *ScheduledTasksService) ChangeCatchUpPolicy(ctx context.Context, req *ChangeCatchUpPolicyRequest) (*ChangeCatchUpPolicyResponse, error) {
+	if req.ScheduledTaskID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Scheduled task ID is required.")
+	}
+
+	if err := s.scheduleService.Update(req.ScheduledTaskID, models.ChangeScheduledTaskParams{
+		CatchUpPolicy: &req.CatchUpPolicy,
+	}); err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	return &ChangeCatchUpPolicyResponse{}, nil
+}
+
+// ListRuns returns a page with runs history of the scheduled task, the latest runs go first.
+// It's used for scheduled backups too.
+func (s *ScheduledTasksService) ListRuns(ctx context.Context, req *ListRunsRequest) (*ListRunsResponse, error) {
+	if req.ScheduledTaskID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Scheduled task ID is required.")
+	}
+
+	if req.PageIndex < 0 || req.PageSize <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Page index shouldn't be negative and page size should be positive.")
+	}
+
+	runs, total, err := s.scheduleService.FindTaskRunsOnPage(req.ScheduledTaskID, req.PageIndex, req.PageSize)
+	if err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	res := &ListRunsResponse{
+		Runs:       make([]*ScheduledTaskRun, 0, len(runs)),
+		TotalItems: total,
+	}
+	for _, r := range runs {
+		res.Runs = append(res.Runs, &ScheduledTaskRun{
+			ScheduledTaskRunID: r.ID,
+			Status:             r.Status,
+			Error:              r.Error,
+			ArtifactID:         r.ArtifactID,
+			Retries:            r.Retries,
+			CatchUp:            r.CatchUp,
+			StartedAt:          r.StartedAt,
+			FinishedAt:         r.FinishedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *ScheduledTasksService) add(task scheduler.Task, params *ScheduledTaskParams) (*AddScheduledTaskResponse, error) {
+	if params.Retries > maxTaskRetries {
+		return nil, status.Errorf(codes.InvalidArgument, "Exceeded max retries %d.", maxTaskRetries)
+	}
+
+	if time.Duration(params.RetryInterval) > maxTaskRetryInterval {
+		return nil, status.Errorf(codes.InvalidArgument, "Exceeded max retry interval %s.", maxTaskRetryInterval)
+	}
+
+	addParams := scheduler.AddParams{
+		CronExpression: params.CronExpression,
+		Disabled:       !params.Enabled,
+		CatchUpPolicy:  params.CatchUpPolicy,
+	}
+	if params.StartAt != nil {
+		addParams.StartAt = *params.StartAt
+	}
+
+	scheduledTask, err := s.scheduleService.Add(task, addParams)
+	if err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	return &AddScheduledTaskResponse{ScheduledTaskID: scheduledTask.ID}, nil
+}
+
+// findTask returns scheduled task which is managed by this API.
+func (s *ScheduledTasksService) findTask(id string) (*models.ScheduledTask, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "Scheduled task ID is required.")
+	}
+
+	task, err := models.FindScheduledTaskByID(s.db.Querier, id)
+	if err != nil {
+		return nil, convertScheduledTaskError(err)
+	}
+
+	switch task.Type {
+	case models.ScheduledSecurityChecksTask,
+		models.ScheduledQueryActionTask,
+		models.ScheduledPTSummaryTask,
+		models.ScheduledArtifactVerificationTask:
+		return task, nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "Scheduled task with ID %q is a %s task, "+
+			"use backups API for it.", id, task.Type)
+	}
+}
+
+func (p *ScheduledTaskParams) taskParams() scheduler.TaskParams {
+	return scheduler.TaskParams{
+		Name:          p.Name,
+		Description:   p.Description,
+		Retries:       p.Retries,
+		RetryInterval: time.Duration(p.RetryInterval),
+	}
+}
+
+func convertScheduledTaskError(err error) error {
+	var errInvalidArgument *models.ErrInvalidArgument
+	switch {
+	case errors.As(err, &errInvalidArgument):
+		return status.Errorf(codes.InvalidArgument, "Invalid argument: %s.", errInvalidArgument.Details)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,108 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package management
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/scheduler"
+	"github.com/percona/pmm-managed/utils/httpapi"
+	"github.com/percona/pmm-managed/utils/tests"
+)
+
+func TestAddSecurityChecksTask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid", func(t *testing.T) {
+		s := NewScheduledTasksService(nil, &mockScheduleService{})
+
+		_, err := s.AddSecurityChecksTask(ctx, &AddSecurityChecksTaskRequest{})
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, "task name can't be empty", err)
+
+		_, err = s.AddSecurityChecksTask(ctx, &AddSecurityChecksTaskRequest{
+			ScheduledTaskParams: ScheduledTaskParams{Name: "checks", Retries: maxTaskRetries + 1},
+		})
+		tests.AssertGRPCErrorRE(t, codes.InvalidArgument, "Exceeded max retries", err)
+	})
+
+	t.Run("normal", func(t *testing.T) {
+		scheduleService := &mockScheduleService{}
+		s := NewScheduledTasksService(nil, scheduleService)
+
+		startAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		scheduleService.On("Add", mock.Anything, scheduler.AddParams{CronExpression: "0 * * * *", StartAt: startAt}).
+			Return(&models.ScheduledTask{ID: "/scheduled_task_id/1"}, nil).Once()
+
+		res, err := s.AddSecurityChecksTask(ctx, &AddSecurityChecksTaskRequest{
+			ScheduledTaskParams: ScheduledTaskParams{
+				CronExpression: "0 * * * *",
+				StartAt:        &startAt,
+				Enabled:        true,
+				Name:           "checks",
+				RetryInterval:  httpapi.Duration(time.Minute),
+			},
+			CheckNames: []string{"mysql_version"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "/scheduled_task_id/1", res.ScheduledTaskID)
+
+		task := scheduleService.Calls[0].Arguments.Get(0).(scheduler.Task)
+		assert.Equal(t, models.ScheduledSecurityChecksTask, task.Type())
+		assert.Equal(t, []string{"mysql_version"}, task.Data().SecurityChecksTask.CheckNames)
+		scheduleService.AssertExpectations(t)
+	})
+}
+
+func TestListRuns(t *testing.T) {
+	ctx := context.Background()
+	scheduleService := &mockScheduleService{}
+	s := NewScheduledTasksService(nil, scheduleService)
+
+	_, err := s.ListRuns(ctx, &ListRunsRequest{ScheduledTaskID: "/scheduled_task_id/1"})
+	tests.AssertGRPCErrorRE(t, codes.InvalidArgument, "page size should be positive", err)
+
+	startedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduleService.On("FindTaskRunsOnPage", "/scheduled_task_id/1", 1, 1).Return([]*models.ScheduledTaskRun{{
+		ID:         "/scheduled_task_run_id/1",
+		Status:     models.SuccessScheduledTaskRunStatus,
+		ArtifactID: "/artifact_id/1",
+		CatchUp:    true,
+		StartedAt:  startedAt,
+	}}, 2, nil).Once()
+
+	res, err := s.ListRuns(ctx, &ListRunsRequest{ScheduledTaskID: "/scheduled_task_id/1", PageIndex: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &ListRunsResponse{
+		Runs: []*ScheduledTaskRun{{
+			ScheduledTaskRunID: "/scheduled_task_run_id/1",
+			Status:             models.SuccessScheduledTaskRunStatus,
+			ArtifactID:         "/artifact_id/1",
+			CatchUp:            true,
+			StartedAt:          startedAt,
+		}},
+		TotalItems: 2,
+	}, res)
+	scheduleService.AssertExpectations(t)
+}
@@ -19,6 +19,7 @@ package minio
 
 import (
 	"context"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -29,6 +30,14 @@ import (
 	"github.com/percona/pmm-managed/models"
 )
 
+// FileInfo contains information about single file.
+type FileInfo struct {
+	Name string
+	Size int64
+	// ETag is the checksum of file contents, it's MD5 for objects uploaded in a single part.
+	ETag string
+}
+
 // Service is wrapper around minio client.
 type Service struct {
 	l *logrus.Entry
@@ -59,6 +68,40 @@ func (s *Service) GetBucketLocation(ctx context.Context, endpoint, accessKey, se
 	return minioClient.GetBucketLocation(ctx, name)
 }
 
+// List lists objects recursively in the bucket with given prefix and suffix.
+// Returned file names are relative to the prefix.
+func (s *Service) List(ctx context.Context, endpoint, accessKey, secretKey, bucketName, prefix, suffix string) ([]FileInfo, error) {
+	minioClient, err := newClient(endpoint, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	options := minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}
+
+	var res []FileInfo
+	for object := range minioClient.ListObjects(ctx, bucketName, options) {
+		if object.Err != nil {
+			return nil, errors.WithStack(object.Err)
+		}
+
+		name := strings.TrimPrefix(object.Key, prefix)
+		if name == "" || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+
+		res = append(res, FileInfo{
+			Name: name,
+			Size: object.Size,
+			ETag: object.ETag,
+		})
+	}
+
+	return res, nil
+}
+
 // RemoveRecursive removes objects recursively from storage with given prefix.
 func (s *Service) RemoveRecursive(ctx context.Context, endpoint, accessKey, secretKey, bucketName, prefix string) (rerr error) {
 	minioClient, err := newClient(endpoint, accessKey, secretKey)
@@ -112,6 +155,52 @@ func (s *Service) RemoveRecursive(ctx context.Context, endpoint, accessKey, secr
 	return nil
 }
 
+// CopyRecursive copies objects recursively with given prefix from the source bucket to the target one.
+// Buckets may be located in different storages, so objects are streamed through pmm-managed.
+func (s *Service) CopyRecursive(ctx context.Context, src, dst *models.S3LocationConfig, prefix string) error {
+	srcClient, err := newClient(src.Endpoint, src.AccessKey, src.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	dstClient, err := newClient(dst.Endpoint, dst.AccessKey, dst.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	options := minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}
+	for object := range srcClient.ListObjects(ctx, src.BucketName, options) {
+		if object.Err != nil {
+			return errors.WithStack(object.Err)
+		}
+
+		if err = copyObject(ctx, srcClient, dstClient, src.BucketName, dst.BucketName, object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyObject(ctx context.Context, srcClient, dstClient *minio.Client, srcBucket, dstBucket string, object minio.ObjectInfo) error {
+	r, err := srcClient.GetObject(ctx, srcBucket, object.Key, minio.GetObjectOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get object %q", object.Key)
+	}
+	defer r.Close() //nolint:errcheck
+
+	if _, err = dstClient.PutObject(ctx, dstBucket, object.Key, r, object.Size, minio.PutObjectOptions{
+		ContentType: object.ContentType,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to put object %q", object.Key)
+	}
+
+	return nil
+}
+
 func newClient(endpoint, accessKey, secretKey string) (*minio.Client, error) {
 	url, err := models.ParseEndpoint(endpoint)
 	if err != nil {
@@ -19,6 +19,7 @@ package scheduler
 import (
 	"context"
 
+	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services/backup"
 )
 
@@ -27,3 +28,24 @@ import (
 type backupService interface {
 	PerformBackup(ctx context.Context, params backup.PerformBackupParams) (string, error)
 }
+
+// actionsService is a subset of methods of agents.ActionsService used by this package.
+type actionsService interface {
+	StartMySQLQueryShowAction(ctx context.Context, id, pmmAgentID, dsn, query string, files map[string]string, tdp *models.DelimiterPair, tlsSkipVerify bool) error
+	StartMySQLQuerySelectAction(ctx context.Context, id, pmmAgentID, dsn, query string, files map[string]string, tdp *models.DelimiterPair, tlsSkipVerify bool) error
+	StartPostgreSQLQueryShowAction(ctx context.Context, id, pmmAgentID, dsn string) error
+	StartPostgreSQLQuerySelectAction(ctx context.Context, id, pmmAgentID, dsn, query string) error
+	StartMongoDBQueryGetParameterAction(ctx context.Context, id, pmmAgentID, dsn string, files map[string]string, tdp *models.DelimiterPair) error
+	StartMongoDBQueryBuildInfoAction(ctx context.Context, id, pmmAgentID, dsn string, files map[string]string, tdp *models.DelimiterPair) error
+	StartPTSummaryAction(ctx context.Context, id, pmmAgentID string) error
+}
+
+// checksService is a subset of methods of checks.Service used by this package.
+type checksService interface {
+	StartChecks(checkNames []string) error
+}
+
+// verificationService is a subset of methods of backup.VerificationService used by this package.
+type verificationService interface {
+	VerifyArtifact(ctx context.Context, artifactID string) (*backup.VerifyArtifactResult, error)
+}
@@ -0,0 +1,101 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/percona/pmm-managed/models"
+	"github.com/percona/pmm-managed/services/backup"
+)
+
+// deferCheckInterval is an interval of checking if deferred task can be run.
+const deferCheckInterval = time.Minute
+
+// runAdmitted calls run when the task isn't restricted by blackout windows. Concurrency limits are checked
+// by backup service atomically with the start of the backup, so run is called again later if it returns
+// backup.ErrBackupLimitReached. Reason of deferring is stored in the task, so deferred runs are shown
+// as queued rather than failed.
+func (s *Service) runAdmitted(ctx context.Context, id string, l *logrus.Entry, run func() error) error {
+	var deferred string
+	defer func() {
+		if deferred == "" {
+			return
+		}
+
+		if _, err := models.ChangeScheduledTask(s.db.Querier, id, models.ChangeScheduledTaskParams{
+			DeferredReason: pointer.ToString(""),
+		}); err != nil {
+			l.Errorf("failed to reset deferred reason: %v", err)
+		}
+	}()
+
+	for {
+		reason, err := s.deferReason(time.Now())
+		if err != nil {
+			// Blackout windows are best effort, task shouldn't be blocked forever because of them.
+			l.Warnf("Failed to check blackout windows: %s.", err)
+			reason = ""
+		}
+
+		if reason == "" {
+			err = run()
+			if !errors.Is(err, backup.ErrBackupLimitReached) {
+				return err
+			}
+			reason = err.Error()
+		}
+
+		if reason != deferred {
+			l.Infof("Task is deferred: %s.", reason)
+			if _, err = models.ChangeScheduledTask(s.db.Querier, id, models.ChangeScheduledTaskParams{
+				DeferredReason: pointer.ToString(reason),
+			}); err != nil {
+				l.Errorf("failed to store deferred reason: %v", err)
+			}
+			deferred = reason
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(deferCheckInterval):
+		}
+	}
+}
+
+// deferReason returns the reason why task can't be run at the given time, empty string if it can.
+func (s *Service) deferReason(now time.Time) (string, error) {
+	settings, err := models.GetSettings(s.db.Querier)
+	if err != nil {
+		return "", err
+	}
+
+	for _, w := range settings.BackupManagement.BlackoutWindows {
+		if w.Contains(now) {
+			return fmt.Sprintf("blackout window started at %s UTC for %s", w.StartTime, w.Duration), nil
+		}
+	}
+
+	return "", nil
+}
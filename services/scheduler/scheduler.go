@@ -25,6 +25,7 @@ import (
 	"github.com/AlekSi/pointer"
 	"github.com/go-co-op/gocron"
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,11 +34,19 @@ import (
 	"github.com/percona/pmm-managed/models"
 )
 
+const (
+	actionTimeout       = 5 * time.Minute
+	actionCheckInterval = time.Second
+)
+
 // Service is responsible for executing tasks and storing them to DB.
 type Service struct {
-	db            *reform.DB
-	l             *logrus.Entry
-	backupService backupService
+	db                  *reform.DB
+	l                   *logrus.Entry
+	backupService       backupService
+	actionsService      actionsService
+	checksService       checksService
+	verificationService verificationService
 
 	mx        sync.Mutex
 	scheduler *gocron.Scheduler
@@ -50,17 +59,22 @@ type Service struct {
 }
 
 // New creates new scheduler service.
-func New(db *reform.DB, backupService backupService) *Service {
+func New(db *reform.DB, backupService backupService, actionsService actionsService, checksService checksService,
+	verificationService verificationService,
+) *Service {
 	scheduler := gocron.NewScheduler(time.UTC)
 	scheduler.TagsUnique()
 	scheduler.WaitForScheduleAll()
 	return &Service{
-		db:            db,
-		scheduler:     scheduler,
-		l:             logrus.WithField("component", "scheduler"),
-		backupService: backupService,
-		tasks:         make(map[string]context.CancelFunc),
-		jobs:          make(map[string]*gocron.Job),
+		db:                  db,
+		scheduler:           scheduler,
+		l:                   logrus.WithField("component", "scheduler"),
+		backupService:       backupService,
+		actionsService:      actionsService,
+		checksService:       checksService,
+		verificationService: verificationService,
+		tasks:               make(map[string]context.CancelFunc),
+		jobs:                make(map[string]*gocron.Job),
 	}
 }
 
@@ -79,6 +93,7 @@ type AddParams struct {
 	CronExpression string
 	Disabled       bool
 	StartAt        time.Time
+	CatchUpPolicy  models.CatchUpPolicy
 }
 
 // Add adds task to scheduler and save it to DB.
@@ -92,12 +107,17 @@ func (s *Service) Add(task Task, params AddParams) (*models.ScheduledTask, error
 			return err
 		}
 
+		if err = checkCatchUpPolicy(task.Type(), params.CatchUpPolicy); err != nil {
+			return err
+		}
+
 		scheduledTask, err = models.CreateScheduledTask(tx.Querier, models.CreateScheduledTaskParams{
 			CronExpression: params.CronExpression,
 			StartAt:        params.StartAt,
 			Type:           task.Type(),
 			Data:           task.Data(),
 			Disabled:       params.Disabled,
+			CatchUpPolicy:  params.CatchUpPolicy,
 		})
 		if err != nil {
 			return err
@@ -160,10 +180,30 @@ func (s *Service) Remove(id string) error {
 // Update changes scheduled task in DB and re-add it to scheduler.
 func (s *Service) Update(id string, params models.ChangeScheduledTaskParams) error {
 	return s.db.InTransactionContext(s.db.Querier.Context(), &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *reform.TX) error {
-		if err := checkPreconditions(tx.Querier, params.Data, !pointer.GetBool(params.Disable), id); err != nil {
+		dbTask, err := models.FindScheduledTaskByID(tx.Querier, id)
+		if err != nil {
+			return err
+		}
+
+		data := params.Data
+		if data == nil {
+			data = dbTask.Data
+		}
+		// Changes of other fields (e.g. catch-up policy) keep the task disabled or enabled as it is.
+		enabled := !dbTask.Disabled
+		if params.Disable != nil {
+			enabled = !*params.Disable
+		}
+		if err = checkPreconditions(tx.Querier, data, enabled, id); err != nil {
 			return err
 		}
 
+		if params.CatchUpPolicy != nil {
+			if err = checkCatchUpPolicy(dbTask.Type, *params.CatchUpPolicy); err != nil {
+				return err
+			}
+		}
+
 		scheduledTask, err := models.ChangeScheduledTask(tx.Querier, id, params)
 		if err != nil {
 			return err
@@ -186,19 +226,122 @@ func (s *Service) loadFromDB() error {
 		return err
 	}
 
+	// Runs started before pmm-managed restart can't be finished.
+	if err = models.FailInterruptedScheduledTaskRuns(s.db.Querier); err != nil {
+		return err
+	}
+
 	s.mx.Lock()
 	s.scheduler.Clear()
 	s.mx.Unlock()
 
+	now := time.Now().UTC()
 	for _, dbTask := range dbTasks {
 		if err := s.addDBTask(dbTask); err != nil {
 			return err
 		}
+
+		if err := s.catchUp(dbTask, now); err != nil {
+			s.l.WithField("id", dbTask.ID).Errorf("Failed to catch up missed runs: %s.", err)
+		}
 	}
 
 	return nil
 }
 
+// catchUp runs the task in accordance with its catch-up policy if any runs were missed before the given time.
+func (s *Service) catchUp(dbTask *models.ScheduledTask, now time.Time) error {
+	var limit int
+	switch dbTask.CatchUpPolicy {
+	case models.RunOnceCatchUpPolicy:
+		limit = 1
+	case models.RunAllCatchUpPolicy:
+		limit = models.MaxCatchUpRuns
+	default:
+		return nil
+	}
+
+	missed, err := missedRuns(dbTask.CronExpression, dbTask.NextRun, now, limit)
+	if err != nil || missed == 0 {
+		return err
+	}
+
+	task, err := s.convertDBTask(dbTask)
+	if err != nil {
+		return err
+	}
+
+	s.l.WithField("id", dbTask.ID).Infof("Catching up %d missed run(s).", missed)
+	fn := s.wrapTask(task, dbTask.ID, true)
+	go func() {
+		for i := 0; i < missed; i++ {
+			fn()
+		}
+	}()
+
+	return nil
+}
+
+// checkCatchUpPolicy checks that catch-up policy can be used for the task type.
+// Backups capture the current state of the service, so making all missed backups at startup
+// only produces back-to-back copies of the same data.
+func checkCatchUpPolicy(taskType models.ScheduledTaskType, policy models.CatchUpPolicy) error {
+	if policy != models.RunAllCatchUpPolicy {
+		return nil
+	}
+
+	switch taskType {
+	case models.ScheduledMySQLBackupTask, models.ScheduledMongoDBBackupTask:
+		return models.NewInvalidArgumentError("catch-up policy %q can't be used for backups, use %q instead",
+			policy, models.RunOnceCatchUpPolicy)
+	}
+
+	return nil
+}
+
+// missedRuns returns the number of runs scheduled between nextRun and now, but not more than limit.
+// Zero nextRun means that task was never scheduled, so no runs were missed.
+func missedRuns(cronExpression string, nextRun, now time.Time, limit int) (int, error) {
+	if nextRun.IsZero() {
+		return 0, nil
+	}
+
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid cron expression")
+	}
+
+	var res int
+	for t := nextRun; !t.After(now) && res < limit; t = schedule.Next(t) {
+		res++
+	}
+
+	return res, nil
+}
+
+// FindTaskRunsOnPage returns a page with runs history of the scheduled task and the total number of runs.
+func (s *Service) FindTaskRunsOnPage(id string, pageIndex, pageSize int) ([]*models.ScheduledTaskRun, int, error) {
+	var runs []*models.ScheduledTaskRun
+	var total int
+	if err := s.db.InTransaction(func(tx *reform.TX) error {
+		if _, err := models.FindScheduledTaskByID(tx.Querier, id); err != nil {
+			return err
+		}
+
+		var err error
+		if runs, err = models.FindScheduledTaskRunsOnPage(tx.Querier, id, pageIndex, pageSize); err != nil {
+			return err
+		}
+
+		total, err = models.CountScheduledTaskRuns(tx.Querier, id)
+		return err
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
 func (s *Service) addDBTask(dbTask *models.ScheduledTask) error {
 	if dbTask.Disabled {
 		return nil
@@ -210,7 +353,7 @@ func (s *Service) addDBTask(dbTask *models.ScheduledTask) error {
 	}
 
 	s.mx.Lock()
-	fn := s.wrapTask(task, dbTask.ID)
+	fn := s.wrapTask(task, dbTask.ID, false)
 	j := s.scheduler.Cron(dbTask.CronExpression).SingletonMode()
 	if !dbTask.StartAt.IsZero() {
 		j = j.StartAt(dbTask.StartAt)
@@ -228,7 +371,9 @@ func (s *Service) addDBTask(dbTask *models.ScheduledTask) error {
 	return nil
 }
 
-func (s *Service) wrapTask(task Task, id string) func() {
+// wrapTask returns function which runs the task and stores its outcome.
+// catchUp is true for runs made at startup instead of runs missed during downtime.
+func (s *Service) wrapTask(task Task, id string, catchUp bool) func() {
 	return func() {
 		var err error
 		l := s.l.WithFields(logrus.Fields{
@@ -237,7 +382,15 @@ func (s *Service) wrapTask(task Task, id string) func() {
 		})
 		ctx, cancel := context.WithCancel(context.Background())
 
+		// Catch-up runs are made outside of gocron, so SingletonMode doesn't prevent them
+		// from overlapping with scheduled runs; the later run is skipped instead.
 		s.taskMx.Lock()
+		if _, ok := s.tasks[id]; ok {
+			s.taskMx.Unlock()
+			cancel()
+			l.Warn("Previous run of the task is still in progress, run is skipped.")
+			return
+		}
 		s.tasks[id] = cancel
 		s.taskMx.Unlock()
 
@@ -248,27 +401,66 @@ func (s *Service) wrapTask(task Task, id string) func() {
 			s.taskMx.Unlock()
 		}()
 
-		t := time.Now()
-		l.Debug("Starting task")
-		_, err = models.ChangeScheduledTask(s.db.Querier, id, models.ChangeScheduledTaskParams{
-			Running: pointer.ToBool(true),
-		})
+		var t time.Time
+		var started bool
+		var run *models.ScheduledTaskRun
+		var res RunResult
+		err = s.runAdmitted(ctx, id, l, func() error {
+			var err error
+			// Run deferred by concurrency limits is already started and stored.
+			if !started {
+				started = true
+				t = time.Now()
+				l.Debug("Starting task")
+				if _, err = models.ChangeScheduledTask(s.db.Querier, id, models.ChangeScheduledTaskParams{
+					Running: pointer.ToBool(true),
+				}); err != nil {
+					l.Errorf("failed to change running state: %v", err)
+				}
+
+				if run, err = models.CreateScheduledTaskRun(s.db.Querier, id, catchUp); err != nil {
+					l.Errorf("failed to store task run: %v", err)
+				}
+			}
 
-		if err != nil {
-			l.Errorf("failed to change running state: %v", err)
+			res, err = task.Run(ctx, s)
+			return err
+		})
+		if !started {
+			l.Warnf("Deferred task is cancelled: %s.", err)
+			return
 		}
 
-		taskErr := task.Run(ctx, s)
+		// It's an error of the task or of cancelling the run deferred by concurrency limits.
+		taskErr := err
 		if taskErr != nil {
 			l.Error(taskErr)
 		}
 		l.WithField("duration", time.Since(t)).Debug("Ended task")
 
-		s.taskFinished(id, taskErr)
+		if run != nil {
+			params := models.FinishScheduledTaskRunParams{
+				ArtifactID: res.ArtifactID,
+				Retries:    res.Retries,
+			}
+			if taskErr != nil {
+				params.Error = taskErr.Error()
+			}
+			if _, err = models.FinishScheduledTaskRun(s.db.Querier, run.ID, params); err != nil {
+				l.Errorf("failed to store task run outcome: %v", err)
+			}
+		}
+
+		var lastRun time.Time
+		if catchUp {
+			lastRun = t.UTC()
+		}
+		s.taskFinished(id, taskErr, lastRun)
 	}
 }
 
-func (s *Service) taskFinished(id string, taskErr error) {
+// taskFinished stores task outcome. Last run time is taken from the scheduler job if lastRun is zero.
+func (s *Service) taskFinished(id string, taskErr error, lastRun time.Time) {
 	s.jobsMx.RLock()
 	job := s.jobs[id]
 	s.jobsMx.RUnlock()
@@ -286,9 +478,15 @@ func (s *Service) taskFinished(id string, taskErr error) {
 			params.Error = pointer.ToString("")
 		}
 
+		if !lastRun.IsZero() {
+			params.LastRun = pointer.ToTime(lastRun)
+		}
+
 		if job != nil {
 			params.NextRun = pointer.ToTime(job.NextRun().UTC())
-			params.LastRun = pointer.ToTime(job.LastRun().UTC())
+			if lastRun.IsZero() {
+				params.LastRun = pointer.ToTime(job.LastRun().UTC())
+			}
 		} else {
 			l.Errorf("failed to find scheduled task")
 		}
@@ -305,6 +503,51 @@ func (s *Service) taskFinished(id string, taskErr error) {
 	}
 }
 
+// storeTaskData replaces data of the scheduled task, e.g. to store output of the latest run.
+func (s *Service) storeTaskData(id string, data *models.ScheduledTaskData) error {
+	_, err := models.ChangeScheduledTask(s.db.Querier, id, models.ChangeScheduledTaskParams{
+		Data: data,
+	})
+	return err
+}
+
+// waitForActionResult waits until the action is done and returns its output.
+// Action result is kept, so it's still available via actions API; old results are removed by clean.Cleaner.
+func (s *Service) waitForActionResult(ctx context.Context, actionID string) (*models.ActionTaskResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(actionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, errors.WithStack(ctx.Err())
+		}
+
+		res, err := models.FindActionResultByID(s.db.Querier, actionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !res.Done {
+			continue
+		}
+
+		if res.Error != "" {
+			return nil, errors.Errorf("action %s failed: %s", actionID, res.Error)
+		}
+
+		return &models.ActionTaskResult{
+			ActionID:   actionID,
+			Output:     res.Output,
+			FinishedAt: res.UpdatedAt,
+		}, nil
+	}
+}
+
 func (s *Service) convertDBTask(dbTask *models.ScheduledTask) (Task, error) {
 	var task Task
 	switch dbTask.Type {
@@ -315,15 +558,16 @@ func (s *Service) convertDBTask(dbTask *models.ScheduledTask) (Task, error) {
 				id: dbTask.ID,
 			},
 			BackupTaskParams: &BackupTaskParams{
-				ServiceID:     data.ServiceID,
-				LocationID:    data.LocationID,
-				Name:          data.Name,
-				Description:   data.Description,
-				DataModel:     data.DataModel,
-				Mode:          data.Mode,
-				Retention:     data.Retention,
-				Retries:       data.Retries,
-				RetryInterval: data.RetryInterval,
+				ServiceID:       data.ServiceID,
+				LocationID:      data.LocationID,
+				Name:            data.Name,
+				Description:     data.Description,
+				DataModel:       data.DataModel,
+				Mode:            data.Mode,
+				Retention:       data.Retention,
+				RetentionPolicy: data.RetentionPolicy,
+				Retries:         data.Retries,
+				RetryInterval:   data.RetryInterval,
 			},
 		}
 	case models.ScheduledMongoDBBackupTask:
@@ -333,15 +577,63 @@ func (s *Service) convertDBTask(dbTask *models.ScheduledTask) (Task, error) {
 				id: dbTask.ID,
 			},
 			BackupTaskParams: &BackupTaskParams{
-				ServiceID:     data.ServiceID,
-				LocationID:    data.LocationID,
-				Name:          data.Name,
-				Description:   data.Description,
-				DataModel:     data.DataModel,
-				Mode:          data.Mode,
-				Retention:     data.Retention,
-				Retries:       data.Retries,
-				RetryInterval: data.RetryInterval,
+				ServiceID:       data.ServiceID,
+				LocationID:      data.LocationID,
+				Name:            data.Name,
+				Description:     data.Description,
+				DataModel:       data.DataModel,
+				Mode:            data.Mode,
+				Retention:       data.Retention,
+				RetentionPolicy: data.RetentionPolicy,
+				Retries:         data.Retries,
+				RetryInterval:   data.RetryInterval,
+			},
+		}
+	case models.ScheduledSecurityChecksTask:
+		data := dbTask.Data.SecurityChecksTask
+		task = &securityChecksTask{
+			common: common{
+				id: dbTask.ID,
+			},
+			SecurityChecksTaskParams: &SecurityChecksTaskParams{
+				TaskParams: taskParamsFromData(data.CommonTaskData),
+				CheckNames: data.CheckNames,
+			},
+		}
+	case models.ScheduledQueryActionTask:
+		data := dbTask.Data.QueryActionTask
+		task = &queryActionTask{
+			common: common{
+				id: dbTask.ID,
+			},
+			QueryActionTaskParams: &QueryActionTaskParams{
+				TaskParams: taskParamsFromData(data.CommonTaskData),
+				ServiceID:  data.ServiceID,
+				ActionType: data.ActionType,
+				Query:      data.Query,
+			},
+		}
+	case models.ScheduledPTSummaryTask:
+		data := dbTask.Data.PTSummaryTask
+		task = &ptSummaryTask{
+			common: common{
+				id: dbTask.ID,
+			},
+			PTSummaryTaskParams: &PTSummaryTaskParams{
+				TaskParams: taskParamsFromData(data.CommonTaskData),
+				NodeID:     data.NodeID,
+			},
+		}
+	case models.ScheduledArtifactVerificationTask:
+		data := dbTask.Data.ArtifactVerificationTask
+		task = &artifactVerificationTask{
+			common: common{
+				id: dbTask.ID,
+			},
+			ArtifactVerificationTaskParams: &ArtifactVerificationTaskParams{
+				TaskParams: taskParamsFromData(data.CommonTaskData),
+				ServiceID:  data.ServiceID,
+				LocationID: data.LocationID,
 			},
 		}
 
@@ -355,6 +647,10 @@ func (s *Service) convertDBTask(dbTask *models.ScheduledTask) (Task, error) {
 func checkPreconditions(q *reform.Querier, data *models.ScheduledTaskData, enabled bool, scheduledTaskID string) error {
 	switch {
 	case data.MySQLBackupTask != nil:
+	case data.SecurityChecksTask != nil:
+	case data.QueryActionTask != nil:
+	case data.PTSummaryTask != nil:
+	case data.ArtifactVerificationTask != nil:
 	case data.MongoDBBackupTask != nil:
 		data := data.MongoDBBackupTask
 		if enabled {
@@ -369,7 +665,11 @@ func checkMongoDBBackupPreconditions(q *reform.Querier, mode models.BackupMode,
 	case models.PITR:
 		// PITR backup can be enabled only if there is no other scheduled backups.
 		tasks, err := models.FindScheduledTasks(q, models.ScheduledTasksFilter{
-			Disabled:  pointer.ToBool(false),
+			Disabled: pointer.ToBool(false),
+			Types: []models.ScheduledTaskType{
+				models.ScheduledMySQLBackupTask,
+				models.ScheduledMongoDBBackupTask,
+			},
 			ServiceID: serviceID,
 		})
 		if err != nil {
@@ -39,7 +39,7 @@ func TestService(t *testing.T) {
 		sqlDB := testdb.Open(t, models.SkipFixtures, nil)
 		db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(t.Logf))
 		backupService := &mockBackupService{}
-		svc := New(db, backupService)
+		svc := New(db, backupService, nil, nil, nil)
 
 		go svc.Run(ctx)
 		for !svc.scheduler.IsRunning() {
@@ -118,4 +118,35 @@ func TestService(t *testing.T) {
 		_, err = models.FindScheduledTaskByID(svc.db.Querier, dbTask.ID)
 		tests.AssertGRPCError(t, status.Newf(codes.NotFound, `ScheduledTask with ID "%s" not found.`, dbTask.ID), err)
 	})
+	t.Run("change catch-up policy of disabled task", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		svc := setup(t, ctx)
+
+		task, err := NewSecurityChecksTask(&SecurityChecksTaskParams{TaskParams: TaskParams{Name: "checks"}})
+		require.NoError(t, err)
+
+		dbTask, err := svc.Add(task, AddParams{
+			CronExpression: "* * * * *",
+			Disabled:       true,
+		})
+		require.NoError(t, err)
+		assert.Len(t, svc.scheduler.Jobs(), 0)
+
+		policy := models.RunOnceCatchUpPolicy
+		err = svc.Update(dbTask.ID, models.ChangeScheduledTaskParams{CatchUpPolicy: &policy})
+		require.NoError(t, err)
+		assert.Len(t, svc.scheduler.Jobs(), 0)
+
+		dbTask, err = models.FindScheduledTaskByID(svc.db.Querier, dbTask.ID)
+		require.NoError(t, err)
+		assert.True(t, dbTask.Disabled)
+		assert.Equal(t, models.RunOnceCatchUpPolicy, dbTask.CatchUpPolicy)
+
+		policy = models.RunAllCatchUpPolicy
+		err = svc.Update(dbTask.ID, models.ChangeScheduledTaskParams{CatchUpPolicy: &policy})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.Remove(dbTask.ID))
+	})
 }
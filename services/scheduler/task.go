@@ -20,15 +20,28 @@ import (
 	"context"
 	"time"
 
+	"github.com/percona/pmm/version"
 	"github.com/pkg/errors"
+	"gopkg.in/reform.v1"
 
 	"github.com/percona/pmm-managed/models"
 	"github.com/percona/pmm-managed/services/backup"
 )
 
+// pt-summary action is supported by pmm-agent since 2.10.0.
+var pmmAgent2100 = version.MustParse("2.10.0")
+
+// RunResult contains details of the task run stored in the runs history.
+type RunResult struct {
+	// ArtifactID is set by backup and artifact verification tasks.
+	ArtifactID string
+	// Retries is a number of retries made by the task itself, backup retries are made by jobs and aren't counted.
+	Retries uint32
+}
+
 // Task represents task which will be run inside scheduler.
 type Task interface {
-	Run(ctx context.Context, scheduler *Service) error
+	Run(ctx context.Context, scheduler *Service) (RunResult, error)
 	ID() string
 	Type() models.ScheduledTaskType
 	Data() *models.ScheduledTaskData
@@ -45,15 +58,16 @@ func (c *common) ID() string {
 
 // BackupTaskParams contains common fields for all backup tasks.
 type BackupTaskParams struct {
-	ServiceID     string
-	LocationID    string
-	Name          string
-	Description   string
-	DataModel     models.DataModel
-	Mode          models.BackupMode
-	Retention     uint32
-	Retries       uint32
-	RetryInterval time.Duration
+	ServiceID       string
+	LocationID      string
+	Name            string
+	Description     string
+	DataModel       models.DataModel
+	Mode            models.BackupMode
+	Retention       uint32
+	RetentionPolicy *models.RetentionPolicy
+	Retries         uint32
+	RetryInterval   time.Duration
 }
 
 // Validate checks backup task parameters for correctness.
@@ -74,6 +88,10 @@ func (p *BackupTaskParams) Validate() error {
 		return err
 	}
 
+	if err := p.RetentionPolicy.Validate(); err != nil {
+		return err
+	}
+
 	return p.Mode.Validate()
 }
 
@@ -101,8 +119,8 @@ func NewMySQLBackupTask(params *BackupTaskParams) (Task, error) {
 	}, nil
 }
 
-func (t *mySQLBackupTask) Run(ctx context.Context, scheduler *Service) error {
-	_, err := scheduler.backupService.PerformBackup(ctx, backup.PerformBackupParams{
+func (t *mySQLBackupTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	artifactID, err := scheduler.backupService.PerformBackup(ctx, backup.PerformBackupParams{
 		ServiceID:     t.ServiceID,
 		LocationID:    t.LocationID,
 		Name:          t.Name,
@@ -112,7 +130,7 @@ func (t *mySQLBackupTask) Run(ctx context.Context, scheduler *Service) error {
 		Retries:       t.Retries,
 		RetryInterval: t.RetryInterval,
 	})
-	return err
+	return RunResult{ArtifactID: artifactID}, err
 }
 
 func (t *mySQLBackupTask) Type() models.ScheduledTaskType {
@@ -123,15 +141,16 @@ func (t *mySQLBackupTask) Data() *models.ScheduledTaskData {
 	return &models.ScheduledTaskData{
 		MySQLBackupTask: &models.MySQLBackupTaskData{
 			CommonBackupTaskData: models.CommonBackupTaskData{
-				ServiceID:     t.ServiceID,
-				LocationID:    t.LocationID,
-				Name:          t.Name,
-				Description:   t.Description,
-				Retention:     t.Retention,
-				DataModel:     t.DataModel,
-				Mode:          t.Mode,
-				Retries:       t.Retries,
-				RetryInterval: t.RetryInterval,
+				ServiceID:       t.ServiceID,
+				LocationID:      t.LocationID,
+				Name:            t.Name,
+				Description:     t.Description,
+				Retention:       t.Retention,
+				RetentionPolicy: t.RetentionPolicy,
+				DataModel:       t.DataModel,
+				Mode:            t.Mode,
+				Retries:         t.Retries,
+				RetryInterval:   t.RetryInterval,
 			},
 		},
 	}
@@ -161,8 +180,8 @@ func NewMongoDBBackupTask(params *BackupTaskParams) (Task, error) {
 	}, nil
 }
 
-func (t *mongoDBBackupTask) Run(ctx context.Context, scheduler *Service) error {
-	_, err := scheduler.backupService.PerformBackup(ctx, backup.PerformBackupParams{
+func (t *mongoDBBackupTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	artifactID, err := scheduler.backupService.PerformBackup(ctx, backup.PerformBackupParams{
 		ServiceID:     t.ServiceID,
 		LocationID:    t.LocationID,
 		Name:          t.Name,
@@ -172,7 +191,7 @@ func (t *mongoDBBackupTask) Run(ctx context.Context, scheduler *Service) error {
 		Retries:       t.Retries,
 		RetryInterval: t.RetryInterval,
 	})
-	return err
+	return RunResult{ArtifactID: artifactID}, err
 }
 
 func (t *mongoDBBackupTask) Type() models.ScheduledTaskType {
@@ -183,16 +202,403 @@ func (t *mongoDBBackupTask) Data() *models.ScheduledTaskData {
 	return &models.ScheduledTaskData{
 		MongoDBBackupTask: &models.MongoBackupTaskData{
 			CommonBackupTaskData: models.CommonBackupTaskData{
-				ServiceID:     t.ServiceID,
-				LocationID:    t.LocationID,
-				Name:          t.Name,
-				Description:   t.Description,
-				DataModel:     t.DataModel,
-				Mode:          t.Mode,
-				Retention:     t.Retention,
-				Retries:       t.Retries,
-				RetryInterval: t.RetryInterval,
+				ServiceID:       t.ServiceID,
+				LocationID:      t.LocationID,
+				Name:            t.Name,
+				Description:     t.Description,
+				DataModel:       t.DataModel,
+				Mode:            t.Mode,
+				Retention:       t.Retention,
+				RetentionPolicy: t.RetentionPolicy,
+				Retries:         t.Retries,
+				RetryInterval:   t.RetryInterval,
 			},
 		},
 	}
 }
+
+// TaskParams contains common fields for tasks which aren't backups.
+type TaskParams struct {
+	Name          string
+	Description   string
+	Retries       uint32
+	RetryInterval time.Duration
+}
+
+// Validate checks common task parameters for correctness.
+func (p *TaskParams) Validate() error {
+	if p.Name == "" {
+		return errors.New("task name can't be empty")
+	}
+
+	return nil
+}
+
+func (p *TaskParams) data() models.CommonTaskData {
+	return models.CommonTaskData{
+		Name:          p.Name,
+		Description:   p.Description,
+		Retries:       p.Retries,
+		RetryInterval: p.RetryInterval,
+	}
+}
+
+func taskParamsFromData(data models.CommonTaskData) TaskParams {
+	return TaskParams{
+		Name:          data.Name,
+		Description:   data.Description,
+		Retries:       data.Retries,
+		RetryInterval: data.RetryInterval,
+	}
+}
+
+// runWithRetries calls fn until it succeeds or retries are exhausted.
+// The number of made retries and the last error are returned.
+func runWithRetries(ctx context.Context, retries uint32, retryInterval time.Duration, fn func() error) (RunResult, error) {
+	var res RunResult
+	for {
+		err := fn()
+		if err == nil || res.Retries >= retries {
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return res, errors.WithStack(ctx.Err())
+		case <-time.After(retryInterval):
+		}
+		res.Retries++
+	}
+}
+
+type securityChecksTask struct {
+	common
+	*SecurityChecksTaskParams
+}
+
+// SecurityChecksTaskParams contains fields for security checks task.
+type SecurityChecksTaskParams struct {
+	TaskParams
+	// CheckNames limits executed checks, all enabled checks are executed if it's empty.
+	CheckNames []string
+}
+
+// NewSecurityChecksTask creates new task which runs security checks.
+func NewSecurityChecksTask(params *SecurityChecksTaskParams) (Task, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &securityChecksTask{
+		SecurityChecksTaskParams: params,
+	}, nil
+}
+
+// Run starts checks, they are executed and their results are stored asynchronously.
+func (t *securityChecksTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	return runWithRetries(ctx, t.Retries, t.RetryInterval, func() error {
+		return scheduler.checksService.StartChecks(t.CheckNames)
+	})
+}
+
+func (t *securityChecksTask) Type() models.ScheduledTaskType {
+	return models.ScheduledSecurityChecksTask
+}
+
+func (t *securityChecksTask) Data() *models.ScheduledTaskData {
+	return &models.ScheduledTaskData{
+		SecurityChecksTask: &models.SecurityChecksTaskData{
+			CommonTaskData: t.data(),
+			CheckNames:     t.CheckNames,
+		},
+	}
+}
+
+// QueryActionTaskParams contains fields for Query Action task.
+type QueryActionTaskParams struct {
+	TaskParams
+	ServiceID  string
+	ActionType models.QueryActionType
+	Query      string
+}
+
+// Validate checks Query Action task parameters for correctness.
+func (p *QueryActionTaskParams) Validate() error {
+	if err := p.TaskParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.ServiceID == "" {
+		return errors.New("service id can't be empty")
+	}
+
+	return p.ActionType.Validate(p.Query)
+}
+
+type queryActionTask struct {
+	common
+	*QueryActionTaskParams
+}
+
+// NewQueryActionTask creates new task which runs Query Action and stores its output.
+func NewQueryActionTask(params *QueryActionTaskParams) (Task, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &queryActionTask{
+		QueryActionTaskParams: params,
+	}, nil
+}
+
+func (t *queryActionTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	return runWithRetries(ctx, t.Retries, t.RetryInterval, func() error {
+		res, err := t.runAction(ctx, scheduler)
+		if err != nil {
+			return err
+		}
+
+		data := t.Data()
+		data.QueryActionTask.LastResult = res
+		return scheduler.storeTaskData(t.ID(), data)
+	})
+}
+
+func (t *queryActionTask) runAction(ctx context.Context, scheduler *Service) (*models.ActionTaskResult, error) {
+	var res *models.ActionResult
+	var dsn string
+	var agent *models.Agent
+	var service *models.Service
+	if err := scheduler.db.InTransaction(func(tx *reform.TX) error {
+		var err error
+		if service, err = models.FindServiceByID(tx.Querier, t.ServiceID); err != nil {
+			return err
+		}
+
+		pmmAgents, err := models.FindPMMAgentsForService(tx.Querier, t.ServiceID)
+		if err != nil {
+			return err
+		}
+
+		pmmAgentID, err := models.FindPmmAgentIDToRunActionOrJob("", pmmAgents)
+		if err != nil {
+			return err
+		}
+
+		if dsn, agent, err = models.FindDSNByServiceIDandPMMAgentID(tx.Querier, t.ServiceID, pmmAgentID, ""); err != nil {
+			return err
+		}
+
+		res, err = models.CreateActionResult(tx.Querier, pmmAgentID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	files := agent.Files()
+	tdp := agent.TemplateDelimiters(service)
+
+	var err error
+	a := scheduler.actionsService
+	switch t.ActionType {
+	case models.MySQLQueryShowAction:
+		err = a.StartMySQLQueryShowAction(ctx, res.ID, res.PMMAgentID, dsn, t.Query, files, tdp, agent.TLSSkipVerify)
+	case models.MySQLQuerySelectAction:
+		err = a.StartMySQLQuerySelectAction(ctx, res.ID, res.PMMAgentID, dsn, t.Query, files, tdp, agent.TLSSkipVerify)
+	case models.PostgreSQLQueryShowAction:
+		err = a.StartPostgreSQLQueryShowAction(ctx, res.ID, res.PMMAgentID, dsn)
+	case models.PostgreSQLQuerySelectAction:
+		err = a.StartPostgreSQLQuerySelectAction(ctx, res.ID, res.PMMAgentID, dsn, t.Query)
+	case models.MongoDBQueryBuildInfoAction:
+		err = a.StartMongoDBQueryBuildInfoAction(ctx, res.ID, res.PMMAgentID, dsn, files, tdp)
+	case models.MongoDBQueryGetParameterAction:
+		err = a.StartMongoDBQueryGetParameterAction(ctx, res.ID, res.PMMAgentID, dsn, files, tdp)
+	default:
+		return nil, errors.Errorf("unknown query action type: %s", t.ActionType)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start query action")
+	}
+
+	return scheduler.waitForActionResult(ctx, res.ID)
+}
+
+func (t *queryActionTask) Type() models.ScheduledTaskType {
+	return models.ScheduledQueryActionTask
+}
+
+func (t *queryActionTask) Data() *models.ScheduledTaskData {
+	return &models.ScheduledTaskData{
+		QueryActionTask: &models.QueryActionTaskData{
+			CommonTaskData: t.data(),
+			ServiceID:      t.ServiceID,
+			ActionType:     t.ActionType,
+			Query:          t.Query,
+		},
+	}
+}
+
+// PTSummaryTaskParams contains fields for pt-summary collection task.
+type PTSummaryTaskParams struct {
+	TaskParams
+	NodeID string
+}
+
+// Validate checks pt-summary task parameters for correctness.
+func (p *PTSummaryTaskParams) Validate() error {
+	if err := p.TaskParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.NodeID == "" {
+		return errors.New("node id can't be empty")
+	}
+
+	return nil
+}
+
+type ptSummaryTask struct {
+	common
+	*PTSummaryTaskParams
+}
+
+// NewPTSummaryTask creates new task which collects pt-summary of the node and stores its output.
+func NewPTSummaryTask(params *PTSummaryTaskParams) (Task, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ptSummaryTask{
+		PTSummaryTaskParams: params,
+	}, nil
+}
+
+func (t *ptSummaryTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	return runWithRetries(ctx, t.Retries, t.RetryInterval, func() error {
+		res, err := t.runAction(ctx, scheduler)
+		if err != nil {
+			return err
+		}
+
+		data := t.Data()
+		data.PTSummaryTask.LastResult = res
+		return scheduler.storeTaskData(t.ID(), data)
+	})
+}
+
+func (t *ptSummaryTask) runAction(ctx context.Context, scheduler *Service) (*models.ActionTaskResult, error) {
+	agents, err := models.FindPMMAgentsRunningOnNode(scheduler.db.Querier, t.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	agents = models.FindPMMAgentsForVersion(scheduler.l, agents, pmmAgent2100)
+	if len(agents) == 0 {
+		return nil, errors.Errorf("no pmm-agent of version %s or newer running on node %s", pmmAgent2100, t.NodeID)
+	}
+
+	pmmAgentID, err := models.FindPmmAgentIDToRunActionOrJob("", agents)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := models.CreateActionResult(scheduler.db.Querier, pmmAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = scheduler.actionsService.StartPTSummaryAction(ctx, res.ID, pmmAgentID); err != nil {
+		return nil, errors.Wrap(err, "failed to start pt-summary action")
+	}
+
+	return scheduler.waitForActionResult(ctx, res.ID)
+}
+
+func (t *ptSummaryTask) Type() models.ScheduledTaskType {
+	return models.ScheduledPTSummaryTask
+}
+
+func (t *ptSummaryTask) Data() *models.ScheduledTaskData {
+	return &models.ScheduledTaskData{
+		PTSummaryTask: &models.PTSummaryTaskData{
+			CommonTaskData: t.data(),
+			NodeID:         t.NodeID,
+		},
+	}
+}
+
+// ArtifactVerificationTaskParams contains fields for artifact verification task.
+type ArtifactVerificationTaskParams struct {
+	TaskParams
+	ServiceID string
+	// LocationID limits verified artifacts to the given location, any location is used if it's empty.
+	LocationID string
+}
+
+// Validate checks artifact verification task parameters for correctness.
+func (p *ArtifactVerificationTaskParams) Validate() error {
+	if err := p.TaskParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.ServiceID == "" {
+		return errors.New("service id can't be empty")
+	}
+
+	return nil
+}
+
+type artifactVerificationTask struct {
+	common
+	*ArtifactVerificationTaskParams
+}
+
+// NewArtifactVerificationTask creates new task which verifies the latest successful artifact of the service.
+func NewArtifactVerificationTask(params *ArtifactVerificationTaskParams) (Task, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &artifactVerificationTask{
+		ArtifactVerificationTaskParams: params,
+	}, nil
+}
+
+// Run verifies the latest successful artifact, verification result is recorded on the artifact.
+func (t *artifactVerificationTask) Run(ctx context.Context, scheduler *Service) (RunResult, error) {
+	artifacts, err := models.FindArtifacts(scheduler.db.Querier, models.ArtifactFilters{
+		ServiceID:  t.ServiceID,
+		LocationID: t.LocationID,
+		Status:     models.SuccessBackupStatus,
+	})
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	if len(artifacts) == 0 {
+		return RunResult{}, errors.Errorf("no successful artifacts to verify for service %s", t.ServiceID)
+	}
+
+	// artifacts are sorted by creation time in descending order
+	artifactID := artifacts[0].ID
+	res, err := runWithRetries(ctx, t.Retries, t.RetryInterval, func() error {
+		_, err := scheduler.verificationService.VerifyArtifact(ctx, artifactID)
+		return err
+	})
+	res.ArtifactID = artifactID
+	return res, err
+}
+
+func (t *artifactVerificationTask) Type() models.ScheduledTaskType {
+	return models.ScheduledArtifactVerificationTask
+}
+
+func (t *artifactVerificationTask) Data() *models.ScheduledTaskData {
+	return &models.ScheduledTaskData{
+		ArtifactVerificationTask: &models.ArtifactVerificationTaskData{
+			CommonTaskData: t.data(),
+			ServiceID:      t.ServiceID,
+			LocationID:     t.LocationID,
+		},
+	}
+}
@@ -17,8 +17,11 @@
 package scheduler
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -321,4 +324,257 @@ func TestValidation(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("query action task", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name   string
+			params *QueryActionTaskParams
+			errMsg string
+		}{
+			{
+				name: "normal",
+				params: &QueryActionTaskParams{
+					TaskParams: TaskParams{Name: "name"},
+					ServiceID:  "service-id",
+					ActionType: models.MySQLQuerySelectAction,
+					Query:      "SELECT 1",
+				},
+				errMsg: "",
+			},
+			{
+				name: "action without query",
+				params: &QueryActionTaskParams{
+					TaskParams: TaskParams{Name: "name"},
+					ServiceID:  "service-id",
+					ActionType: models.MongoDBQueryBuildInfoAction,
+				},
+				errMsg: "",
+			},
+			{
+				name: "empty name",
+				params: &QueryActionTaskParams{
+					ServiceID:  "service-id",
+					ActionType: models.MySQLQuerySelectAction,
+					Query:      "SELECT 1",
+				},
+				errMsg: "task name can't be empty",
+			},
+			{
+				name: "empty service id",
+				params: &QueryActionTaskParams{
+					TaskParams: TaskParams{Name: "name"},
+					ActionType: models.MySQLQuerySelectAction,
+					Query:      "SELECT 1",
+				},
+				errMsg: "service id can't be empty",
+			},
+			{
+				name: "empty query",
+				params: &QueryActionTaskParams{
+					TaskParams: TaskParams{Name: "name"},
+					ServiceID:  "service-id",
+					ActionType: models.PostgreSQLQuerySelectAction,
+				},
+				errMsg: "invalid argument: query can't be empty for postgresql_query_select action",
+			},
+			{
+				name: "invalid action type",
+				params: &QueryActionTaskParams{
+					TaskParams: TaskParams{Name: "name"},
+					ServiceID:  "service-id",
+					ActionType: "invalid",
+				},
+				errMsg: "invalid argument: invalid query action type 'invalid'",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := NewQueryActionTask(tt.params)
+
+				if tt.errMsg != "" {
+					assert.EqualError(t, err, tt.errMsg)
+					return
+				}
+
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("pt-summary task", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewPTSummaryTask(&PTSummaryTaskParams{TaskParams: TaskParams{Name: "name"}, NodeID: "node-id"})
+		require.NoError(t, err)
+
+		_, err = NewPTSummaryTask(&PTSummaryTaskParams{TaskParams: TaskParams{Name: "name"}})
+		assert.EqualError(t, err, "node id can't be empty")
+	})
+
+	t.Run("security checks task", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSecurityChecksTask(&SecurityChecksTaskParams{TaskParams: TaskParams{Name: "name"}})
+		require.NoError(t, err)
+
+		_, err = NewSecurityChecksTask(&SecurityChecksTaskParams{CheckNames: []string{"check"}})
+		assert.EqualError(t, err, "task name can't be empty")
+	})
+	t.Run("artifact verification task", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewArtifactVerificationTask(&ArtifactVerificationTaskParams{TaskParams: TaskParams{Name: "name"}, ServiceID: "service-id"})
+		require.NoError(t, err)
+
+		_, err = NewArtifactVerificationTask(&ArtifactVerificationTaskParams{TaskParams: TaskParams{Name: "name"}})
+		assert.EqualError(t, err, "service id can't be empty")
+	})
+}
+
+func TestConvertDBTask(t *testing.T) {
+	t.Parallel()
+
+	taskParams := TaskParams{
+		Name:          "name",
+		Description:   "description",
+		Retries:       2,
+		RetryInterval: time.Minute,
+	}
+
+	securityChecks, err := NewSecurityChecksTask(&SecurityChecksTaskParams{
+		TaskParams: taskParams,
+		CheckNames: []string{"mysql_version"},
+	})
+	require.NoError(t, err)
+
+	queryAction, err := NewQueryActionTask(&QueryActionTaskParams{
+		TaskParams: taskParams,
+		ServiceID:  "service-id",
+		ActionType: models.PostgreSQLQuerySelectAction,
+		Query:      "SELECT 1",
+	})
+	require.NoError(t, err)
+
+	ptSummary, err := NewPTSummaryTask(&PTSummaryTaskParams{
+		TaskParams: taskParams,
+		NodeID:     "node-id",
+	})
+	require.NoError(t, err)
+
+	artifactVerification, err := NewArtifactVerificationTask(&ArtifactVerificationTaskParams{
+		TaskParams: taskParams,
+		ServiceID:  "service-id",
+		LocationID: "location-id",
+	})
+	require.NoError(t, err)
+
+	s := &Service{}
+	for _, task := range []Task{securityChecks, queryAction, ptSummary, artifactVerification} {
+		task := task
+		t.Run(string(task.Type()), func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := s.convertDBTask(&models.ScheduledTask{
+				ID:   "task-id",
+				Type: task.Type(),
+				Data: task.Data(),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "task-id", actual.ID())
+			assert.Equal(t, task.Type(), actual.Type())
+			assert.Equal(t, task.Data(), actual.Data())
+		})
+	}
+}
+
+func TestRunWithRetries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("succeeds after retry", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		res, err := runWithRetries(context.Background(), 2, time.Millisecond, func() error {
+			calls++
+			if calls < 2 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, uint32(1), res.Retries)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		res, err := runWithRetries(context.Background(), 2, time.Millisecond, func() error {
+			calls++
+			return errors.Errorf("failed %d", calls)
+		})
+		assert.EqualError(t, err, "failed 3")
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, uint32(2), res.Retries)
+	})
+}
+
+func TestMissedRuns(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 6, 10, 12, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		nextRun  time.Time
+		limit    int
+		expected int
+	}{
+		{
+			name:     "never scheduled",
+			limit:    models.MaxCatchUpRuns,
+			expected: 0,
+		},
+		{
+			name:     "not missed",
+			nextRun:  time.Date(2021, 6, 11, 0, 0, 0, 0, time.UTC),
+			limit:    models.MaxCatchUpRuns,
+			expected: 0,
+		},
+		{
+			name:     "missed three runs",
+			nextRun:  time.Date(2021, 6, 8, 0, 0, 0, 0, time.UTC),
+			limit:    models.MaxCatchUpRuns,
+			expected: 3,
+		},
+		{
+			name:     "limited",
+			nextRun:  time.Date(2021, 6, 8, 0, 0, 0, 0, time.UTC),
+			limit:    1,
+			expected: 1,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := missedRuns("0 0 * * *", tc.nextRun, now, tc.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestCheckCatchUpPolicy(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkCatchUpPolicy(models.ScheduledMySQLBackupTask, models.RunOnceCatchUpPolicy))
+	assert.NoError(t, checkCatchUpPolicy(models.ScheduledSecurityChecksTask, models.RunAllCatchUpPolicy))
+	assert.EqualError(t, checkCatchUpPolicy(models.ScheduledMongoDBBackupTask, models.RunAllCatchUpPolicy),
+		`invalid argument: catch-up policy "run_all" can't be used for backups, use "run_once" instead`)
 }
@@ -618,6 +618,7 @@ func (s *Server) ChangeSettings(ctx context.Context, req *serverpb.ChangeSetting
 			RemoveSlackAlertingSettings: req.RemoveSlackAlertingSettings,
 			EnableBackupManagement:      req.EnableBackupManagement,
 			DisableBackupManagement:     req.DisableBackupManagement,
+			// API doesn't allow to change backup concurrency limits and blackout windows yet.
 
 			EnableDBaaS:  req.EnableDbaas,
 			DisableDBaaS: req.DisableDbaas,
@@ -0,0 +1,221 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package httpapi serves JSON HTTP APIs for the features that are not described in pmm API protobuf files yet.
+// Handlers follow grpc-gateway conventions: POST requests with JSON bodies, gRPC status errors converted
+// to HTTP status codes, and newline-delimited {"result": ...} objects for streaming responses.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	grpc_gateway "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/percona/pmm-managed/utils/logger"
+)
+
+// errorBody is the same error response body as grpc-gateway returns.
+type errorBody struct {
+	Error   string `json:"error"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+// Mux is the subset of http.ServeMux methods used for handlers registration.
+type Mux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Handle registers handler for the given path. Request body is decoded into Req,
+// and handler's response is encoded as the response body.
+func Handle[Req, Res any](mux Mux, path string, handler func(ctx context.Context, req *Req) (*Res, error)) {
+	mux.HandleFunc(path, func(rw http.ResponseWriter, req *http.Request) {
+		ctx, l := requestContext(req)
+
+		var in Req
+		var res *Res
+		err := logRequest(l, "HTTP "+path, func() error {
+			if err := decodeRequest(req, &in); err != nil {
+				return err
+			}
+
+			var err error
+			res, err = handler(ctx, &in)
+			return err
+		})
+		if err != nil {
+			writeError(rw, l, err)
+			return
+		}
+
+		writeJSON(rw, l, http.StatusOK, res)
+	})
+}
+
+// HandleStream registers streaming handler for the given path. Request body is decoded into Req,
+// and every message passed to send is written to the response as a separate {"result": ...} line.
+// Error returned after the first message is written as the last {"error": ...} line.
+func HandleStream[Req, Res any](mux Mux, path string, handler func(ctx context.Context, req *Req, send func(*Res) error) error) {
+	mux.HandleFunc(path, func(rw http.ResponseWriter, req *http.Request) {
+		ctx, l := requestContext(req)
+
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			writeError(rw, l, status.Error(codes.Internal, "Streaming is not supported."))
+			return
+		}
+
+		var in Req
+		var started bool
+		err := logRequest(l, "HTTP stream "+path, func() error {
+			if err := decodeRequest(req, &in); err != nil {
+				return err
+			}
+
+			return handler(ctx, &in, func(msg *Res) error {
+				if !started {
+					rw.Header().Set("Content-Type", "application/json")
+					rw.WriteHeader(http.StatusOK)
+					started = true
+				}
+
+				b, err := json.Marshal(map[string]*Res{"result": msg})
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if _, err = rw.Write(append(b, '\n')); err != nil {
+					return errors.WithStack(err)
+				}
+				flusher.Flush()
+				return nil
+			})
+		})
+
+		switch {
+		case err == nil:
+		case !started:
+			writeError(rw, l, err)
+		default:
+			b, _ := json.Marshal(map[string]*errorBody{"error": makeErrorBody(err)})
+			if _, err = rw.Write(append(b, '\n')); err != nil {
+				l.Debugf("Failed to write error: %s.", err)
+			}
+		}
+	})
+}
+
+// requestContext returns context for the handler. Request headers are passed as incoming gRPC metadata
+// the same way as grpc-gateway does it, so handlers can use the same authentication helpers
+// (for example, Grafana user lookup by Authorization and Cookie headers) as gRPC handlers.
+func requestContext(req *http.Request) (context.Context, *logrus.Entry) {
+	l := logrus.WithField("request", logger.MakeRequestID())
+	ctx := metadata.NewIncomingContext(req.Context(), headersMetadata(req.Header))
+	return logger.SetEntry(ctx, l), l
+}
+
+// headersMetadata converts HTTP headers to gRPC metadata like grpc-gateway's DefaultHeaderMatcher does it.
+func headersMetadata(header http.Header) metadata.MD {
+	md := make(metadata.MD)
+	for key, vals := range header {
+		key = textproto.CanonicalMIMEHeaderKey(key)
+		for _, val := range vals {
+			// grpc-gateway passes 'authorization' header with no prefix for backwards-compatibility
+			if key == "Authorization" {
+				md.Append("authorization", val)
+			}
+			if h, ok := grpc_gateway.DefaultHeaderMatcher(key); ok {
+				md.Append(h, val)
+			}
+		}
+	}
+	return md
+}
+
+// decodeRequest decodes JSON request body into dst; empty body is allowed.
+func decodeRequest(req *http.Request, dst interface{}) error {
+	if req.Method != http.MethodPost {
+		return status.Errorf(codes.Unimplemented, "Method %s is not allowed, use POST.", req.Method)
+	}
+
+	d := json.NewDecoder(req.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(dst); err != nil && err != io.EOF {
+		return status.Errorf(codes.InvalidArgument, "Invalid request body: %s.", err)
+	}
+
+	return nil
+}
+
+// logRequest logs request the same way as gRPC interceptors do it, unexpected errors are replaced with Internal error.
+func logRequest(l *logrus.Entry, prefix string, f func() error) error {
+	start := time.Now()
+	l.Infof("Starting %s ...", prefix)
+
+	err := f()
+	dur := time.Since(start)
+
+	_, gRPCError := status.FromError(errors.Cause(err))
+	switch {
+	case err == nil:
+		l.Infof("%s done in %s.", prefix, dur)
+	case gRPCError:
+		l.Warnf("%s done in %s with gRPC error: %+v", prefix, dur, err)
+	default:
+		l.Errorf("%s done in %s with unexpected error: %+v", prefix, dur, err)
+		err = status.Error(codes.Internal, "Internal server error.")
+	}
+
+	return err
+}
+
+func makeErrorBody(err error) *errorBody {
+	s, _ := status.FromError(errors.Cause(err))
+	return &errorBody{
+		Error:   s.Message(),
+		Code:    int32(s.Code()),
+		Message: s.Message(),
+	}
+}
+
+func writeError(rw http.ResponseWriter, l *logrus.Entry, err error) {
+	body := makeErrorBody(err)
+	writeJSON(rw, l, grpc_gateway.HTTPStatusFromCode(codes.Code(body.Code)), body)
+}
+
+func writeJSON(rw http.ResponseWriter, l *logrus.Entry, code int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		l.Errorf("Failed to marshal response: %+v.", err)
+		code = http.StatusInternalServerError
+		b, _ = json.Marshal(makeErrorBody(status.Error(codes.Internal, "Internal server error.")))
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if _, err = rw.Write(b); err != nil {
+		l.Debugf("Failed to write response: %s.", err)
+	}
+}
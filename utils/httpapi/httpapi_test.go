@@ -0,0 +1,139 @@
+// pmm-managed
+// Copyright (C) 2017 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package httpapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testRequest struct {
+	Name     string   `json:"name"`
+	Interval Duration `json:"interval"`
+}
+
+type testResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func post(t *testing.T, mux *http.ServeMux, path, body string) (int, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	b, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(b)
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	Handle(mux, "/v1/Test/Hello", func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.Name {
+		case "":
+			return nil, status.Error(codes.InvalidArgument, "Empty name.")
+		case "panic":
+			return nil, errors.New("unexpected")
+		}
+		return &testResponse{Greeting: "Hello, " + req.Name + " every " + time.Duration(req.Interval).String()}, nil
+	})
+
+	code, body := post(t, mux, "/v1/Test/Hello", `{"name": "pmm", "interval": "60s"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"greeting": "Hello, pmm every 1m0s"}`, body)
+
+	code, body = post(t, mux, "/v1/Test/Hello", ``)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.JSONEq(t, `{"error": "Empty name.", "code": 3, "message": "Empty name."}`, body)
+
+	code, body = post(t, mux, "/v1/Test/Hello", `{"name": "pmm", "unknown": 1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, `unknown field \"unknown\"`)
+
+	code, body = post(t, mux, "/v1/Test/Hello", `{"name": "panic"}`)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.JSONEq(t, `{"error": "Internal server error.", "code": 13, "message": "Internal server error."}`, body)
+}
+
+func TestHandleMetadata(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	Handle(mux, "/v1/Test/Whoami", func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "No metadata.")
+		}
+		greeting := strings.Join(md.Get("authorization"), ",") + ";" +
+			strings.Join(md.Get("grpcgateway-cookie"), ",") + ";" +
+			strings.Join(md.Get("x-custom"), ",") + ";" +
+			strings.Join(md.Get("x-ignored"), ",")
+		return &testResponse{Greeting: greeting}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/Test/Whoami", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	req.Header.Set("Cookie", "grafana_session=123")
+	req.Header.Set("Grpc-Metadata-X-Custom", "value")
+	req.Header.Set("X-Ignored", "value")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"greeting": "Basic YWRtaW46YWRtaW4=;grafana_session=123;value;"}`, rec.Body.String())
+}
+
+func TestHandleStream(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	HandleStream(mux, "/v1/Test/Count", func(ctx context.Context, req *testRequest, send func(*testResponse) error) error {
+		for _, s := range []string{"one", "two"} {
+			if err := send(&testResponse{Greeting: s}); err != nil {
+				return err
+			}
+		}
+		if req.Name == "fail" {
+			return status.Error(codes.Aborted, "Stopped.")
+		}
+		return nil
+	})
+
+	code, body := post(t, mux, "/v1/Test/Count", `{}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"result":{"greeting":"one"}}`+"\n"+`{"result":{"greeting":"two"}}`+"\n", body)
+
+	code, body = post(t, mux, "/v1/Test/Count", `{"name": "fail"}`)
+	assert.Equal(t, http.StatusOK, code)
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"error": {"error": "Stopped.", "code": 10, "message": "Stopped."}}`, lines[2])
+}